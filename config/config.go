@@ -2,24 +2,179 @@ package config
 
 import (
 	"contact-go/helper/apperrors"
+	"contact-go/helper/phone"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
+	Host     string   `mapstructure:"host"`
 	Port     string   `mapstructure:"port"`
+	Server   Server   `mapstructure:"server"`
+	TLS      TLS      `mapstructure:"tls"`
+	CORS     CORS     `mapstructure:"cors"`
 	Debug    bool     `mapstructure:"debug"`
 	Storage  string   `mapstructure:"storage"`
 	Mode     string   `mapstructure:"mode"`
 	Database Database `mapstructure:"db"`
+	JSON     JSON     `mapstructure:"json"`
+	Phone    Phone    `mapstructure:"phone"`
+	Trash    Trash    `mapstructure:"trash"`
+	Import   Import   `mapstructure:"import"`
+	Auth     Auth     `mapstructure:"auth"`
+	RBAC     RBAC     `mapstructure:"rbac"`
+	Tenant   Tenant   `mapstructure:"tenant"`
 }
 
+// Server configures the http server, which listens on the Host and
+// Port of Config, on every interface when Host is empty, as it has to
+// in a container. A request has ReadTimeout to be read and WriteTimeout
+// to be answered, an idle connection is kept for IdleTimeout, and on
+// SIGINT or SIGTERM the requests in flight have ShutdownTimeout to
+// finish before the server stops anyway.
+type Server struct {
+	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+}
+
+// TLS configures https, which the http server serves instead of http
+// with a CertFile and KeyFile, loading them again as they change. With
+// a ClientCAFile, a client certificate issued by a CA of that bundle
+// tells who is calling, and is required of every caller with
+// RequireClientCert, which cannot be set without it.
+type TLS struct {
+	CertFile          string `mapstructure:"cert_file"`
+	KeyFile           string `mapstructure:"key_file"`
+	ClientCAFile      string `mapstructure:"client_ca_file"`
+	RequireClientCert bool   `mapstructure:"require_client_cert"`
+}
+
+// Enabled reports whether the http server serves https.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// ClientCerts reports whether client certificates tell who is calling.
+func (t TLS) ClientCerts() bool {
+	return t.Enabled() && t.ClientCAFile != ""
+}
+
+// CORS configures the browser pages that may call the http api, by
+// their AllowedOrigins, such as https://app.example.com.
+type CORS struct {
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+}
+
+// Database configures the sql storage. Timeout bounds every
+// storage call, zero leaving only the caller's own deadline.
 type Database struct {
-	Driver string `mapstructure:"driver"`
-	URL    string `mapstructure:"url"`
+	Driver      string        `mapstructure:"driver"`
+	URL         string        `mapstructure:"url"`
+	Path        string        `mapstructure:"path"`
+	AutoMigrate bool          `mapstructure:"auto_migrate"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+}
+
+// JSON configures the json storage, keeping Backups previous
+// versions of the file at Path next to it. The groups and the audit
+// log are kept in files of their own, at GroupsPath and AuditPath, and
+// the address books there are at AddressBooksPath. Every address book
+// but the default one keeps each of those in a file named after it,
+// data/contact.sales.json for the contacts of sales.
+type JSON struct {
+	Path             string `mapstructure:"path"`
+	Backups          int    `mapstructure:"backups"`
+	GroupsPath       string `mapstructure:"groups_path"`
+	AuditPath        string `mapstructure:"audit_path"`
+	AddressBooksPath string `mapstructure:"address_books_path"`
+}
+
+// Phone configures phone numbers, which are read as numbers of
+// DefaultRegion unless they start with a country calling code.
+type Phone struct {
+	DefaultRegion string `mapstructure:"default_region"`
+}
+
+// Trash configures deleted contacts, which are kept for Retention
+// before a purge removes them for good.
+type Trash struct {
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// Import configures the vcard and csv imports of the http api, which
+// take files of up to MaxSize bytes and MaxRows cards or rows, zero
+// leaving either unbounded.
+type Import struct {
+	MaxSize int64 `mapstructure:"max_size"`
+	MaxRows int   `mapstructure:"max_rows"`
+}
+
+// Auth configures who may call the http api, which is open to anyone
+// until there are APIKeys, each "name:key", or a JWTKeyFile holding the
+// HMAC secret or RSA public key bearer tokens are signed with. A token
+// has to carry an expiry, and be from JWTIssuer, for JWTAudience and
+// grant JWTScope, where those are set.
+type Auth struct {
+	APIKeys     []string `mapstructure:"api_keys"`
+	JWTKeyFile  string   `mapstructure:"jwt_key_file"`
+	JWTIssuer   string   `mapstructure:"jwt_issuer"`
+	JWTAudience string   `mapstructure:"jwt_audience"`
+	JWTScope    string   `mapstructure:"jwt_scope"`
+}
+
+// Enabled reports whether the http api asks who is calling it.
+func (a Auth) Enabled() bool {
+	return len(a.APIKeys) > 0 || a.JWTKeyFile != ""
+}
+
+// RBAC configures what each caller may do, which is anything until
+// there are Roles. Roles gives the permissions of each role, among read,
+// write, delete, purge and admin, CLI the roles of the interactive menu,
+// and Principals the roles of each caller by how they authenticate,
+// apikey, jwt or cert, then by name. A token may add roles of its own,
+// and a caller without any has DefaultRole.
+type RBAC struct {
+	Roles       map[string][]string            `mapstructure:"roles"`
+	CLI         []string                       `mapstructure:"cli"`
+	Principals  map[string]map[string][]string `mapstructure:"principals"`
+	DefaultRole string                         `mapstructure:"default_role"`
+}
+
+// Enabled reports whether calls to the usecases are checked.
+func (r RBAC) Enabled() bool {
+	return len(r.Roles) > 0
+}
+
+// Tenant configures the address books of the http api. Principals
+// binds callers, by how they authenticate then by name as in RBAC, to
+// the address book they alone work on. A token may bind its subject
+// itself, and any other caller picks an address book with the X-Tenant
+// header, which takes the admin permission but for the default one.
+type Tenant struct {
+	Principals map[string]map[string]string `mapstructure:"principals"`
 }
 
 func LoadConfig() (*Config, error) {
+	viper.SetDefault("host", "")
+	viper.SetDefault("server.read_timeout", 15*time.Second)
+	viper.SetDefault("server.write_timeout", 60*time.Second)
+	viper.SetDefault("server.idle_timeout", 120*time.Second)
+	viper.SetDefault("server.shutdown_timeout", 30*time.Second)
+	viper.SetDefault("json.path", "data/contact.json")
+	viper.SetDefault("json.backups", 3)
+	viper.SetDefault("json.groups_path", "data/group.json")
+	viper.SetDefault("json.audit_path", "data/audit.json")
+	viper.SetDefault("json.address_books_path", "data/address_book.json")
+	viper.SetDefault("db.path", "data/contact.db")
+	viper.SetDefault("db.timeout", 10*time.Second)
+	viper.SetDefault("phone.default_region", "ID")
+	viper.SetDefault("trash.retention", 30*24*time.Hour)
+	viper.SetDefault("import.max_size", 1<<30)
+	viper.SetDefault("import.max_rows", 100000)
+
 	viper.SetConfigFile(".env")
 	err := viper.ReadInConfig()
 	if err != nil {
@@ -36,5 +191,13 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if !phone.ValidRegion(config.Phone.DefaultRegion) {
+		return nil, apperrors.NewAppError(apperrors.ErrPhoneRegionNotSupported)
+	}
+
+	if config.TLS.RequireClientCert && !config.TLS.ClientCerts() {
+		return nil, apperrors.NewAppError(apperrors.ErrTLSClientCANotExist)
+	}
+
 	return config, nil
 }
@@ -0,0 +1,384 @@
+package db
+
+import (
+	"contact-go/helper/apperrors"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFS holds the up and down SQL of every dialect, named
+// migrations/<dialect>/<version>_<name>.<up|down>.sql
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// dollarTag matches the tag a postgres dollar quoted string starts with.
+var dollarTag = regexp.MustCompile(`^\$(?:[A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+const (
+	DialectMysql    = "mysql"
+	DialectPostgres = "postgres"
+	DialectSqlite   = "sqlite"
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt string
+}
+
+// Migrator applies the embedded migrations of one dialect to db,
+// recording each applied version in the schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, dialect string) (*Migrator, error) {
+	migrations, err := loadMigrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(Migrator)
+	m.db = db
+	m.dialect = dialect
+	m.migrations = migrations
+	return m, nil
+}
+
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrDbDialectNotSupported)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// placeholder returns the nth bind parameter in the dialect's syntax.
+func (m *Migrator) placeholder(n int) string {
+	if m.dialect == DialectPostgres {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	sqlQuery := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+	if _, err := m.db.ExecContext(ctx, sqlQuery); err != nil {
+		return err
+	}
+	if m.dialect != DialectMysql {
+		return nil
+	}
+
+	sqlQuery = `CREATE TABLE IF NOT EXISTS schema_migration_statements (
+		version BIGINT NOT NULL,
+		direction VARCHAR(4) NOT NULL,
+		statement INT NOT NULL,
+		PRIMARY KEY (version, direction, statement)
+	)`
+	_, err := m.db.ExecContext(ctx, sqlQuery)
+	return err
+}
+
+// applied returns when each applied version was applied.
+func (m *Migrator) applied(ctx context.Context) (map[int64]string, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]string)
+	for rows.Next() {
+		var version int64
+		var appliedAt sql.NullString
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt.String
+	}
+
+	return applied, rows.Err()
+}
+
+// splitStatements breaks a migration into single statements,
+// since not every driver accepts several in one Exec. Only a ";"
+// outside of quotes and comments ends a statement, and what holds
+// nothing but comments is left out.
+func splitStatements(dialect string, script string) []string {
+	var statements []string
+	start, code := 0, false
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i, code = skipQuoted(dialect, script, i), true
+		case strings.HasPrefix(script[i:], "--"), c == '#' && dialect == DialectMysql:
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+		case c == '$' && dialect == DialectPostgres:
+			if tag := dollarTag.FindString(script[i:]); tag != "" {
+				if end := strings.Index(script[i+len(tag):], tag); end >= 0 {
+					i += len(tag) + end + len(tag) - 1
+				} else {
+					i = len(script)
+				}
+			}
+			code = true
+		case c == ';':
+			if code {
+				statements = append(statements, strings.TrimSpace(script[start:i]))
+			}
+			start, code = i+1, false
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			code = true
+		}
+	}
+	if code {
+		statements = append(statements, strings.TrimSpace(script[start:]))
+	}
+	return statements
+}
+
+// skipQuoted returns where the quoted string, identifier or name that
+// starts at script[i] ends, a doubled quote being part of it, as is a
+// quote escaped by a backslash in mysql.
+func skipQuoted(dialect string, script string, i int) int {
+	quote := script[i]
+	for j := i + 1; j < len(script); j++ {
+		switch {
+		case script[j] == '\\' && quote != '`' && dialect == DialectMysql:
+			j++
+		case script[j] == quote && j+1 < len(script) && script[j+1] == quote:
+			j++
+		case script[j] == quote:
+			return j
+		}
+	}
+	return len(script)
+}
+
+// run applies script, then record. It is all one transaction where
+// schema changes are transactional. MySQL commits every schema change
+// on its own, so there each statement is recorded in
+// schema_migration_statements once applied, and a migration that
+// failed halfway resumes after the last statement it applied.
+func (m *Migrator) run(ctx context.Context, version int64, direction string, script string, record string, args ...interface{}) error {
+	statements := splitStatements(m.dialect, script)
+
+	if m.dialect == DialectMysql {
+		done, err := m.statementsDone(ctx, version, direction)
+		if err != nil {
+			return err
+		}
+
+		for i, statement := range statements {
+			if done[i] {
+				continue
+			}
+			if _, err := m.db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("statement %d: %w", i+1, err)
+			}
+			_, err := m.db.ExecContext(ctx, "INSERT INTO schema_migration_statements (version, direction, statement) VALUES (?, ?, ?)", version, direction, i)
+			if err != nil {
+				return err
+			}
+		}
+		statements = nil
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	if m.dialect == DialectMysql {
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migration_statements WHERE version = ? AND direction = ?", version, direction)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// statementsDone returns which statements of the migration to version
+// in direction were applied before it failed.
+func (m *Migrator) statementsDone(ctx context.Context, version int64, direction string) (map[int]bool, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT statement FROM schema_migration_statements WHERE version = ? AND direction = ?", version, direction)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int]bool)
+	for rows.Next() {
+		var statement int
+		if err := rows.Scan(&statement); err != nil {
+			return nil, err
+		}
+		done[statement] = true
+	}
+
+	return done, rows.Err()
+}
+
+// Up applies every pending migration in version order
+// and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	record := fmt.Sprintf("INSERT INTO schema_migrations (version, name) VALUES (%s, %s)", m.placeholder(1), m.placeholder(2))
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.run(ctx, migration.Version, "up", migration.Up, record, migration.Version, migration.Name)
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the most recently applied migration.
+// It returns nil when there is nothing to roll back.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	record := fmt.Sprintf("DELETE FROM schema_migrations WHERE version = %s", m.placeholder(1))
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := m.run(ctx, migration.Version, "down", migration.Down, record, migration.Version)
+		if err != nil {
+			return nil, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return &migration, nil
+	}
+
+	return nil, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_splitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		script  string
+		want    []string
+	}{
+		{
+			name:    "statements",
+			dialect: DialectSqlite,
+			script:  "CREATE TABLE a (id INT);\n\nDROP TABLE b;\n",
+			want:    []string{"CREATE TABLE a (id INT)", "DROP TABLE b"},
+		},
+		{
+			name:    "without a last semicolon",
+			dialect: DialectSqlite,
+			script:  "DROP TABLE a; DROP TABLE b",
+			want:    []string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{
+			name:    "semicolons in quotes",
+			dialect: DialectSqlite,
+			script:  `UPDATE a SET note = 'one; two', "x;y" = 'it''s; fine';`,
+			want:    []string{`UPDATE a SET note = 'one; two', "x;y" = 'it''s; fine'`},
+		},
+		{
+			name:    "semicolons in comments",
+			dialect: DialectSqlite,
+			script:  "-- first; then second\nDROP TABLE a; /* the old; one */ DROP TABLE b;\n-- done;\n",
+			want:    []string{"-- first; then second\nDROP TABLE a", "/* the old; one */ DROP TABLE b"},
+		},
+		{
+			name:    "mysql escapes and comments",
+			dialect: DialectMysql,
+			script:  "UPDATE a SET note = 'it\\'s; fine', `x;y` = 1; # last;\n",
+			want:    []string{"UPDATE a SET note = 'it\\'s; fine', `x;y` = 1"},
+		},
+		{
+			name:    "postgres dollar quotes",
+			dialect: DialectPostgres,
+			script:  "CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN RETURN NEW; END; $body$ LANGUAGE plpgsql;\nSELECT $$a;b$$;",
+			want:    []string{"CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN RETURN NEW; END; $body$ LANGUAGE plpgsql", "SELECT $$a;b$$"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStatements(tt.dialect, tt.script))
+		})
+	}
+}
+
+func TestMigrator_UpResumesMysql(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+
+	m := &Migrator{db: sqlDB, dialect: DialectMysql, migrations: []Migration{{
+		Version: 8,
+		Name:    "address_books",
+		Up:      "ALTER TABLE contact ADD COLUMN tenant VARCHAR(64);\nCREATE INDEX idx_contact_tenant ON contact (tenant);\n",
+	}}}
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations (")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migration_statements (")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+
+	//* the column was added before the index failed to be created
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT statement FROM schema_migration_statements WHERE version = ? AND direction = ?")).
+		WithArgs(8, "up").WillReturnRows(sqlmock.NewRows([]string{"statement"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX idx_contact_tenant ON contact (tenant)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migration_statements (version, direction, statement) VALUES (?, ?, ?)")).
+		WithArgs(8, "up", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migration_statements WHERE version = ? AND direction = ?")).
+		WithArgs(8, "up").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name) VALUES (?, ?)")).
+		WithArgs(8, "address_books").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	done, err := m.Up(context.Background())
+	if assert.NoError(t, err) {
+		assert.Len(t, done, 1)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS contact (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    no_telp VARCHAR(64) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_contact_name (name),
+    INDEX idx_contact_no_telp (no_telp)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
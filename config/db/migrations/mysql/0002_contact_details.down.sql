@@ -0,0 +1,10 @@
+ALTER TABLE contact
+    DROP COLUMN phones,
+    DROP COLUMN emails,
+    DROP COLUMN addresses,
+    DROP COLUMN company,
+    DROP COLUMN job_title,
+    DROP COLUMN birthday,
+    DROP COLUMN notes,
+    DROP COLUMN created_at,
+    DROP COLUMN updated_at;
@@ -0,0 +1,10 @@
+ALTER TABLE contact
+    ADD COLUMN phones JSON NULL,
+    ADD COLUMN emails JSON NULL,
+    ADD COLUMN addresses JSON NULL,
+    ADD COLUMN company VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN job_title VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN birthday VARCHAR(10) NOT NULL DEFAULT '',
+    ADD COLUMN notes TEXT NOT NULL,
+    ADD COLUMN created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP;
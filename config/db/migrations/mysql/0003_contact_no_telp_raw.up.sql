@@ -0,0 +1,3 @@
+ALTER TABLE contact ADD COLUMN no_telp_raw VARCHAR(64) NOT NULL DEFAULT '';
+-- rows stored before normalization keep what was typed in both columns
+UPDATE contact SET no_telp_raw = no_telp;
@@ -0,0 +1,2 @@
+DROP INDEX idx_contact_deleted_at ON contact;
+ALTER TABLE contact DROP COLUMN deleted_at;
@@ -0,0 +1,2 @@
+ALTER TABLE contact ADD COLUMN deleted_at DATETIME NULL DEFAULT NULL;
+CREATE INDEX idx_contact_deleted_at ON contact (deleted_at);
@@ -0,0 +1 @@
+ALTER TABLE contact DROP COLUMN version;
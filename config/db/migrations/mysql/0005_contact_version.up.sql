@@ -0,0 +1 @@
+ALTER TABLE contact ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
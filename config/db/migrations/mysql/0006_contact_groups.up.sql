@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS contact_group (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_contact_group_name (name)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+CREATE TABLE IF NOT EXISTS contact_group_member (
+    group_id BIGINT NOT NULL,
+    contact_id BIGINT NOT NULL,
+    PRIMARY KEY (group_id, contact_id),
+    INDEX idx_contact_group_member_contact_id (contact_id),
+    FOREIGN KEY (group_id) REFERENCES contact_group (id) ON DELETE CASCADE,
+    FOREIGN KEY (contact_id) REFERENCES contact (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
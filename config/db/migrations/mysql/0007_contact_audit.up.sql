@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS contact_audit (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    contact_id BIGINT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    operation VARCHAR(16) NOT NULL,
+    before_data JSON NULL,
+    after_data JSON NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_contact_audit_contact_id (contact_id, id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
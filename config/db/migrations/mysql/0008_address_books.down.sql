@@ -0,0 +1,14 @@
+-- the other address books cannot be merged into the default one without
+-- clashing group names, so only the default one is kept
+DELETE FROM contact_audit WHERE tenant <> 'default';
+DELETE FROM contact_group WHERE tenant <> 'default';
+DELETE FROM contact WHERE tenant <> 'default';
+DROP INDEX idx_contact_audit_contact_id ON contact_audit;
+CREATE INDEX idx_contact_audit_contact_id ON contact_audit (contact_id, id);
+ALTER TABLE contact_audit DROP COLUMN tenant;
+DROP INDEX idx_contact_group_name ON contact_group;
+CREATE UNIQUE INDEX idx_contact_group_name ON contact_group (name);
+ALTER TABLE contact_group DROP COLUMN tenant;
+DROP INDEX idx_contact_tenant ON contact;
+ALTER TABLE contact DROP COLUMN tenant;
+DROP TABLE IF EXISTS address_book;
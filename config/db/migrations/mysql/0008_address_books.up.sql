@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS address_book (
+    name VARCHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+ALTER TABLE contact ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX idx_contact_tenant ON contact (tenant, deleted_at);
+ALTER TABLE contact_group ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+DROP INDEX idx_contact_group_name ON contact_group;
+CREATE UNIQUE INDEX idx_contact_group_name ON contact_group (tenant, name);
+ALTER TABLE contact_audit ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+DROP INDEX idx_contact_audit_contact_id ON contact_audit;
+CREATE INDEX idx_contact_audit_contact_id ON contact_audit (tenant, contact_id, id);
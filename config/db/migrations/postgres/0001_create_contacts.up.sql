@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS contacts (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    no_telp TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_contacts_no_telp ON contacts (no_telp);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_contacts_name_trgm;
@@ -0,0 +1,10 @@
+ALTER TABLE contacts
+    DROP COLUMN IF EXISTS phones,
+    DROP COLUMN IF EXISTS emails,
+    DROP COLUMN IF EXISTS addresses,
+    DROP COLUMN IF EXISTS company,
+    DROP COLUMN IF EXISTS job_title,
+    DROP COLUMN IF EXISTS birthday,
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS updated_at;
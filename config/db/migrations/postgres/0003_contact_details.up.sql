@@ -0,0 +1,10 @@
+ALTER TABLE contacts
+    ADD COLUMN IF NOT EXISTS phones JSONB,
+    ADD COLUMN IF NOT EXISTS emails JSONB,
+    ADD COLUMN IF NOT EXISTS addresses JSONB,
+    ADD COLUMN IF NOT EXISTS company TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS job_title TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS birthday VARCHAR(10) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
@@ -0,0 +1 @@
+ALTER TABLE contacts DROP COLUMN IF EXISTS no_telp_raw;
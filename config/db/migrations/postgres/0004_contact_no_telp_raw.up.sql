@@ -0,0 +1,3 @@
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS no_telp_raw TEXT NOT NULL DEFAULT '';
+-- rows stored before normalization keep what was typed in both columns
+UPDATE contacts SET no_telp_raw = no_telp;
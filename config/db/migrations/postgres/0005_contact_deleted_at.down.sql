@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_contacts_deleted_at;
+ALTER TABLE contacts DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,2 @@
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_contacts_deleted_at ON contacts (deleted_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS contact_group_members;
+DROP TABLE IF EXISTS contact_groups;
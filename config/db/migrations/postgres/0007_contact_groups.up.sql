@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS contact_groups (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_groups_name ON contact_groups (LOWER(name));
+CREATE TABLE IF NOT EXISTS contact_group_members (
+    group_id BIGINT NOT NULL REFERENCES contact_groups (id) ON DELETE CASCADE,
+    contact_id BIGINT NOT NULL REFERENCES contacts (id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, contact_id)
+);
+CREATE INDEX IF NOT EXISTS idx_contact_group_members_contact_id ON contact_group_members (contact_id);
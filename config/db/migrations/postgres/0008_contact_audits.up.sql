@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS contact_audits (
+    id BIGSERIAL PRIMARY KEY,
+    contact_id BIGINT NOT NULL,
+    actor TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    before_data JSONB,
+    after_data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_contact_audits_contact_id ON contact_audits (contact_id, id);
@@ -0,0 +1,14 @@
+-- the other address books cannot be merged into the default one without
+-- clashing group names, so only the default one is kept
+DELETE FROM contact_audits WHERE tenant <> 'default';
+DELETE FROM contact_groups WHERE tenant <> 'default';
+DELETE FROM contacts WHERE tenant <> 'default';
+DROP INDEX IF EXISTS idx_contact_audits_contact_id;
+CREATE INDEX IF NOT EXISTS idx_contact_audits_contact_id ON contact_audits (contact_id, id);
+ALTER TABLE contact_audits DROP COLUMN IF EXISTS tenant;
+DROP INDEX IF EXISTS idx_contact_groups_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_groups_name ON contact_groups (LOWER(name));
+ALTER TABLE contact_groups DROP COLUMN IF EXISTS tenant;
+DROP INDEX IF EXISTS idx_contacts_tenant;
+ALTER TABLE contacts DROP COLUMN IF EXISTS tenant;
+DROP TABLE IF EXISTS address_books;
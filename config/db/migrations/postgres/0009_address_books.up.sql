@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS address_books (
+    name TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_contacts_tenant ON contacts (tenant, deleted_at);
+ALTER TABLE contact_groups ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_contact_groups_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_groups_name ON contact_groups (tenant, LOWER(name));
+ALTER TABLE contact_audits ADD COLUMN IF NOT EXISTS tenant TEXT NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_contact_audits_contact_id;
+CREATE INDEX IF NOT EXISTS idx_contact_audits_contact_id ON contact_audits (tenant, contact_id, id);
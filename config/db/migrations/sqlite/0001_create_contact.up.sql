@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS contact (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    no_telp TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_contact_name ON contact (name);
+CREATE INDEX IF NOT EXISTS idx_contact_no_telp ON contact (no_telp);
@@ -0,0 +1,9 @@
+ALTER TABLE contact DROP COLUMN phones;
+ALTER TABLE contact DROP COLUMN emails;
+ALTER TABLE contact DROP COLUMN addresses;
+ALTER TABLE contact DROP COLUMN company;
+ALTER TABLE contact DROP COLUMN job_title;
+ALTER TABLE contact DROP COLUMN birthday;
+ALTER TABLE contact DROP COLUMN notes;
+ALTER TABLE contact DROP COLUMN created_at;
+ALTER TABLE contact DROP COLUMN updated_at;
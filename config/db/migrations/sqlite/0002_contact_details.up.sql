@@ -0,0 +1,11 @@
+ALTER TABLE contact ADD COLUMN phones TEXT;
+ALTER TABLE contact ADD COLUMN emails TEXT;
+ALTER TABLE contact ADD COLUMN addresses TEXT;
+ALTER TABLE contact ADD COLUMN company TEXT NOT NULL DEFAULT '';
+ALTER TABLE contact ADD COLUMN job_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE contact ADD COLUMN birthday TEXT NOT NULL DEFAULT '';
+ALTER TABLE contact ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+-- ALTER TABLE only accepts constant defaults, existing rows are stamped below
+ALTER TABLE contact ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE contact ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE contact SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
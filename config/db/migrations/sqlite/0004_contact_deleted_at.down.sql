@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_contact_deleted_at;
+ALTER TABLE contact DROP COLUMN deleted_at;
@@ -0,0 +1,2 @@
+ALTER TABLE contact ADD COLUMN deleted_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_contact_deleted_at ON contact (deleted_at);
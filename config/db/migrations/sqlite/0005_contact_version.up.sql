@@ -0,0 +1 @@
+ALTER TABLE contact ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
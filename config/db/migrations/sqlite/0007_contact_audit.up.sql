@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS contact_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contact_id INTEGER NOT NULL,
+    actor TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    before_data TEXT,
+    after_data TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_contact_audit_contact_id ON contact_audit (contact_id, id);
@@ -0,0 +1,32 @@
+-- the other address books cannot be merged into the default one without
+-- clashing group names, so only the default one is kept
+DELETE FROM contact_audit WHERE tenant <> 'default';
+DELETE FROM contact_group WHERE tenant <> 'default';
+DELETE FROM contact WHERE tenant <> 'default';
+CREATE TABLE contact_group_member_old AS SELECT group_id, contact_id FROM contact_group_member;
+DROP TABLE contact_group_member;
+ALTER TABLE contact_group RENAME TO contact_group_old;
+CREATE TABLE contact_group (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL COLLATE NOCASE UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO contact_group (id, name, description, created_at, updated_at)
+    SELECT id, name, description, created_at, updated_at FROM contact_group_old;
+DROP TABLE contact_group_old;
+CREATE TABLE contact_group_member (
+    group_id INTEGER NOT NULL REFERENCES contact_group (id) ON DELETE CASCADE,
+    contact_id INTEGER NOT NULL REFERENCES contact (id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, contact_id)
+);
+INSERT INTO contact_group_member (group_id, contact_id) SELECT group_id, contact_id FROM contact_group_member_old;
+DROP TABLE contact_group_member_old;
+CREATE INDEX IF NOT EXISTS idx_contact_group_member_contact_id ON contact_group_member (contact_id);
+DROP INDEX IF EXISTS idx_contact_audit_contact_id;
+CREATE INDEX IF NOT EXISTS idx_contact_audit_contact_id ON contact_audit (contact_id, id);
+ALTER TABLE contact_audit DROP COLUMN tenant;
+DROP INDEX IF EXISTS idx_contact_tenant;
+ALTER TABLE contact DROP COLUMN tenant;
+DROP TABLE IF EXISTS address_book;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS address_book (
+    name TEXT NOT NULL PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE contact ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_contact_tenant ON contact (tenant, deleted_at);
+ALTER TABLE contact_audit ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_contact_audit_contact_id;
+CREATE INDEX IF NOT EXISTS idx_contact_audit_contact_id ON contact_audit (tenant, contact_id, id);
+-- the unique name of a group is part of its column, so the table is
+-- rebuilt, moving the memberships aside first for the foreign keys not
+-- to cascade them away
+CREATE TABLE contact_group_member_old AS SELECT group_id, contact_id FROM contact_group_member;
+DROP TABLE contact_group_member;
+ALTER TABLE contact_group RENAME TO contact_group_old;
+CREATE TABLE contact_group (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant TEXT NOT NULL DEFAULT 'default',
+    name TEXT NOT NULL COLLATE NOCASE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant, name)
+);
+INSERT INTO contact_group (id, name, description, created_at, updated_at)
+    SELECT id, name, description, created_at, updated_at FROM contact_group_old;
+DROP TABLE contact_group_old;
+CREATE TABLE contact_group_member (
+    group_id INTEGER NOT NULL REFERENCES contact_group (id) ON DELETE CASCADE,
+    contact_id INTEGER NOT NULL REFERENCES contact (id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, contact_id)
+);
+INSERT INTO contact_group_member (group_id, contact_id) SELECT group_id, contact_id FROM contact_group_member_old;
+DROP TABLE contact_group_member_old;
+CREATE INDEX IF NOT EXISTS idx_contact_group_member_contact_id ON contact_group_member (contact_id);
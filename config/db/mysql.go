@@ -6,7 +6,7 @@ import (
 	"database/sql"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 func NewMysqlDatabase(cfg *config.Config) (*sql.DB, error) {
@@ -14,7 +14,15 @@ func NewMysqlDatabase(cfg *config.Config) (*sql.DB, error) {
 		return nil, apperrors.NewAppError(apperrors.ErrDbUrlNotExist)
 	}
 
-	db, err := sql.Open(cfg.Database.Driver, cfg.Database.URL)
+	// created_at and updated_at are scanned into time.Time,
+	// which the driver only does with parseTime on
+	mysqlConf, err := mysql.ParseDSN(cfg.Database.URL)
+	if err != nil {
+		return nil, err
+	}
+	mysqlConf.ParseTime = true
+
+	db, err := sql.Open(cfg.Database.Driver, mysqlConf.FormatDSN())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"contact-go/config"
+	"contact-go/helper/apperrors"
+	"context"
+	"database/sql"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// NewSqliteDatabase opens the database file and migrates it to the latest schema.
+func NewSqliteDatabase(cfg *config.Config) (*sql.DB, error) {
+	db, err := OpenSqliteDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// an embedded database has no one to run migrations by hand,
+	// so its schema is always brought up to date on open
+	migrator, err := NewMigrator(db, DialectSqlite)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if _, err = migrator.Up(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// OpenSqliteDatabase opens the database file without touching its schema.
+func OpenSqliteDatabase(cfg *config.Config) (*sql.DB, error) {
+	if cfg.Database.Path == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrDbPathNotExist)
+	}
+
+	// WAL lets readers carry on while a write is in progress,
+	// busy_timeout makes concurrent writers wait instead of failing,
+	// _txlock=immediate has a transaction that reads before it writes
+	// wait for the write lock up front rather than fail to take it later,
+	// _time_format writes times the way CURRENT_TIMESTAMP does
+	dsn := "file:" + cfg.Database.Path +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate&_time_format=sqlite"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxIdleConns(10)
+	db.SetMaxOpenConns(10)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+	db.SetConnMaxLifetime(60 * time.Minute)
+
+	return db, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"contact-go/config"
+	"contact-go/helper/health"
+	"contact-go/helper/version"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// runDoctor handles `contact-go doctor`, telling which build is running
+// and checking the configured storage the way /readyz does.
+func runDoctor(cfg *config.Config) error {
+	info := version.Get(storageOf(cfg))
+	fmt.Printf("version\t%s\n", info.Version)
+	fmt.Printf("commit\t%s\n", info.Commit)
+	fmt.Printf("go\t%s\n", info.GoVersion)
+	fmt.Printf("storage\t%s\n", info.Storage)
+	if info.Driver != "" {
+		fmt.Printf("driver\t%s\n", info.Driver)
+	}
+
+	//* opened without migrating it, a doctor only looks
+	var sqlDB *sql.DB
+	if cfg.Storage == "sql" {
+		var err error
+		sqlDB, _, err = openDatabase(cfg)
+		if err != nil {
+			fmt.Printf("database\t%s\t%v\n", health.StatusFail, err)
+			return errors.New("storage not ready")
+		}
+		defer sqlDB.Close()
+	}
+
+	results, passed := health.Run(context.Background(), storageChecks(cfg, sqlDB), cfg.Database.Timeout)
+	missing := false
+	for _, result := range results {
+		missing = missing || result.Status == health.StatusMissing
+		if result.Error != "" {
+			fmt.Printf("%s\t%s\t%s\n", result.Name, result.Status, result.Error)
+			continue
+		}
+		fmt.Printf("%s\t%s\n", result.Name, result.Status)
+	}
+
+	if !passed {
+		return errors.New("storage not ready")
+	}
+	if missing {
+		fmt.Println("storage ready, the missing files are created on first use")
+		return nil
+	}
+	fmt.Println("storage ready")
+	return nil
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/response"
+	"contact-go/model"
+	"contact-go/usecase"
+	"encoding/json"
+	"net/http"
+)
+
+type addressBookHTTPHandler struct {
+	AddressBookUC usecase.AddressBookUsecase
+}
+
+func NewAddressBookHTTPHandler(addressBookUC usecase.AddressBookUsecase) AddressBookHTTPHandler {
+	return &addressBookHTTPHandler{
+		AddressBookUC: addressBookUC,
+	}
+}
+
+func (handler *addressBookHTTPHandler) List(w http.ResponseWriter, r *http.Request) {
+	books, err := handler.AddressBookUC.List(r.Context())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", books); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *addressBookHTTPHandler) Add(w http.ResponseWriter, r *http.Request) {
+	var addressBookRequest model.AddressBookRequest
+	err := json.NewDecoder(r.Body).Decode(&addressBookRequest)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	book, err := handler.AddressBookUC.Add(r.Context(), &addressBookRequest)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusCreated, "Created", book); err != nil {
+		panic(err)
+	}
+}
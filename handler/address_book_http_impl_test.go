@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/mocks"
+	"contact-go/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_addressBookHTTPHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		handler    func(AddressBookHTTPHandler) http.HandlerFunc
+		beforeTest func(*mocks.AddressBookUsecase)
+		wantStatus int
+	}{
+		{
+			name:    "list",
+			method:  "GET",
+			handler: func(h AddressBookHTTPHandler) http.HandlerFunc { return h.List },
+			beforeTest: func(uc *mocks.AddressBookUsecase) {
+				uc.On("List", mock.Anything).Return([]model.AddressBook{{Name: "default"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "add",
+			method:  "POST",
+			body:    `{"name": "sales"}`,
+			handler: func(h AddressBookHTTPHandler) http.HandlerFunc { return h.Add },
+			beforeTest: func(uc *mocks.AddressBookUsecase) {
+				uc.On("Add", mock.Anything, &model.AddressBookRequest{Name: "sales"}).Return(&model.AddressBook{Name: "sales"}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "add duplicate",
+			method:  "POST",
+			body:    `{"name": "sales"}`,
+			handler: func(h AddressBookHTTPHandler) http.HandlerFunc { return h.Add },
+			beforeTest: func(uc *mocks.AddressBookUsecase) {
+				uc.On("Add", mock.Anything, &model.AddressBookRequest{Name: "sales"}).Return(nil, apperrors.NewAppError(apperrors.ErrAddressBookDuplicate))
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:    "add invalid name",
+			method:  "POST",
+			body:    `{"name": "../sales"}`,
+			handler: func(h AddressBookHTTPHandler) http.HandlerFunc { return h.Add },
+			beforeTest: func(uc *mocks.AddressBookUsecase) {
+				uc.On("Add", mock.Anything, &model.AddressBookRequest{Name: "../sales"}).Return(nil, apperrors.NewAppError(apperrors.ErrAddressBookNameNotValid))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "add malformed body",
+			method:     "POST",
+			body:       `{"name": `,
+			handler:    func(h AddressBookHTTPHandler) http.HandlerFunc { return h.Add },
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAddressBookUC := mocks.NewAddressBookUsecase(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mockAddressBookUC)
+			}
+
+			h := NewAddressBookHTTPHandler(mockAddressBookUC)
+
+			m := useMiddleware(tt.handler(h))
+
+			req := httptest.NewRequest(tt.method, "http://localhost:8080/address-books", strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Result().StatusCode
+			assert.Equal(t, tt.wantStatus, got, "AddressBookHTTPHandler handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package handler
+
+import "net/http"
+
+type AddressBookHTTPHandler interface {
+	List(w http.ResponseWriter, r *http.Request)
+	Add(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/contactcsv"
+	"contact-go/model"
+	"contact-go/usecase"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// contactPager pages through the contacts matching a query,
+// model.MaxContactLimit at a time, so an export never holds
+// more than one page in memory. A query with its own limit
+// is read as a single page.
+type contactPager struct {
+	contactUC usecase.ContactUsecase
+	query     model.ContactQuery
+	single    bool
+	done      bool
+}
+
+func newContactPager(contactUC usecase.ContactUsecase, query *model.ContactQuery) *contactPager {
+	pager := new(contactPager)
+	pager.contactUC = contactUC
+	pager.query = *query
+	if pager.query.Limit > 0 {
+		pager.single = true
+	} else {
+		pager.query.Limit = model.MaxContactLimit
+	}
+	return pager
+}
+
+// Next returns the next page, or nil once every page has been read.
+func (p *contactPager) Next(ctx context.Context) ([]model.Contact, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	contacts, total, err := p.contactUC.List(ctx, &p.query)
+	if err != nil {
+		return nil, err
+	}
+
+	p.query.Offset += len(contacts)
+	p.done = p.single || len(contacts) < p.query.Limit || int64(p.query.Offset) >= total
+	return contacts, nil
+}
+
+// writeCSV writes page and every page after it as CSV rows.
+func writeCSV(ctx context.Context, w io.Writer, pager *contactPager, page []model.Contact) error {
+	writer := contactcsv.NewWriter(w)
+	if err := writer.WriteHeader(); err != nil {
+		return err
+	}
+
+	for page != nil {
+		for i := range page {
+			if err := writer.Write(&page[i]); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if page, err = pager.Next(ctx); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// parseCSVMapping parses "Header:field" pairs, splitting on the last
+// colon so that headers may contain one.
+func parseCSVMapping(pairs []string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range pairs {
+		i := strings.LastIndex(pair, ":")
+		if i < 0 {
+			return nil, apperrors.NewAppError(apperrors.ErrCSVMappingNotValid)
+		}
+
+		header := strings.TrimSpace(pair[:i])
+		field := strings.ToLower(strings.TrimSpace(pair[i+1:]))
+		if header == "" || field == "" {
+			return nil, apperrors.NewAppError(apperrors.ErrCSVMappingNotValid)
+		}
+		mapping[header] = field
+	}
+	return mapping, nil
+}
+
+// importCSV creates a contact for every row read from r, or updates the
+// contact already holding the row's phone with the columns the file has.
+// A dry run only validates each row. Rows that are malformed or fail
+// validation are rejected without stopping the import; a file that cannot
+// be read, has more than maxRows rows, zero leaving them unbounded, or a
+// failing store stops it, returning the results so far along with the
+// error.
+func importCSV(ctx context.Context, contactUC usecase.ContactUsecase, r io.Reader, mapping map[string]string, dryRun bool, maxRows int) ([]model.ContactCSVResult, *model.ContactCSVSummary, error) {
+	results := []model.ContactCSVResult{}
+	summary := &model.ContactCSVSummary{DryRun: dryRun}
+
+	reader, err := contactcsv.NewReader(r, mapping)
+	if err == io.EOF {
+		return results, summary, nil
+	}
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, apperrors.NewAppError(apperrors.ErrCSVFileNotValid)
+	}
+
+	// phones created earlier in the file, which a dry run cannot find
+	created := make(map[string]bool)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return results, summary, err
+		}
+
+		line, row, err := reader.Read()
+		if err == io.EOF {
+			return results, summary, nil
+		}
+		if maxRows > 0 && len(results) >= maxRows {
+			return results, summary, apperrors.NewAppError(apperrors.ErrImportTooLarge)
+		}
+
+		var result model.ContactCSVResult
+		var parseErr *csv.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			result = model.ContactCSVResult{Action: model.ImportReject, Error: parseErr.Err.Error()}
+		case err != nil:
+			return results, summary, apperrors.NewAppError(apperrors.ErrCSVFileNotValid)
+		default:
+			result, err = importCSVRow(ctx, contactUC, reader, row, dryRun, created)
+			if err != nil {
+				return results, summary, err
+			}
+		}
+		result.Line = line
+
+		switch result.Action {
+		case model.ImportCreate:
+			summary.Created++
+		case model.ImportUpdate:
+			summary.Updated++
+		default:
+			summary.Rejected++
+		}
+		results = append(results, result)
+	}
+}
+
+func importCSVRow(ctx context.Context, contactUC usecase.ContactUsecase, reader *contactcsv.Reader, row *model.ContactRequest, dryRun bool, created map[string]bool) (model.ContactCSVResult, error) {
+	result := model.ContactCSVResult{
+		Action: model.ImportCreate,
+		Name:   row.Name,
+		NoTelp: row.NoTelp,
+	}
+
+	reject := func(message string) (model.ContactCSVResult, error) {
+		result.Action = model.ImportReject
+		result.ID = 0
+		result.Error = message
+		return result, nil
+	}
+
+	if row.Name == "" {
+		return reject(apperrors.ErrContactNameNotValid)
+	}
+	if row.NoTelp == "" {
+		return reject(apperrors.ErrContactNoTelpNotValid)
+	}
+
+	existing, err := contactUC.FindByPhone(ctx, row.NoTelp)
+	if err != nil {
+		switch code, message := apperrors.HandleAppError(err); code {
+		case http.StatusNotFound:
+			existing = nil
+		case http.StatusBadRequest:
+			return reject(message)
+		default:
+			return result, err
+		}
+	}
+
+	req := row
+	if existing != nil {
+		result.Action = model.ImportUpdate
+		result.ID = existing.ID
+		req = newContactRequest(existing)
+		reader.Apply(req, row)
+	} else if dryRun && created[row.NoTelp] {
+		result.Action = model.ImportUpdate
+	}
+
+	var contact *model.Contact
+	switch {
+	case dryRun:
+		err = contactUC.Validate(ctx, req)
+	case existing != nil:
+		contact, err = contactUC.Update(ctx, existing.ID, req)
+	default:
+		contact, err = contactUC.Add(ctx, req)
+	}
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		if code != http.StatusBadRequest && code != http.StatusConflict {
+			return result, err
+		}
+		return reject(message)
+	}
+
+	if contact != nil {
+		result.ID = contact.ID
+	}
+	if result.Action == model.ImportCreate {
+		created[row.NoTelp] = true
+	}
+	return result, nil
+}
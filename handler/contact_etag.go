@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// contactETag is the entity tag of contact: its version, quoted.
+func contactETag(contact *model.Contact) string {
+	return `"` + strconv.FormatInt(contact.Version, 10) + `"`
+}
+
+// etagVersions reads the versions listed in an If-Match or If-None-Match
+// header, and whether it holds "*". Weak tags only count when weak is
+// set, as If-None-Match compares weakly and If-Match strongly. Tags this
+// API never hands out are skipped.
+func etagVersions(header string, weak bool) (versions []int64, any bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			any = true
+			continue
+		}
+
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil || version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, any
+}
+
+// notModified reports whether the If-None-Match header of r already
+// names the version of contact.
+func notModified(r *http.Request, contact *model.Contact) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	versions, any := etagVersions(header, true)
+	if any {
+		return true
+	}
+	for _, version := range versions {
+		if version == contact.Version {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion turns the If-Match header of r into the version an
+// update or delete of the contact id has to find, 0 for any version.
+// Without the header fallback is used. When the header lists several
+// tags the contact is read to pick the one still current, if any.
+func (handler *contactHTTPHandler) ifMatchVersion(r *http.Request, id int64, fallback int64) (int64, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return fallback, nil
+	}
+
+	versions, any := etagVersions(header, false)
+	switch {
+	case any:
+		return 0, nil
+	case len(versions) == 0:
+		return 0, apperrors.NewAppError(apperrors.ErrContactVersion)
+	case len(versions) == 1:
+		return versions[0], nil
+	}
+
+	contact, err := handler.ContactUC.Detail(r.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+	for _, version := range versions {
+		if version == contact.Version {
+			return version, nil
+		}
+	}
+	return 0, apperrors.NewAppError(apperrors.ErrContactVersion)
+}
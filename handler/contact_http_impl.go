@@ -2,32 +2,125 @@ package handler
 
 import (
 	"contact-go/helper/apperrors"
+	"contact-go/helper/contactcsv"
 	"contact-go/helper/response"
+	"contact-go/helper/vcard"
 	"contact-go/model"
 	"contact-go/usecase"
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 )
 
 type contactHTTPHandler struct {
-	ContactUC usecase.ContactUsecase
+	ContactUC     usecase.ContactUsecase
+	MaxImportSize int64
+	MaxImportRows int
 }
 
-func NewContactHTTPHandler(contactUC usecase.ContactUsecase) ContactHTTPHandler {
+// NewContactHTTPHandler takes imports of up to maxImportSize bytes and
+// maxImportRows cards or csv rows, zero leaving either unbounded.
+func NewContactHTTPHandler(contactUC usecase.ContactUsecase, maxImportSize int64, maxImportRows int) ContactHTTPHandler {
 	return &contactHTTPHandler{
-		ContactUC: contactUC,
+		ContactUC:     contactUC,
+		MaxImportSize: maxImportSize,
+		MaxImportRows: maxImportRows,
 	}
 }
 
+func parseContactQuery(values url.Values) (*model.ContactQuery, error) {
+	query := &model.ContactQuery{
+		Limit:  model.DefaultContactLimit,
+		SortBy: values.Get("sort"),
+		Order:  values.Get("order"),
+		Name:   values.Get("name"),
+		NoTelp: values.Get("no_telp"),
+		Tag:    values.Get("tag"),
+	}
+
+	if groupStr := values.Get("group"); groupStr != "" {
+		group, err := strconv.ParseInt(groupStr, 10, 64)
+		if err != nil || group <= 0 {
+			return nil, apperrors.NewAppError(apperrors.ErrGroupIdNotValid)
+		}
+		query.Group = group
+	}
+
+	limit, offset, err := parsePage(values)
+	if err != nil {
+		return nil, err
+	}
+	query.Limit, query.Offset = limit, offset
+
+	return query, nil
+}
+
+// parsePage reads the limit and offset of a paginated request,
+// DefaultContactLimit and 0 when left out.
+func parsePage(values url.Values) (int, int, error) {
+	limit, offset := model.DefaultContactLimit, 0
+
+	if limitStr := values.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return 0, 0, apperrors.NewAppError(apperrors.ErrContactLimitNotValid)
+		}
+	}
+
+	if offsetStr := values.Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			return 0, 0, apperrors.NewAppError(apperrors.ErrContactOffsetNotValid)
+		}
+	}
+
+	return limit, offset, nil
+}
+
+func newPageMeta(u *url.URL, limit, offset int, total int64) *response.PageMeta {
+	meta := &response.PageMeta{
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	nextOffset := offset + limit
+	if int64(nextOffset) < total {
+		values := u.Query()
+		values.Set("limit", strconv.Itoa(limit))
+		values.Set("offset", strconv.Itoa(nextOffset))
+
+		meta.NextOffset = &nextOffset
+		meta.Next = u.Path + "?" + values.Encode()
+	}
+
+	return meta
+}
+
 func (handler *contactHTTPHandler) List(w http.ResponseWriter, r *http.Request) {
-	contacts, err := handler.ContactUC.List()
+	query, err := parseContactQuery(r.URL.Query())
 	if err != nil {
-		panic(err)
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
 	}
 
-	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contacts); err != nil {
+	contacts, total, err := handler.ContactUC.List(r.Context(), query)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	meta := newPageMeta(r.URL, query.Limit, query.Offset, total)
+	if err := response.NewJsonResponseWithMeta(w, http.StatusOK, "OK", contacts, meta); err != nil {
 		panic(err)
 	}
 }
@@ -36,7 +129,8 @@ func (handler *contactHTTPHandler) Add(w http.ResponseWriter, r *http.Request) {
 	var contactRequest model.ContactRequest
 	err := json.NewDecoder(r.Body).Decode(&contactRequest)
 	if err != nil {
-		panic(err)
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
 
 	if contactRequest.Name == "" {
@@ -49,7 +143,16 @@ func (handler *contactHTTPHandler) Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contact, err := handler.ContactUC.Add(&contactRequest)
+	if force, _ := strconv.ParseBool(r.URL.Query().Get("force")); force {
+		contactRequest.Force = true
+	}
+
+	contact, err := handler.ContactUC.Add(r.Context(), &contactRequest)
+	var duplicateErr *usecase.DuplicateError
+	if errors.As(err, &duplicateErr) {
+		_ = response.NewJsonResponse(w, http.StatusConflict, apperrors.ErrContactDuplicate, duplicateErr.Candidates)
+		return
+	}
 	if err != nil {
 		code, message := apperrors.HandleAppError(err)
 		_ = response.NewJsonResponse(w, code, message, nil)
@@ -75,18 +178,25 @@ func (handler *contactHTTPHandler) Detail(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	contact, err := handler.ContactUC.Detail(int64(id))
+	contact, err := handler.ContactUC.Detail(r.Context(), int64(id))
 	if err != nil {
 		code, message := apperrors.HandleAppError(err)
 		_ = response.NewJsonResponse(w, code, message, nil)
 		return
 	}
 
+	w.Header().Set("ETag", contactETag(contact))
+	if notModified(r, contact) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contact); err != nil {
 		panic(err)
 	}
 }
 
+// Update replaces the contact whole, clearing the fields left out.
 func (handler *contactHTTPHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/contacts/")
 
@@ -118,13 +228,63 @@ func (handler *contactHTTPHandler) Update(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	contact, err := handler.ContactUC.Update(int64(id), &contactRequest)
+	contactRequest.Version, err = handler.ifMatchVersion(r, int64(id), contactRequest.Version)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	contact, err := handler.ContactUC.Update(r.Context(), int64(id), &contactRequest)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	w.Header().Set("ETag", contactETag(contact))
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contact); err != nil {
+		panic(err)
+	}
+}
+
+// Patch applies a JSON Merge Patch, changing only the fields it names.
+func (handler *contactHTTPHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/contacts/")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	if id <= 0 {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	contactRequest, fields, err := mergePatch(r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	contactRequest.Version, err = handler.ifMatchVersion(r, int64(id), contactRequest.Version)
 	if err != nil {
 		code, message := apperrors.HandleAppError(err)
 		_ = response.NewJsonResponse(w, code, message, nil)
 		return
 	}
 
+	contact, err := handler.ContactUC.Patch(r.Context(), int64(id), contactRequest, fields)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	w.Header().Set("ETag", contactETag(contact))
 	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contact); err != nil {
 		panic(err)
 	}
@@ -144,7 +304,14 @@ func (handler *contactHTTPHandler) Delete(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = handler.ContactUC.Delete(int64(id))
+	version, err := handler.ifMatchVersion(r, int64(id), 0)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	err = handler.ContactUC.Delete(r.Context(), int64(id), version)
 	if err != nil {
 		code, message := apperrors.HandleAppError(err)
 		_ = response.NewJsonResponse(w, code, message, nil)
@@ -155,3 +322,427 @@ func (handler *contactHTTPHandler) Delete(w http.ResponseWriter, r *http.Request
 		panic(err)
 	}
 }
+
+func (handler *contactHTTPHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	query, err := parseContactQuery(r.URL.Query())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+	query.Deleted = true
+
+	contacts, total, err := handler.ContactUC.List(r.Context(), query)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	meta := newPageMeta(r.URL, query.Limit, query.Offset, total)
+	if err := response.NewJsonResponseWithMeta(w, http.StatusOK, "OK", contacts, meta); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *contactHTTPHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/contacts/"), "/restore")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	if id <= 0 {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	contact, err := handler.ContactUC.Restore(r.Context(), int64(id))
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contact); err != nil {
+		panic(err)
+	}
+}
+
+// History pages through the audit log of a contact, newest entry first.
+func (handler *contactHTTPHandler) History(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/contacts/"), "/history")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	limit, offset, err := parsePage(r.URL.Query())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	entries, total, err := handler.ContactUC.History(r.Context(), int64(id), &model.AuditQuery{Limit: limit, Offset: offset})
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	meta := newPageMeta(r.URL, limit, offset, total)
+	if err := response.NewJsonResponseWithMeta(w, http.StatusOK, "OK", entries, meta); err != nil {
+		panic(err)
+	}
+}
+
+// Revert brings a contact back to the revision given in the query,
+// the ID of an entry of its history.
+func (handler *contactHTTPHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/contacts/"), "/revert")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	revision, err := strconv.ParseInt(r.URL.Query().Get("revision"), 10, 64)
+	if err != nil || revision <= 0 {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrRevisionNotValid, nil)
+		return
+	}
+
+	contact, err := handler.ContactUC.Revert(r.Context(), int64(id), revision)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if contact != nil {
+		w.Header().Set("ETag", contactETag(contact))
+	}
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contact); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *contactHTTPHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	purged, err := handler.ContactUC.Purge(r.Context())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", &model.ContactPurgeResult{Purged: purged}); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *contactHTTPHandler) Search(w http.ResponseWriter, r *http.Request) {
+	contacts, err := handler.ContactUC.Search(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contacts); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *contactHTTPHandler) Duplicates(w http.ResponseWriter, r *http.Request) {
+	groups, err := handler.ContactUC.Duplicates(r.Context())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", groups); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *contactHTTPHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	var mergeRequest model.ContactMergeRequest
+	err := json.NewDecoder(r.Body).Decode(&mergeRequest)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	contact, err := handler.ContactUC.Merge(r.Context(), mergeRequest.IDs)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contact); err != nil {
+		panic(err)
+	}
+}
+
+// Batch runs the create, update and delete operations of the request,
+// answering with how each fared. A failed atomic batch answers with the
+// status of the operation that failed it.
+func (handler *contactHTTPHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var batchRequest model.ContactBatchRequest
+	err := json.NewDecoder(r.Body).Decode(&batchRequest)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	results, err := handler.ContactUC.Batch(r.Context(), &batchRequest)
+	if results == nil && err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	summary := &model.ContactBatchSummary{Atomic: batchRequest.Atomic}
+	for _, result := range results {
+		if result.Error != "" {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+	}
+
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponseWithMeta(w, code, message, results, summary)
+		return
+	}
+
+	if err := response.NewJsonResponseWithMeta(w, http.StatusOK, "OK", results, summary); err != nil {
+		panic(err)
+	}
+}
+
+func parseVCardVersion(values url.Values) (string, error) {
+	version := values.Get("version")
+	if version == "" {
+		return vcard.Version3, nil
+	}
+
+	if !vcard.ValidVersion(version) {
+		return "", apperrors.NewAppError(apperrors.ErrVCardVersionNotValid)
+	}
+	return version, nil
+}
+
+func writeVCardHeader(w http.ResponseWriter, filename string) {
+	w.Header().Set("Content-Type", vcard.MediaType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// ExportVCard writes every contact matching the List filters as one .vcf
+// file, a page at a time, unpaginated unless a limit is given.
+func (handler *contactHTTPHandler) ExportVCard(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	version, err := parseVCardVersion(values)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	query, err := parseContactQuery(values)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+	if values.Get("limit") == "" {
+		query.Limit = 0
+	}
+
+	pager := newContactPager(handler.ContactUC, query)
+	page, err := pager.Next(r.Context())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	writeVCardHeader(w, "contacts.vcf")
+	if err := writeVCardPages(r.Context(), w, pager, page, version); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *contactHTTPHandler) DetailVCard(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/contacts/"), ".vcf")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	if id <= 0 {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrContactIdNotValid, nil)
+		return
+	}
+
+	version, err := parseVCardVersion(r.URL.Query())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	contact, err := handler.ContactUC.Detail(r.Context(), int64(id))
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	writeVCardHeader(w, "contact-"+idStr+".vcf")
+	if err := writeVCards(w, []model.Contact{*contact}, version); err != nil {
+		panic(err)
+	}
+}
+
+// limitImport bounds the size of the file uploaded with r.
+func (handler *contactHTTPHandler) limitImport(w http.ResponseWriter, r *http.Request) {
+	if handler.MaxImportSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, handler.MaxImportSize)
+	}
+}
+
+// ImportVCard adds the cards of a .vcf file sent either as the request
+// body or as the "file" field of a multipart form, reporting per card.
+func (handler *contactHTTPHandler) ImportVCard(w http.ResponseWriter, r *http.Request) {
+	handler.limitImport(w, r)
+
+	body, err := importBody(r)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrVCardFileNotValid, nil)
+		return
+	}
+
+	results, err := importVCards(r.Context(), handler.ContactUC, body, handler.MaxImportRows)
+	if err != nil {
+		code, message := http.StatusBadRequest, apperrors.ErrVCardFileNotValid
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			code, message = apperrors.HandleAppError(err)
+		}
+		_ = response.NewJsonResponse(w, code, message, results)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", results); err != nil {
+		panic(err)
+	}
+}
+
+// importBody returns the uploaded file, streamed from the multipart
+// form when there is one.
+func importBody(r *http.Request) (io.Reader, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
+
+// ExportCSV writes every contact matching the List filters as CSV,
+// a page at a time, unpaginated unless a limit is given.
+func (handler *contactHTTPHandler) ExportCSV(w http.ResponseWriter, r *http.Request) {
+	query, err := parseContactQuery(r.URL.Query())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+	if r.URL.Query().Get("limit") == "" {
+		query.Limit = 0
+	}
+
+	pager := newContactPager(handler.ContactUC, query)
+	page, err := pager.Next(r.Context())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", contactcsv.MediaType)
+	w.Header().Set("Content-Disposition", `attachment; filename="contacts.csv"`)
+	w.WriteHeader(http.StatusOK)
+	if err := writeCSV(r.Context(), w, pager, page); err != nil {
+		panic(err)
+	}
+}
+
+// ImportCSV imports a CSV file sent the way ImportVCard takes a .vcf.
+// Each map query parameter maps a header to a field as "Header:field",
+// and dry_run=true reports what the import would do without doing it.
+func (handler *contactHTTPHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	mapping, err := parseCSVMapping(values["map"])
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := values.Get("dry_run"); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrCSVDryRunNotValid, nil)
+			return
+		}
+	}
+
+	handler.limitImport(w, r)
+
+	body, err := importBody(r)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, apperrors.ErrCSVFileNotValid, nil)
+		return
+	}
+
+	results, summary, err := importCSV(r.Context(), handler.ContactUC, body, mapping, dryRun, handler.MaxImportRows)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponseWithMeta(w, code, message, results, summary)
+		return
+	}
+
+	if err := response.NewJsonResponseWithMeta(w, http.StatusOK, "OK", results, summary); err != nil {
+		panic(err)
+	}
+}
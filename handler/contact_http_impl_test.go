@@ -2,17 +2,23 @@ package handler
 
 import (
 	"bytes"
+	"contact-go/helper/apperrors"
 	"contact-go/helper/logger"
 	"contact-go/middleware"
 	"contact-go/mocks"
 	"contact-go/model"
+	"contact-go/usecase"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,7 +30,11 @@ func useMiddleware(handler http.HandlerFunc) *middleware.Middleware {
 	muxMiddleware := new(middleware.Middleware)
 	muxMiddleware.Handler = handler
 
-	muxMiddleware.Use(middleware.Cors)
+	muxMiddleware.Use(
+		func(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
+			return middleware.Cors([]string{"https://app.example.com"}, w, r, next)
+		},
+	)
 	muxMiddleware.Use(middleware.ContentTypeJson)
 	muxMiddleware.Use(
 		func(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
@@ -40,28 +50,84 @@ func useMiddleware(handler http.HandlerFunc) *middleware.Middleware {
 	return muxMiddleware
 }
 
+func Test_cors(t *testing.T) {
+	h := useMiddleware(func(w http.ResponseWriter, r *http.Request) {})
+
+	r := httptest.NewRequest("OPTIONS", "/contacts/1", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "DELETE")
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+
+	r = httptest.NewRequest("GET", "/contacts", nil)
+	r.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+
 func Test_contactHTTPHandler_List(t *testing.T) {
 	tests := []struct {
 		name       string
+		url        string
+		query      *model.ContactQuery
 		UCResult   []model.Contact
+		UCTotal    int64
 		UCErr      error
 		wantStatus int
+		wantNext   string
 		wantErr    bool
 	}{
 		// TODO: Add test cases.
 		{
-			name: "success",
+			name:  "success",
+			url:   "http://localhost:8080/contacts",
+			query: &model.ContactQuery{Limit: model.DefaultContactLimit},
 			UCResult: []model.Contact{
 				{ID: 1, Name: "jaguar", NoTelp: "999-888-7777"},
 				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
 				{ID: 3, Name: "jangkrik", NoTelp: "000-000-0000"},
 			},
+			UCTotal:    3,
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:  "success with next page",
+			url:   "http://localhost:8080/contacts?limit=2&sort=name&order=desc&name=ja",
+			query: &model.ContactQuery{Limit: 2, SortBy: "name", Order: "desc", Name: "ja"},
+			UCResult: []model.Contact{
+				{ID: 3, Name: "jangkrik", NoTelp: "000-000-0000"},
+				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
+			},
+			UCTotal:    3,
 			UCErr:      nil,
 			wantStatus: http.StatusOK,
+			wantNext:   "/contacts?limit=2&name=ja&offset=2&order=desc&sort=name",
+			wantErr:    false,
+		},
+		{
+			name:       "invalid limit",
+			url:        "http://localhost:8080/contacts?limit=abc",
+			UCErr:      nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    false,
+		},
+		{
+			name:       "invalid offset",
+			url:        "http://localhost:8080/contacts?offset=abc",
+			UCErr:      nil,
+			wantStatus: http.StatusBadRequest,
 			wantErr:    false,
 		},
 		{
 			name:       "failed",
+			url:        "http://localhost:8080/contacts",
+			query:      &model.ContactQuery{Limit: model.DefaultContactLimit},
 			UCResult:   []model.Contact{},
 			UCErr:      assert.AnError,
 			wantStatus: http.StatusInternalServerError,
@@ -72,20 +138,19 @@ func Test_contactHTTPHandler_List(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
-			if !tt.wantErr && tt.wantStatus == 200 || tt.wantStatus == 500 {
-				mockContactUC.On("List").Return(tt.UCResult, tt.UCErr)
+			if tt.query != nil {
+				mockContactUC.On("List", mock.Anything, tt.query).Return(tt.UCResult, tt.UCTotal, tt.UCErr)
 			}
 
-			h := NewContactHTTPHandler(mockContactUC)
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
 
 			// Define your HTTP handler
 			handler := http.HandlerFunc(h.List)
 			method := "GET"
-			url := "http://localhost:8080/contacts"
 
 			m := useMiddleware(handler)
 
-			req := httptest.NewRequest(method, url, nil)
+			req := httptest.NewRequest(method, tt.url, nil)
 			recorder := httptest.NewRecorder()
 
 			m.ServeHTTP(recorder, req)
@@ -96,6 +161,19 @@ func Test_contactHTTPHandler_List(t *testing.T) {
 			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.List error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
 				assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.List handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
 			}
+
+			if tt.wantStatus == http.StatusOK {
+				var body struct {
+					Meta struct {
+						Total int64  `json:"total"`
+						Next  string `json:"next"`
+					} `json:"meta"`
+				}
+				err := json.NewDecoder(response.Body).Decode(&body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.UCTotal, body.Meta.Total)
+				assert.Equal(t, tt.wantNext, body.Meta.Next)
+			}
 		})
 	}
 }
@@ -169,6 +247,33 @@ func Test_contactHTTPHandler_Add(t *testing.T) {
 			wantStatus: http.StatusInternalServerError,
 			wantErr:    true,
 		},
+		{
+			name: "invalid email on usecase",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "1231451431",
+					Emails: []model.Email{{Address: "not-an-email"}},
+				},
+			},
+			UCResult:   nil,
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactEmailNotValid),
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "duplicate on usecase",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+				},
+			},
+			UCResult:   nil,
+			UCErr:      &usecase.DuplicateError{Candidates: []model.Contact{{ID: 3, Name: "Test", NoTelp: "+12222223232"}}},
+			wantStatus: http.StatusConflict,
+			wantErr:    true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -180,11 +285,11 @@ func Test_contactHTTPHandler_Add(t *testing.T) {
 
 			mockContactUC := mocks.NewContactUsecase(t)
 
-			if !tt.wantErr && tt.wantStatus == 201 || tt.wantStatus == 500 {
-				mockContactUC.On("Add", mock.Anything).Return(tt.UCResult, tt.UCErr)
+			if !tt.wantErr && tt.wantStatus == 201 || strings.Contains(tt.name, "usecase") {
+				mockContactUC.On("Add", mock.Anything, mock.Anything).Return(tt.UCResult, tt.UCErr)
 			}
 
-			h := NewContactHTTPHandler(mockContactUC)
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
 
 			// Define your HTTP handler
 			handler := http.HandlerFunc(h.Add)
@@ -200,6 +305,7 @@ func Test_contactHTTPHandler_Add(t *testing.T) {
 			mockContactRequest := new(model.ContactRequest)
 			mockContactRequest.Name = tt.args.req.Name
 			mockContactRequest.NoTelp = tt.args.req.NoTelp
+			mockContactRequest.Emails = tt.args.req.Emails
 
 			reqBody, _ := json.Marshal(mockContactRequest)
 			req := httptest.NewRequest(method, url, bytes.NewReader(reqBody))
@@ -219,6 +325,69 @@ func Test_contactHTTPHandler_Add(t *testing.T) {
 	}
 }
 
+func Test_contactHTTPHandler_Add_malformed(t *testing.T) {
+	h := NewContactHTTPHandler(mocks.NewContactUsecase(t), 0, 0)
+	m := useMiddleware(http.HandlerFunc(h.Add))
+
+	req := httptest.NewRequest("POST", "http://localhost:8080/contacts", strings.NewReader(`{"name":"test",`))
+	recorder := httptest.NewRecorder()
+
+	m.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func Test_contactHTTPHandler_Add_duplicate(t *testing.T) {
+	candidates := []model.Contact{{ID: 3, Name: "Test", NoTelp: "+12222223232"}}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{
+			name:       "conflict",
+			url:        "http://localhost:8080/contacts",
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "forced",
+			url:        "http://localhost:8080/contacts?force=true",
+			wantStatus: http.StatusCreated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			mockContactUC.On("Add", mock.Anything, mock.MatchedBy(func(req *model.ContactRequest) bool { return !req.Force })).
+				Return(nil, &usecase.DuplicateError{Candidates: candidates}).Maybe()
+			mockContactUC.On("Add", mock.Anything, mock.MatchedBy(func(req *model.ContactRequest) bool { return req.Force })).
+				Return(&model.Contact{ID: 4, Name: "test", NoTelp: "+12222223232"}, nil).Maybe()
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Add))
+
+			reqBody := `{"name":"test","no_telp":"222-222-3232"}`
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(reqBody))
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus == http.StatusConflict {
+				var body struct {
+					Message string          `json:"message"`
+					Data    []model.Contact `json:"data"`
+				}
+				if assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&body)) {
+					assert.Equal(t, apperrors.ErrContactDuplicate, body.Message)
+					assert.Equal(t, candidates, body.Data)
+				}
+			}
+		})
+	}
+}
+
 func Test_contactHTTPHandler_Detail(t *testing.T) {
 	type args struct {
 		idStr string
@@ -284,10 +453,10 @@ func Test_contactHTTPHandler_Detail(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
 			if !tt.wantErr && tt.wantStatus == 200 || tt.wantStatus == 500 {
-				mockContactUC.On("Detail", tt.args.id).Return(tt.UCResult, tt.UCErr)
+				mockContactUC.On("Detail", mock.Anything, tt.args.id).Return(tt.UCResult, tt.UCErr)
 			}
 
-			h := NewContactHTTPHandler(mockContactUC)
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
 
 			// Define your HTTP handler
 			handler := http.HandlerFunc(h.Detail)
@@ -430,14 +599,14 @@ func Test_contactHTTPHandler_Update(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
 			if !tt.wantErr && tt.wantStatus == 200 || tt.wantStatus == 500 {
-				mockContactUC.On("Update", mock.Anything, mock.Anything).Return(tt.UCResult, tt.UCErr)
+				mockContactUC.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(tt.UCResult, tt.UCErr)
 			}
 
-			h := NewContactHTTPHandler(mockContactUC)
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
 
 			// Define your HTTP handler
 			handler := http.HandlerFunc(h.Update)
-			method := "PATCH"
+			method := "PUT"
 			url := fmt.Sprintf("http://localhost:8080/contacts/%v", tt.args.idStr)
 
 			m := useMiddleware(handler)
@@ -460,6 +629,112 @@ func Test_contactHTTPHandler_Update(t *testing.T) {
 	}
 }
 
+func Test_contactHTTPHandler_Patch(t *testing.T) {
+	patched := &model.Contact{ID: 1, Name: "test", NoTelp: "+12222223232", Version: 4}
+
+	tests := []struct {
+		name        string
+		contentType string
+		ifMatch     string
+		body        string
+		wantReq     *model.ContactRequest
+		wantFields  []string
+		UCErr       error
+		wantStatus  int
+	}{
+		{
+			name:        "merge patch",
+			contentType: "application/merge-patch+json",
+			body:        `{"company":"Acme","notes":null}`,
+			wantReq:     &model.ContactRequest{Company: "Acme"},
+			wantFields:  []string{"company", "notes"},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "plain json with version",
+			body:       `{"name":"test","version":3}`,
+			wantReq:    &model.ContactRequest{Name: "test", Version: 3},
+			wantFields: []string{"name"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "if match over version",
+			ifMatch:    `"3"`,
+			body:       `{"name":"test","version":2}`,
+			wantReq:    &model.ContactRequest{Name: "test", Version: 3},
+			wantFields: []string{"name"},
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactVersion),
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "invalid on usecase",
+			body:       `{"name":null}`,
+			wantReq:    &model.ContactRequest{},
+			wantFields: []string{"name"},
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactNameNotValid),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "read-only member",
+			body:       `{"name":"test","id":2}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "empty patch",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not an object",
+			body:       `["name"]`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong type",
+			body:       `{"name":5}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "json patch",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"replace","path":"/name","value":"test"}]`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if tt.wantReq != nil {
+				var result *model.Contact
+				if tt.UCErr == nil {
+					result = patched
+				}
+				mockContactUC.On("Patch", mock.Anything, int64(1), tt.wantReq, tt.wantFields).Return(result, tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Patch))
+
+			req := httptest.NewRequest("PATCH", "http://localhost:8080/contacts/1", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, `"4"`, recorder.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
 func Test_contactHTTPHandler_Delete(t *testing.T) {
 	type args struct {
 		idStr string
@@ -516,10 +791,10 @@ func Test_contactHTTPHandler_Delete(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
 			if !tt.wantErr && tt.wantStatus == 200 || tt.wantStatus == 500 {
-				mockContactUC.On("Delete", tt.args.id).Return(tt.UCErr)
+				mockContactUC.On("Delete", mock.Anything, tt.args.id, int64(0)).Return(tt.UCErr)
 			}
 
-			h := NewContactHTTPHandler(mockContactUC)
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
 
 			// Define your HTTP handler
 			handler := http.HandlerFunc(h.Delete)
@@ -544,3 +819,1240 @@ func Test_contactHTTPHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_contactHTTPHandler_Preconditions(t *testing.T) {
+	contact := &model.Contact{ID: 1, Name: "test", NoTelp: "+12222223232", Version: 3}
+	body := `{"name":"test","no_telp":"222-222-3232"}`
+
+	tests := []struct {
+		name       string
+		method     string
+		header     http.Header
+		beforeTest func(*mocks.ContactUsecase)
+		wantStatus int
+		wantETag   string
+	}{
+		{
+			name:   "detail sends etag",
+			method: "GET",
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				uc.On("Detail", mock.Anything, int64(1)).Return(contact, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:   "detail not modified",
+			method: "GET",
+			header: http.Header{"If-None-Match": {`"2", W/"3"`}},
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				uc.On("Detail", mock.Anything, int64(1)).Return(contact, nil)
+			},
+			wantStatus: http.StatusNotModified,
+			wantETag:   `"3"`,
+		},
+		{
+			name:   "detail modified since",
+			method: "GET",
+			header: http.Header{"If-None-Match": {`"2"`}},
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				uc.On("Detail", mock.Anything, int64(1)).Return(contact, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:   "update if match",
+			method: "PUT",
+			header: http.Header{"If-Match": {`"3"`}},
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				req := &model.ContactRequest{Name: "test", NoTelp: "222-222-3232", Version: 3}
+				updated := &model.Contact{ID: 1, Name: "test", NoTelp: "+12222223232", Version: 4}
+				uc.On("Update", mock.Anything, int64(1), req).Return(updated, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"4"`,
+		},
+		{
+			name:   "update stale",
+			method: "PUT",
+			header: http.Header{"If-Match": {`"2"`}},
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				req := &model.ContactRequest{Name: "test", NoTelp: "222-222-3232", Version: 2}
+				uc.On("Update", mock.Anything, int64(1), req).Return(nil, apperrors.NewAppError(apperrors.ErrContactVersion))
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "update weak if match",
+			method:     "PUT",
+			header:     http.Header{"If-Match": {`W/"3"`}},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "delete any version",
+			method: "DELETE",
+			header: http.Header{"If-Match": {`*`}},
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				uc.On("Delete", mock.Anything, int64(1), int64(0)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "delete one of several",
+			method: "DELETE",
+			header: http.Header{"If-Match": {`"2", "3"`}},
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				uc.On("Detail", mock.Anything, int64(1)).Return(contact, nil)
+				uc.On("Delete", mock.Anything, int64(1), int64(3)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "delete none of several",
+			method: "DELETE",
+			header: http.Header{"If-Match": {`"1", "2"`}},
+			beforeTest: func(uc *mocks.ContactUsecase) {
+				uc.On("Detail", mock.Anything, int64(1)).Return(contact, nil)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mockContactUC)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			handlers := map[string]http.HandlerFunc{
+				"GET":    h.Detail,
+				"PUT":    h.Update,
+				"DELETE": h.Delete,
+			}
+			m := useMiddleware(handlers[tt.method])
+
+			req := httptest.NewRequest(tt.method, "http://localhost:8080/contacts/1", strings.NewReader(body))
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Equal(t, tt.wantETag, recorder.Header().Get("ETag"))
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Trash(t *testing.T) {
+	deletedAt := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantQuery  *model.ContactQuery
+		UCResult   []model.Contact
+		UCErr      error
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			url:        "http://localhost:8080/contacts/trash?name=te",
+			wantQuery:  &model.ContactQuery{Limit: model.DefaultContactLimit, Name: "te", Deleted: true},
+			UCResult:   []model.Contact{{ID: 1, Name: "test", NoTelp: "+15555553232", DeletedAt: &deletedAt}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid limit",
+			url:        "http://localhost:8080/contacts/trash?limit=x",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid on usecase",
+			url:        "http://localhost:8080/contacts/trash",
+			wantQuery:  &model.ContactQuery{Limit: model.DefaultContactLimit, Deleted: true},
+			UCErr:      assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.wantQuery != nil {
+				mockContactUC.On("List", mock.Anything, tt.wantQuery).Return(tt.UCResult, int64(len(tt.UCResult)), tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Trash))
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Code
+			assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.Trash handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name       string
+		idStr      string
+		id         int64
+		UCResult   *model.Contact
+		UCErr      error
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			idStr:      "1",
+			id:         1,
+			UCResult:   &model.Contact{ID: 1, Name: "test", NoTelp: "+15555553232"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			idStr:      "x",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not in trash",
+			idStr:      "2",
+			id:         2,
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactNotFound),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.id != 0 {
+				mockContactUC.On("Restore", mock.Anything, tt.id).Return(tt.UCResult, tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Restore))
+
+			url := fmt.Sprintf("http://localhost:8080/contacts/%v/restore", tt.idStr)
+			req := httptest.NewRequest("POST", url, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Code
+			assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.Restore handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Purge(t *testing.T) {
+	tests := []struct {
+		name       string
+		UCResult   int64
+		UCErr      error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "success",
+			UCResult:   2,
+			wantStatus: http.StatusOK,
+			wantBody:   `"purged":2`,
+		},
+		{
+			name:       "invalid on usecase",
+			UCErr:      assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			mockContactUC.On("Purge", mock.Anything).Return(tt.UCResult, tt.UCErr)
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Purge))
+
+			req := httptest.NewRequest("POST", "http://localhost:8080/contacts/trash/purge", nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Code
+			assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.Purge handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+			assert.Contains(t, recorder.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Search(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		UCResult   []model.Contact
+		UCErr      error
+		wantStatus int
+		wantErr    bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:  "success",
+			query: "jane",
+			UCResult: []model.Contact{
+				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
+			},
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "empty keyword",
+			query:      "",
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactQueryNotValid),
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid on usecase",
+			query:      "jane",
+			UCErr:      assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			mockContactUC.On("Search", mock.Anything, tt.query).Return(tt.UCResult, tt.UCErr)
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			handler := http.HandlerFunc(h.Search)
+			method := "GET"
+			url := "http://localhost:8080/contacts/search?q=" + tt.query
+
+			m := useMiddleware(handler)
+
+			req := httptest.NewRequest(method, url, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			got := response.StatusCode
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.Search error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.Search handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_ExportVCard(t *testing.T) {
+	page := make([]model.Contact, model.MaxContactLimit)
+	for i := range page {
+		page[i] = model.Contact{ID: int64(i + 1), Name: fmt.Sprintf("contact %d", i+1), NoTelp: "999-888-7777"}
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		query      *model.ContactQuery
+		pages      [][]model.Contact
+		UCErr      error
+		wantStatus int
+		wantCards  int
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "success",
+			url:        "http://localhost:8080/contacts/export.vcf",
+			pages:      [][]model.Contact{page, {{ID: 1001, Name: "Jane Smith", NoTelp: "555-555-5678"}}},
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantCards:  model.MaxContactLimit + 1,
+			want:       "VERSION:3.0\r\nFN:Jane Smith\r\n",
+			wantErr:    false,
+		},
+		{
+			name:  "success filtered",
+			url:   "http://localhost:8080/contacts/export.vcf?name=jane&limit=10&version=4.0",
+			query: &model.ContactQuery{Limit: 10, Name: "jane"},
+			pages: [][]model.Contact{{
+				{ID: 2, Name: "Jane Smith", NoTelp: "555-555-5678"},
+			}},
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantCards:  1,
+			want:       "VERSION:4.0\r\nFN:Jane Smith\r\n",
+			wantErr:    false,
+		},
+		{
+			name:       "invalid version",
+			url:        "http://localhost:8080/contacts/export.vcf?version=2.1",
+			UCErr:      nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    false,
+		},
+		{
+			name:       "failed",
+			url:        "http://localhost:8080/contacts/export.vcf",
+			pages:      [][]model.Contact{nil},
+			UCErr:      assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			total := int64(0)
+			for _, contacts := range tt.pages {
+				total += int64(len(contacts))
+			}
+			offset := 0
+			for _, contacts := range tt.pages {
+				query := &model.ContactQuery{Limit: model.MaxContactLimit, Offset: offset}
+				if tt.query != nil {
+					query = tt.query
+				}
+				mockContactUC.On("List", mock.Anything, query).Return(contacts, total, tt.UCErr).Once()
+				offset += len(contacts)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			handler := http.HandlerFunc(h.ExportVCard)
+			method := "GET"
+
+			m := useMiddleware(handler)
+
+			req := httptest.NewRequest(method, tt.url, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			got := response.StatusCode
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.List error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.ExportVCard handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "text/vcard; charset=utf-8", response.Header.Get("Content-Type"))
+				assert.Equal(t, tt.wantCards, strings.Count(recorder.Body.String(), "BEGIN:VCARD"))
+				assert.Contains(t, recorder.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_DetailVCard(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		id         int64
+		UCResult   *model.Contact
+		UCErr      error
+		wantStatus int
+		wantErr    bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:       "success",
+			path:       "/contacts/1.vcf",
+			id:         1,
+			UCResult:   &model.Contact{ID: 1, Name: "test", NoTelp: "222-222-3232"},
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "invalid id",
+			path:       "/contacts/abc.vcf",
+			UCErr:      sql.ErrNoRows,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid version",
+			path:       "/contacts/1.vcf?version=5.0",
+			UCErr:      sql.ErrNoRows,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "not found on usecase",
+			path:       "/contacts/2.vcf",
+			id:         2,
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactNotFound),
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if tt.id != 0 {
+				mockContactUC.On("Detail", mock.Anything, tt.id).Return(tt.UCResult, tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			handler := http.HandlerFunc(h.DetailVCard)
+			method := "GET"
+
+			m := useMiddleware(handler)
+
+			req := httptest.NewRequest(method, "http://localhost:8080"+tt.path, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			got := response.StatusCode
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.Detail error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.DetailVCard handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, `attachment; filename="contact-1.vcf"`, response.Header.Get("Content-Disposition"))
+				assert.Contains(t, recorder.Body.String(), "FN:test\r\n")
+			} else {
+				assert.Contains(t, response.Header.Get("Content-Type"), "application/json")
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_ImportVCard(t *testing.T) {
+	cards := "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Jane Smith\r\nTEL;TYPE=PREF:555-555-5678\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:No Phone\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nTEL:000\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Bad Email\r\nTEL:111\r\nEMAIL:nope\r\nEND:VCARD\r\n"
+
+	tests := []struct {
+		name        string
+		multipart   bool
+		body        string
+		wantStatus  int
+		wantResults []model.ContactImportResult
+	}{
+		// TODO: Add test cases.
+		{
+			name:       "success",
+			body:       cards,
+			wantStatus: http.StatusOK,
+			wantResults: []model.ContactImportResult{
+				{Card: 1, Name: "Jane Smith", ID: 1},
+				{Card: 2, Name: "No Phone", Error: apperrors.ErrContactNoTelpNotValid},
+				{Card: 3, Error: "vcard: line 10: missing FN"},
+				{Card: 4, Name: "Bad Email", Error: apperrors.ErrContactEmailNotValid},
+			},
+		},
+		{
+			name:       "success multipart",
+			multipart:  true,
+			body:       cards,
+			wantStatus: http.StatusOK,
+			wantResults: []model.ContactImportResult{
+				{Card: 1, Name: "Jane Smith", ID: 1},
+				{Card: 2, Name: "No Phone", Error: apperrors.ErrContactNoTelpNotValid},
+				{Card: 3, Error: "vcard: line 10: missing FN"},
+				{Card: 4, Name: "Bad Email", Error: apperrors.ErrContactEmailNotValid},
+			},
+		},
+		{
+			name:        "empty file",
+			body:        "",
+			wantStatus:  http.StatusOK,
+			wantResults: []model.ContactImportResult{},
+		},
+		{
+			name:       "multipart without file",
+			multipart:  true,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if len(tt.wantResults) > 0 {
+				mockContactUC.On("Add", mock.Anything, &model.ContactRequest{Name: "Jane Smith", NoTelp: "555-555-5678"}).
+					Return(&model.Contact{ID: 1, Name: "Jane Smith", NoTelp: "555-555-5678"}, nil)
+				mockContactUC.On("Add", mock.Anything, mock.MatchedBy(func(req *model.ContactRequest) bool { return req.Name == "Bad Email" })).
+					Return(nil, apperrors.NewAppError(apperrors.ErrContactEmailNotValid))
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			handler := http.HandlerFunc(h.ImportVCard)
+			method := "POST"
+
+			m := useMiddleware(handler)
+
+			body := new(bytes.Buffer)
+			contentType := "text/vcard"
+			if tt.multipart {
+				writer := multipart.NewWriter(body)
+				if tt.body != "" {
+					part, _ := writer.CreateFormFile("file", "contacts.vcf")
+					_, _ = part.Write([]byte(tt.body))
+				}
+				_ = writer.Close()
+				contentType = writer.FormDataContentType()
+			} else {
+				body.WriteString(tt.body)
+			}
+
+			req := httptest.NewRequest(method, "http://localhost:8080/contacts/import", body)
+			req.Header.Set("Content-Type", contentType)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			got := response.StatusCode
+
+			assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.ImportVCard handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+
+			if tt.wantStatus == http.StatusOK {
+				var res struct {
+					Data []model.ContactImportResult `json:"data"`
+				}
+				assert.NoError(t, json.NewDecoder(response.Body).Decode(&res))
+				assert.Equal(t, tt.wantResults, res.Data)
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_ImportLimits(t *testing.T) {
+	cards := "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Jane Smith\r\nTEL:555-555-5678\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:John\r\nTEL:555-555-0000\r\nEND:VCARD\r\n"
+	rows := "name,no_telp\nJane Smith,555-555-5678\nJohn,555-555-0000\n"
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		maxSize    int64
+		maxRows    int
+		wantStatus int
+		wantAdded  int
+	}{
+		{name: "cards within the limits", url: "/contacts/import", body: cards, maxSize: int64(len(cards)), maxRows: 2, wantStatus: http.StatusOK, wantAdded: 2},
+		{name: "too many cards", url: "/contacts/import", body: cards, maxRows: 1, wantStatus: http.StatusRequestEntityTooLarge, wantAdded: 1},
+		{name: "too many rows", url: "/contacts/import.csv", body: rows, maxRows: 1, wantStatus: http.StatusRequestEntityTooLarge, wantAdded: 1},
+		{name: "file too large", url: "/contacts/import", body: cards, maxSize: 16, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := new(mocks.ContactUsecase)
+			mockContactUC.On("FindByPhone", mock.Anything, mock.Anything).Return(nil, apperrors.NewAppError(apperrors.ErrContactNotFound))
+			mockContactUC.On("Add", mock.Anything, mock.Anything).Return(&model.Contact{ID: 1}, nil)
+
+			h := NewContactHTTPHandler(mockContactUC, tt.maxSize, tt.maxRows)
+			handler := http.HandlerFunc(h.ImportVCard)
+			if strings.HasSuffix(tt.url, ".csv") {
+				handler = h.ImportCSV
+			}
+
+			recorder := httptest.NewRecorder()
+			useMiddleware(handler).ServeHTTP(recorder, httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			mockContactUC.AssertNumberOfCalls(t, "Add", tt.wantAdded)
+		})
+	}
+}
+
+func Test_contactHTTPHandler_ExportCSV(t *testing.T) {
+	page := make([]model.Contact, model.MaxContactLimit)
+	for i := range page {
+		page[i] = model.Contact{ID: int64(i + 1), Name: fmt.Sprintf("contact %d", i+1), NoTelp: "000"}
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		pages      [][]model.Contact
+		UCErr      error
+		wantStatus int
+		wantRows   int
+		wantErr    bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:       "success",
+			url:        "http://localhost:8080/contacts/export.csv",
+			pages:      [][]model.Contact{page, {{ID: 1001, Name: "last", NoTelp: "111"}}},
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantRows:   model.MaxContactLimit + 1,
+			wantErr:    false,
+		},
+		{
+			name:       "success with limit",
+			url:        "http://localhost:8080/contacts/export.csv?limit=2&name=contact",
+			pages:      [][]model.Contact{page[:2]},
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantRows:   2,
+			wantErr:    false,
+		},
+		{
+			name:       "invalid limit",
+			url:        "http://localhost:8080/contacts/export.csv?limit=abc",
+			UCErr:      nil,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    false,
+		},
+		{
+			name:       "failed",
+			url:        "http://localhost:8080/contacts/export.csv",
+			pages:      [][]model.Contact{nil},
+			UCErr:      assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			total := int64(0)
+			for _, contacts := range tt.pages {
+				total += int64(len(contacts))
+			}
+			offset := 0
+			for _, contacts := range tt.pages {
+				query := &model.ContactQuery{Limit: model.MaxContactLimit, Offset: offset}
+				if strings.Contains(tt.url, "limit=") {
+					query = &model.ContactQuery{Limit: 2, Name: "contact"}
+				}
+				mockContactUC.On("List", mock.Anything, query).Return(contacts, total, tt.UCErr).Once()
+				offset += len(contacts)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			handler := http.HandlerFunc(h.ExportCSV)
+			method := "GET"
+
+			m := useMiddleware(handler)
+
+			req := httptest.NewRequest(method, tt.url, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			got := response.StatusCode
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.List error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.ExportCSV handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "text/csv; charset=utf-8", response.Header.Get("Content-Type"))
+
+				rows := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+				assert.Equal(t, tt.wantRows+1, len(rows))
+				assert.True(t, strings.HasPrefix(rows[0], "id,name,no_telp,"))
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_ImportCSV(t *testing.T) {
+	file := "Full Name,HP,email\n" +
+		"Jane Smith,555-555-5678,jane@example.com\n" +
+		"John,555-555-0000,\n" +
+		",555-555-1111,\n" +
+		"Bad Email,555-555-2222,nope\n"
+	existing := &model.Contact{
+		ID:     2,
+		Name:   "John",
+		NoTelp: "555-555-0000",
+		Emails: []model.Email{{Type: model.TypeWork, Address: "john@example.com"}},
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		multipart   bool
+		storeErr    error
+		wantStatus  int
+		wantResults []model.ContactCSVResult
+		wantSummary model.ContactCSVSummary
+	}{
+		// TODO: Add test cases.
+		{
+			name:       "success dry run",
+			url:        "/contacts/import.csv?dry_run=true&map=Full+Name:name&map=HP:no_telp",
+			wantStatus: http.StatusOK,
+			wantResults: []model.ContactCSVResult{
+				{Line: 2, Action: model.ImportCreate, Name: "Jane Smith", NoTelp: "555-555-5678"},
+				{Line: 3, Action: model.ImportUpdate, Name: "John", NoTelp: "555-555-0000", ID: 2},
+				{Line: 4, Action: model.ImportReject, NoTelp: "555-555-1111", Error: apperrors.ErrContactNameNotValid},
+				{Line: 5, Action: model.ImportReject, Name: "Bad Email", NoTelp: "555-555-2222", Error: apperrors.ErrContactEmailNotValid},
+			},
+			wantSummary: model.ContactCSVSummary{DryRun: true, Created: 1, Updated: 1, Rejected: 2},
+		},
+		{
+			name:       "success",
+			url:        "/contacts/import.csv?map=Full+Name:name&map=HP:no_telp",
+			multipart:  true,
+			wantStatus: http.StatusOK,
+			wantResults: []model.ContactCSVResult{
+				{Line: 2, Action: model.ImportCreate, Name: "Jane Smith", NoTelp: "555-555-5678", ID: 1},
+				{Line: 3, Action: model.ImportUpdate, Name: "John", NoTelp: "555-555-0000", ID: 2},
+				{Line: 4, Action: model.ImportReject, NoTelp: "555-555-1111", Error: apperrors.ErrContactNameNotValid},
+				{Line: 5, Action: model.ImportReject, Name: "Bad Email", NoTelp: "555-555-2222", Error: apperrors.ErrContactEmailNotValid},
+			},
+			wantSummary: model.ContactCSVSummary{Created: 1, Updated: 1, Rejected: 2},
+		},
+		{
+			name:       "failed store",
+			url:        "/contacts/import.csv?map=Full+Name:name&map=HP:no_telp",
+			storeErr:   assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid mapping",
+			url:        "/contacts/import.csv?map=Full+Name:id",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid dry run",
+			url:        "/contacts/import.csv?dry_run=maybe",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			dryRun := strings.Contains(tt.url, "dry_run=true")
+			if tt.storeErr != nil {
+				mockContactUC.On("FindByPhone", mock.Anything, mock.Anything).Return(nil, tt.storeErr).Once()
+			} else if tt.wantResults != nil {
+				for _, noTelp := range []string{"555-555-5678", "555-555-2222"} {
+					mockContactUC.On("FindByPhone", mock.Anything, noTelp).Return(nil, apperrors.NewAppError(apperrors.ErrContactNotFound))
+				}
+				mockContactUC.On("FindByPhone", mock.Anything, "555-555-0000").Return(existing, nil)
+
+				// the update keeps the emails, which the file has no column for
+				update := &model.ContactRequest{Name: "John", NoTelp: "555-555-0000", Emails: existing.Emails}
+				jane := &model.ContactRequest{Name: "Jane Smith", NoTelp: "555-555-5678"}
+				badEmail := &model.ContactRequest{Name: "Bad Email", NoTelp: "555-555-2222"}
+				if dryRun {
+					mockContactUC.On("Validate", mock.Anything, jane).Return(nil)
+					mockContactUC.On("Validate", mock.Anything, update).Return(nil)
+					mockContactUC.On("Validate", mock.Anything, badEmail).Return(apperrors.NewAppError(apperrors.ErrContactEmailNotValid))
+				} else {
+					mockContactUC.On("Add", mock.Anything, jane).Return(&model.Contact{ID: 1}, nil)
+					mockContactUC.On("Update", mock.Anything, int64(2), update).Return(existing, nil)
+					mockContactUC.On("Add", mock.Anything, badEmail).Return(nil, apperrors.NewAppError(apperrors.ErrContactEmailNotValid))
+				}
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			handler := http.HandlerFunc(h.ImportCSV)
+			method := "POST"
+
+			m := useMiddleware(handler)
+
+			body := new(bytes.Buffer)
+			contentType := "text/csv"
+			if tt.multipart {
+				writer := multipart.NewWriter(body)
+				part, _ := writer.CreateFormFile("file", "contacts.csv")
+				_, _ = part.Write([]byte(file))
+				_ = writer.Close()
+				contentType = writer.FormDataContentType()
+			} else {
+				body.WriteString(file)
+			}
+
+			req := httptest.NewRequest(method, "http://localhost:8080"+tt.url, body)
+			req.Header.Set("Content-Type", contentType)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			got := response.StatusCode
+
+			assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.ImportCSV handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+
+			if tt.wantStatus == http.StatusOK {
+				var res struct {
+					Data []model.ContactCSVResult `json:"data"`
+					Meta model.ContactCSVSummary  `json:"meta"`
+				}
+				assert.NoError(t, json.NewDecoder(response.Body).Decode(&res))
+				assert.Equal(t, tt.wantResults, res.Data)
+				assert.Equal(t, tt.wantSummary, res.Meta)
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Duplicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		UCResult   [][]model.Contact
+		UCErr      error
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			UCResult: [][]model.Contact{
+				{{ID: 1, Name: "Jane Smith", NoTelp: "+15555551234"}, {ID: 4, Name: "Smith Jane", NoTelp: "+15555559876"}},
+			},
+			UCErr:      nil,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "invalid on usecase",
+			UCErr:      assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			mockContactUC.On("Duplicates", mock.Anything).Return(tt.UCResult, tt.UCErr)
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Duplicates))
+
+			req := httptest.NewRequest("GET", "http://localhost:8080/contacts/duplicates", nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Code
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.Duplicates error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.Duplicates handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Merge(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantIDs    []int64
+		UCResult   *model.Contact
+		UCErr      error
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			body:       `{"ids":[1,4]}`,
+			wantIDs:    []int64{1, 4},
+			UCResult:   &model.Contact{ID: 1, Name: "Jane Smith", NoTelp: "+15555551234"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid body",
+			body:       `{"ids":"1,4"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid ids on usecase",
+			body:       `{"ids":[1]}`,
+			wantIDs:    []int64{1},
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactMergeNotValid),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not found on usecase",
+			body:       `{"ids":[1,99]}`,
+			wantIDs:    []int64{1, 99},
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactNotFound),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.wantIDs != nil {
+				mockContactUC.On("Merge", mock.Anything, tt.wantIDs).Return(tt.UCResult, tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Merge))
+
+			req := httptest.NewRequest("POST", "http://localhost:8080/contacts/merge", strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Code
+			assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.Merge handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Batch(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantRequest *model.ContactBatchRequest
+		UCResult    []model.ContactBatchResult
+		UCErr       error
+		wantStatus  int
+		wantMeta    string
+	}{
+		{
+			name: "best effort",
+			body: `{"operations":[{"op":"create","contact":{"name":"Jane","no_telp":"555-555-1234"}},{"op":"delete","id":9}]}`,
+			wantRequest: &model.ContactBatchRequest{Operations: []model.ContactBatchOperation{
+				{Op: model.BatchCreate, Contact: &model.ContactRequest{Name: "Jane", NoTelp: "555-555-1234"}},
+				{Op: model.BatchDelete, ID: 9},
+			}},
+			UCResult: []model.ContactBatchResult{
+				{Operation: 1, Op: model.BatchCreate, ID: 5, Contact: &model.Contact{ID: 5, Name: "Jane", NoTelp: "+15555551234"}},
+				{Operation: 2, Op: model.BatchDelete, ID: 9, Error: apperrors.ErrContactNotFound},
+			},
+			wantStatus: http.StatusOK,
+			wantMeta:   `{"atomic":false,"succeeded":1,"failed":1}`,
+		},
+		{
+			name: "atomic failed",
+			body: `{"atomic":true,"operations":[{"op":"delete","id":9}]}`,
+			wantRequest: &model.ContactBatchRequest{Atomic: true, Operations: []model.ContactBatchOperation{
+				{Op: model.BatchDelete, ID: 9},
+			}},
+			UCResult: []model.ContactBatchResult{
+				{Operation: 1, Op: model.BatchDelete, ID: 9, Error: apperrors.ErrContactNotFound},
+			},
+			UCErr:      apperrors.NewAppError(apperrors.ErrContactNotFound),
+			wantStatus: http.StatusNotFound,
+			wantMeta:   `{"atomic":true,"succeeded":0,"failed":1}`,
+		},
+		{
+			name:        "empty batch",
+			body:        `{"operations":[]}`,
+			wantRequest: &model.ContactBatchRequest{Operations: []model.ContactBatchOperation{}},
+			UCErr:       apperrors.NewAppError(apperrors.ErrBatchNotValid),
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:       "invalid body",
+			body:       `{"operations":{}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.wantRequest != nil {
+				mockContactUC.On("Batch", mock.Anything, tt.wantRequest).Return(tt.UCResult, tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+			m := useMiddleware(http.HandlerFunc(h.Batch))
+
+			req := httptest.NewRequest("POST", "http://localhost:8080/contacts/batch", strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Code
+			assert.Equal(t, tt.wantStatus, got, "ContactHTTPHandler.Batch handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+
+			if tt.wantMeta != "" {
+				var body struct {
+					Meta json.RawMessage `json:"meta"`
+				}
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				assert.JSONEq(t, tt.wantMeta, string(body.Meta))
+			}
+		})
+	}
+}
+
+func Test_importCSV_duplicate(t *testing.T) {
+	file := "name,no_telp\nJane Smyth,555-555-5678\n"
+	jane := &model.ContactRequest{Name: "Jane Smyth", NoTelp: "555-555-5678"}
+
+	mockContactUC := mocks.NewContactUsecase(t)
+	mockContactUC.On("FindByPhone", mock.Anything, "555-555-5678").Return(nil, apperrors.NewAppError(apperrors.ErrContactNotFound))
+	mockContactUC.On("Add", mock.Anything, jane).Return(nil, &usecase.DuplicateError{Candidates: []model.Contact{{ID: 2, Name: "Jane Smith"}}})
+
+	results, summary, err := importCSV(context.Background(), mockContactUC, strings.NewReader(file), nil, false, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ContactCSVResult{
+		{Line: 2, Action: model.ImportReject, Name: "Jane Smyth", NoTelp: "555-555-5678", Error: apperrors.ErrContactDuplicate},
+	}, results)
+	assert.Equal(t, 1, summary.Rejected)
+}
+
+func Test_contactHTTPHandler_History(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		query      *model.AuditQuery
+		UCResult   []model.AuditEntry
+		UCTotal    int64
+		UCErr      error
+		wantStatus int
+		wantNext   string
+	}{
+		{
+			name:       "success",
+			url:        "http://localhost:8080/contacts/1/history?limit=1",
+			query:      &model.AuditQuery{Limit: 1},
+			UCResult:   []model.AuditEntry{{ID: 2, ContactID: 1, Actor: "cli", Operation: model.AuditUpdate}},
+			UCTotal:    2,
+			wantStatus: http.StatusOK,
+			wantNext:   "/contacts/1/history?limit=1&offset=1",
+		},
+		{
+			name:       "invalid id",
+			url:        "http://localhost:8080/contacts/abc/history",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			url:        "http://localhost:8080/contacts/1/history?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "failed",
+			url:        "http://localhost:8080/contacts/1/history",
+			query:      &model.AuditQuery{Limit: model.DefaultContactLimit},
+			UCErr:      assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if tt.query != nil {
+				mockContactUC.On("History", mock.Anything, int64(1), tt.query).Return(tt.UCResult, tt.UCTotal, tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			m := useMiddleware(http.HandlerFunc(h.History))
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			assert.Equal(t, tt.wantStatus, response.StatusCode, "ContactHTTPHandler.History handler returned wrong status code")
+
+			if tt.wantStatus == http.StatusOK {
+				var body struct {
+					Data []model.AuditEntry `json:"data"`
+					Meta struct {
+						Total int64  `json:"total"`
+						Next  string `json:"next"`
+					} `json:"meta"`
+				}
+				err := json.NewDecoder(response.Body).Decode(&body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.UCResult, body.Data)
+				assert.Equal(t, tt.UCTotal, body.Meta.Total)
+				assert.Equal(t, tt.wantNext, body.Meta.Next)
+			}
+		})
+	}
+}
+
+func Test_contactHTTPHandler_Revert(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		revision   int64
+		UCResult   *model.Contact
+		UCErr      error
+		wantStatus int
+		wantETag   string
+	}{
+		{
+			name:       "success",
+			url:        "http://localhost:8080/contacts/1/revert?revision=4",
+			revision:   4,
+			UCResult:   &model.Contact{ID: 1, Name: "Test", Version: 3},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:       "reverted to deleted",
+			url:        "http://localhost:8080/contacts/1/revert?revision=5",
+			revision:   5,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			url:        "http://localhost:8080/contacts/abc/revert?revision=4",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid revision",
+			url:        "http://localhost:8080/contacts/1/revert",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "revision not found",
+			url:        "http://localhost:8080/contacts/1/revert?revision=9",
+			revision:   9,
+			UCErr:      apperrors.NewAppError(apperrors.ErrRevisionNotFound),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if tt.revision != 0 {
+				mockContactUC.On("Revert", mock.Anything, int64(1), tt.revision).Return(tt.UCResult, tt.UCErr)
+			}
+
+			h := NewContactHTTPHandler(mockContactUC, 0, 0)
+
+			m := useMiddleware(http.HandlerFunc(h.Revert))
+
+			req := httptest.NewRequest("POST", tt.url, nil)
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			response := recorder.Result()
+			assert.Equal(t, tt.wantStatus, response.StatusCode, "ContactHTTPHandler.Revert handler returned wrong status code")
+			assert.Equal(t, tt.wantETag, response.Header.Get("ETag"))
+		})
+	}
+}
@@ -7,5 +7,20 @@ type ContactHTTPHandler interface {
 	Add(w http.ResponseWriter, r *http.Request)
 	Detail(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
+	Patch(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	Trash(w http.ResponseWriter, r *http.Request)
+	Restore(w http.ResponseWriter, r *http.Request)
+	Purge(w http.ResponseWriter, r *http.Request)
+	Search(w http.ResponseWriter, r *http.Request)
+	ExportVCard(w http.ResponseWriter, r *http.Request)
+	DetailVCard(w http.ResponseWriter, r *http.Request)
+	ImportVCard(w http.ResponseWriter, r *http.Request)
+	ExportCSV(w http.ResponseWriter, r *http.Request)
+	ImportCSV(w http.ResponseWriter, r *http.Request)
+	Duplicates(w http.ResponseWriter, r *http.Request)
+	Merge(w http.ResponseWriter, r *http.Request)
+	Batch(w http.ResponseWriter, r *http.Request)
+	History(w http.ResponseWriter, r *http.Request)
+	Revert(w http.ResponseWriter, r *http.Request)
 }
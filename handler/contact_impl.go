@@ -1,11 +1,21 @@
 package handler
 
 import (
+	"bytes"
 	"contact-go/helper"
+	"contact-go/helper/actor"
 	"contact-go/helper/input"
+	"contact-go/helper/phone"
+	"contact-go/helper/tenant"
+	"contact-go/helper/vcard"
 	"contact-go/model"
 	"contact-go/usecase"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strconv"
 	"strings"
 )
@@ -13,32 +23,53 @@ import (
 type contactHandler struct {
 	ContactUC usecase.ContactUsecase
 	Input     *input.InputReader
+	// AddressBook is the address book the menu works on.
+	AddressBook string
+	// revisions are the changes made in this session, newest last,
+	// for Undo to take back.
+	revisions []int64
 }
 
-func NewContactHandler(contactUC usecase.ContactUsecase, input *input.InputReader) ContactHandler {
+func NewContactHandler(contactUC usecase.ContactUsecase, input *input.InputReader, addressBook string) ContactHandler {
 	contactHandler := new(contactHandler)
 	contactHandler.ContactUC = contactUC
 	contactHandler.Input = input
+	contactHandler.AddressBook = addressBook
 
 	return contactHandler
 }
 
+// newContext returns the context of one menu operation, made by
+// actor.CLI in the address book of the menu and cancelled when the
+// user interrupts it with Ctrl+C.
+func (handler *contactHandler) newContext() (context.Context, context.CancelFunc) {
+	ctx := tenant.NewContext(actor.NewContext(context.Background(), actor.CLI), handler.AddressBook)
+	return signal.NotifyContext(ctx, os.Interrupt)
+}
+
+// remember keeps the newest revision of the contact id, the one the
+// change just made recorded, for Undo. A change that went unrecorded
+// cannot be undone and is left out.
+func (handler *contactHandler) remember(ctx context.Context, id int64) {
+	entries, _, err := handler.ContactUC.History(ctx, id, &model.AuditQuery{Limit: 1})
+	if err != nil || len(entries) == 0 {
+		return
+	}
+	handler.revisions = append(handler.revisions, entries[0].ID)
+}
+
 func (handler *contactHandler) List() {
 	_ = helper.ClearTerminal()
 
-	contacts, err := handler.ContactUC.List()
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contacts, _, err := handler.ContactUC.List(ctx, new(model.ContactQuery))
 
 	if err != nil {
 		fmt.Println(err.Error())
 	} else {
-		fmt.Printf("|---------------|-----------------------|-----------------------|\n")
-		fmt.Printf("| ID\t\t| Nama\t\t\t| No.Telp\t\t|\n")
-		fmt.Printf("|---------------|-----------------------|-----------------------|\n")
-
-		for _, v := range contacts {
-			fmt.Printf("| %d\t\t| %s\t\t| %s\t\t|\n", v.ID, v.Name, v.NoTelp)
-		}
-		fmt.Printf("|---------------|-----------------------|-----------------------|\n")
+		printContactTable(contacts)
 	}
 }
 
@@ -63,11 +94,35 @@ func (handler *contactHandler) Add() {
 		Name:   name,
 		NoTelp: noTelp,
 	}
+	if !handler.scanContactDetails(&contactRequest) {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contact, err := handler.ContactUC.Add(ctx, &contactRequest)
+
+	var duplicateErr *usecase.DuplicateError
+	if errors.As(err, &duplicateErr) {
+		fmt.Println("Contact serupa sudah ada:")
+		printContactTable(duplicateErr.Candidates)
+
+		fmt.Print("Tetap simpan? (y/n) = ")
+		confirm, scanErr := handler.Input.Scan()
+		if scanErr != nil || !strings.EqualFold(strings.TrimSpace(confirm), "y") {
+			fmt.Println("Add contact dibatalkan")
+			return
+		}
+
+		contactRequest.Force = true
+		contact, err = handler.ContactUC.Add(ctx, &contactRequest)
+	}
 
-	contact, err := handler.ContactUC.Add(&contactRequest)
 	if err != nil {
 		fmt.Println(err.Error())
 	} else {
+		handler.remember(ctx, contact.ID)
 		fmt.Println("Berhasil add contact with id", contact.ID)
 	}
 }
@@ -88,12 +143,36 @@ func (handler *contactHandler) Detail() {
 		return
 	}
 
-	contact, err := handler.ContactUC.Detail(id)
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contact, err := handler.ContactUC.Detail(ctx, id)
 	if err != nil {
 		fmt.Println(err.Error())
-	} else {
-		fmt.Printf("ID : \t\t%d\nNama : \t\t%s\nNo.Telp : \t%s\n", contact.ID, contact.Name, contact.NoTelp)
+		return
+	}
+
+	fmt.Printf("ID : \t\t%d\nNama : \t\t%s\nNo.Telp : \t%s\n", contact.ID, contact.Name, phone.Format(contact.NoTelp))
+	printContactDetails(contact)
+
+	fmt.Print("Tampilkan history? (y/n) = ")
+	confirm, err := handler.Input.Scan()
+	if err != nil || !strings.EqualFold(strings.TrimSpace(confirm), "y") {
+		return
+	}
+
+	entries, _, err := handler.ContactUC.History(ctx, id, &model.AuditQuery{Limit: model.DefaultContactLimit})
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Belum ada history")
+		return
 	}
+
+	printHistoryTable(entries)
 }
 
 func (handler *contactHandler) Update() {
@@ -130,11 +209,18 @@ func (handler *contactHandler) Update() {
 		Name:   name,
 		NoTelp: noTelp,
 	}
+	if !handler.scanContactDetails(&contactRequest) {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
 
-	contact, err := handler.ContactUC.Update(id, &contactRequest)
+	contact, err := handler.ContactUC.Update(ctx, id, &contactRequest)
 	if err != nil {
 		fmt.Println(err.Error())
 	} else {
+		handler.remember(ctx, contact.ID)
 		fmt.Println("Berhasil update contact with id", contact.ID)
 	}
 }
@@ -155,10 +241,645 @@ func (handler *contactHandler) Delete() {
 		return
 	}
 
-	err = handler.ContactUC.Delete(id)
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	err = handler.ContactUC.Delete(ctx, id, 0)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		handler.remember(ctx, id)
+		fmt.Println("Berhasil pindahkan contact with id", id, "ke trash")
+	}
+}
+
+func (handler *contactHandler) Trash() {
+	_ = helper.ClearTerminal()
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contacts, _, err := handler.ContactUC.List(ctx, &model.ContactQuery{Deleted: true})
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if len(contacts) == 0 {
+		fmt.Println("Trash kosong")
+		return
+	}
+
+	printContactTable(contacts)
+}
+
+func (handler *contactHandler) Restore() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("ID = ")
+	idStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contact, err := handler.ContactUC.Restore(ctx, id)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		handler.remember(ctx, contact.ID)
+		fmt.Println("Berhasil restore contact with id", contact.ID)
+	}
+}
+
+// Undo takes back the last changes made in this session, newest first.
+func (handler *contactHandler) Undo() {
+	_ = helper.ClearTerminal()
+
+	if len(handler.revisions) == 0 {
+		fmt.Println("Belum ada perubahan untuk di-undo")
+		return
+	}
+
+	fmt.Printf("Jumlah perubahan (1-%d) = ", len(handler.revisions))
+	nStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Jumlah yang dimasukkan tidak valid")
+		return
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(nStr))
+	if err != nil || n <= 0 || n > len(handler.revisions) {
+		fmt.Println("Jumlah yang dimasukkan tidak valid")
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	for i := 0; i < n; i++ {
+		last := len(handler.revisions) - 1
+		contact, err := handler.ContactUC.Undo(ctx, handler.revisions[last])
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		handler.revisions = handler.revisions[:last]
+
+		if contact == nil {
+			fmt.Println("Berhasil undo, contact dihapus")
+		} else {
+			fmt.Println("Berhasil undo contact with id", contact.ID)
+		}
+	}
+}
+
+// Revert brings a contact back to a revision from its history.
+func (handler *contactHandler) Revert() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("ID = ")
+	idStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return
+	}
+
+	fmt.Print("Revision = ")
+	revisionStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Revision yang dimasukkan tidak valid")
+		return
+	}
+
+	revision, err := strconv.ParseInt(strings.TrimSpace(revisionStr), 10, 64)
+	if err != nil || revision <= 0 {
+		fmt.Println("Revision yang dimasukkan tidak valid")
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contact, err := handler.ContactUC.Revert(ctx, id, revision)
+	switch {
+	case err != nil:
+		fmt.Println(err.Error())
+	case contact == nil:
+		handler.remember(ctx, id)
+		fmt.Println("Berhasil revert, contact with id", id, "dihapus")
+	default:
+		handler.remember(ctx, contact.ID)
+		fmt.Println("Berhasil revert contact with id", contact.ID)
+	}
+}
+
+// Purge removes for good the contacts kept in
+// the trash for longer than the configured retention.
+func (handler *contactHandler) Purge() {
+	_ = helper.ClearTerminal()
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	purged, err := handler.ContactUC.Purge(ctx)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		fmt.Printf("Berhasil hapus permanen %d contact dari trash\n", purged)
+	}
+}
+
+func (handler *contactHandler) Search() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("Keyword = ")
+	query, err := handler.Input.Scan()
+	if err != nil || strings.TrimSpace(query) == "" {
+		fmt.Println("Keyword yang dimasukkan tidak valid")
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contacts, err := handler.ContactUC.Search(ctx, query)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if len(contacts) == 0 {
+		fmt.Println("Contact tidak ditemukan")
+		return
+	}
+
+	printContactTable(contacts)
+}
+
+func (handler *contactHandler) Duplicates() {
+	_ = helper.ClearTerminal()
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	groups, err := handler.ContactUC.Duplicates(ctx)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("Tidak ada contact duplikat")
+		return
+	}
+
+	for i, group := range groups {
+		fmt.Printf("Grup %d\n", i+1)
+		printContactTable(group)
+	}
+}
+
+// Merge asks for the contact to keep and the contacts
+// to merge into it, which are moved to the trash afterwards.
+func (handler *contactHandler) Merge() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("ID contact yang disimpan = ")
+	idStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return
+	}
+
+	fmt.Print("ID contact yang digabung (pisahkan dengan koma) = ")
+	idsStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return
+	}
+
+	ids := []int64{id}
+	for _, part := range strings.Split(idsStr, ",") {
+		mergedID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || mergedID <= 0 {
+			fmt.Println("ID yang dimasukkan tidak valid")
+			return
+		}
+		ids = append(ids, mergedID)
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contact, err := handler.ContactUC.Merge(ctx, ids)
 	if err != nil {
 		fmt.Println(err.Error())
 	} else {
-		fmt.Println("Berhasil delete contact with id", id)
+		fmt.Println("Berhasil gabung contact ke id", contact.ID)
+	}
+}
+
+func printContactTable(contacts []model.Contact) {
+	fmt.Printf("|---------------|-----------------------|-----------------------|\n")
+	fmt.Printf("| ID\t\t| Nama\t\t\t| No.Telp\t\t|\n")
+	fmt.Printf("|---------------|-----------------------|-----------------------|\n")
+
+	for _, v := range contacts {
+		fmt.Printf("| %d\t\t| %s\t\t| %s\t\t|\n", v.ID, v.Name, phone.Format(v.NoTelp))
+	}
+	fmt.Printf("|---------------|-----------------------|-----------------------|\n")
+}
+
+// printHistoryTable lists audit entries with the fields each one changed.
+func printHistoryTable(entries []model.AuditEntry) {
+	fmt.Printf("|-----------------------|---------------|---------------|-------------------------------|\n")
+	fmt.Printf("| Waktu\t\t\t| Actor\t\t| Operasi\t| Perubahan\t\t\t|\n")
+	fmt.Printf("|-----------------------|---------------|---------------|-------------------------------|\n")
+
+	for _, v := range entries {
+		fmt.Printf("| %s\t| %s\t\t| %s\t\t| %s\t\t|\n", v.CreatedAt.Format("2006-01-02 15:04:05"), v.Actor, v.Operation,
+			strings.Join(changedFields(v.Before, v.After), ", "))
+	}
+	fmt.Printf("|-----------------------|---------------|---------------|-------------------------------|\n")
+}
+
+// changedFields returns the model.ContactFields whose values differ
+// between before and after, none when either of them is missing.
+func changedFields(before, after *model.Contact) []string {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	var beforeFields, afterFields map[string]json.RawMessage
+	beforeData, _ := json.Marshal(before)
+	afterData, _ := json.Marshal(after)
+	if json.Unmarshal(beforeData, &beforeFields) != nil || json.Unmarshal(afterData, &afterFields) != nil {
+		return nil
+	}
+
+	var changed []string
+	for _, field := range model.ContactFields {
+		if !bytes.Equal(beforeFields[field], afterFields[field]) {
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+func (handler *contactHandler) ExportVCard() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("File path = ")
+	path, err := handler.Input.Scan()
+	if err != nil || strings.TrimSpace(path) == "" {
+		fmt.Println("File path yang dimasukkan tidak valid")
+		return
+	}
+
+	fmt.Print("Versi vCard (3.0 atau 4.0, kosongkan untuk 3.0) = ")
+	version, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Versi yang dimasukkan tidak valid")
+		return
+	}
+	version = strings.TrimSpace(version)
+	if version == "" {
+		version = vcard.Version3
+	}
+	if !vcard.ValidVersion(version) {
+		fmt.Println("Versi yang dimasukkan tidak valid")
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	contacts, _, err := handler.ContactUC.List(ctx, new(model.ContactQuery))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	file, err := os.Create(strings.TrimSpace(path))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	defer file.Close()
+
+	if err := writeVCards(file, contacts, version); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	if err := file.Close(); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("Berhasil export", len(contacts), "contact ke", strings.TrimSpace(path))
+}
+
+func (handler *contactHandler) ImportVCard() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("File path = ")
+	path, err := handler.Input.Scan()
+	if err != nil || strings.TrimSpace(path) == "" {
+		fmt.Println("File path yang dimasukkan tidak valid")
+		return
+	}
+
+	file, err := os.Open(strings.TrimSpace(path))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	defer file.Close()
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	results, err := importVCards(ctx, handler.ContactUC, file, 0)
+
+	imported := 0
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Printf("Card %d %s: gagal, %s\n", result.Card, result.Name, result.Error)
+			continue
+		}
+		fmt.Printf("Card %d %s: berhasil dengan id %d\n", result.Card, result.Name, result.ID)
+		imported++
+	}
+
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	fmt.Printf("Berhasil import %d dari %d contact\n", imported, len(results))
+}
+
+func (handler *contactHandler) ExportCSV() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("File path = ")
+	path, err := handler.Input.Scan()
+	if err != nil || strings.TrimSpace(path) == "" {
+		fmt.Println("File path yang dimasukkan tidak valid")
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	pager := newContactPager(handler.ContactUC, new(model.ContactQuery))
+	page, err := pager.Next(ctx)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	file, err := os.Create(strings.TrimSpace(path))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	defer file.Close()
+
+	if err := writeCSV(ctx, file, pager, page); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	if err := file.Close(); err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("Berhasil export contact ke", strings.TrimSpace(path))
+}
+
+// ImportCSV shows what importing a file would do before asking
+// whether to go ahead with it.
+func (handler *contactHandler) ImportCSV() {
+	_ = helper.ClearTerminal()
+
+	fmt.Print("File path = ")
+	path, err := handler.Input.Scan()
+	if err != nil || strings.TrimSpace(path) == "" {
+		fmt.Println("File path yang dimasukkan tidak valid")
+		return
+	}
+	path = strings.TrimSpace(path)
+
+	fmt.Println("Kosongkan jika header sudah sama dengan nama field, contoh: Nama Lengkap:name, HP:no_telp")
+	fmt.Print("Mapping kolom (pisahkan dengan koma) = ")
+	mappingStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Mapping yang dimasukkan tidak valid")
+		return
+	}
+
+	var pairs []string
+	for _, pair := range strings.Split(mappingStr, ",") {
+		if strings.TrimSpace(pair) != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	mapping, err := parseCSVMapping(pairs)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if !handler.importCSVFile(path, mapping, true) {
+		return
+	}
+
+	fmt.Print("Lanjutkan import? (y/n) = ")
+	confirm, err := handler.Input.Scan()
+	if err != nil || !strings.EqualFold(strings.TrimSpace(confirm), "y") {
+		fmt.Println("Import dibatalkan")
+		return
+	}
+
+	handler.importCSVFile(path, mapping, false)
+}
+
+func (handler *contactHandler) importCSVFile(path string, mapping map[string]string, dryRun bool) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Println(err.Error())
+		return false
+	}
+	defer file.Close()
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	results, summary, err := importCSV(ctx, handler.ContactUC, file, mapping, dryRun, 0)
+	if summary == nil {
+		fmt.Println(err.Error())
+		return false
+	}
+
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Printf("Baris %d %s: %s, %s\n", result.Line, result.Name, result.Action, result.Error)
+			continue
+		}
+		fmt.Printf("Baris %d %s: %s\n", result.Line, result.Name, result.Action)
+	}
+	fmt.Printf("Create: %d, Update: %d, Reject: %d\n", summary.Created, summary.Updated, summary.Rejected)
+
+	if err != nil {
+		fmt.Println(err.Error())
+		return false
+	}
+	return true
+}
+
+// scanContactDetails prompts for the optional details of a contact.
+// Phones, emails and addresses may be prefixed with their type, e.g. "work:".
+func (handler *contactHandler) scanContactDetails(req *model.ContactRequest) bool {
+	fmt.Println("Kosongkan jika tidak ada, awali dengan home:, work: atau mobile: untuk memberi tipe")
+
+	fmt.Print("Phone lain (pisahkan dengan koma) = ")
+	phones, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Phone yang dimasukkan tidak valid")
+		return false
+	}
+	for _, v := range splitTypedValues(phones) {
+		req.Phones = append(req.Phones, model.Phone{Type: v.Type, Number: v.Value})
+	}
+
+	fmt.Print("Email (pisahkan dengan koma) = ")
+	emails, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Email yang dimasukkan tidak valid")
+		return false
+	}
+	for _, v := range splitTypedValues(emails) {
+		req.Emails = append(req.Emails, model.Email{Type: v.Type, Address: v.Value})
+	}
+
+	fmt.Print("Alamat = ")
+	address, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Alamat yang dimasukkan tidak valid")
+		return false
+	}
+	if v := parseTypedValue(address); v.Value != "" {
+		req.Addresses = append(req.Addresses, model.Address{Type: v.Type, Street: v.Value})
+	}
+
+	fields := []struct {
+		prompt string
+		value  *string
+	}{
+		{"Company = ", &req.Company},
+		{"Jabatan = ", &req.JobTitle},
+		{"Tanggal lahir (YYYY-MM-DD) = ", &req.Birthday},
+		{"Catatan = ", &req.Notes},
+	}
+	for _, field := range fields {
+		fmt.Print(field.prompt)
+		value, err := handler.Input.Scan()
+		if err != nil {
+			fmt.Println("Input yang dimasukkan tidak valid")
+			return false
+		}
+		*field.value = strings.TrimSpace(value)
+	}
+
+	return true
+}
+
+type typedValue struct {
+	Type  string
+	Value string
+}
+
+// parseTypedValue splits "work:value" into its type and value,
+// leaving the type empty when s has no known type prefix.
+func parseTypedValue(s string) typedValue {
+	s = strings.TrimSpace(s)
+	if prefix, value, ok := strings.Cut(s, ":"); ok {
+		if contactType := strings.ToLower(strings.TrimSpace(prefix)); model.ContactTypes[contactType] {
+			return typedValue{Type: contactType, Value: strings.TrimSpace(value)}
+		}
+	}
+	return typedValue{Value: s}
+}
+
+func splitTypedValues(s string) []typedValue {
+	var values []typedValue
+	for _, part := range strings.Split(s, ",") {
+		if v := parseTypedValue(part); v.Value != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func printContactDetails(contact *model.Contact) {
+	for _, p := range contact.Phones {
+		fmt.Printf("Telp (%s) : \t%s\n", p.Type, phone.Format(p.Number))
+	}
+	for _, email := range contact.Emails {
+		fmt.Printf("Email (%s) : \t%s\n", email.Type, email.Address)
+	}
+	for _, address := range contact.Addresses {
+		fmt.Printf("Alamat (%s) : \t%s\n", address.Type, formatAddress(address))
+	}
+
+	details := []struct {
+		label string
+		value string
+	}{
+		{"Company", contact.Company},
+		{"Jabatan", contact.JobTitle},
+		{"Tgl lahir", contact.Birthday},
+		{"Catatan", contact.Notes},
+	}
+	for _, detail := range details {
+		if detail.value != "" {
+			fmt.Printf("%s : \t%s\n", detail.label, detail.value)
+		}
+	}
+
+	if !contact.CreatedAt.IsZero() {
+		fmt.Printf("Dibuat : \t%s\n", contact.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("Diubah : \t%s\n", contact.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+}
+
+func formatAddress(address model.Address) string {
+	var parts []string
+	for _, part := range []string{address.Street, address.City, address.Region, address.PostalCode, address.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
 	}
+	return strings.Join(parts, ", ")
 }
@@ -2,14 +2,18 @@ package handler
 
 import (
 	"bytes"
+	"contact-go/helper/apperrors"
 	"contact-go/helper/input"
+	"contact-go/helper/tenant"
 	"contact-go/mocks"
 	"contact-go/model"
+	"contact-go/usecase"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -47,9 +51,9 @@ func Test_contactHandler_List(t *testing.T) {
 
 			mockContactUC := mocks.NewContactUsecase(t)
 
-			mockContactUC.On("List").Return(tt.UCResult, tt.UCErr)
+			mockContactUC.On("List", mock.Anything, new(model.ContactQuery)).Return(tt.UCResult, int64(len(tt.UCResult)), tt.UCErr)
 
-			h := NewContactHandler(mockContactUC, inputReader)
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
 
 			h.List()
 
@@ -163,10 +167,11 @@ func Test_contactHandler_Add(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
 			if !tt.wantErr || strings.Contains(tt.name, "usecase") {
-				mockContactUC.On("Add", mock.Anything).Return(tt.UCResult, tt.UCErr)
+				mockContactUC.On("Add", mock.Anything, mock.Anything).Return(tt.UCResult, tt.UCErr)
 			}
+			mockContactUC.On("History", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
 
-			h := NewContactHandler(mockContactUC, inputReader)
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
 
 			restore, outC := captureStdout()
 			h.Add()
@@ -179,6 +184,91 @@ func Test_contactHandler_Add(t *testing.T) {
 	}
 }
 
+func Test_contactHandler_Add_details(t *testing.T) {
+	lines := strings.Join([]string{
+		"test",
+		"222-222-3232",
+		"work:222-222-4444, 222-222-5555",
+		"test@example.com",
+		"home: Jl. Merdeka 1, Bandung",
+		"Acme",
+		"Engineer",
+		"1990-05-17",
+		"likes tea",
+	}, "\n") + "\n"
+	inputReader := input.NewInputReader(strings.NewReader(lines))
+
+	want := &model.ContactRequest{
+		Name:      "test",
+		NoTelp:    "222-222-3232",
+		Phones:    []model.Phone{{Type: model.TypeWork, Number: "222-222-4444"}, {Number: "222-222-5555"}},
+		Emails:    []model.Email{{Address: "test@example.com"}},
+		Addresses: []model.Address{{Type: model.TypeHome, Street: "Jl. Merdeka 1, Bandung"}},
+		Company:   "Acme",
+		JobTitle:  "Engineer",
+		Birthday:  "1990-05-17",
+		Notes:     "likes tea",
+	}
+
+	mockContactUC := mocks.NewContactUsecase(t)
+	mockContactUC.On("Add", mock.Anything, want).Return(&model.Contact{ID: 1}, nil)
+	mockContactUC.On("History", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
+
+	h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+	restore, outC := captureStdout()
+	h.Add()
+	got := restoreStdout(restore, outC)
+
+	assert.Contains(t, got, "Berhasil add contact with id 1")
+}
+
+func Test_contactHandler_Add_duplicate(t *testing.T) {
+	candidates := []model.Contact{{ID: 3, Name: "Test", NoTelp: "+12222223232"}}
+
+	tests := []struct {
+		name    string
+		confirm string
+		want    string
+	}{
+		{
+			name:    "saved anyway",
+			confirm: "y",
+			want:    "Berhasil add contact with id 4",
+		},
+		{
+			name:    "cancelled",
+			confirm: "n",
+			want:    "Add contact dibatalkan",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := "test\n222-222-3232\n" + strings.Repeat("\n", 7) + tt.confirm + "\n"
+			inputReader := input.NewInputReader(strings.NewReader(lines))
+
+			mockContactUC := mocks.NewContactUsecase(t)
+			mockContactUC.On("Add", mock.Anything, mock.MatchedBy(func(req *model.ContactRequest) bool { return !req.Force })).
+				Return(nil, &usecase.DuplicateError{Candidates: candidates}).Once()
+			if tt.confirm == "y" {
+				mockContactUC.On("Add", mock.Anything, mock.MatchedBy(func(req *model.ContactRequest) bool { return req.Force })).
+					Return(&model.Contact{ID: 4}, nil).Once()
+				mockContactUC.On("History", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
+			}
+
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Add()
+			got := restoreStdout(restore, outC)
+
+			assert.Contains(t, got, "Contact serupa sudah ada")
+			assert.Contains(t, got, "| 3\t\t| Test")
+			assert.Contains(t, got, tt.want)
+		})
+	}
+}
+
 func Test_contactHandler_Detail(t *testing.T) {
 	type args struct {
 		idStr string
@@ -200,10 +290,27 @@ func Test_contactHandler_Detail(t *testing.T) {
 			UCResult: &model.Contact{
 				ID:     1,
 				Name:   "test",
-				NoTelp: "222-222-3232",
+				NoTelp: "+12222223232",
+			},
+			UCErr:   nil,
+			want:    "ID : 		1\nNama : 		test\nNo.Telp : 	+1 222-222-3232",
+			wantErr: false,
+		},
+		{
+			name: "success with details",
+			args: args{
+				idStr: "1",
+			},
+			UCResult: &model.Contact{
+				ID:        1,
+				Name:      "test",
+				NoTelp:    "222-222-3232",
+				Emails:    []model.Email{{Type: model.TypeWork, Address: "test@example.com"}},
+				Addresses: []model.Address{{Type: model.TypeHome, Street: "Jl. Merdeka 1", City: "Bandung"}},
+				Company:   "Acme",
 			},
 			UCErr:   nil,
-			want:    "ID : 		1\nNama : 		test\nNo.Telp : 	222-222-3232",
+			want:    "Email (work) : 	test@example.com\nAlamat (home) : 	Jl. Merdeka 1, Bandung\nCompany : 	Acme",
 			wantErr: false,
 		},
 		{
@@ -235,10 +342,10 @@ func Test_contactHandler_Detail(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
 			if !tt.wantErr || strings.Contains(tt.name, "usecase") {
-				mockContactUC.On("Detail", mock.Anything).Return(tt.UCResult, tt.UCErr)
+				mockContactUC.On("Detail", mock.Anything, mock.Anything).Return(tt.UCResult, tt.UCErr)
 			}
 
-			h := NewContactHandler(mockContactUC, inputReader)
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
 
 			restore, outC := captureStdout()
 			h.Detail()
@@ -251,6 +358,30 @@ func Test_contactHandler_Detail(t *testing.T) {
 	}
 }
 
+func Test_contactHandler_Detail_history(t *testing.T) {
+	createdAt := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	before := &model.Contact{ID: 1, Name: "test", NoTelp: "+12222223232"}
+	after := &model.Contact{ID: 1, Name: "test", NoTelp: "+12222223232", Company: "Acme", Version: 2}
+
+	inputReader := input.NewInputReader(strings.NewReader("1\ny\n"))
+
+	mockContactUC := mocks.NewContactUsecase(t)
+	mockContactUC.On("Detail", mock.Anything, int64(1)).Return(after, nil)
+	mockContactUC.On("History", mock.Anything, int64(1), &model.AuditQuery{Limit: model.DefaultContactLimit}).Return([]model.AuditEntry{
+		{ID: 2, ContactID: 1, Actor: "alice", Operation: model.AuditUpdate, Before: before, After: after, CreatedAt: createdAt},
+		{ID: 1, ContactID: 1, Actor: "cli", Operation: model.AuditAdd, After: before, CreatedAt: createdAt},
+	}, int64(2), nil)
+
+	h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+	restore, outC := captureStdout()
+	h.Detail()
+	got := restoreStdout(restore, outC)
+
+	assert.Contains(t, got, "| 2023-01-02 03:04:05\t| alice\t\t| update\t\t| company\t\t|")
+	assert.Contains(t, got, "| 2023-01-02 03:04:05\t| cli\t\t| add\t\t| \t\t|")
+}
+
 func Test_contactHandler_Update(t *testing.T) {
 	type args struct {
 		idStr string
@@ -348,10 +479,11 @@ func Test_contactHandler_Update(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
 			if !tt.wantErr || strings.Contains(tt.name, "usecase") {
-				mockContactUC.On("Update", mock.Anything, mock.Anything).Return(tt.UCResult, tt.UCErr)
+				mockContactUC.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(tt.UCResult, tt.UCErr)
 			}
+			mockContactUC.On("History", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
 
-			h := NewContactHandler(mockContactUC, inputReader)
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
 
 			restore, outC := captureStdout()
 			h.Update()
@@ -382,7 +514,7 @@ func Test_contactHandler_Delete(t *testing.T) {
 				idStr: "1",
 			},
 			UCErr:   nil,
-			want:    "Berhasil delete contact with id",
+			want:    "Berhasil pindahkan contact with id",
 			wantErr: false,
 		},
 		{
@@ -412,10 +544,11 @@ func Test_contactHandler_Delete(t *testing.T) {
 			mockContactUC := mocks.NewContactUsecase(t)
 
 			if !tt.wantErr || strings.Contains(tt.name, "usecase") {
-				mockContactUC.On("Delete", mock.Anything).Return(tt.UCErr)
+				mockContactUC.On("Delete", mock.Anything, mock.Anything, int64(0)).Return(tt.UCErr)
 			}
+			mockContactUC.On("History", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
 
-			h := NewContactHandler(mockContactUC, inputReader)
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
 
 			restore, outC := captureStdout()
 			h.Delete()
@@ -427,3 +560,649 @@ func Test_contactHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_contactHandler_Search(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		UCResult []model.Contact
+		UCErr    error
+		want     string
+		wantErr  bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:  "success",
+			query: "jane",
+			UCResult: []model.Contact{
+				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
+			},
+			UCErr:   nil,
+			want:    "Jane_Smith",
+			wantErr: false,
+		},
+		{
+			name:     "not found",
+			query:    "zzz",
+			UCResult: []model.Contact{},
+			UCErr:    nil,
+			want:     "Contact tidak ditemukan",
+			wantErr:  false,
+		},
+		{
+			name:    "invalid keyword",
+			query:   " ",
+			UCErr:   assert.AnError,
+			want:    "Keyword yang dimasukkan tidak valid",
+			wantErr: true,
+		},
+		{
+			name:    "invalid on usecase",
+			query:   "jane",
+			UCErr:   assert.AnError,
+			want:    assert.AnError.Error(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := strings.NewReader(fmt.Sprintf("%s\n", tt.query))
+			inputReader := input.NewInputReader(reader)
+
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if !tt.wantErr || strings.Contains(tt.name, "usecase") {
+				mockContactUC.On("Search", mock.Anything, tt.query).Return(tt.UCResult, tt.UCErr)
+			}
+
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Search()
+			got := restoreStdout(restore, outC)
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.Search error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Contains(t, got, tt.want, "Expected got to contain '%s', but got '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_contactHandler_ExportVCard(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		UCResult []model.Contact
+		UCErr    error
+		want     string
+		wantFile string
+		wantErr  bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:    "success",
+			version: "",
+			UCResult: []model.Contact{
+				{ID: 1, Name: "jaguar", NoTelp: "999-888-7777"},
+				{ID: 2, Name: "Jane Smith", NoTelp: "555-555-5678"},
+			},
+			UCErr:    nil,
+			want:     "Berhasil export 2 contact",
+			wantFile: "VERSION:3.0\r\nFN:jaguar\r\n",
+			wantErr:  false,
+		},
+		{
+			name:    "success version 4.0",
+			version: "4.0",
+			UCResult: []model.Contact{
+				{ID: 2, Name: "Jane Smith", NoTelp: "555-555-5678"},
+			},
+			UCErr:    nil,
+			want:     "Berhasil export 1 contact",
+			wantFile: "VERSION:4.0\r\nFN:Jane Smith\r\n",
+			wantErr:  false,
+		},
+		{
+			name:    "invalid version",
+			version: "2.1",
+			UCErr:   assert.AnError,
+			want:    "Versi yang dimasukkan tidak valid",
+			wantErr: true,
+		},
+		{
+			name:    "invalid on usecase",
+			UCErr:   assert.AnError,
+			want:    assert.AnError.Error(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/contacts.vcf"
+			reader := strings.NewReader(fmt.Sprintf("%s\n%s\n", path, tt.version))
+			inputReader := input.NewInputReader(reader)
+
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if !tt.wantErr || strings.Contains(tt.name, "usecase") {
+				mockContactUC.On("List", mock.Anything, new(model.ContactQuery)).Return(tt.UCResult, int64(len(tt.UCResult)), tt.UCErr)
+			}
+
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+			restore, outC := captureStdout()
+			h.ExportVCard()
+			got := restoreStdout(restore, outC)
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.List error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Contains(t, got, tt.want, "Expected got to contain '%s', but got '%s'", tt.want, got)
+			}
+
+			if !tt.wantErr {
+				file, err := os.ReadFile(path)
+				assert.NoError(t, err)
+				assert.Contains(t, string(file), tt.wantFile)
+			}
+		})
+	}
+}
+
+func Test_contactHandler_ImportVCard(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		UCErr   error
+		want    []string
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:    "success",
+			file:    "BEGIN:VCARD\nVERSION:3.0\nFN:Jane Smith\nTEL:555-555-5678\nEND:VCARD\nBEGIN:VCARD\nVERSION:3.0\nFN:No Phone\nEND:VCARD\n",
+			UCErr:   nil,
+			want:    []string{"Card 1 Jane Smith: berhasil dengan id 1", "Card 2 No Phone: gagal", "Berhasil import 1 dari 2 contact"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid on usecase",
+			file:    "BEGIN:VCARD\nVERSION:3.0\nFN:Jane Smith\nTEL:555-555-5678\nEND:VCARD\n",
+			UCErr:   assert.AnError,
+			want:    []string{"Card 1 Jane Smith: gagal, " + assert.AnError.Error(), "Berhasil import 0 dari 1 contact"},
+			wantErr: true,
+		},
+		{
+			name:    "file not found",
+			UCErr:   assert.AnError,
+			want:    []string{"no such file or directory"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/contacts.vcf"
+			if tt.file != "" {
+				assert.NoError(t, os.WriteFile(path, []byte(tt.file), 0o644))
+			}
+
+			reader := strings.NewReader(fmt.Sprintf("%s\n", path))
+			inputReader := input.NewInputReader(reader)
+
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			if !tt.wantErr || strings.Contains(tt.name, "usecase") {
+				var UCResult *model.Contact
+				if tt.UCErr == nil {
+					UCResult = &model.Contact{ID: 1, Name: "Jane Smith", NoTelp: "555-555-5678"}
+				}
+				mockContactUC.On("Add", mock.Anything, &model.ContactRequest{Name: "Jane Smith", NoTelp: "555-555-5678"}).Return(UCResult, tt.UCErr)
+			}
+
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+			restore, outC := captureStdout()
+			h.ImportVCard()
+			got := restoreStdout(restore, outC)
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.Add error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				for _, want := range tt.want {
+					assert.Contains(t, got, want, "Expected got to contain '%s', but got '%s'", want, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_contactHandler_ExportCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		UCResult []model.Contact
+		UCErr    error
+		want     string
+		wantFile string
+		wantErr  bool
+	}{
+		// TODO: Add test cases.
+		{
+			name: "success",
+			UCResult: []model.Contact{
+				{ID: 1, Name: "jaguar", NoTelp: "999-888-7777"},
+				{ID: 2, Name: "Jane Smith", NoTelp: "555-555-5678", Emails: []model.Email{{Type: model.TypeWork, Address: "jane@example.com"}}},
+			},
+			UCErr:    nil,
+			want:     "Berhasil export contact",
+			wantFile: "2,Jane Smith,555-555-5678,,work:jane@example.com,",
+			wantErr:  false,
+		},
+		{
+			name:    "invalid on usecase",
+			UCErr:   assert.AnError,
+			want:    assert.AnError.Error(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/contacts.csv"
+			reader := strings.NewReader(fmt.Sprintf("%s\n", path))
+			inputReader := input.NewInputReader(reader)
+
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			query := &model.ContactQuery{Limit: model.MaxContactLimit}
+			mockContactUC.On("List", mock.Anything, query).Return(tt.UCResult, int64(len(tt.UCResult)), tt.UCErr)
+
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+			restore, outC := captureStdout()
+			h.ExportCSV()
+			got := restoreStdout(restore, outC)
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.List error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				assert.Contains(t, got, tt.want, "Expected got to contain '%s', but got '%s'", tt.want, got)
+			}
+
+			if !tt.wantErr {
+				file, err := os.ReadFile(path)
+				assert.NoError(t, err)
+				assert.Contains(t, string(file), tt.wantFile)
+			}
+		})
+	}
+}
+
+func Test_contactHandler_ImportCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		mapping string
+		confirm string
+		UCErr   error
+		want    []string
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:    "success",
+			file:    "Nama,HP\nJane Smith,555-555-5678\n,555-555-0000\n",
+			mapping: "Nama:name, HP:no_telp",
+			confirm: "y",
+			UCErr:   nil,
+			want:    []string{"Baris 2 Jane Smith: create", "Baris 3 : reject, " + apperrors.ErrContactNameNotValid, "Create: 1, Update: 0, Reject: 1"},
+			wantErr: false,
+		},
+		{
+			name:    "cancelled",
+			file:    "name,no_telp\nJane Smith,555-555-5678\n",
+			confirm: "n",
+			UCErr:   nil,
+			want:    []string{"Baris 2 Jane Smith: create", "Import dibatalkan"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid mapping",
+			file:    "name,no_telp\nJane Smith,555-555-5678\n",
+			mapping: "name",
+			UCErr:   assert.AnError,
+			want:    []string{apperrors.ErrCSVMappingNotValid},
+			wantErr: true,
+		},
+		{
+			name:    "invalid on usecase",
+			file:    "name,no_telp\nJane Smith,555-555-5678\n",
+			UCErr:   assert.AnError,
+			want:    []string{assert.AnError.Error(), "Create: 0, Update: 0, Reject: 0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/contacts.csv"
+			assert.NoError(t, os.WriteFile(path, []byte(tt.file), 0o644))
+
+			reader := strings.NewReader(fmt.Sprintf("%s\n%s\n%s\n", path, tt.mapping, tt.confirm))
+			inputReader := input.NewInputReader(reader)
+
+			mockContactUC := mocks.NewContactUsecase(t)
+
+			jane := &model.ContactRequest{Name: "Jane Smith", NoTelp: "555-555-5678"}
+			if !tt.wantErr {
+				mockContactUC.On("FindByPhone", mock.Anything, jane.NoTelp).Return(nil, apperrors.NewAppError(apperrors.ErrContactNotFound))
+				mockContactUC.On("Validate", mock.Anything, jane).Return(nil).Once()
+			} else if strings.Contains(tt.name, "usecase") {
+				mockContactUC.On("FindByPhone", mock.Anything, jane.NoTelp).Return(nil, tt.UCErr)
+			}
+			if tt.confirm == "y" {
+				mockContactUC.On("Add", mock.Anything, jane).Return(&model.Contact{ID: 1, Name: jane.Name, NoTelp: jane.NoTelp}, nil).Once()
+			}
+
+			h := NewContactHandler(mockContactUC, inputReader, tenant.Default)
+
+			restore, outC := captureStdout()
+			h.ImportCSV()
+			got := restoreStdout(restore, outC)
+
+			if assert.Equal(t, tt.wantErr, tt.UCErr != nil, "ContactUsecase.Add error = %v, wantErr %v", tt.UCErr, tt.wantErr) {
+				for _, want := range tt.want {
+					assert.Contains(t, got, want, "Expected got to contain '%s', but got '%s'", want, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_contactHandler_Duplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		UCResult [][]model.Contact
+		UCErr    error
+		want     []string
+	}{
+		{
+			name: "success",
+			UCResult: [][]model.Contact{
+				{{ID: 1, Name: "Jane Smith", NoTelp: "+15555551234"}, {ID: 4, Name: "Smith Jane", NoTelp: "+15555559876"}},
+			},
+			want: []string{"Grup 1", "| 1\t\t| Jane Smith", "| 4\t\t| Smith Jane"},
+		},
+		{
+			name:     "no duplicates",
+			UCResult: [][]model.Contact{},
+			want:     []string{"Tidak ada contact duplikat"},
+		},
+		{
+			name:  "invalid on usecase",
+			UCErr: assert.AnError,
+			want:  []string{assert.AnError.Error()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			mockContactUC.On("Duplicates", mock.Anything).Return(tt.UCResult, tt.UCErr)
+
+			h := NewContactHandler(mockContactUC, input.NewInputReader(strings.NewReader("")), tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Duplicates()
+			got := restoreStdout(restore, outC)
+
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+		})
+	}
+}
+
+func Test_contactHandler_Trash(t *testing.T) {
+	tests := []struct {
+		name     string
+		UCResult []model.Contact
+		UCErr    error
+		want     []string
+	}{
+		{
+			name:     "success",
+			UCResult: []model.Contact{{ID: 3, Name: "Jane Smith", NoTelp: "+15555551234"}},
+			want:     []string{"| 3\t\t| Jane Smith"},
+		},
+		{
+			name:     "empty trash",
+			UCResult: []model.Contact{},
+			want:     []string{"Trash kosong"},
+		},
+		{
+			name:  "invalid on usecase",
+			UCErr: assert.AnError,
+			want:  []string{assert.AnError.Error()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			mockContactUC.On("List", mock.Anything, &model.ContactQuery{Deleted: true}).Return(tt.UCResult, int64(len(tt.UCResult)), tt.UCErr)
+
+			h := NewContactHandler(mockContactUC, input.NewInputReader(strings.NewReader("")), tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Trash()
+			got := restoreStdout(restore, outC)
+
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+		})
+	}
+}
+
+func Test_contactHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantID   int64
+		UCResult *model.Contact
+		UCErr    error
+		want     string
+	}{
+		{
+			name:     "success",
+			input:    "3\n",
+			wantID:   3,
+			UCResult: &model.Contact{ID: 3, Name: "Jane Smith"},
+			want:     "Berhasil restore contact with id 3",
+		},
+		{
+			name:  "invalid id",
+			input: "x\n",
+			want:  "ID yang dimasukkan tidak valid",
+		},
+		{
+			name:   "not in trash",
+			input:  "4\n",
+			wantID: 4,
+			UCErr:  apperrors.NewAppError(apperrors.ErrContactNotFound),
+			want:   apperrors.ErrContactNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.wantID != 0 {
+				mockContactUC.On("Restore", mock.Anything, tt.wantID).Return(tt.UCResult, tt.UCErr)
+			}
+			mockContactUC.On("History", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
+
+			h := NewContactHandler(mockContactUC, input.NewInputReader(strings.NewReader(tt.input)), tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Restore()
+			got := restoreStdout(restore, outC)
+
+			assert.Contains(t, got, tt.want)
+		})
+	}
+}
+
+func Test_contactHandler_Purge(t *testing.T) {
+	tests := []struct {
+		name     string
+		UCResult int64
+		UCErr    error
+		want     string
+	}{
+		{
+			name:     "success",
+			UCResult: 2,
+			want:     "Berhasil hapus permanen 2 contact dari trash",
+		},
+		{
+			name:  "invalid on usecase",
+			UCErr: assert.AnError,
+			want:  assert.AnError.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			mockContactUC.On("Purge", mock.Anything).Return(tt.UCResult, tt.UCErr)
+
+			h := NewContactHandler(mockContactUC, input.NewInputReader(strings.NewReader("")), tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Purge()
+			got := restoreStdout(restore, outC)
+
+			assert.Contains(t, got, tt.want)
+		})
+	}
+}
+
+func Test_contactHandler_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantIDs  []int64
+		UCResult *model.Contact
+		UCErr    error
+		want     string
+	}{
+		{
+			name:     "success",
+			input:    "1\n4, 7\n",
+			wantIDs:  []int64{1, 4, 7},
+			UCResult: &model.Contact{ID: 1},
+			want:     "Berhasil gabung contact ke id 1",
+		},
+		{
+			name:  "invalid id",
+			input: "abc\n",
+			want:  "ID yang dimasukkan tidak valid",
+		},
+		{
+			name:  "invalid merged id",
+			input: "1\n4, x\n",
+			want:  "ID yang dimasukkan tidak valid",
+		},
+		{
+			name:    "invalid on usecase",
+			input:   "1\n99\n",
+			wantIDs: []int64{1, 99},
+			UCErr:   apperrors.NewAppError(apperrors.ErrContactNotFound),
+			want:    apperrors.ErrContactNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.wantIDs != nil {
+				mockContactUC.On("Merge", mock.Anything, tt.wantIDs).Return(tt.UCResult, tt.UCErr)
+			}
+
+			h := NewContactHandler(mockContactUC, input.NewInputReader(strings.NewReader(tt.input)), tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Merge()
+			got := restoreStdout(restore, outC)
+
+			assert.Contains(t, got, tt.want)
+		})
+	}
+}
+
+func Test_contactHandler_Undo(t *testing.T) {
+	mockContactUC := mocks.NewContactUsecase(t)
+	mockContactUC.On("Delete", mock.Anything, int64(1), int64(0)).Return(nil).Once()
+	mockContactUC.On("History", mock.Anything, int64(1), &model.AuditQuery{Limit: 1}).Return([]model.AuditEntry{{ID: 4, ContactID: 1}}, int64(3), nil).Once()
+	mockContactUC.On("Restore", mock.Anything, int64(2)).Return(&model.Contact{ID: 2}, nil).Once()
+	mockContactUC.On("History", mock.Anything, int64(2), &model.AuditQuery{Limit: 1}).Return([]model.AuditEntry{{ID: 5, ContactID: 2}}, int64(2), nil).Once()
+	mockContactUC.On("Undo", mock.Anything, int64(5)).Return(nil, nil).Once()
+	mockContactUC.On("Undo", mock.Anything, int64(4)).Return(&model.Contact{ID: 1}, nil).Once()
+
+	h := NewContactHandler(mockContactUC, input.NewInputReader(strings.NewReader("1\n2\n3\n2\n")), tenant.Default)
+
+	restore, outC := captureStdout()
+	h.Delete()
+	h.Restore()
+	h.Undo()
+	h.Undo()
+	h.Undo()
+	got := restoreStdout(restore, outC)
+
+	assert.Contains(t, got, "Jumlah yang dimasukkan tidak valid")
+	assert.Contains(t, got, "Berhasil undo, contact dihapus")
+	assert.Contains(t, got, "Berhasil undo contact with id 1")
+	assert.Contains(t, got, "Belum ada perubahan untuk di-undo")
+}
+
+func Test_contactHandler_Revert(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantRevision int64
+		UCResult     *model.Contact
+		UCErr        error
+		want         string
+	}{
+		{
+			name:         "success",
+			input:        "1\n4\n",
+			wantRevision: 4,
+			UCResult:     &model.Contact{ID: 1},
+			want:         "Berhasil revert contact with id 1",
+		},
+		{
+			name:         "reverted to deleted",
+			input:        "1\n5\n",
+			wantRevision: 5,
+			want:         "Berhasil revert, contact with id 1 dihapus",
+		},
+		{
+			name:  "invalid revision",
+			input: "1\nx\n",
+			want:  "Revision yang dimasukkan tidak valid",
+		},
+		{
+			name:         "revision not found",
+			input:        "1\n9\n",
+			wantRevision: 9,
+			UCErr:        apperrors.NewAppError(apperrors.ErrRevisionNotFound),
+			want:         apperrors.ErrRevisionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactUC := mocks.NewContactUsecase(t)
+			if tt.wantRevision != 0 {
+				mockContactUC.On("Revert", mock.Anything, int64(1), tt.wantRevision).Return(tt.UCResult, tt.UCErr)
+			}
+			mockContactUC.On("History", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), nil).Maybe()
+
+			h := NewContactHandler(mockContactUC, input.NewInputReader(strings.NewReader(tt.input)), tenant.Default)
+
+			restore, outC := captureStdout()
+			h.Revert()
+			got := restoreStdout(restore, outC)
+
+			assert.Contains(t, got, tt.want)
+		})
+	}
+}
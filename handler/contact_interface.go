@@ -7,4 +7,16 @@ type ContactHandler interface {
 	Detail()
 	Update()
 	Delete()
+	Trash()
+	Restore()
+	Purge()
+	Search()
+	ExportVCard()
+	ImportVCard()
+	ExportCSV()
+	ImportCSV()
+	Duplicates()
+	Merge()
+	Undo()
+	Revert()
 }
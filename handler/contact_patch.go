@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"encoding/json"
+	"io"
+	"mime"
+)
+
+// mergePatchType is the media type of an RFC 7396 JSON Merge Patch.
+// Plain JSON is read as one too.
+const mergePatchType = "application/merge-patch+json"
+
+// mergePatch reads an RFC 7396 merge patch of a contact, returning the
+// values it sets and the model.ContactFields it names. A contact holds
+// no nested objects, so each member replaces a field whole, and null
+// clears it. The patch may carry the version it expects in "version".
+func mergePatch(contentType string, body io.Reader) (*model.ContactRequest, []string, error) {
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchType && mediaType != "application/json") {
+			return nil, nil, apperrors.NewAppError(apperrors.ErrPatchTypeNotSupported)
+		}
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return nil, nil, apperrors.NewAppError(apperrors.ErrContactPatchNotValid)
+	}
+
+	var fields []string
+	for _, field := range model.ContactFields {
+		if _, ok := members[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+
+	known := len(fields)
+	if _, ok := members["version"]; ok {
+		known++
+	}
+	//* read-only or unknown members are refused rather than dropped
+	if len(fields) == 0 || known != len(members) {
+		return nil, nil, apperrors.NewAppError(apperrors.ErrContactPatchNotValid)
+	}
+
+	req := new(model.ContactRequest)
+	if err := json.Unmarshal(data, req); err != nil {
+		return nil, nil, apperrors.NewAppError(apperrors.ErrContactPatchNotValid)
+	}
+
+	return req, fields, nil
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/vcard"
+	"contact-go/model"
+	"contact-go/usecase"
+	"context"
+	"errors"
+	"io"
+)
+
+func writeVCards(w io.Writer, contacts []model.Contact, version string) error {
+	encoder := vcard.NewEncoder(w, version)
+	for i := range contacts {
+		if err := encoder.Encode(&contacts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeVCardPages writes page and every page after it as cards.
+func writeVCardPages(ctx context.Context, w io.Writer, pager *contactPager, page []model.Contact, version string) error {
+	encoder := vcard.NewEncoder(w, version)
+	for page != nil {
+		for i := range page {
+			if err := encoder.Encode(&page[i]); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if page, err = pager.Next(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newContactRequest(contact *model.Contact) *model.ContactRequest {
+	return &model.ContactRequest{
+		Name:      contact.Name,
+		NoTelp:    contact.NoTelp,
+		Phones:    contact.Phones,
+		Emails:    contact.Emails,
+		Addresses: contact.Addresses,
+		Company:   contact.Company,
+		JobTitle:  contact.JobTitle,
+		Birthday:  contact.Birthday,
+		Notes:     contact.Notes,
+	}
+}
+
+// importVCards adds a contact for every card read from r, carrying on
+// past the cards that are malformed or rejected. It only stops early when
+// r fails, ctx is done or r has more than maxCards cards, zero leaving
+// them unbounded, returning the results so far along with why.
+func importVCards(ctx context.Context, contactUC usecase.ContactUsecase, r io.Reader, maxCards int) ([]model.ContactImportResult, error) {
+	results := []model.ContactImportResult{}
+	decoder := vcard.NewDecoder(r)
+
+	for card := 1; ; card++ {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		contact, err := decoder.Decode()
+		if err == io.EOF {
+			return results, nil
+		}
+		if maxCards > 0 && card > maxCards {
+			return results, apperrors.NewAppError(apperrors.ErrImportTooLarge)
+		}
+
+		result := model.ContactImportResult{Card: card}
+
+		var parseErr *vcard.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			result.Error = err.Error()
+		case err != nil:
+			return results, err
+		case contact.NoTelp == "":
+			result.Name = contact.Name
+			result.Error = apperrors.ErrContactNoTelpNotValid
+		default:
+			result.Name = contact.Name
+			added, err := contactUC.Add(ctx, newContactRequest(contact))
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.ID = added.ID
+			}
+		}
+
+		results = append(results, result)
+	}
+}
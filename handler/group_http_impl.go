@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/response"
+	"contact-go/model"
+	"contact-go/usecase"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type groupHTTPHandler struct {
+	GroupUC usecase.GroupUsecase
+}
+
+func NewGroupHTTPHandler(groupUC usecase.GroupUsecase) GroupHTTPHandler {
+	return &groupHTTPHandler{
+		GroupUC: groupUC,
+	}
+}
+
+// groupID reads the id out of a path of /groups/{id}, followed by suffix.
+func groupID(r *http.Request, suffix string) (int64, error) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/groups/"), suffix)
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, apperrors.NewAppError(apperrors.ErrGroupIdNotValid)
+	}
+	return id, nil
+}
+
+func (handler *groupHTTPHandler) List(w http.ResponseWriter, r *http.Request) {
+	groups, err := handler.GroupUC.List(r.Context())
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", groups); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *groupHTTPHandler) Add(w http.ResponseWriter, r *http.Request) {
+	var groupRequest model.GroupRequest
+	err := json.NewDecoder(r.Body).Decode(&groupRequest)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	group, err := handler.GroupUC.Add(r.Context(), &groupRequest)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusCreated, "Created", group); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *groupHTTPHandler) Detail(w http.ResponseWriter, r *http.Request) {
+	id, err := groupID(r, "")
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	group, err := handler.GroupUC.Detail(r.Context(), id)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", group); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *groupHTTPHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := groupID(r, "")
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var groupRequest model.GroupRequest
+	err = json.NewDecoder(r.Body).Decode(&groupRequest)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	group, err := handler.GroupUC.Update(r.Context(), id, &groupRequest)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", group); err != nil {
+		panic(err)
+	}
+}
+
+// Delete removes the group, leaving its contacts as they are.
+func (handler *groupHTTPHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := groupID(r, "")
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	err = handler.GroupUC.Delete(r.Context(), id)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", nil); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *groupHTTPHandler) Members(w http.ResponseWriter, r *http.Request) {
+	id, err := groupID(r, "/members")
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	contacts, err := handler.GroupUC.Members(r.Context(), id)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contacts); err != nil {
+		panic(err)
+	}
+}
+
+// AddMembers puts the contacts named in the body in the group.
+func (handler *groupHTTPHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	handler.changeMembers(w, r, handler.GroupUC.AddMembers)
+}
+
+// RemoveMembers takes the contacts named in the body out of the group.
+func (handler *groupHTTPHandler) RemoveMembers(w http.ResponseWriter, r *http.Request) {
+	handler.changeMembers(w, r, handler.GroupUC.RemoveMembers)
+}
+
+func (handler *groupHTTPHandler) changeMembers(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, id int64, contactIDs []int64) error) {
+	id, err := groupID(r, "/members")
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var membersRequest model.GroupMembersRequest
+	err = json.NewDecoder(r.Body).Decode(&membersRequest)
+	if err != nil {
+		_ = response.NewJsonResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	err = change(r.Context(), id, membersRequest.ContactIDs)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	contacts, err := handler.GroupUC.Members(r.Context(), id)
+	if err != nil {
+		code, message := apperrors.HandleAppError(err)
+		_ = response.NewJsonResponse(w, code, message, nil)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", contacts); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/mocks"
+	"contact-go/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_groupHTTPHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		handler    func(GroupHTTPHandler) http.HandlerFunc
+		beforeTest func(*mocks.GroupUsecase)
+		wantStatus int
+	}{
+		{
+			name:    "list",
+			method:  "GET",
+			url:     "http://localhost:8080/groups",
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.List },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("List", mock.Anything).Return([]model.Group{{ID: 1, Name: "team"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "add",
+			method:  "POST",
+			url:     "http://localhost:8080/groups",
+			body:    `{"name": "team"}`,
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.Add },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Add", mock.Anything, &model.GroupRequest{Name: "team"}).Return(&model.Group{ID: 1, Name: "team"}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "add duplicate",
+			method:  "POST",
+			url:     "http://localhost:8080/groups",
+			body:    `{"name": "team"}`,
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.Add },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Add", mock.Anything, &model.GroupRequest{Name: "team"}).Return(nil, apperrors.NewAppError(apperrors.ErrGroupDuplicate))
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "add malformed body",
+			method:     "POST",
+			url:        "http://localhost:8080/groups",
+			body:       `{"name": `,
+			handler:    func(h GroupHTTPHandler) http.HandlerFunc { return h.Add },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "detail missing",
+			method:  "GET",
+			url:     "http://localhost:8080/groups/9",
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.Detail },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Detail", mock.Anything, int64(9)).Return(nil, apperrors.NewAppError(apperrors.ErrGroupNotFound))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "detail invalid id",
+			method:     "GET",
+			url:        "http://localhost:8080/groups/abc",
+			handler:    func(h GroupHTTPHandler) http.HandlerFunc { return h.Detail },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "update",
+			method:  "PUT",
+			url:     "http://localhost:8080/groups/1",
+			body:    `{"name": "friends", "description": "close ones"}`,
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.Update },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Update", mock.Anything, int64(1), &model.GroupRequest{Name: "friends", Description: "close ones"}).
+					Return(&model.Group{ID: 1, Name: "friends", Description: "close ones"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "delete",
+			method:  "DELETE",
+			url:     "http://localhost:8080/groups/1",
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.Delete },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Delete", mock.Anything, int64(1)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "members",
+			method:  "GET",
+			url:     "http://localhost:8080/groups/1/members",
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.Members },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Members", mock.Anything, int64(1)).Return([]model.Contact{{ID: 2, Name: "jane"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "add members",
+			method:  "POST",
+			url:     "http://localhost:8080/groups/1/members",
+			body:    `{"contact_ids": [2, 3]}`,
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.AddMembers },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("AddMembers", mock.Anything, int64(1), []int64{2, 3}).Return(nil)
+				uc.On("Members", mock.Anything, int64(1)).Return([]model.Contact{{ID: 2}, {ID: 3}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "add missing contact",
+			method:  "POST",
+			url:     "http://localhost:8080/groups/1/members",
+			body:    `{"contact_ids": [9]}`,
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.AddMembers },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("AddMembers", mock.Anything, int64(1), []int64{9}).Return(apperrors.NewAppError(apperrors.ErrContactNotFound))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "remove members",
+			method:  "DELETE",
+			url:     "http://localhost:8080/groups/1/members",
+			body:    `{"contact_ids": [2]}`,
+			handler: func(h GroupHTTPHandler) http.HandlerFunc { return h.RemoveMembers },
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("RemoveMembers", mock.Anything, int64(1), []int64{2}).Return(nil)
+				uc.On("Members", mock.Anything, int64(1)).Return([]model.Contact{{ID: 3}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "remove members invalid id",
+			method:     "DELETE",
+			url:        "http://localhost:8080/groups/0/members",
+			body:       `{"contact_ids": [2]}`,
+			handler:    func(h GroupHTTPHandler) http.HandlerFunc { return h.RemoveMembers },
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGroupUC := mocks.NewGroupUsecase(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mockGroupUC)
+			}
+
+			h := NewGroupHTTPHandler(mockGroupUC)
+
+			m := useMiddleware(tt.handler(h))
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+
+			m.ServeHTTP(recorder, req)
+
+			got := recorder.Result().StatusCode
+			assert.Equal(t, tt.wantStatus, got, "GroupHTTPHandler handler returned wrong status code: = %v, want %v", got, tt.wantStatus)
+		})
+	}
+}
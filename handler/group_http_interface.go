@@ -0,0 +1,14 @@
+package handler
+
+import "net/http"
+
+type GroupHTTPHandler interface {
+	List(w http.ResponseWriter, r *http.Request)
+	Add(w http.ResponseWriter, r *http.Request)
+	Detail(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Members(w http.ResponseWriter, r *http.Request)
+	AddMembers(w http.ResponseWriter, r *http.Request)
+	RemoveMembers(w http.ResponseWriter, r *http.Request)
+}
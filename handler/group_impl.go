@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"contact-go/helper"
+	"contact-go/helper/actor"
+	"contact-go/helper/input"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"contact-go/usecase"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+type groupHandler struct {
+	GroupUC usecase.GroupUsecase
+	Input   *input.InputReader
+	// AddressBook is the address book the menu works on.
+	AddressBook string
+}
+
+func NewGroupHandler(groupUC usecase.GroupUsecase, input *input.InputReader, addressBook string) GroupHandler {
+	groupHandler := new(groupHandler)
+	groupHandler.GroupUC = groupUC
+	groupHandler.Input = input
+	groupHandler.AddressBook = addressBook
+
+	return groupHandler
+}
+
+// newContext returns the context of one menu operation in the address
+// book of the menu, cancelled when the user interrupts it with Ctrl+C.
+func (handler *groupHandler) newContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(tenant.NewContext(actor.NewContext(context.Background(), actor.CLI), handler.AddressBook), os.Interrupt)
+}
+
+// scanID prompts for the id of a group.
+func (handler *groupHandler) scanID() (int64, bool) {
+	fmt.Print("Group ID = ")
+	idStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(idStr), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return 0, false
+	}
+	return id, true
+}
+
+// scanRequest prompts for the name and description of a group.
+func (handler *groupHandler) scanRequest() (*model.GroupRequest, bool) {
+	fmt.Print("Name = ")
+	name, err := handler.Input.Scan()
+	if err != nil || strings.TrimSpace(name) == "" {
+		fmt.Println("Name yang dimasukkan tidak valid")
+		return nil, false
+	}
+
+	fmt.Print("Deskripsi = ")
+	description, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("Deskripsi yang dimasukkan tidak valid")
+		return nil, false
+	}
+
+	return &model.GroupRequest{Name: name, Description: description}, true
+}
+
+// scanContactIDs prompts for the contacts to add to or remove from a group.
+func (handler *groupHandler) scanContactIDs() ([]int64, bool) {
+	fmt.Print("ID contact (pisahkan dengan koma) = ")
+	idsStr, err := handler.Input.Scan()
+	if err != nil {
+		fmt.Println("ID yang dimasukkan tidak valid")
+		return nil, false
+	}
+
+	var ids []int64
+	for _, part := range strings.Split(idsStr, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			fmt.Println("ID yang dimasukkan tidak valid")
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+func (handler *groupHandler) List() {
+	_ = helper.ClearTerminal()
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	groups, err := handler.GroupUC.List(ctx)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("Belum ada group")
+		return
+	}
+
+	printGroupTable(groups)
+}
+
+func (handler *groupHandler) Add() {
+	_ = helper.ClearTerminal()
+
+	groupRequest, ok := handler.scanRequest()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	group, err := handler.GroupUC.Add(ctx, groupRequest)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		fmt.Println("Berhasil add group with id", group.ID)
+	}
+}
+
+// Detail shows the group along with the contacts in it.
+func (handler *groupHandler) Detail() {
+	_ = helper.ClearTerminal()
+
+	id, ok := handler.scanID()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	group, err := handler.GroupUC.Detail(ctx, id)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Printf("ID : \t\t%d\nNama : \t\t%s\n", group.ID, group.Name)
+	if group.Description != "" {
+		fmt.Printf("Deskripsi : \t%s\n", group.Description)
+	}
+
+	contacts, err := handler.GroupUC.Members(ctx, id)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if len(contacts) == 0 {
+		fmt.Println("Group belum punya anggota")
+		return
+	}
+
+	printContactTable(contacts)
+}
+
+func (handler *groupHandler) Update() {
+	_ = helper.ClearTerminal()
+
+	id, ok := handler.scanID()
+	if !ok {
+		return
+	}
+
+	groupRequest, ok := handler.scanRequest()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	group, err := handler.GroupUC.Update(ctx, id, groupRequest)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		fmt.Println("Berhasil update group with id", group.ID)
+	}
+}
+
+func (handler *groupHandler) Delete() {
+	_ = helper.ClearTerminal()
+
+	id, ok := handler.scanID()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	err := handler.GroupUC.Delete(ctx, id)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		fmt.Println("Berhasil delete group with id", id)
+	}
+}
+
+func (handler *groupHandler) AddMembers() {
+	_ = helper.ClearTerminal()
+
+	id, ok := handler.scanID()
+	if !ok {
+		return
+	}
+
+	contactIDs, ok := handler.scanContactIDs()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	err := handler.GroupUC.AddMembers(ctx, id, contactIDs)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		fmt.Println("Berhasil tambah contact ke group with id", id)
+	}
+}
+
+func (handler *groupHandler) RemoveMembers() {
+	_ = helper.ClearTerminal()
+
+	id, ok := handler.scanID()
+	if !ok {
+		return
+	}
+
+	contactIDs, ok := handler.scanContactIDs()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := handler.newContext()
+	defer cancel()
+
+	err := handler.GroupUC.RemoveMembers(ctx, id, contactIDs)
+	if err != nil {
+		fmt.Println(err.Error())
+	} else {
+		fmt.Println("Berhasil keluarkan contact dari group with id", id)
+	}
+}
+
+func printGroupTable(groups []model.Group) {
+	fmt.Printf("|---------------|-----------------------|-----------------------|\n")
+	fmt.Printf("| ID\t\t| Nama\t\t\t| Deskripsi\t\t|\n")
+	fmt.Printf("|---------------|-----------------------|-----------------------|\n")
+
+	for _, v := range groups {
+		fmt.Printf("| %d\t\t| %s\t\t| %s\t\t|\n", v.ID, v.Name, v.Description)
+	}
+	fmt.Printf("|---------------|-----------------------|-----------------------|\n")
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/input"
+	"contact-go/helper/tenant"
+	"contact-go/mocks"
+	"contact-go/model"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_groupHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		run        func(GroupHandler)
+		beforeTest func(*mocks.GroupUsecase)
+		want       string
+	}{
+		{
+			name: "list",
+			run:  GroupHandler.List,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("List", mock.Anything).Return([]model.Group{{ID: 1, Name: "team", Description: "on-call"}}, nil)
+			},
+			want: "| 1\t\t| team",
+		},
+		{
+			name: "list empty",
+			run:  GroupHandler.List,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("List", mock.Anything).Return(nil, nil)
+			},
+			want: "Belum ada group",
+		},
+		{
+			name:  "add",
+			input: "team\non-call\n",
+			run:   GroupHandler.Add,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Add", mock.Anything, &model.GroupRequest{Name: "team", Description: "on-call"}).Return(&model.Group{ID: 1, Name: "team"}, nil)
+			},
+			want: "Berhasil add group with id 1",
+		},
+		{
+			name:  "add duplicate",
+			input: "team\n\n",
+			run:   GroupHandler.Add,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Add", mock.Anything, &model.GroupRequest{Name: "team"}).Return(nil, apperrors.NewAppError(apperrors.ErrGroupDuplicate))
+			},
+			want: apperrors.ErrGroupDuplicate,
+		},
+		{
+			name:  "detail",
+			input: "1\n",
+			run:   GroupHandler.Detail,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Detail", mock.Anything, int64(1)).Return(&model.Group{ID: 1, Name: "team"}, nil)
+				uc.On("Members", mock.Anything, int64(1)).Return([]model.Contact{{ID: 2, Name: "jane", NoTelp: "555"}}, nil)
+			},
+			want: "| 2\t\t| jane",
+		},
+		{
+			name:  "detail invalid id",
+			input: "abc\n",
+			run:   GroupHandler.Detail,
+			want:  "ID yang dimasukkan tidak valid",
+		},
+		{
+			name:  "update",
+			input: "1\nfriends\n\n",
+			run:   GroupHandler.Update,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Update", mock.Anything, int64(1), &model.GroupRequest{Name: "friends"}).Return(&model.Group{ID: 1, Name: "friends"}, nil)
+			},
+			want: "Berhasil update group with id 1",
+		},
+		{
+			name:  "delete",
+			input: "1\n",
+			run:   GroupHandler.Delete,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("Delete", mock.Anything, int64(1)).Return(nil)
+			},
+			want: "Berhasil delete group with id 1",
+		},
+		{
+			name:  "add members",
+			input: "1\n2, 3\n",
+			run:   GroupHandler.AddMembers,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("AddMembers", mock.Anything, int64(1), []int64{2, 3}).Return(nil)
+			},
+			want: "Berhasil tambah contact ke group with id 1",
+		},
+		{
+			name:  "add members invalid ids",
+			input: "1\n2, x\n",
+			run:   GroupHandler.AddMembers,
+			want:  "ID yang dimasukkan tidak valid",
+		},
+		{
+			name:  "remove members",
+			input: "1\n2\n",
+			run:   GroupHandler.RemoveMembers,
+			beforeTest: func(uc *mocks.GroupUsecase) {
+				uc.On("RemoveMembers", mock.Anything, int64(1), []int64{2}).Return(nil)
+			},
+			want: "Berhasil keluarkan contact dari group with id 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputReader := input.NewInputReader(strings.NewReader(tt.input))
+
+			mockGroupUC := mocks.NewGroupUsecase(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mockGroupUC)
+			}
+
+			h := NewGroupHandler(mockGroupUC, inputReader, tenant.Default)
+
+			restore, outC := captureStdout()
+			tt.run(h)
+			got := restoreStdout(restore, outC)
+
+			assert.Contains(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+//go:generate mockery --output=../mocks --name GroupHandler
+package handler
+
+type GroupHandler interface {
+	List()
+	Add()
+	Detail()
+	Update()
+	Delete()
+	AddMembers()
+	RemoveMembers()
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"contact-go/helper/health"
+	"contact-go/helper/response"
+	"contact-go/helper/version"
+	"net/http"
+	"time"
+)
+
+type healthHTTPHandler struct {
+	Checks  []health.Check
+	Timeout time.Duration
+	Info    version.Info
+}
+
+// NewHealthHTTPHandler answers probes with checks of the storage, each
+// given up to timeout, and tells which build info is.
+func NewHealthHTTPHandler(checks []health.Check, timeout time.Duration, info version.Info) HealthHTTPHandler {
+	return &healthHTTPHandler{
+		Checks:  checks,
+		Timeout: timeout,
+		Info:    info,
+	}
+}
+
+// Healthz answers as long as the process is alive to.
+func (handler *healthHTTPHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", nil); err != nil {
+		panic(err)
+	}
+}
+
+// Readyz answers 503 while any check of the storage fails.
+func (handler *healthHTTPHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	results, passed := health.Run(r.Context(), handler.Checks, handler.Timeout)
+	if !passed {
+		_ = response.NewJsonResponse(w, http.StatusServiceUnavailable, "storage not ready", results)
+		return
+	}
+
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", results); err != nil {
+		panic(err)
+	}
+}
+
+func (handler *healthHTTPHandler) Version(w http.ResponseWriter, r *http.Request) {
+	if err := response.NewJsonResponse(w, http.StatusOK, "OK", handler.Info); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"contact-go/helper/health"
+	"contact-go/helper/response"
+	"contact-go/helper/version"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_healthHTTPHandler(t *testing.T) {
+	up := health.Check{Name: "database", Run: func(context.Context) error { return nil }}
+	down := health.Check{Name: "database", Run: func(context.Context) error { return errors.New("connection refused") }}
+	info := version.Info{Version: "v1.2.0", Commit: "0a4ba5d", GoVersion: "go1.20", Storage: "sql", Driver: "mysql"}
+
+	tests := []struct {
+		name       string
+		checks     []health.Check
+		handler    func(HealthHTTPHandler) http.HandlerFunc
+		wantStatus int
+		wantData   interface{}
+	}{
+		{
+			name:       "healthz",
+			checks:     []health.Check{down},
+			handler:    func(h HealthHTTPHandler) http.HandlerFunc { return h.Healthz },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "readyz",
+			checks:     []health.Check{up},
+			handler:    func(h HealthHTTPHandler) http.HandlerFunc { return h.Readyz },
+			wantStatus: http.StatusOK,
+			wantData:   []interface{}{map[string]interface{}{"name": "database", "status": "ok"}},
+		},
+		{
+			name:       "readyz without checks",
+			handler:    func(h HealthHTTPHandler) http.HandlerFunc { return h.Readyz },
+			wantStatus: http.StatusOK,
+			wantData:   []interface{}{},
+		},
+		{
+			name:       "readyz with the storage down",
+			checks:     []health.Check{down},
+			handler:    func(h HealthHTTPHandler) http.HandlerFunc { return h.Readyz },
+			wantStatus: http.StatusServiceUnavailable,
+			wantData:   []interface{}{map[string]interface{}{"name": "database", "status": "fail", "error": "connection refused"}},
+		},
+		{
+			name:       "version",
+			handler:    func(h HealthHTTPHandler) http.HandlerFunc { return h.Version },
+			wantStatus: http.StatusOK,
+			wantData: map[string]interface{}{
+				"version":    "v1.2.0",
+				"commit":     "0a4ba5d",
+				"go_version": "go1.20",
+				"storage":    "sql",
+				"driver":     "mysql",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHTTPHandler(tt.checks, 0, info)
+
+			w := httptest.NewRecorder()
+			tt.handler(h)(w, httptest.NewRequest("GET", "/", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var got response.JsonResponse
+			if assert.NoError(t, json.NewDecoder(w.Body).Decode(&got)) {
+				assert.Equal(t, tt.wantData, got.Data)
+			}
+		})
+	}
+}
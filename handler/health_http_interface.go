@@ -0,0 +1,9 @@
+package handler
+
+import "net/http"
+
+type HealthHTTPHandler interface {
+	Healthz(w http.ResponseWriter, r *http.Request)
+	Readyz(w http.ResponseWriter, r *http.Request)
+	Version(w http.ResponseWriter, r *http.Request)
+}
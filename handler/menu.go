@@ -9,22 +9,36 @@ import (
 )
 
 type Menu struct {
-	h            ContactHandler
-	i            *input.InputReader
-	clear        func() error
-	showMenuList func()
+	h                 ContactHandler
+	g                 GroupHandler
+	i                 *input.InputReader
+	clear             func() error
+	showMenuList      func()
+	showGroupMenuList func()
 }
 
-func NewMenu(handler ContactHandler, input *input.InputReader, clear func() error, showMenuList func()) *Menu {
+func NewMenu(handler ContactHandler, groupHandler GroupHandler, input *input.InputReader, clear func() error, showMenuList func(), showGroupMenuList func()) *Menu {
 	menu := new(Menu)
 	menu.h = handler
+	menu.g = groupHandler
 	menu.i = input
 	menu.clear = clear
 	menu.showMenuList = showMenuList
+	menu.showGroupMenuList = showGroupMenuList
 
 	return menu
 }
 
+// scanMenu reads the number of the menu picked, 0 for anything else.
+func (m *Menu) scanMenu() (int32, error) {
+	menuStr, _ := m.i.Scan()
+	menu64, err := strconv.ParseInt(strings.TrimSpace(menuStr), 10, 32)
+	if err != nil && !errors.Is(err, strconv.ErrSyntax) {
+		return 0, err
+	}
+	return int32(menu64), nil
+}
+
 func (m *Menu) ShowMenu() error {
 	err := m.clear()
 	if err != nil {
@@ -34,15 +48,13 @@ func (m *Menu) ShowMenu() error {
 	m.showMenuList()
 
 	for {
-		menuStr, _ := m.i.Scan()
-		menu64, err := strconv.ParseInt(strings.TrimSpace(menuStr), 10, 32)
-		if err != nil && !errors.Is(err, strconv.ErrSyntax) {
+		menu, err := m.scanMenu()
+		if err != nil {
 			fmt.Println(err)
 			break
 		}
-		menu := int32(menu64)
 
-		if menu == 6 {
+		if menu == 19 {
 			_ = m.clear()
 			break
 		}
@@ -66,7 +78,92 @@ func (m *Menu) ShowMenu() error {
 		case 5:
 			fmt.Println("Delete a contact")
 			m.h.Delete()
+		case 6:
+			fmt.Println("Search contacts")
+			m.h.Search()
+		case 7:
+			fmt.Println("Export contacts to vCard")
+			m.h.ExportVCard()
+		case 8:
+			fmt.Println("Import contacts from vCard")
+			m.h.ImportVCard()
+		case 9:
+			fmt.Println("Export contacts to CSV")
+			m.h.ExportCSV()
+		case 10:
+			fmt.Println("Import contacts from CSV")
+			m.h.ImportCSV()
+		case 11:
+			fmt.Println("Find duplicate contacts")
+			m.h.Duplicates()
+		case 12:
+			fmt.Println("Merge contacts")
+			m.h.Merge()
+		case 13:
+			fmt.Println("Contact trash")
+			m.h.Trash()
+		case 14:
+			fmt.Println("Restore a contact")
+			m.h.Restore()
+		case 15:
+			fmt.Println("Purge the trash")
+			m.h.Purge()
+		case 16:
+			fmt.Println("Manage groups")
+			if err := m.showGroupMenu(); err != nil {
+				fmt.Println(err)
+				return nil
+			}
+			m.showMenuList()
+		case 17:
+			fmt.Println("Undo changes")
+			m.h.Undo()
+		case 18:
+			fmt.Println("Revert a contact")
+			m.h.Revert()
 		}
 	}
 	return nil
 }
+
+// showGroupMenu runs the group menu until the user goes back.
+func (m *Menu) showGroupMenu() error {
+	m.showGroupMenuList()
+
+	for {
+		menu, err := m.scanMenu()
+		if err != nil {
+			return err
+		}
+
+		if menu == 8 {
+			return nil
+		}
+
+		switch menu {
+		default:
+			m.showGroupMenuList()
+		case 1:
+			fmt.Println("Group list")
+			m.g.List()
+		case 2:
+			fmt.Println("Add a new group")
+			m.g.Add()
+		case 3:
+			fmt.Println("Group detail")
+			m.g.Detail()
+		case 4:
+			fmt.Println("Update a group")
+			m.g.Update()
+		case 5:
+			fmt.Println("Delete a group")
+			m.g.Delete()
+		case 6:
+			fmt.Println("Add contacts to a group")
+			m.g.AddMembers()
+		case 7:
+			fmt.Println("Remove contacts from a group")
+			m.g.RemoveMembers()
+		}
+	}
+}
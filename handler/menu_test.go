@@ -22,42 +22,132 @@ func TestMenu_ShowMenu(t *testing.T) {
 		{
 			name:    "success list",
 			method:  "List",
-			input:   "1\n6",
+			input:   "1\n19",
 			want:    "Contact list",
 			wantErr: false,
 		},
 		{
 			name:    "success add",
 			method:  "Add",
-			input:   "2\n6",
+			input:   "2\n19",
 			want:    "Add a new contact",
 			wantErr: false,
 		},
 		{
 			name:    "success detail",
 			method:  "Detail",
-			input:   "3\n6",
+			input:   "3\n19",
 			want:    "Contact detail",
 			wantErr: false,
 		},
 		{
 			name:    "success update",
 			method:  "Update",
-			input:   "4\n6",
+			input:   "4\n19",
 			want:    "Update a contact",
 			wantErr: false,
 		},
 		{
 			name:    "success delete",
 			method:  "Delete",
-			input:   "5\n6",
+			input:   "5\n19",
 			want:    "Delete a contact",
 			wantErr: false,
 		},
+		{
+			name:    "success search",
+			method:  "Search",
+			input:   "6\n19",
+			want:    "Search contacts",
+			wantErr: false,
+		},
+		{
+			name:    "success export vcard",
+			method:  "ExportVCard",
+			input:   "7\n19",
+			want:    "Export contacts to vCard",
+			wantErr: false,
+		},
+		{
+			name:    "success import vcard",
+			method:  "ImportVCard",
+			input:   "8\n19",
+			want:    "Import contacts from vCard",
+			wantErr: false,
+		},
+		{
+			name:    "success export csv",
+			method:  "ExportCSV",
+			input:   "9\n19",
+			want:    "Export contacts to CSV",
+			wantErr: false,
+		},
+		{
+			name:    "success import csv",
+			method:  "ImportCSV",
+			input:   "10\n19",
+			want:    "Import contacts from CSV",
+			wantErr: false,
+		},
+		{
+			name:    "success duplicates",
+			method:  "Duplicates",
+			input:   "11\n19",
+			want:    "Find duplicate contacts",
+			wantErr: false,
+		},
+		{
+			name:    "success merge",
+			method:  "Merge",
+			input:   "12\n19",
+			want:    "Merge contacts",
+			wantErr: false,
+		},
+		{
+			name:    "success trash",
+			method:  "Trash",
+			input:   "13\n19",
+			want:    "Contact trash",
+			wantErr: false,
+		},
+		{
+			name:    "success restore",
+			method:  "Restore",
+			input:   "14\n19",
+			want:    "Restore a contact",
+			wantErr: false,
+		},
+		{
+			name:    "success purge",
+			method:  "Purge",
+			input:   "15\n19",
+			want:    "Purge the trash",
+			wantErr: false,
+		},
+		{
+			name:    "success undo",
+			method:  "Undo",
+			input:   "17\n19",
+			want:    "Undo changes",
+			wantErr: false,
+		},
+		{
+			name:    "success revert",
+			method:  "Revert",
+			input:   "18\n19",
+			want:    "Revert a contact",
+			wantErr: false,
+		},
+		{
+			name:    "group menu",
+			input:   "16\n8\n19",
+			want:    "Manage groups",
+			wantErr: false,
+		},
 		{
 			name:    "back to menu",
 			method:  "List",
-			input:   "\n6",
+			input:   "\n19",
 			want:    "",
 			wantErr: false,
 		},
@@ -75,7 +165,7 @@ func TestMenu_ShowMenu(t *testing.T) {
 			clear := func() error { return nil }
 			showMenuList := func() {}
 
-			m := NewMenu(mockContactHandler, inputReader, clear, showMenuList)
+			m := NewMenu(mockContactHandler, mocks.NewGroupHandler(t), inputReader, clear, showMenuList, showMenuList)
 
 			restore, outC := captureStdout()
 			err := m.ShowMenu()
@@ -90,3 +180,39 @@ func TestMenu_ShowMenu(t *testing.T) {
 		})
 	}
 }
+
+func TestMenu_showGroupMenu(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		method string
+		want   string
+	}{
+		{name: "list", method: "List", input: "1\n8", want: "Group list"},
+		{name: "add", method: "Add", input: "2\n8", want: "Add a new group"},
+		{name: "detail", method: "Detail", input: "3\n8", want: "Group detail"},
+		{name: "update", method: "Update", input: "4\n8", want: "Update a group"},
+		{name: "delete", method: "Delete", input: "5\n8", want: "Delete a group"},
+		{name: "add members", method: "AddMembers", input: "6\n8", want: "Add contacts to a group"},
+		{name: "remove members", method: "RemoveMembers", input: "7\n8", want: "Remove contacts from a group"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputReader := input.NewInputReader(strings.NewReader(tt.input))
+
+			mockGroupHandler := mocks.NewGroupHandler(t)
+			mockGroupHandler.On(tt.method).Return()
+
+			showMenuList := func() {}
+			m := NewMenu(mocks.NewContactHandler(t), mockGroupHandler, inputReader, func() error { return nil }, showMenuList, showMenuList)
+
+			restore, outC := captureStdout()
+			err := m.showGroupMenu()
+
+			got := restoreStdout(restore, outC)
+
+			assert.NoError(t, err)
+			assert.Contains(t, got, tt.want, "Expected got to contain '%s', but got '%s'", tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Package actor carries who is making a change through a context,
+// for the audit log to record.
+package actor
+
+import "context"
+
+const (
+	// CLI is the actor of every change made from the interactive menu.
+	CLI = "cli"
+	// Anonymous is the actor of a change whose context names none.
+	Anonymous = "anonymous"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying name as its actor.
+func NewContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext returns the actor ctx carries, or Anonymous.
+func FromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(contextKey{}).(string); ok && name != "" {
+		return name
+	}
+	return Anonymous
+}
@@ -1,30 +1,118 @@
 package apperrors
 
 import (
+	"errors"
 	"net/http"
 )
 
 const (
-	ErrPlatformNotSupported  = "your platform is unsupported! i can't clear terminal screen :("
-	ErrDbUrlNotExist         = "database URL not found"
-	ErrEnvNotFound           = ".env file not found"
-	ErrContactNameNotValid   = "name yang dimasukkan tidak valid"
-	ErrContactNoTelpNotValid = "no_telp yang dimasukkan tidak valid"
-	ErrContactIdNotValid     = "contact id yang dimasukkan tidak valid"
-
-	ErrContactNotFound = "contact not found"
+	ErrPlatformNotSupported    = "your platform is unsupported! i can't clear terminal screen :("
+	ErrDbUrlNotExist           = "database URL not found"
+	ErrDbPathNotExist          = "database path not found"
+	ErrDbDialectNotSupported   = "database dialect has no migrations"
+	ErrEnvNotFound             = ".env file not found"
+	ErrPhoneRegionNotSupported = "phone default region not supported"
+	ErrAuthAPIKeyNotValid      = "auth api key must be name:key"
+	ErrTLSKeyPairNotExist      = "tls cert file and key file must both be set"
+	ErrTLSClientCANotValid     = "tls client ca file has no certificate"
+	ErrTLSClientCANotExist     = "tls client ca file must be set to require client certs"
+	ErrContactNameNotValid     = "name yang dimasukkan tidak valid"
+	ErrContactNoTelpNotValid   = "no_telp yang dimasukkan tidak valid"
+	ErrContactIdNotValid       = "contact id yang dimasukkan tidak valid"
+	ErrContactSortNotValid     = "sort yang dimasukkan tidak valid"
+	ErrContactOrderNotValid    = "order yang dimasukkan tidak valid"
+	ErrContactLimitNotValid    = "limit yang dimasukkan tidak valid"
+	ErrContactOffsetNotValid   = "offset yang dimasukkan tidak valid"
+	ErrContactQueryNotValid    = "keyword yang dimasukkan tidak valid"
+	ErrContactPhoneNotValid    = "phones yang dimasukkan tidak valid"
+	ErrContactEmailNotValid    = "emails yang dimasukkan tidak valid"
+	ErrContactAddressNotValid  = "addresses yang dimasukkan tidak valid"
+	ErrContactTypeNotValid     = "type yang dimasukkan tidak valid"
+	ErrContactBirthdayNotValid = "birthday yang dimasukkan tidak valid"
+	ErrContactNumberNotValid   = "nomor telepon yang dimasukkan tidak valid"
+	ErrContactMergeNotValid    = "ids yang dimasukkan tidak valid"
+	ErrVCardVersionNotValid    = "version vcard yang dimasukkan tidak valid"
+	ErrVCardFileNotValid       = "file vcard yang dimasukkan tidak valid"
+	ErrCSVMappingNotValid      = "mapping csv yang dimasukkan tidak valid"
+	ErrCSVFileNotValid         = "file csv yang dimasukkan tidak valid"
+	ErrCSVDryRunNotValid       = "dry_run yang dimasukkan tidak valid"
+	ErrContactPatchNotValid    = "patch yang dimasukkan tidak valid"
+	ErrPatchTypeNotSupported   = "content type patch tidak didukung"
+	ErrBatchNotValid           = "operations yang dimasukkan tidak valid"
+	ErrBatchIdDuplicate        = "id yang sama hanya boleh diubah sekali dalam operations"
+	ErrGroupNameNotValid       = "name group yang dimasukkan tidak valid"
+	ErrGroupIdNotValid         = "group id yang dimasukkan tidak valid"
+	ErrGroupMembersNotValid    = "contact_ids yang dimasukkan tidak valid"
+	ErrRevisionNotValid        = "revision yang dimasukkan tidak valid"
+	ErrAddressBookNameNotValid = "name address book yang dimasukkan tidak valid"
+
+	ErrContactNotFound      = "contact not found"
+	ErrContactDuplicate     = "contact serupa sudah ada"
+	ErrContactVersion       = "contact sudah diubah, version tidak cocok"
+	ErrBatchAborted         = "batch dibatalkan karena operasi lain gagal"
+	ErrGroupNotFound        = "group not found"
+	ErrGroupDuplicate       = "group dengan name tersebut sudah ada"
+	ErrRevisionNotFound     = "revision not found"
+	ErrAddressBookNotFound  = "address book not found"
+	ErrAddressBookDuplicate = "address book dengan name tersebut sudah ada"
+	ErrAddressBookForbidden = "tidak punya akses ke address book ini"
+	ErrUnauthorized         = "api key atau token tidak valid"
+	ErrForbidden            = "token tidak punya akses"
+	ErrPermissionDenied     = "tidak punya izin untuk operasi ini"
+	ErrImportTooLarge       = "file import melebihi batas baris"
 )
 
+// HandleAppError maps err, or the *AppError it wraps, to a status code
+// and the message to respond with.
 func HandleAppError(err error) (int, string) {
-	switch e := err.(type) {
-	case *AppError:
-		switch e.Message {
-		case ErrContactNotFound:
-			return http.StatusNotFound, err.Error()
-		default:
-			return http.StatusInternalServerError, err.Error()
-		}
+	var e *AppError
+	if !errors.As(err, &e) {
+		return http.StatusInternalServerError, err.Error()
+	}
 
+	switch e.Message {
+	case ErrUnauthorized:
+		return http.StatusUnauthorized, err.Error()
+	case ErrForbidden, ErrPermissionDenied, ErrAddressBookForbidden:
+		return http.StatusForbidden, err.Error()
+	case ErrContactNotFound, ErrGroupNotFound, ErrRevisionNotFound, ErrAddressBookNotFound:
+		return http.StatusNotFound, err.Error()
+	case ErrContactDuplicate, ErrGroupDuplicate, ErrAddressBookDuplicate:
+		return http.StatusConflict, err.Error()
+	case ErrContactVersion:
+		return http.StatusPreconditionFailed, err.Error()
+	case ErrPatchTypeNotSupported:
+		return http.StatusUnsupportedMediaType, err.Error()
+	case ErrImportTooLarge:
+		return http.StatusRequestEntityTooLarge, err.Error()
+	case ErrContactNameNotValid,
+		ErrContactNoTelpNotValid,
+		ErrContactPatchNotValid,
+		ErrBatchNotValid,
+		ErrBatchIdDuplicate,
+		ErrContactSortNotValid,
+		ErrContactOrderNotValid,
+		ErrContactLimitNotValid,
+		ErrContactOffsetNotValid,
+		ErrContactQueryNotValid,
+		ErrContactPhoneNotValid,
+		ErrContactEmailNotValid,
+		ErrContactAddressNotValid,
+		ErrContactTypeNotValid,
+		ErrContactBirthdayNotValid,
+		ErrContactNumberNotValid,
+		ErrContactMergeNotValid,
+		ErrVCardVersionNotValid,
+		ErrVCardFileNotValid,
+		ErrCSVMappingNotValid,
+		ErrCSVFileNotValid,
+		ErrCSVDryRunNotValid,
+		ErrGroupNameNotValid,
+		ErrGroupIdNotValid,
+		ErrGroupMembersNotValid,
+		ErrRevisionNotValid,
+		ErrAddressBookNameNotValid:
+		return http.StatusBadRequest, err.Error()
 	default:
 		return http.StatusInternalServerError, err.Error()
 	}
@@ -0,0 +1,179 @@
+// Package auth tells who is calling the http api, from a static api key,
+// a JWT bearer token or a verified TLS client certificate, and carries
+// them through a request context.
+package auth
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/jwt"
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderAPIKey is the request header an api key is sent in.
+	HeaderAPIKey = "X-API-Key"
+
+	MethodAPIKey     = "api_key"
+	MethodJWT        = "jwt"
+	MethodClientCert = "client_cert"
+)
+
+// namespaces are the prefixes of the IDs of the principals of each method.
+var namespaces = map[string]string{
+	MethodAPIKey:     "apikey",
+	MethodJWT:        "jwt",
+	MethodClientCert: "cert",
+}
+
+// Principal is who made a request: the name of their api key, the
+// subject of their token or the name on their client certificate, which
+// of those they used, and the roles and address book their token gives
+// them.
+type Principal struct {
+	Name   string
+	Method string
+	Roles  []string
+	Tenant string
+}
+
+// ID returns the name of principal qualified by how they authenticated,
+// apikey:ci for the api key ci, so that no one passes for a principal of
+// another method, or for the cli, whose actor has no namespace.
+func (p *Principal) ID() string {
+	return namespaces[p.Method] + ":" + p.Name
+}
+
+// QualifiedID returns the ID of the principal named name who
+// authenticates by namespace, one of apikey, jwt and cert.
+func QualifiedID(namespace string, name string) (string, error) {
+	namespace = strings.ToLower(strings.TrimSpace(namespace))
+	for _, known := range namespaces {
+		if namespace == known {
+			return namespace + ":" + name, nil
+		}
+	}
+	return "", fmt.Errorf("auth: unknown principal namespace %q", namespace)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying principal.
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the principal ctx carries, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+	return principal, ok
+}
+
+type apiKey struct {
+	name string
+	key  []byte
+}
+
+// Authenticator accepts the api keys it was made with and the bearer
+// tokens its verifier does. A token needs Scope among its scopes when
+// Scope is set. With ClientCerts, a request that has neither is made by
+// the client certificate the server verified, if any.
+type Authenticator struct {
+	Scope       string
+	ClientCerts bool
+
+	keys     []apiKey
+	verifier *jwt.Verifier
+	now      func() time.Time
+}
+
+// NewAuthenticator returns the authenticator of apiKeys, each given as
+// "name:key", and of the tokens verifier accepts. A nil verifier
+// accepts no token.
+func NewAuthenticator(apiKeys []string, verifier *jwt.Verifier) (*Authenticator, error) {
+	authenticator := new(Authenticator)
+	authenticator.verifier = verifier
+	authenticator.now = time.Now
+
+	for _, v := range apiKeys {
+		name, key, ok := strings.Cut(strings.TrimSpace(v), ":")
+		if !ok || name == "" || key == "" {
+			return nil, apperrors.NewAppError(apperrors.ErrAuthAPIKeyNotValid)
+		}
+		authenticator.keys = append(authenticator.keys, apiKey{name: name, key: []byte(key)})
+	}
+
+	return authenticator, nil
+}
+
+// Authenticate returns who made r. It fails with apperrors.ErrUnauthorized
+// when r carries no credentials it accepts, and with apperrors.ErrForbidden
+// when the token of r lacks Scope.
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return a.authenticateKey(key)
+	}
+
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if strings.EqualFold(scheme, "Bearer") && token != "" && a.verifier != nil {
+		return a.authenticateToken(strings.TrimSpace(token))
+	}
+
+	//* only a chain the server verified against its client ca bundle,
+	//* a certificate it was merely shown could be anyone's
+	if a.ClientCerts && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return authenticateCert(r.TLS.VerifiedChains[0][0])
+	}
+
+	return nil, apperrors.NewAppError(apperrors.ErrUnauthorized)
+}
+
+// authenticateKey compares key with every api key in constant time,
+// so that the time taken tells nothing of how close it came.
+func (a *Authenticator) authenticateKey(key string) (*Principal, error) {
+	var principal *Principal
+	for _, v := range a.keys {
+		if subtle.ConstantTimeCompare(v.key, []byte(key)) == 1 && principal == nil {
+			principal = &Principal{Name: v.name, Method: MethodAPIKey}
+		}
+	}
+
+	if principal == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrUnauthorized)
+	}
+	return principal, nil
+}
+
+// authenticateCert names the client of cert by its common name or,
+// lacking one, its first dns name or uri.
+func authenticateCert(cert *x509.Certificate) (*Principal, error) {
+	name := cert.Subject.CommonName
+	if name == "" && len(cert.DNSNames) > 0 {
+		name = cert.DNSNames[0]
+	}
+	if name == "" && len(cert.URIs) > 0 {
+		name = cert.URIs[0].String()
+	}
+
+	if name == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrUnauthorized)
+	}
+	return &Principal{Name: name, Method: MethodClientCert}, nil
+}
+
+func (a *Authenticator) authenticateToken(token string) (*Principal, error) {
+	claims, err := a.verifier.Verify(token, a.now())
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrUnauthorized)
+	}
+	if a.Scope != "" && !claims.HasScope(a.Scope) {
+		return nil, apperrors.NewAppError(apperrors.ErrForbidden)
+	}
+
+	return &Principal{Name: claims.Subject, Method: MethodJWT, Roles: claims.Roles, Tenant: claims.Tenant}, nil
+}
@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/jwt"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+// hs256 builds a token of claims signed with secret.
+func hs256(claims jwt.Claims, secret string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, _ := json.Marshal(claims)
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	verifier, err := jwt.NewVerifier([]byte("s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := NewAuthenticator([]string{"ci:key-one", " backup:key-two "}, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.Scope = "contacts"
+	authenticator.now = func() time.Time { return testNow }
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    *Principal
+		wantErr string
+	}{
+		{
+			name:    "api key",
+			headers: map[string]string{HeaderAPIKey: "key-two"},
+			want:    &Principal{Name: "backup", Method: MethodAPIKey},
+		},
+		{
+			name:    "unknown api key",
+			headers: map[string]string{HeaderAPIKey: "key-three"},
+			wantErr: apperrors.ErrUnauthorized,
+		},
+		{
+			name:    "token",
+			headers: map[string]string{"Authorization": "Bearer " + hs256(jwt.Claims{Subject: "alice", Scope: "contacts", ExpiresAt: testNow.Add(time.Hour).Unix()}, "s3cret")},
+			want:    &Principal{Name: "alice", Method: MethodJWT},
+		},
+		{
+			name:    "token with roles",
+			headers: map[string]string{"Authorization": "Bearer " + hs256(jwt.Claims{Subject: "alice", Scope: "contacts", Roles: []string{"support"}, ExpiresAt: testNow.Add(time.Hour).Unix()}, "s3cret")},
+			want:    &Principal{Name: "alice", Method: MethodJWT, Roles: []string{"support"}},
+		},
+		{
+			name:    "token with an address book",
+			headers: map[string]string{"Authorization": "Bearer " + hs256(jwt.Claims{Subject: "alice", Scope: "contacts", Tenant: "sales", ExpiresAt: testNow.Add(time.Hour).Unix()}, "s3cret")},
+			want:    &Principal{Name: "alice", Method: MethodJWT, Tenant: "sales"},
+		},
+		{
+			name:    "token without the scope",
+			headers: map[string]string{"Authorization": "bearer " + hs256(jwt.Claims{Subject: "alice", Scope: "groups", ExpiresAt: testNow.Add(time.Hour).Unix()}, "s3cret")},
+			wantErr: apperrors.ErrForbidden,
+		},
+		{
+			name:    "expired token",
+			headers: map[string]string{"Authorization": "Bearer " + hs256(jwt.Claims{Subject: "alice", Scope: "contacts", ExpiresAt: testNow.Unix()}, "s3cret")},
+			wantErr: apperrors.ErrUnauthorized,
+		},
+		{
+			name:    "token of another secret",
+			headers: map[string]string{"Authorization": "Bearer " + hs256(jwt.Claims{Subject: "alice", Scope: "contacts", ExpiresAt: testNow.Add(time.Hour).Unix()}, "other")},
+			wantErr: apperrors.ErrUnauthorized,
+		},
+		{
+			name:    "basic auth",
+			headers: map[string]string{"Authorization": "Basic Y2k6a2V5LW9uZQ=="},
+			wantErr: apperrors.ErrUnauthorized,
+		},
+		{
+			name:    "no credentials",
+			wantErr: apperrors.ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/contacts", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			got, err := authenticator.Authenticate(r)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_ClientCert(t *testing.T) {
+	authenticator, err := NewAuthenticator([]string{"ci:key-one"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.ClientCerts = true
+
+	billing := &x509.Certificate{Subject: pkix.Name{CommonName: "billing"}}
+	spiffe, _ := url.Parse("spiffe://example.org/billing")
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		tls     *tls.ConnectionState
+		want    *Principal
+		wantErr string
+	}{
+		{
+			name: "verified certificate",
+			tls:  &tls.ConnectionState{PeerCertificates: []*x509.Certificate{billing}, VerifiedChains: [][]*x509.Certificate{{billing}}},
+			want: &Principal{Name: "billing", Method: MethodClientCert},
+		},
+		{
+			name: "verified certificate without a common name",
+			tls:  &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{DNSNames: []string{"billing.internal"}}}}},
+			want: &Principal{Name: "billing.internal", Method: MethodClientCert},
+		},
+		{
+			name: "verified certificate with only a uri",
+			tls:  &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{URIs: []*url.URL{spiffe}}}}},
+			want: &Principal{Name: "spiffe://example.org/billing", Method: MethodClientCert},
+		},
+		{
+			name:    "verified certificate without a name",
+			tls:     &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}},
+			wantErr: apperrors.ErrUnauthorized,
+		},
+		{
+			name:    "certificate that was not verified",
+			tls:     &tls.ConnectionState{PeerCertificates: []*x509.Certificate{billing}},
+			wantErr: apperrors.ErrUnauthorized,
+		},
+		{
+			name:    "api key and certificate",
+			headers: map[string]string{HeaderAPIKey: "key-one"},
+			tls:     &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{billing}}},
+			want:    &Principal{Name: "ci", Method: MethodAPIKey},
+		},
+		{
+			name:    "no credentials over tls",
+			tls:     &tls.ConnectionState{},
+			wantErr: apperrors.ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/contacts", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			r.TLS = tt.tls
+
+			got, err := authenticator.Authenticate(r)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	authenticator.ClientCerts = false
+	r := httptest.NewRequest("GET", "/contacts", nil)
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{billing}}}
+	_, err = authenticator.Authenticate(r)
+	assert.EqualError(t, err, apperrors.ErrUnauthorized, "certificate accepted without ClientCerts")
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	for _, apiKeys := range [][]string{{"key-only"}, {":key"}, {"ci:"}} {
+		_, err := NewAuthenticator(apiKeys, nil)
+		assert.EqualError(t, err, apperrors.ErrAuthAPIKeyNotValid, "NewAuthenticator(%q)", apiKeys)
+	}
+
+	authenticator, err := NewAuthenticator(nil, nil)
+	if assert.NoError(t, err) {
+		r := httptest.NewRequest("GET", "/contacts", nil)
+		r.Header.Set("Authorization", "Bearer a.b.c")
+		_, err = authenticator.Authenticate(r)
+		assert.EqualError(t, err, apperrors.ErrUnauthorized)
+	}
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	principal := &Principal{Name: "ci", Method: MethodAPIKey}
+	got, ok := FromContext(NewContext(context.Background(), principal))
+	assert.True(t, ok)
+	assert.Equal(t, principal, got)
+}
+
+func TestPrincipal_ID(t *testing.T) {
+	assert.Equal(t, "apikey:cli", (&Principal{Name: "cli", Method: MethodAPIKey}).ID())
+	assert.Equal(t, "jwt:alice", (&Principal{Name: "alice", Method: MethodJWT}).ID())
+	assert.Equal(t, "cert:billing", (&Principal{Name: "billing", Method: MethodClientCert}).ID())
+
+	id, err := QualifiedID("JWT", "alice")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "jwt:alice", id)
+	}
+	_, err = QualifiedID("api_key", "ci")
+	assert.EqualError(t, err, `auth: unknown principal namespace "api_key"`)
+}
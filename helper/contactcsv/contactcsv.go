@@ -0,0 +1,356 @@
+// Package contactcsv reads and writes contacts as CSV rows, one
+// contact per row with a header naming the field of each column.
+//
+// Phones and emails share one column each, written as
+// "type:value; type:value". Only the first address of a contact
+// has columns, split into street, city, region, postal code and country.
+package contactcsv
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FieldID         = "id"
+	FieldName       = "name"
+	FieldNoTelp     = "no_telp"
+	FieldPhones     = "phones"
+	FieldEmails     = "emails"
+	FieldStreet     = "street"
+	FieldCity       = "city"
+	FieldRegion     = "region"
+	FieldPostalCode = "postal_code"
+	FieldCountry    = "country"
+	FieldCompany    = "company"
+	FieldJobTitle   = "job_title"
+	FieldBirthday   = "birthday"
+	FieldNotes      = "notes"
+	FieldCreatedAt  = "created_at"
+	FieldUpdatedAt  = "updated_at"
+
+	// MediaType is the content type of a .csv file.
+	MediaType = "text/csv; charset=utf-8"
+
+	valueSeparator = ";"
+)
+
+// Header is the header a Writer writes.
+var Header = []string{
+	FieldID, FieldName, FieldNoTelp, FieldPhones, FieldEmails,
+	FieldStreet, FieldCity, FieldRegion, FieldPostalCode, FieldCountry,
+	FieldCompany, FieldJobTitle, FieldBirthday, FieldNotes,
+	FieldCreatedAt, FieldUpdatedAt,
+}
+
+// importFields are the fields a column can be mapped to on import;
+// IDs and timestamps are left to the store.
+var importFields = map[string]bool{
+	FieldName:       true,
+	FieldNoTelp:     true,
+	FieldPhones:     true,
+	FieldEmails:     true,
+	FieldStreet:     true,
+	FieldCity:       true,
+	FieldRegion:     true,
+	FieldPostalCode: true,
+	FieldCountry:    true,
+	FieldCompany:    true,
+	FieldJobTitle:   true,
+	FieldBirthday:   true,
+	FieldNotes:      true,
+}
+
+type Writer struct {
+	w *csv.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	writer := new(Writer)
+	writer.w = csv.NewWriter(w)
+	return writer
+}
+
+func (w *Writer) WriteHeader() error {
+	return w.w.Write(Header)
+}
+
+func (w *Writer) Write(contact *model.Contact) error {
+	var phones, emails []string
+	for _, phone := range contact.Phones {
+		phones = append(phones, phone.Type+":"+phone.Number)
+	}
+	for _, email := range contact.Emails {
+		emails = append(emails, email.Type+":"+email.Address)
+	}
+
+	var address model.Address
+	if len(contact.Addresses) > 0 {
+		address = contact.Addresses[0]
+	}
+
+	return w.w.Write([]string{
+		strconv.FormatInt(contact.ID, 10),
+		contact.Name,
+		contact.NoTelp,
+		strings.Join(phones, valueSeparator+" "),
+		strings.Join(emails, valueSeparator+" "),
+		address.Street,
+		address.City,
+		address.Region,
+		address.PostalCode,
+		address.Country,
+		contact.Company,
+		contact.JobTitle,
+		contact.Birthday,
+		contact.Notes,
+		formatTime(contact.CreatedAt),
+		formatTime(contact.UpdatedAt),
+	})
+}
+
+// Flush writes any buffered rows, returning the first error
+// met while writing.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Reader reads contact requests from CSV rows, one row at a time.
+type Reader struct {
+	r *csv.Reader
+
+	// columns holds the field of each column, "" when it is not imported
+	columns []string
+	fields  map[string]bool
+}
+
+// NewReader reads the header of r and maps its columns to fields.
+// mapping maps a header to a field; headers it leaves out are
+// matched to the field of the same name, and ignored when there is none.
+// It returns io.EOF when r is empty.
+func NewReader(r io.Reader, mapping map[string]string) (*Reader, error) {
+	for _, field := range mapping {
+		if !importFields[field] {
+			return nil, apperrors.NewAppError(apperrors.ErrCSVMappingNotValid)
+		}
+	}
+
+	reader := new(Reader)
+	reader.r = csv.NewReader(r)
+	reader.r.FieldsPerRecord = -1
+	reader.r.TrimLeadingSpace = true
+	reader.r.ReuseRecord = true
+	reader.fields = make(map[string]bool)
+
+	header, err := reader.r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := make(map[string]bool)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+
+		field, ok := mapping[name]
+		if ok {
+			mapped[name] = true
+		} else if lower := strings.ToLower(name); importFields[lower] {
+			field = lower
+		}
+
+		if reader.fields[field] {
+			// two columns for one field, e.g. "Name" and "name"
+			return nil, apperrors.NewAppError(apperrors.ErrCSVMappingNotValid)
+		}
+		if field != "" {
+			reader.fields[field] = true
+		}
+		reader.columns = append(reader.columns, field)
+	}
+
+	// a mapping naming a header the file lacks is likely a typo
+	for name := range mapping {
+		if !mapped[name] {
+			return nil, apperrors.NewAppError(apperrors.ErrCSVMappingNotValid)
+		}
+	}
+
+	return reader, nil
+}
+
+// Read returns the next row and the line it starts on.
+// A malformed row is returned as a *csv.ParseError,
+// after which Read carries on with the next row.
+func (r *Reader) Read() (int, *model.ContactRequest, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			return parseErr.StartLine, nil, err
+		}
+		return 0, nil, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	req := new(model.ContactRequest)
+	for i, value := range record {
+		if i < len(r.columns) && r.columns[i] != "" {
+			setField(req, r.columns[i], strings.TrimSpace(value))
+		}
+	}
+	return line, req, nil
+}
+
+// Apply overwrites the fields of dst that the file has columns for
+// with those of src, leaving the rest of dst as it is.
+func (r *Reader) Apply(dst *model.ContactRequest, src *model.ContactRequest) {
+	for field := range r.fields {
+		switch field {
+		case FieldName:
+			dst.Name = src.Name
+		case FieldNoTelp:
+			dst.NoTelp = src.NoTelp
+		case FieldPhones:
+			dst.Phones = src.Phones
+		case FieldEmails:
+			dst.Emails = src.Emails
+		case FieldCompany:
+			dst.Company = src.Company
+		case FieldJobTitle:
+			dst.JobTitle = src.JobTitle
+		case FieldBirthday:
+			dst.Birthday = src.Birthday
+		case FieldNotes:
+			dst.Notes = src.Notes
+		}
+	}
+
+	var srcAddress model.Address
+	if len(src.Addresses) > 0 {
+		srcAddress = src.Addresses[0]
+	}
+
+	var dstAddress model.Address
+	if len(dst.Addresses) > 0 {
+		dstAddress = dst.Addresses[0]
+	}
+
+	components := []struct {
+		field string
+		dst   *string
+		src   string
+	}{
+		{FieldStreet, &dstAddress.Street, srcAddress.Street},
+		{FieldCity, &dstAddress.City, srcAddress.City},
+		{FieldRegion, &dstAddress.Region, srcAddress.Region},
+		{FieldPostalCode, &dstAddress.PostalCode, srcAddress.PostalCode},
+		{FieldCountry, &dstAddress.Country, srcAddress.Country},
+	}
+
+	changed := false
+	for _, component := range components {
+		if r.fields[component.field] {
+			*component.dst = component.src
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	empty := dstAddress == model.Address{Type: dstAddress.Type}
+	switch {
+	case len(dst.Addresses) > 0 && empty:
+		dst.Addresses = dst.Addresses[1:]
+	case len(dst.Addresses) > 0:
+		dst.Addresses = append([]model.Address{dstAddress}, dst.Addresses[1:]...)
+	case !empty:
+		dst.Addresses = []model.Address{dstAddress}
+	}
+}
+
+func setField(req *model.ContactRequest, field string, value string) {
+	switch field {
+	case FieldName:
+		req.Name = value
+	case FieldNoTelp:
+		req.NoTelp = value
+	case FieldPhones:
+		for _, v := range splitTypedValues(value) {
+			req.Phones = append(req.Phones, model.Phone{Type: v.Type, Number: v.Value})
+		}
+	case FieldEmails:
+		for _, v := range splitTypedValues(value) {
+			req.Emails = append(req.Emails, model.Email{Type: v.Type, Address: v.Value})
+		}
+	case FieldStreet:
+		setAddressField(req, func(address *model.Address) { address.Street = value }, value)
+	case FieldCity:
+		setAddressField(req, func(address *model.Address) { address.City = value }, value)
+	case FieldRegion:
+		setAddressField(req, func(address *model.Address) { address.Region = value }, value)
+	case FieldPostalCode:
+		setAddressField(req, func(address *model.Address) { address.PostalCode = value }, value)
+	case FieldCountry:
+		setAddressField(req, func(address *model.Address) { address.Country = value }, value)
+	case FieldCompany:
+		req.Company = value
+	case FieldJobTitle:
+		req.JobTitle = value
+	case FieldBirthday:
+		req.Birthday = value
+	case FieldNotes:
+		req.Notes = value
+	}
+}
+
+func setAddressField(req *model.ContactRequest, set func(address *model.Address), value string) {
+	if value == "" {
+		return
+	}
+	if len(req.Addresses) == 0 {
+		req.Addresses = []model.Address{{}}
+	}
+	set(&req.Addresses[0])
+}
+
+type typedValue struct {
+	Type  string
+	Value string
+}
+
+// splitTypedValues splits "work:value; value" into its values,
+// leaving the type empty when a value has no known type prefix.
+func splitTypedValues(s string) []typedValue {
+	var values []typedValue
+	for _, part := range strings.Split(s, valueSeparator) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		value := typedValue{Value: part}
+		if prefix, rest, ok := strings.Cut(part, ":"); ok {
+			if contactType := strings.ToLower(strings.TrimSpace(prefix)); model.ContactTypes[contactType] {
+				value = typedValue{Type: contactType, Value: strings.TrimSpace(rest)}
+			}
+		}
+		values = append(values, value)
+	}
+	return values
+}
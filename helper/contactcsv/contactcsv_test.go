@@ -0,0 +1,170 @@
+package contactcsv
+
+import (
+	"bytes"
+	"contact-go/model"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRead(t *testing.T) {
+	contact := model.Contact{
+		ID:     7,
+		Name:   "Jane Smith",
+		NoTelp: "555-555-5678",
+		Phones: []model.Phone{
+			{Type: model.TypeWork, Number: "555-555-0000"},
+			{Type: model.TypeOther, Number: "555-555-1111"},
+		},
+		Emails: []model.Email{
+			{Type: model.TypeHome, Address: "jane@example.com"},
+		},
+		Addresses: []model.Address{
+			{Type: model.TypeOther, Street: "Jl. Sudirman 1, Lt. 2", City: "Jakarta", Country: "Indonesia"},
+		},
+		Company:   "Acme",
+		Birthday:  "1990-05-17",
+		Notes:     "line one\nline \"two\"",
+		CreatedAt: time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 6, 2, 12, 30, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+	assert.NoError(t, writer.WriteHeader())
+	assert.NoError(t, writer.Write(&contact))
+	assert.NoError(t, writer.Flush())
+
+	assert.Contains(t, buf.String(), "work:555-555-0000; other:555-555-1111")
+	assert.Contains(t, buf.String(), "2023-06-01T12:30:00Z")
+
+	reader, err := NewReader(&buf, nil)
+	assert.NoError(t, err)
+
+	line, got, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, line)
+	assert.Equal(t, &model.ContactRequest{
+		Name:      contact.Name,
+		NoTelp:    contact.NoTelp,
+		Phones:    contact.Phones,
+		Emails:    contact.Emails,
+		Addresses: []model.Address{{Street: "Jl. Sudirman 1, Lt. 2", City: "Jakarta", Country: "Indonesia"}},
+		Company:   contact.Company,
+		Birthday:  contact.Birthday,
+		Notes:     contact.Notes,
+	}, got)
+
+	_, _, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		mapping map[string]string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "header names",
+			file:    "\ufeffName,No_Telp,ID,Unknown\n",
+			want:    []string{FieldName, FieldNoTelp, "", ""},
+			wantErr: false,
+		},
+		{
+			name:    "mapping",
+			file:    "Full Name,HP,Kota\n",
+			mapping: map[string]string{"Full Name": FieldName, "HP": FieldNoTelp, "Kota": FieldCity},
+			want:    []string{FieldName, FieldNoTelp, FieldCity},
+			wantErr: false,
+		},
+		{
+			name:    "unknown field",
+			file:    "Full Name\n",
+			mapping: map[string]string{"Full Name": FieldID},
+			wantErr: true,
+		},
+		{
+			name:    "missing header",
+			file:    "Full Name\n",
+			mapping: map[string]string{"Nama": FieldName},
+			wantErr: true,
+		},
+		{
+			name:    "two columns for one field",
+			file:    "name,Full Name\n",
+			mapping: map[string]string{"Full Name": FieldName},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewReader(strings.NewReader(tt.file), tt.mapping)
+
+			if assert.Equal(t, tt.wantErr, err != nil, "NewReader() error = %v, wantErr %v", err, tt.wantErr) && err == nil {
+				assert.Equal(t, tt.want, got.columns)
+			}
+		})
+	}
+}
+
+func TestReader_Read(t *testing.T) {
+	file := "name,no_telp,phones\n" +
+		"Jane,111,\"mobile:222;\n 333\"\n" +
+		"bad\"quote,444,\n" +
+		"Short\n"
+	reader, err := NewReader(strings.NewReader(file), nil)
+	assert.NoError(t, err)
+
+	line, got, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, line)
+	assert.Equal(t, &model.ContactRequest{
+		Name:   "Jane",
+		NoTelp: "111",
+		Phones: []model.Phone{{Type: model.TypeMobile, Number: "222"}, {Number: "333"}},
+	}, got)
+
+	line, _, err = reader.Read()
+	var parseErr *csv.ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 4, line)
+
+	line, got, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, line)
+	assert.Equal(t, &model.ContactRequest{Name: "Short"}, got)
+}
+
+func TestReader_Apply(t *testing.T) {
+	reader, err := NewReader(strings.NewReader("name,city,company\n"), nil)
+	assert.NoError(t, err)
+
+	dst := &model.ContactRequest{
+		Name:      "Jane",
+		NoTelp:    "111",
+		Emails:    []model.Email{{Type: model.TypeWork, Address: "jane@example.com"}},
+		Addresses: []model.Address{{Type: model.TypeHome, Street: "Jl. Sudirman 1", City: "Jakarta"}},
+		Company:   "Acme",
+	}
+	reader.Apply(dst, &model.ContactRequest{
+		Name:      "Jane Smith",
+		NoTelp:    "999",
+		Addresses: []model.Address{{City: "Bandung"}},
+	})
+
+	assert.Equal(t, &model.ContactRequest{
+		Name:      "Jane Smith",
+		NoTelp:    "111",
+		Emails:    []model.Email{{Type: model.TypeWork, Address: "jane@example.com"}},
+		Addresses: []model.Address{{Type: model.TypeHome, Street: "Jl. Sudirman 1", City: "Bandung"}},
+	}, dst)
+}
@@ -0,0 +1,46 @@
+// Package filelock provides advisory file locks that are honoured
+// across processes, so several contact-go instances can share one file.
+package filelock
+
+import "os"
+
+type Lock struct {
+	file *os.File
+}
+
+// Exclusive blocks until it holds the only lock on path.
+func Exclusive(path string) (*Lock, error) {
+	return acquire(path, true)
+}
+
+// Shared blocks until no exclusive lock is held on path.
+// Any number of shared locks may be held at once.
+func Shared(path string) (*Lock, error) {
+	return acquire(path, false)
+}
+
+// acquire opens its own descriptor for every lock, because the OS ties
+// the lock to the descriptor and unlocking one would release them all.
+func acquire(path string, exclusive bool) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(file, exclusive); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	l := new(Lock)
+	l.file = file
+	return l, nil
+}
+
+func (l *Lock) Unlock() error {
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "jane", b: "", want: 4},
+		{a: "jane", b: "jane", want: 0},
+		{a: "jane", b: "jnae", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "rené", b: "rene", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, Distance(tt.a, tt.b))
+			assert.Equal(t, tt.want, Distance(tt.b, tt.a))
+		})
+	}
+}
+
+func TestMaxTypos(t *testing.T) {
+	assert.Equal(t, 0, MaxTypos(3))
+	assert.Equal(t, 1, MaxTypos(4))
+	assert.Equal(t, 1, MaxTypos(7))
+	assert.Equal(t, 2, MaxTypos(8))
+}
@@ -0,0 +1,111 @@
+// Package health checks whether what the service needs to serve, its
+// storage, can be reached.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	StatusOK      = "ok"
+	StatusMissing = "missing"
+	StatusFail    = "fail"
+)
+
+// ErrNotCreated is what a check fails with when what it checks is yet to
+// be created, and can be, which Run does not count as failing.
+var ErrNotCreated = errors.New("not created yet")
+
+// Check is one thing the service needs, named for the report of Run.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is how a check went, with the error it failed with.
+type Result struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Run runs checks one after the other, giving each up to timeout, zero
+// leaving only the deadline of ctx, and reports whether they all passed,
+// those that found nothing created yet included.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) ([]Result, bool) {
+	results := make([]Result, 0, len(checks))
+	passed := true
+
+	for _, check := range checks {
+		err := run(ctx, check, timeout)
+
+		result := Result{Name: check.Name, Status: StatusOK}
+		switch {
+		case errors.Is(err, ErrNotCreated):
+			result.Status = StatusMissing
+			result.Error = err.Error()
+		case err != nil:
+			result.Status = StatusFail
+			result.Error = err.Error()
+			passed = false
+		}
+		results = append(results, result)
+	}
+
+	return results, passed
+}
+
+func run(ctx context.Context, check Check, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return check.Run(ctx)
+}
+
+// Ping checks that db answers.
+func Ping(db *sql.DB) Check {
+	return Check{Name: "database", Run: db.PingContext}
+}
+
+// File checks that the file at path can be read and written, and that
+// its directory takes new files, which the json storage writes a file
+// to before renaming it over path. A file not there yet, as on the
+// first run, fails with ErrNotCreated when its directory takes it.
+func File(name string, path string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error {
+		file, err := os.OpenFile(path, os.O_RDWR, 0)
+		missing := errors.Is(err, fs.ErrNotExist)
+		switch {
+		case err == nil:
+			file.Close()
+		case !missing:
+			return err
+		}
+
+		temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".check-*")
+		if err != nil && missing {
+			return fmt.Errorf("%s not created yet, and cannot be: %w", path, err)
+		}
+		if err != nil {
+			return err
+		}
+		temp.Close()
+		if err := os.Remove(temp.Name()); err != nil {
+			return err
+		}
+
+		if missing {
+			return fmt.Errorf("%s %w", path, ErrNotCreated)
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var deadline bool
+	checks := []Check{
+		{Name: "first", Run: func(ctx context.Context) error {
+			_, deadline = ctx.Deadline()
+			return nil
+		}},
+		{Name: "second", Run: func(ctx context.Context) error { return errors.New("unreachable") }},
+	}
+
+	results, passed := Run(context.Background(), checks, time.Second)
+	assert.False(t, passed)
+	assert.True(t, deadline)
+	assert.Equal(t, []Result{
+		{Name: "first", Status: StatusOK},
+		{Name: "second", Status: StatusFail, Error: "unreachable"},
+	}, results)
+
+	results, passed = Run(context.Background(), checks[:1], 0)
+	assert.True(t, passed)
+	assert.False(t, deadline)
+	assert.Len(t, results, 1)
+
+	missing := Check{Name: "missing", Run: func(ctx context.Context) error {
+		return fmt.Errorf("data/contact.json %w", ErrNotCreated)
+	}}
+	results, passed = Run(context.Background(), []Check{missing}, 0)
+	assert.True(t, passed)
+	assert.Equal(t, []Result{{Name: "missing", Status: StatusMissing, Error: "data/contact.json not created yet"}}, results)
+
+	results, passed = Run(context.Background(), nil, 0)
+	assert.True(t, passed)
+	assert.NotNil(t, results)
+}
+
+func TestPing(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing()
+	assert.NoError(t, Ping(db).Run(context.Background()))
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	assert.EqualError(t, Ping(db).Run(context.Background()), "connection refused")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "contact.json")
+	err := File("contacts", path).Run(context.Background())
+	assert.ErrorIs(t, err, ErrNotCreated)
+	assert.EqualError(t, err, path+" not created yet")
+
+	if err := os.WriteFile(path, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, File("contacts", path).Run(context.Background()))
+
+	entries, err := os.ReadDir(dir)
+	if assert.NoError(t, err) {
+		assert.Len(t, entries, 1, "check left a file behind")
+	}
+
+	err = File("contacts", filepath.Join(dir, "missing", "contact.json")).Run(context.Background())
+	if assert.Error(t, err) {
+		assert.NotErrorIs(t, err, ErrNotCreated)
+		assert.Contains(t, err.Error(), "not created yet, and cannot be")
+	}
+
+	if os.Geteuid() != 0 {
+		if err := os.Chmod(path, 0o444); err != nil {
+			t.Fatal(err)
+		}
+		assert.Error(t, File("contacts", path).Run(context.Background()), "read only file")
+	}
+}
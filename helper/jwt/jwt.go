@@ -0,0 +1,251 @@
+// Package jwt verifies JSON Web Tokens signed with an HMAC secret
+// (HS256, HS384, HS512) or an RSA key (RS256, RS384, RS512).
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	// registers the hashes the algorithms sign with
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+var (
+	ErrKeyNotValid       = errors.New("jwt: key is neither an hmac secret nor an rsa public key")
+	ErrTokenMalformed    = errors.New("jwt: token is malformed")
+	ErrAlgorithm         = errors.New("jwt: token is signed with an algorithm the key does not allow")
+	ErrSignature         = errors.New("jwt: token signature is not valid")
+	ErrTokenExpired      = errors.New("jwt: token is expired")
+	ErrExpiryNotPresent  = errors.New("jwt: token has no expiry")
+	ErrTokenNotYetValid  = errors.New("jwt: token is not valid yet")
+	ErrIssuerNotValid    = errors.New("jwt: token is from another issuer")
+	ErrAudienceNotValid  = errors.New("jwt: token is for another audience")
+	ErrSubjectNotPresent = errors.New("jwt: token has no subject")
+)
+
+var hashes = map[string]crypto.Hash{
+	"HS256": crypto.SHA256,
+	"HS384": crypto.SHA384,
+	"HS512": crypto.SHA512,
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// Claims are the registered claims of a token, with the space-separated
+// scopes it grants, the roles of its subject and the address book it is
+// bound to.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Scope     string   `json:"scope,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	Tenant    string   `json:"tenant,omitempty"`
+}
+
+// HasScope reports whether the token grants scope.
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range strings.Fields(c.Scope) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Audience is the aud claim, which is either one string or a list.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = Audience{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a Audience) contains(audience string) bool {
+	for _, v := range a {
+		if v == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier checks the tokens signed with one key. A token needs an
+// expiry, and to be from Issuer and for Audience when those are set.
+// Leeway allows for the clocks of the issuer and this host to differ.
+type Verifier struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+
+	secret []byte
+	public *rsa.PublicKey
+}
+
+// NewVerifier returns the verifier of the tokens signed with key, an
+// RSA public key or certificate in PEM or else an HMAC secret.
+func NewVerifier(key []byte) (*Verifier, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		secret := bytes.TrimRight(key, "\r\n")
+		if len(secret) == 0 {
+			return nil, ErrKeyNotValid
+		}
+		return &Verifier{secret: secret}, nil
+	}
+
+	var public interface{}
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			public = cert.PublicKey
+		}
+	default:
+		return nil, ErrKeyNotValid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := public.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrKeyNotValid
+	}
+	return &Verifier{public: rsaKey}, nil
+}
+
+// LoadVerifier is NewVerifier for the key in the file at path.
+func LoadVerifier(path string) (*Verifier, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(key)
+}
+
+// Verify checks the signature and the claims of token at now,
+// and returns its claims.
+func (v *Verifier) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	if err := v.verifySignature(header.Alg, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := new(Claims)
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+	if err := v.verifyClaims(claims, now); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// verifySignature checks signature against the signed header and
+// payload. An HMAC secret only allows HS algorithms and an RSA key only
+// RS ones, so that a public key cannot be used as a shared secret.
+func (v *Verifier) verifySignature(alg string, signed string, signature []byte) error {
+	hash, ok := hashes[alg]
+	if !ok {
+		return ErrAlgorithm
+	}
+
+	switch {
+	case v.secret != nil && strings.HasPrefix(alg, "HS"):
+		mac := hmac.New(hash.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrSignature
+		}
+		return nil
+	case v.public != nil && strings.HasPrefix(alg, "RS"):
+		digest := hash.New()
+		digest.Write([]byte(signed))
+		if rsa.VerifyPKCS1v15(v.public, hash, digest.Sum(nil), signature) != nil {
+			return ErrSignature
+		}
+		return nil
+	default:
+		return ErrAlgorithm
+	}
+}
+
+func (v *Verifier) verifyClaims(claims *Claims, now time.Time) error {
+	if claims.ExpiresAt == 0 {
+		return ErrExpiryNotPresent
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0).Add(v.Leeway)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(v.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrTokenNotYetValid
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return ErrIssuerNotValid
+	}
+	if v.Audience != "" && !claims.Audience.contains(v.Audience) {
+		return ErrAudienceNotValid
+	}
+	if claims.Subject == "" {
+		return ErrSubjectNotPresent
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrTokenMalformed
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+// sign builds a token of claims, signed with key the way alg says.
+func sign(t *testing.T, alg string, key interface{}, claims interface{}) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	hash, ok := hashes[alg]
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		if !ok {
+			break
+		}
+		mac := hmac.New(hash.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := hash.New()
+		digest.Write([]byte(signed))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, hash, digest.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerify_hmac(t *testing.T) {
+	secret := []byte("s3cret")
+	verifier, err := NewVerifier(append(secret, '\n'))
+	if !assert.NoError(t, err) {
+		return
+	}
+	verifier.Issuer = "contact-go"
+	verifier.Audience = "api"
+
+	exp := testNow.Add(time.Hour).Unix()
+	valid := Claims{Subject: "alice", Issuer: "contact-go", Audience: Audience{"api", "web"}, ExpiresAt: exp}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "HS256", token: sign(t, "HS256", secret, valid)},
+		{name: "HS512", token: sign(t, "HS512", secret, valid)},
+		{name: "audience as a string", token: sign(t, "HS256", secret, map[string]interface{}{"sub": "alice", "iss": "contact-go", "aud": "api", "exp": exp})},
+		{name: "other secret", token: sign(t, "HS256", []byte("other"), valid), wantErr: ErrSignature},
+		{name: "none", token: sign(t, "none", secret, valid), wantErr: ErrAlgorithm},
+		{name: "RS256", token: sign(t, "RS256", secret, valid), wantErr: ErrAlgorithm},
+		{name: "expired", token: sign(t, "HS256", secret, Claims{Subject: "alice", Issuer: "contact-go", Audience: Audience{"api"}, ExpiresAt: testNow.Unix()}), wantErr: ErrTokenExpired},
+		{name: "no expiry", token: sign(t, "HS256", secret, Claims{Subject: "alice", Issuer: "contact-go", Audience: Audience{"api"}}), wantErr: ErrExpiryNotPresent},
+		{name: "not yet valid", token: sign(t, "HS256", secret, Claims{Subject: "alice", Issuer: "contact-go", Audience: Audience{"api"}, ExpiresAt: exp, NotBefore: testNow.Add(time.Minute).Unix()}), wantErr: ErrTokenNotYetValid},
+		{name: "other issuer", token: sign(t, "HS256", secret, Claims{Subject: "alice", Issuer: "other", Audience: Audience{"api"}, ExpiresAt: exp}), wantErr: ErrIssuerNotValid},
+		{name: "other audience", token: sign(t, "HS256", secret, Claims{Subject: "alice", Issuer: "contact-go", Audience: Audience{"web"}, ExpiresAt: exp}), wantErr: ErrAudienceNotValid},
+		{name: "no subject", token: sign(t, "HS256", secret, Claims{Issuer: "contact-go", Audience: Audience{"api"}, ExpiresAt: exp}), wantErr: ErrSubjectNotPresent},
+		{name: "malformed", token: "not-a-token", wantErr: ErrTokenMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(tt.token, testNow)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, "alice", claims.Subject)
+			}
+		})
+	}
+}
+
+func TestVerify_rsa(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})
+
+	verifier, err := NewVerifier(publicPEM)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	claims := Claims{Subject: "bob", Scope: "contacts:read contacts:write", ExpiresAt: testNow.Add(time.Hour).Unix()}
+
+	got, err := verifier.Verify(sign(t, "RS256", private, claims), testNow)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "bob", got.Subject)
+		assert.True(t, got.HasScope("contacts:write"))
+		assert.False(t, got.HasScope("contacts"))
+	}
+
+	// the public key must not pass for an hmac secret
+	_, err = verifier.Verify(sign(t, "HS256", publicPEM, claims), testNow)
+	assert.ErrorIs(t, err, ErrAlgorithm)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = verifier.Verify(sign(t, "RS384", other, claims), testNow)
+	assert.ErrorIs(t, err, ErrSignature)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&private.PublicKey)})
+	verifier, err = NewVerifier(pkcs1)
+	if assert.NoError(t, err) {
+		_, err = verifier.Verify(sign(t, "RS512", private, claims), testNow)
+		assert.NoError(t, err)
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, err := NewVerifier([]byte("\n"))
+	assert.ErrorIs(t, err, ErrKeyNotValid)
+
+	_, err = NewVerifier(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("x")}))
+	assert.ErrorIs(t, err, ErrKeyNotValid)
+
+	_, err = LoadVerifier("testdata/missing.key")
+	assert.Error(t, err)
+}
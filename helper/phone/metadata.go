@@ -0,0 +1,119 @@
+package phone
+
+// region is the numbering plan of one country: its calling code,
+// the trunk prefix dialled before national numbers, and the length
+// range of its national significant numbers.
+type region struct {
+	callingCode string
+	trunkPrefix string
+	minLength   int
+	maxLength   int
+}
+
+// regions is keyed by ISO 3166-1 alpha-2 code. Lengths are kept
+// loose, covering both fixed and mobile numbers of each country.
+var regions = map[string]region{
+	"AE": {"971", "0", 8, 9},
+	"AF": {"93", "0", 9, 9},
+	"AR": {"54", "0", 10, 11},
+	"AT": {"43", "0", 4, 13},
+	"AU": {"61", "0", 9, 9},
+	"BD": {"880", "0", 10, 10},
+	"BE": {"32", "0", 8, 9},
+	"BH": {"973", "", 8, 8},
+	"BN": {"673", "", 7, 7},
+	"BR": {"55", "0", 10, 11},
+	"CA": {"1", "1", 10, 10},
+	"CH": {"41", "0", 9, 9},
+	"CL": {"56", "", 9, 9},
+	"CN": {"86", "0", 9, 11},
+	"CO": {"57", "0", 8, 10},
+	"CZ": {"420", "", 9, 9},
+	"DE": {"49", "0", 5, 13},
+	"DK": {"45", "", 8, 8},
+	"DZ": {"213", "0", 8, 9},
+	"EG": {"20", "0", 8, 10},
+	"ES": {"34", "", 9, 9},
+	"ET": {"251", "0", 9, 9},
+	"FI": {"358", "0", 5, 12},
+	"FR": {"33", "0", 9, 9},
+	"GB": {"44", "0", 9, 10},
+	"GH": {"233", "0", 9, 9},
+	"GR": {"30", "", 10, 10},
+	"HK": {"852", "", 8, 8},
+	"HU": {"36", "06", 8, 9},
+	"ID": {"62", "0", 8, 12},
+	"IE": {"353", "0", 7, 9},
+	"IL": {"972", "0", 8, 9},
+	"IN": {"91", "0", 10, 10},
+	"IR": {"98", "0", 10, 10},
+	"IT": {"39", "", 6, 11},
+	"JO": {"962", "0", 8, 9},
+	"JP": {"81", "0", 9, 10},
+	"KE": {"254", "0", 9, 9},
+	"KH": {"855", "0", 8, 9},
+	"KR": {"82", "0", 8, 10},
+	"KW": {"965", "", 8, 8},
+	"KZ": {"7", "8", 10, 10},
+	"LA": {"856", "0", 8, 10},
+	"LB": {"961", "0", 7, 8},
+	"LK": {"94", "0", 9, 9},
+	"MA": {"212", "0", 9, 9},
+	"MM": {"95", "0", 7, 10},
+	"MO": {"853", "", 8, 8},
+	"MX": {"52", "", 10, 10},
+	"MY": {"60", "0", 8, 10},
+	"NG": {"234", "0", 8, 10},
+	"NL": {"31", "0", 9, 9},
+	"NO": {"47", "", 8, 8},
+	"NP": {"977", "0", 8, 10},
+	"NZ": {"64", "0", 8, 10},
+	"OM": {"968", "", 8, 8},
+	"PE": {"51", "0", 8, 9},
+	"PG": {"675", "", 7, 8},
+	"PH": {"63", "0", 8, 10},
+	"PK": {"92", "0", 9, 10},
+	"PL": {"48", "", 9, 9},
+	"PT": {"351", "", 9, 9},
+	"QA": {"974", "", 8, 8},
+	"RO": {"40", "0", 9, 9},
+	"RU": {"7", "8", 10, 10},
+	"SA": {"966", "0", 9, 9},
+	"SE": {"46", "0", 7, 10},
+	"SG": {"65", "", 8, 8},
+	"SK": {"421", "0", 9, 9},
+	"TH": {"66", "0", 8, 9},
+	"TL": {"670", "", 7, 8},
+	"TN": {"216", "", 8, 8},
+	"TR": {"90", "0", 10, 10},
+	"TW": {"886", "0", 8, 9},
+	"TZ": {"255", "0", 9, 9},
+	"UA": {"380", "0", 9, 9},
+	"UG": {"256", "0", 9, 9},
+	"US": {"1", "1", 10, 10},
+	"VE": {"58", "0", 10, 10},
+	"VN": {"84", "0", 9, 10},
+	"ZA": {"27", "0", 9, 9},
+}
+
+// callingCodes merges the regions sharing a calling code, such as
+// the US and Canada, into the widest plan among them.
+var callingCodes = func() map[string]region {
+	codes := make(map[string]region)
+	for _, r := range regions {
+		merged, ok := codes[r.callingCode]
+		if !ok {
+			codes[r.callingCode] = r
+			continue
+		}
+
+		if r.minLength < merged.minLength {
+			merged.minLength = r.minLength
+		}
+		if r.maxLength > merged.maxLength {
+			merged.maxLength = r.maxLength
+		}
+		codes[r.callingCode] = merged
+	}
+	return codes
+}()
@@ -0,0 +1,178 @@
+// Package phone validates phone numbers against bundled numbering
+// plan metadata, normalizes them to E.164 and formats them for display.
+//
+// The metadata only knows each country's calling code, trunk prefix
+// and number lengths, so a number of the right length is accepted
+// even where no such number is assigned.
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNotANumber         = errors.New("phone: not a number")
+	ErrUnknownRegion      = errors.New("phone: unknown region")
+	ErrInvalidCountryCode = errors.New("phone: invalid country calling code")
+	ErrTooShort           = errors.New("phone: too short")
+	ErrTooLong            = errors.New("phone: too long")
+)
+
+// Number is a parsed phone number.
+type Number struct {
+	// CountryCode is the calling code, without the leading +.
+	CountryCode string
+	// National is the national significant number,
+	// the digits after the calling code.
+	National string
+}
+
+// E164 returns the number as +<calling code><national number>.
+func (n Number) E164() string {
+	return "+" + n.CountryCode + n.National
+}
+
+// ValidRegion reports whether region is a known ISO 3166-1
+// alpha-2 code, in either case.
+func ValidRegion(region string) bool {
+	_, ok := regions[strings.ToUpper(region)]
+	return ok
+}
+
+// Parse reads raw as an international number when it starts with
+// + or 00, and as a national number of defaultRegion otherwise.
+// Spaces, dashes, dots, slashes and parentheses are ignored.
+func Parse(raw string, defaultRegion string) (Number, error) {
+	raw = strings.TrimSpace(raw)
+
+	international := strings.HasPrefix(raw, "+")
+	if international {
+		raw = raw[1:]
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(" -./()", r):
+		default:
+			return Number{}, ErrNotANumber
+		}
+	}
+
+	number := digits.String()
+	if number == "" {
+		return Number{}, ErrNotANumber
+	}
+
+	if !international && strings.HasPrefix(number, "00") {
+		international = true
+		number = number[2:]
+	}
+
+	if international {
+		return parseInternational(number)
+	}
+	return parseNational(number, defaultRegion)
+}
+
+func parseInternational(number string) (Number, error) {
+	for i := 1; i <= 3 && i < len(number); i++ {
+		plan, ok := callingCodes[number[:i]]
+		if !ok {
+			continue
+		}
+
+		national := number[i:]
+		// a trunk prefix left in, as in +62 0812..., is dropped
+		if plan.trunkPrefix != "" && strings.HasPrefix(national, plan.trunkPrefix) && len(national)-len(plan.trunkPrefix) >= plan.minLength {
+			national = national[len(plan.trunkPrefix):]
+		}
+		return newNumber(plan, national)
+	}
+
+	return Number{}, ErrInvalidCountryCode
+}
+
+func parseNational(number string, defaultRegion string) (Number, error) {
+	plan, ok := regions[strings.ToUpper(defaultRegion)]
+	if !ok {
+		return Number{}, ErrUnknownRegion
+	}
+
+	switch {
+	case plan.trunkPrefix != "" && strings.HasPrefix(number, plan.trunkPrefix):
+		number = number[len(plan.trunkPrefix):]
+	case strings.HasPrefix(number, plan.callingCode) && fits(plan, len(number)-len(plan.callingCode)):
+		// numbers dialled without + or the trunk prefix may still carry
+		// the calling code, which a country without a trunk prefix can
+		// only tell apart by the number being too long otherwise
+		if plan.trunkPrefix != "" || len(number) > plan.maxLength {
+			number = number[len(plan.callingCode):]
+		}
+	}
+
+	return newNumber(plan, number)
+}
+
+func fits(plan region, length int) bool {
+	return length >= plan.minLength && length <= plan.maxLength
+}
+
+func newNumber(plan region, national string) (Number, error) {
+	if len(national) < plan.minLength {
+		return Number{}, ErrTooShort
+	}
+	if len(national) > plan.maxLength {
+		return Number{}, ErrTooLong
+	}
+	return Number{CountryCode: plan.callingCode, National: national}, nil
+}
+
+// Normalize returns raw in E.164, as read by Parse.
+func Normalize(raw string, defaultRegion string) (string, error) {
+	number, err := Parse(raw, defaultRegion)
+	if err != nil {
+		return "", err
+	}
+	return number.E164(), nil
+}
+
+// Format returns an E.164 number spaced out for display, such as
+// +62 812 3456 7890 or +1 555-555-1234. Anything that is not
+// a valid E.164 number is returned as it is.
+func Format(e164 string) string {
+	if !strings.HasPrefix(e164, "+") {
+		return e164
+	}
+
+	number, err := Parse(e164, "")
+	if err != nil {
+		return e164
+	}
+
+	national := number.National
+	if number.CountryCode == "1" {
+		return "+1 " + national[:3] + "-" + national[3:6] + "-" + national[6:]
+	}
+
+	groups := []string{"+" + number.CountryCode}
+
+	// longer numbers lead with an area or operator code of three
+	// digits, the rest is split into groups of three or four
+	rest := national
+	if len(rest) > 8 {
+		groups = append(groups, rest[:3])
+		rest = rest[3:]
+	}
+	count := (len(rest) + 3) / 4
+	for i := 0; i < count; i++ {
+		size := len(rest) / (count - i)
+		groups = append(groups, rest[:size])
+		rest = rest[size:]
+	}
+
+	return strings.Join(groups, " ")
+}
@@ -0,0 +1,67 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		region  string
+		want    string
+		wantErr error
+	}{
+		{name: "national with trunk prefix", raw: "0812-3456-7890", region: "ID", want: "+6281234567890"},
+		{name: "international", raw: "+62 812 3456 7890", region: "US", want: "+6281234567890"},
+		{name: "international with trunk prefix", raw: "+62 (0)812 3456 7890", region: "ID", want: "+6281234567890"},
+		{name: "00 prefix", raw: "0044 20 7946 0958", region: "ID", want: "+442079460958"},
+		{name: "calling code without plus", raw: "6281234567890", region: "ID", want: "+6281234567890"},
+		{name: "nanp", raw: "(555) 555-5678", region: "US", want: "+15555555678"},
+		{name: "nanp with trunk prefix", raw: "1 555 555 5678", region: "us", want: "+15555555678"},
+		{name: "leading zero kept", raw: "06 1234 5678", region: "IT", want: "+390612345678"},
+		{name: "calling code without plus, no trunk prefix", raw: "39 06 1234 5678", region: "IT", want: "+390612345678"},
+		{name: "letters", raw: "0812-CALL-ME", region: "ID", wantErr: ErrNotANumber},
+		{name: "empty", raw: " ", region: "ID", wantErr: ErrNotANumber},
+		{name: "unknown region", raw: "0812 3456 7890", region: "XX", wantErr: ErrUnknownRegion},
+		{name: "unknown calling code", raw: "+999 1234 5678", region: "ID", wantErr: ErrInvalidCountryCode},
+		{name: "too short", raw: "555-5678", region: "US", wantErr: ErrTooShort},
+		{name: "too long", raw: "+62 812 3456 7890 123", region: "ID", wantErr: ErrTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.raw, tt.region)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		e164 string
+		want string
+	}{
+		{e164: "+15555555678", want: "+1 555-555-5678"},
+		{e164: "+6281234567890", want: "+62 812 3456 7890"},
+		{e164: "+442079460958", want: "+44 207 946 0958"},
+		{e164: "+6591234567", want: "+65 9123 4567"},
+		{e164: "555-5678", want: "555-5678"},
+		{e164: "+999", want: "+999"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.e164, func(t *testing.T) {
+			assert.Equal(t, tt.want, Format(tt.e164))
+		})
+	}
+}
+
+func TestValidRegion(t *testing.T) {
+	assert.True(t, ValidRegion("ID"))
+	assert.True(t, ValidRegion("gb"))
+	assert.False(t, ValidRegion("XX"))
+	assert.False(t, ValidRegion(""))
+}
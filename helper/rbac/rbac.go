@@ -0,0 +1,88 @@
+// Package rbac decides what a caller may do with contacts, from the
+// permissions of the roles they have.
+package rbac
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	PermissionRead   = "read"
+	PermissionWrite  = "write"
+	PermissionDelete = "delete"
+	PermissionPurge  = "purge"
+	PermissionAdmin  = "admin"
+)
+
+var permissions = map[string]bool{
+	PermissionRead:   true,
+	PermissionWrite:  true,
+	PermissionDelete: true,
+	PermissionPurge:  true,
+	PermissionAdmin:  true,
+}
+
+// Policy holds the permissions of every role and the roles of every
+// caller it knows by name. A caller it does not know has the default
+// role, if there is one.
+type Policy struct {
+	roles       map[string]map[string]bool
+	principals  map[string][]string
+	defaultRole string
+}
+
+// NewPolicy returns the policy of roles, the permissions of each role,
+// and principals, the roles of each caller. Names are not case
+// sensitive. Every role given to a caller, defaultRole included, has to
+// be among roles.
+func NewPolicy(roles map[string][]string, principals map[string][]string, defaultRole string) (*Policy, error) {
+	policy := new(Policy)
+	policy.roles = make(map[string]map[string]bool, len(roles))
+	policy.principals = make(map[string][]string, len(principals))
+	policy.defaultRole = strings.ToLower(strings.TrimSpace(defaultRole))
+
+	for role, granted := range roles {
+		allowed := make(map[string]bool, len(granted))
+		for _, permission := range granted {
+			permission = strings.ToLower(strings.TrimSpace(permission))
+			if !permissions[permission] {
+				return nil, fmt.Errorf("rbac: role %s has unknown permission %q", role, permission)
+			}
+			allowed[permission] = true
+		}
+		policy.roles[strings.ToLower(role)] = allowed
+	}
+
+	for name, assigned := range principals {
+		for _, role := range assigned {
+			role = strings.ToLower(strings.TrimSpace(role))
+			if _, ok := policy.roles[role]; !ok {
+				return nil, fmt.Errorf("rbac: %s has unknown role %q", name, role)
+			}
+			policy.principals[strings.ToLower(name)] = append(policy.principals[strings.ToLower(name)], role)
+		}
+	}
+
+	if _, ok := policy.roles[policy.defaultRole]; policy.defaultRole != "" && !ok {
+		return nil, fmt.Errorf("rbac: default role %q is unknown", policy.defaultRole)
+	}
+
+	return policy, nil
+}
+
+// Allowed reports whether the caller name, who has roles on top of the
+// ones the policy gives them, has permission.
+func (p *Policy) Allowed(name string, roles []string, permission string) bool {
+	roles = append(append([]string(nil), roles...), p.principals[strings.ToLower(name)]...)
+	if len(roles) == 0 && p.defaultRole != "" {
+		roles = []string{p.defaultRole}
+	}
+
+	for _, role := range roles {
+		if p.roles[strings.ToLower(role)][permission] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRoles = map[string][]string{
+	"intern":  {PermissionRead},
+	"support": {PermissionRead, PermissionWrite},
+	"admin":   {PermissionRead, PermissionWrite, PermissionDelete, PermissionPurge},
+}
+
+func TestPolicy_Allowed(t *testing.T) {
+	policy, err := NewPolicy(testRoles, map[string][]string{
+		"cli":   {"admin"},
+		"Alice": {"support"},
+	}, "intern")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name       string
+		caller     string
+		roles      []string
+		permission string
+		want       bool
+	}{
+		{name: "admin purges", caller: "cli", permission: PermissionPurge, want: true},
+		{name: "support edits", caller: "alice", permission: PermissionWrite, want: true},
+		{name: "support does not delete", caller: "ALICE", permission: PermissionDelete, want: false},
+		{name: "support from the token deletes as admin", caller: "alice", roles: []string{"Admin"}, permission: PermissionDelete, want: true},
+		{name: "default role reads", caller: "bob", permission: PermissionRead, want: true},
+		{name: "default role does not edit", caller: "bob", permission: PermissionWrite, want: false},
+		{name: "token role replaces the default role", caller: "bob", roles: []string{"support"}, permission: PermissionWrite, want: true},
+		{name: "unknown token role", caller: "bob", roles: []string{"root"}, permission: PermissionRead, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Allowed(tt.caller, tt.roles, tt.permission))
+		})
+	}
+
+	policy, err = NewPolicy(testRoles, nil, "")
+	if assert.NoError(t, err) {
+		assert.False(t, policy.Allowed("bob", nil, PermissionRead), "a caller without a role")
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	_, err := NewPolicy(map[string][]string{"intern": {"look"}}, nil, "")
+	assert.Error(t, err, "unknown permission")
+
+	_, err = NewPolicy(testRoles, map[string][]string{"alice": {"root"}}, "")
+	assert.Error(t, err, "unknown role")
+
+	_, err = NewPolicy(testRoles, nil, "root")
+	assert.Error(t, err, "unknown default role")
+}
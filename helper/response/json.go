@@ -9,13 +9,29 @@ type JsonResponse struct {
 	Status  int         `json:"status"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
+	Meta    interface{} `json:"meta,omitempty"`
+}
+
+// PageMeta describes where a paginated response sits in the full result.
+// NextOffset and Next are empty on the last page.
+type PageMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextOffset *int   `json:"next_offset"`
+	Next       string `json:"next,omitempty"`
 }
 
 func NewJsonResponse(w http.ResponseWriter, code int, message string, data interface{}) error {
+	return NewJsonResponseWithMeta(w, code, message, data, nil)
+}
+
+func NewJsonResponseWithMeta(w http.ResponseWriter, code int, message string, data interface{}, meta interface{}) error {
 	res := JsonResponse{
 		Status:  code,
 		Message: message,
 		Data:    data,
+		Meta:    meta,
 	}
 
 	w.WriteHeader(code)
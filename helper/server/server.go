@@ -0,0 +1,52 @@
+// Package server runs an http.Server until it is asked to stop, then
+// lets the requests in flight finish before returning.
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Run binds srv to its Addr, calls onListen with the address it is
+// bound to and serves until srv fails or ctx is done, over TLS when srv
+// has a TLSConfig. Once ctx is done, srv stops taking connections and
+// the requests in flight are given up to shutdownTimeout to finish,
+// zero waiting for as long as they take.
+func Run(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration, onListen func(addr net.Addr)) error {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	if onListen != nil {
+		onListen(listener.Addr())
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			//* the certificate is the TLSConfig's own to provide
+			served <- srv.ServeTLS(listener, "", "")
+			return
+		}
+		served <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx := context.Background()
+	if shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, shutdownTimeout)
+		defer cancel()
+	}
+
+	//* Serve returns http.ErrServerClosed as soon as Shutdown starts,
+	//* it is Shutdown that waits for the requests in flight
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDrainsRequestsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := &http.Server{
+		Addr: "127.0.0.1:0",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			io.WriteString(w, "done")
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listening := make(chan net.Addr, 1)
+	ran := make(chan error, 1)
+	go func() {
+		ran <- Run(ctx, srv, 5*time.Second, func(addr net.Addr) { listening <- addr })
+	}()
+	addr := <-listening
+
+	type result struct {
+		body string
+		err  error
+	}
+	answered := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr.String())
+		if err != nil {
+			answered <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		answered <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	cancel()
+
+	//* the server stops taking connections but waits for the request
+	_, err := net.DialTimeout("tcp", addr.String(), time.Second)
+	for i := 0; err == nil && i < 50; i++ {
+		time.Sleep(10 * time.Millisecond)
+		_, err = net.DialTimeout("tcp", addr.String(), time.Second)
+	}
+	assert.Error(t, err)
+	select {
+	case err := <-ran:
+		t.Fatalf("Run returned %v before the request in flight finished", err)
+	default:
+	}
+
+	close(release)
+	got := <-answered
+	if assert.NoError(t, got.err) {
+		assert.Equal(t, "done", got.body)
+	}
+	assert.NoError(t, <-ran)
+}
+
+func TestRunShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	srv := &http.Server{
+		Addr: "127.0.0.1:0",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listening := make(chan net.Addr, 1)
+	ran := make(chan error, 1)
+	go func() {
+		ran <- Run(ctx, srv, 50*time.Millisecond, func(addr net.Addr) { listening <- addr })
+	}()
+	addr := <-listening
+
+	go http.Get("http://" + addr.String())
+	<-started
+	cancel()
+
+	assert.ErrorIs(t, <-ran, context.DeadlineExceeded)
+}
+
+func TestRunAddressInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	srv := &http.Server{Addr: listener.Addr().String()}
+	err = Run(context.Background(), srv, time.Second, func(net.Addr) {
+		t.Error("onListen called without a listener")
+	})
+	assert.Error(t, err)
+}
@@ -9,7 +9,34 @@ func ShowMenuList() {
 	fmt.Println("3. Detail contact")
 	fmt.Println("4. Update contact")
 	fmt.Println("5. Delete contact")
-	fmt.Println("6. Exit")
+	fmt.Println("6. Search contact")
+	fmt.Println("7. Export contact ke vCard")
+	fmt.Println("8. Import contact dari vCard")
+	fmt.Println("9. Export contact ke CSV")
+	fmt.Println("10. Import contact dari CSV")
+	fmt.Println("11. Cari contact duplikat")
+	fmt.Println("12. Gabung contact")
+	fmt.Println("13. Lihat trash")
+	fmt.Println("14. Restore contact")
+	fmt.Println("15. Kosongkan trash")
+	fmt.Println("16. Kelola group")
+	fmt.Println("17. Undo perubahan")
+	fmt.Println("18. Revert contact")
+	fmt.Println("19. Exit")
+	fmt.Println()
+	fmt.Println("Pilih menu")
+}
+
+func ShowGroupMenuList() {
+	fmt.Println("Menu group")
+	fmt.Println("1. List group")
+	fmt.Println("2. Add group")
+	fmt.Println("3. Detail group")
+	fmt.Println("4. Update group")
+	fmt.Println("5. Delete group")
+	fmt.Println("6. Tambah contact ke group")
+	fmt.Println("7. Keluarkan contact dari group")
+	fmt.Println("8. Kembali")
 	fmt.Println()
 	fmt.Println("Pilih menu")
 }
@@ -0,0 +1,91 @@
+// Package tenant carries the address book a call works on through a
+// context, for the repositories to keep every address book apart.
+package tenant
+
+import (
+	"contact-go/helper/apperrors"
+	"context"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Default is the address book of a context that names none, which
+	// holds every contact made before there were address books.
+	Default = "default"
+	// Header is the request header an address book is asked for in.
+	Header = "X-Tenant"
+)
+
+// names keeps address book names fit for a file name and a column.
+var names = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type (
+	contextKey struct{}
+	boundKey   struct{}
+)
+
+// NewContext returns a copy of ctx carrying name as its address book.
+func NewContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext returns the address book ctx carries, or Default.
+func FromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(contextKey{}).(string); ok && name != "" {
+		return name
+	}
+	return Default
+}
+
+// NewBoundContext returns a copy of ctx carrying name as the address
+// book its caller is bound to.
+func NewBoundContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(NewContext(ctx, name), boundKey{}, true)
+}
+
+// Bound reports whether the caller of ctx is bound to its address book.
+func Bound(ctx context.Context) bool {
+	bound, _ := ctx.Value(boundKey{}).(bool)
+	return bound
+}
+
+// Normalize returns name the way address books are named, trimmed and
+// in lower case.
+func Normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Valid reports whether name, normalized, can name an address book:
+// up to 63 lower case letters, digits, dashes and underscores, starting
+// with a letter or a digit.
+func Valid(name string) bool {
+	return names.MatchString(name)
+}
+
+// Select returns the address book of a caller bound to bound, who asked
+// for requested. A caller bound to an address book only ever works on
+// that one, and fails with apperrors.ErrAddressBookForbidden asking for
+// another. Any other caller gets the one they asked for, or Default.
+// Either name has to be Valid.
+func Select(bound string, requested string) (string, error) {
+	bound, requested = Normalize(bound), Normalize(requested)
+
+	if bound != "" {
+		if !Valid(bound) {
+			return "", apperrors.NewAppError(apperrors.ErrAddressBookNameNotValid)
+		}
+		if requested != "" && requested != bound {
+			return "", apperrors.NewAppError(apperrors.ErrAddressBookForbidden)
+		}
+		return bound, nil
+	}
+
+	if requested == "" {
+		return Default, nil
+	}
+	if !Valid(requested) {
+		return "", apperrors.NewAppError(apperrors.ErrAddressBookNameNotValid)
+	}
+	return requested, nil
+}
@@ -0,0 +1,58 @@
+package tenant
+
+import (
+	"contact-go/helper/apperrors"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name      string
+		bound     string
+		requested string
+		want      string
+		wantErr   string
+	}{
+		{name: "nothing asked", want: Default},
+		{name: "asked", requested: " Sales ", want: "sales"},
+		{name: "asked for a name that is not valid", requested: "../sales", wantErr: apperrors.ErrAddressBookNameNotValid},
+		{name: "bound", bound: "sales", want: "sales"},
+		{name: "bound to a name that is not valid", bound: "../sales", wantErr: apperrors.ErrAddressBookNameNotValid},
+		{name: "bound and asked for the same", bound: "sales", requested: "SALES", want: "sales"},
+		{name: "bound and asked for another", bound: "sales", requested: "support", wantErr: apperrors.ErrAddressBookForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Select(tt.bound, tt.requested)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, name := range []string{"default", "sales", "sales-2023", "r_and_d", "0"} {
+		assert.True(t, Valid(name), "Valid(%q)", name)
+	}
+	for _, name := range []string{"", "Sales", "-sales", "sales/2023", "sales.json", "a b"} {
+		assert.False(t, Valid(name), "Valid(%q)", name)
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, "sales", FromContext(NewContext(context.Background(), "sales")))
+
+	assert.False(t, Bound(NewContext(context.Background(), "sales")))
+	ctx := NewBoundContext(context.Background(), "sales")
+	assert.True(t, Bound(ctx))
+	assert.Equal(t, "sales", FromContext(ctx))
+}
@@ -0,0 +1,192 @@
+// Package tlscert serves TLS with a certificate, and the CA bundle
+// client certificates are verified against, kept in files, loading them
+// again whenever a file changes so that a renewed certificate is served
+// without a restart.
+package tlscert
+
+import (
+	"contact-go/helper/apperrors"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkInterval is how often, at most, the files are looked at for a
+// change, so that a busy server does not stat them on every handshake.
+const checkInterval = time.Second
+
+// stamp tells whether a file changed since it was last loaded.
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Loader keeps the TLS config of its files.
+type Loader struct {
+	// OnReload, when set, is called every time the files changed and
+	// were loaded again, with the error they failed to load with, if
+	// any. What was loaded before keeps being served until they load.
+	OnReload func(err error)
+
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	clientAuth   tls.ClientAuthType
+
+	mu      sync.Mutex
+	config  *tls.Config
+	stamps  []stamp
+	checked time.Time
+	now     func() time.Time
+}
+
+// NewLoader loads the certificate and key of certFile and keyFile.
+// With a clientCAFile, client certificates are verified against the CA
+// bundle in it, and required of every client when requireClientCert is
+// set, which needs a clientCAFile. It fails when the files do not load,
+// so that a server does not start without them.
+func NewLoader(certFile string, keyFile string, clientCAFile string, requireClientCert bool) (*Loader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrTLSKeyPairNotExist)
+	}
+	if requireClientCert && clientCAFile == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrTLSClientCANotExist)
+	}
+
+	loader := new(Loader)
+	loader.certFile = certFile
+	loader.keyFile = keyFile
+	loader.clientCAFile = clientCAFile
+	loader.clientAuth = tls.NoClientCert
+	if clientCAFile != "" {
+		loader.clientAuth = tls.VerifyClientCertIfGiven
+		if requireClientCert {
+			loader.clientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	loader.now = time.Now
+
+	loader.stamps = loader.stat()
+	config, err := loader.load()
+	if err != nil {
+		return nil, err
+	}
+	loader.config = config
+	loader.checked = loader.now()
+
+	return loader, nil
+}
+
+// TLSConfig returns the config of a server, which hands every
+// handshake its own settings along with what the loader last loaded.
+func (l *Loader) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	base.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return &l.current().Certificates[0], nil
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		loaded := l.current()
+
+		config := base.Clone()
+		config.GetCertificate = nil
+		config.GetConfigForClient = nil
+		config.Certificates = loaded.Certificates
+		config.ClientAuth = loaded.ClientAuth
+		config.ClientCAs = loaded.ClientCAs
+		return config, nil
+	}
+	return base
+}
+
+// current returns the config of the files, loading them again first
+// when they changed since they were last looked at.
+func (l *Loader) current() *tls.Config {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.checked) < checkInterval {
+		return l.config
+	}
+	l.checked = now
+
+	stamps := l.stat()
+	if equalStamps(stamps, l.stamps) {
+		return l.config
+	}
+	//* kept even when the files fail to load, a certificate and key
+	//* written one after the other load once the second one is written
+	l.stamps = stamps
+
+	config, err := l.load()
+	if err == nil {
+		l.config = config
+	}
+	if l.OnReload != nil {
+		l.OnReload(err)
+	}
+
+	return l.config
+}
+
+func (l *Loader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   l.clientAuth,
+	}
+
+	if l.clientCAFile != "" {
+		bundle, err := os.ReadFile(l.clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, apperrors.NewAppError(apperrors.ErrTLSClientCANotValid)
+		}
+		config.ClientCAs = pool
+	}
+
+	return config, nil
+}
+
+// stat returns the stamps of the files, a zero one for a file that
+// cannot be looked at.
+func (l *Loader) stat() []stamp {
+	files := []string{l.certFile, l.keyFile}
+	if l.clientCAFile != "" {
+		files = append(files, l.clientCAFile)
+	}
+
+	stamps := make([]stamp, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			stamps[i] = stamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+func equalStamps(a []stamp, b []stamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,340 @@
+package tlscert
+
+import (
+	"contact-go/helper/apperrors"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the certificate and key ca issues to name, in PEM.
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// clientCert returns the tls certificate ca issues to the client name.
+func (ca *testCA) clientCert(t *testing.T, name string) *tls.Certificate {
+	certPEM, keyPEM := ca.issue(t, name, x509.ExtKeyUsageClientAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &cert
+}
+
+// writeFile writes data to path, modified at modTime so that a change
+// shows however coarse the clock of the file system is.
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake connects a client of clientConfig to a server of
+// serverConfig, returning what the server saw of the connection and
+// the name on the certificate the client was served.
+func handshake(t *testing.T, serverConfig *tls.Config, clientConfig *tls.Config) (tls.ConnectionState, string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	type accepted struct {
+		state tls.ConnectionState
+		err   error
+	}
+	done := make(chan accepted, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			done <- accepted{err: err}
+			return
+		}
+		defer conn.Close()
+		server := tls.Server(conn, serverConfig)
+		err = server.Handshake()
+		done <- accepted{state: server.ConnectionState(), err: err}
+	}()
+
+	client, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	var served string
+	if err == nil {
+		served = client.ConnectionState().PeerCertificates[0].Subject.CommonName
+		//* a client certificate is only turned down once the client
+		//* has finished its own side of the handshake
+		client.Read(make([]byte, 1))
+		client.Close()
+	}
+
+	got := <-done
+	if err == nil {
+		err = got.err
+	}
+	return got.state, served, err
+}
+
+func TestLoaderReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	ca := newCA(t, "test ca")
+	certPEM, keyPEM := ca.issue(t, "server-1", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, testNow)
+	writeFile(t, keyFile, keyPEM, testNow)
+
+	loader, err := NewLoader(certFile, keyFile, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := testNow
+	loader.now = func() time.Time { return clock }
+	loader.checked = clock
+	var reloads []error
+	loader.OnReload = func(err error) { reloads = append(reloads, err) }
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+	_, served, err := handshake(t, loader.TLSConfig(), clientConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "server-1", served)
+	}
+
+	//* renewed
+	certPEM, keyPEM = ca.issue(t, "server-2", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, testNow.Add(time.Minute))
+	writeFile(t, keyFile, keyPEM, testNow.Add(time.Minute))
+
+	_, served, err = handshake(t, loader.TLSConfig(), clientConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "server-1", served, "files looked at again before checkInterval")
+	}
+
+	clock = clock.Add(checkInterval)
+	_, served, err = handshake(t, loader.TLSConfig(), clientConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "server-2", served)
+	}
+	assert.Equal(t, []error{nil}, reloads)
+
+	//* half written, the certificate no longer matches the key
+	certPEM, _ = ca.issue(t, "server-3", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, testNow.Add(2*time.Minute))
+
+	clock = clock.Add(checkInterval)
+	_, served, err = handshake(t, loader.TLSConfig(), clientConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "server-2", served)
+	}
+	if assert.Len(t, reloads, 2) {
+		assert.Error(t, reloads[1])
+	}
+
+	clock = clock.Add(checkInterval)
+	_, _, err = handshake(t, loader.TLSConfig(), clientConfig)
+	assert.NoError(t, err)
+	assert.Len(t, reloads, 2, "files that did not change loaded again")
+}
+
+func TestLoaderHTTP2(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	ca := newCA(t, "test ca")
+	certPEM, keyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, testNow)
+	writeFile(t, keyFile, keyPEM, testNow)
+
+	loader, err := NewLoader(certFile, keyFile, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		TLSConfig: loader.TLSConfig(),
+	}
+	go srv.ServeTLS(listener, "", "")
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, ServerName: "localhost"},
+		ForceAttemptHTTP2: true,
+	}}
+
+	resp, err := client.Get("https://" + listener.Addr().String())
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, "HTTP/2.0", resp.Proto)
+		assert.Equal(t, "h2", resp.TLS.NegotiatedProtocol)
+	}
+}
+
+func TestLoaderClientCert(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	ca := newCA(t, "test ca")
+	certPEM, keyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, testNow)
+	writeFile(t, keyFile, keyPEM, testNow)
+	writeFile(t, caFile, ca.pem, testNow)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	other := newCA(t, "other ca")
+
+	tests := []struct {
+		name       string
+		require    bool
+		clientCert *tls.Certificate
+		want       string
+		wantErr    bool
+	}{
+		{name: "certificate of the ca", clientCert: ca.clientCert(t, "billing"), want: "billing"},
+		{name: "certificate of another ca", clientCert: other.clientCert(t, "billing"), wantErr: true},
+		{name: "no certificate"},
+		{name: "no certificate where one is required", require: true, wantErr: true},
+		{name: "certificate where one is required", require: true, clientCert: ca.clientCert(t, "billing"), want: "billing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader, err := NewLoader(certFile, keyFile, caFile, tt.require)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			//* sent whoever issued it, where the client would otherwise
+			//* leave out a certificate of a ca the server does not ask for
+			clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+			clientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				if tt.clientCert == nil {
+					return new(tls.Certificate), nil
+				}
+				return tt.clientCert, nil
+			}
+
+			state, _, err := handshake(t, loader.TLSConfig(), clientConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			if tt.want == "" {
+				assert.Empty(t, state.VerifiedChains)
+				return
+			}
+			if assert.NotEmpty(t, state.VerifiedChains) {
+				assert.Equal(t, tt.want, state.VerifiedChains[0][0].Subject.CommonName)
+			}
+		})
+	}
+}
+
+func TestNewLoader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	ca := newCA(t, "test ca")
+	certPEM, keyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, testNow)
+	writeFile(t, keyFile, keyPEM, testNow)
+	writeFile(t, caFile, []byte("not a certificate\n"), testNow)
+
+	_, err := NewLoader(certFile, "", "", false)
+	assert.EqualError(t, err, apperrors.ErrTLSKeyPairNotExist)
+
+	_, err = NewLoader(certFile, keyFile, "", true)
+	assert.EqualError(t, err, apperrors.ErrTLSClientCANotExist)
+
+	_, err = NewLoader(certFile, filepath.Join(dir, "missing.key"), "", false)
+	assert.Error(t, err)
+
+	_, err = NewLoader(certFile, keyFile, caFile, false)
+	assert.EqualError(t, err, apperrors.ErrTLSClientCANotValid)
+}
@@ -0,0 +1,393 @@
+package vcard
+
+import (
+	"bufio"
+	"contact-go/model"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxCardLine bounds an unfolded content line, which is enough
+// for any text property while still rejecting embedded photos.
+const maxCardLine = 1 << 20
+
+// Decoder reads contacts from a stream of cards, in any of vCard
+// 2.1, 3.0 and 4.0, accepting both CRLF and LF line breaks.
+type Decoder struct {
+	s    *bufio.Scanner
+	line int
+
+	// next physical line, read ahead to unfold the current one
+	peeked    string
+	hasPeeked bool
+
+	// content line pushed back by unread
+	unreadLine   string
+	unreadNumber int
+	hasUnread    bool
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	d := new(Decoder)
+	d.s = bufio.NewScanner(r)
+	d.s.Buffer(make([]byte, 0, 64*1024), maxCardLine)
+	return d
+}
+
+// Decode reads the next card. It returns io.EOF when there are no
+// cards left, and a *ParseError for a malformed card, after which
+// Decode may be called again to read the cards that follow it.
+// Any other error comes from the underlying reader.
+func (d *Decoder) Decode() (*model.Contact, error) {
+	start, err := d.begin()
+	if err != nil {
+		return nil, err
+	}
+
+	var props []property
+	var parseErr *ParseError
+	for {
+		line, n, err := d.next()
+		if err == io.EOF {
+			return nil, &ParseError{Line: start, Msg: "missing END:VCARD"}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.EqualFold(line, "END:VCARD") {
+			break
+		}
+		if strings.EqualFold(line, "BEGIN:VCARD") {
+			d.unread(line, n)
+			return nil, &ParseError{Line: start, Msg: "missing END:VCARD"}
+		}
+
+		prop, ok := parseProperty(line)
+		if !ok {
+			if parseErr == nil {
+				parseErr = &ParseError{Line: n, Msg: "malformed content line"}
+			}
+			continue
+		}
+		props = append(props, prop)
+	}
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	contact, msg := buildContact(props)
+	if msg != "" {
+		return nil, &ParseError{Line: start, Msg: msg}
+	}
+	return contact, nil
+}
+
+// begin skips to the next BEGIN:VCARD and returns its line. Anything
+// else found on the way is reported as one ParseError.
+func (d *Decoder) begin() (int, error) {
+	var parseErr *ParseError
+	for {
+		line, n, err := d.next()
+		if err == io.EOF && parseErr != nil {
+			return 0, parseErr
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if strings.EqualFold(line, "BEGIN:VCARD") {
+			if parseErr != nil {
+				d.unread(line, n)
+				return 0, parseErr
+			}
+			return n, nil
+		}
+		if strings.TrimSpace(line) != "" && parseErr == nil {
+			parseErr = &ParseError{Line: n, Msg: "expected BEGIN:VCARD"}
+		}
+	}
+}
+
+func (d *Decoder) unread(line string, n int) {
+	d.unreadLine = line
+	d.unreadNumber = n
+	d.hasUnread = true
+}
+
+// next returns the next unfolded content line and the number of
+// the physical line it starts on.
+func (d *Decoder) next() (string, int, error) {
+	if d.hasUnread {
+		d.hasUnread = false
+		return d.unreadLine, d.unreadNumber, nil
+	}
+
+	line, ok := d.physical()
+	if !ok {
+		return "", 0, d.err()
+	}
+	n := d.line
+
+	for {
+		cont, ok := d.physical()
+		if !ok {
+			break
+		}
+		if cont == "" || (cont[0] != ' ' && cont[0] != '\t') {
+			d.peeked = cont
+			d.hasPeeked = true
+			d.line--
+			break
+		}
+		line += cont[1:]
+	}
+
+	return line, n, nil
+}
+
+func (d *Decoder) physical() (string, bool) {
+	d.line++
+	if d.hasPeeked {
+		d.hasPeeked = false
+		return d.peeked, true
+	}
+
+	if !d.s.Scan() {
+		d.line--
+		return "", false
+	}
+	return strings.TrimSuffix(d.s.Text(), "\r"), true
+}
+
+func (d *Decoder) err() error {
+	if err := d.s.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+type property struct {
+	name   string
+	params map[string][]string
+	value  string
+}
+
+func (p property) types() []string {
+	return p.params["TYPE"]
+}
+
+func (p property) hasType(t string) bool {
+	for _, value := range p.types() {
+		if strings.EqualFold(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p property) preferred() bool {
+	_, ok := p.params["PREF"]
+	return ok || p.hasType("pref")
+}
+
+// parseProperty splits a content line into its name without the
+// group, its parameters and its raw value.
+func parseProperty(line string) (property, bool) {
+	colon := indexUnquoted(line, ':')
+	if colon <= 0 {
+		return property{}, false
+	}
+
+	prop := property{value: line[colon+1:], params: make(map[string][]string)}
+	parts := splitUnquoted(line[:colon], ';')
+
+	name := parts[0]
+	if dot := strings.LastIndexByte(name, '.'); dot >= 0 {
+		name = name[dot+1:]
+	}
+	if name == "" {
+		return property{}, false
+	}
+	prop.name = strings.ToUpper(name)
+
+	for _, param := range parts[1:] {
+		key, values, ok := strings.Cut(param, "=")
+		if !ok {
+			// vCard 2.1 writes TEL;WORK;CELL:...
+			key, values = "TYPE", param
+		}
+
+		key = strings.ToUpper(strings.TrimSpace(key))
+		for _, value := range splitUnquoted(values, ',') {
+			prop.params[key] = append(prop.params[key], strings.Trim(value, `"`))
+		}
+	}
+
+	return prop, true
+}
+
+func indexUnquoted(s string, c byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case c:
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	for {
+		i := indexUnquoted(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
+
+type tel struct {
+	number    string
+	types     []string
+	preferred bool
+}
+
+// buildContact maps the properties of one card to a contact, or
+// returns why the card cannot be one.
+func buildContact(props []property) (*model.Contact, string) {
+	contact := new(model.Contact)
+	var givenName, familyName string
+	var tels []tel
+
+	for _, prop := range props {
+		switch prop.name {
+		case "VERSION":
+			switch prop.value {
+			case "2.1", Version3, Version4:
+			default:
+				return nil, "unsupported version " + prop.value
+			}
+		case "FN":
+			contact.Name = strings.TrimSpace(unescapeText(prop.value))
+		case "N":
+			components := splitValue(prop.value, ';')
+			familyName = components[0]
+			if len(components) > 1 {
+				givenName = components[1]
+			}
+		case "TEL":
+			number := strings.TrimPrefix(unescapeText(prop.value), "tel:")
+			tels = append(tels, tel{
+				number:    strings.TrimSpace(number),
+				types:     prop.types(),
+				preferred: prop.preferred(),
+			})
+		case "EMAIL":
+			contact.Emails = append(contact.Emails, model.Email{
+				Type:    contactType(prop.types()),
+				Address: strings.TrimSpace(unescapeText(prop.value)),
+			})
+		case "ADR":
+			components := append(splitValue(prop.value, ';'), make([]string, 7)...)
+			contact.Addresses = append(contact.Addresses, model.Address{
+				Type:       contactType(prop.types()),
+				Street:     components[2],
+				City:       components[3],
+				Region:     components[4],
+				PostalCode: components[5],
+				Country:    components[6],
+			})
+		case "ORG":
+			contact.Company = splitValue(prop.value, ';')[0]
+		case "TITLE":
+			contact.JobTitle = unescapeText(prop.value)
+		case "BDAY":
+			contact.Birthday = parseBirthday(prop.value)
+		case "NOTE":
+			contact.Notes = unescapeText(prop.value)
+		case "REV":
+			contact.UpdatedAt = parseTimestamp(prop.value)
+		}
+	}
+
+	if contact.Name == "" {
+		contact.Name = strings.TrimSpace(strings.TrimSpace(givenName) + " " + strings.TrimSpace(familyName))
+	}
+	if contact.Name == "" {
+		return nil, "missing FN"
+	}
+
+	main := -1
+	for i, t := range tels {
+		if t.preferred {
+			main = i
+			break
+		}
+	}
+	if main < 0 && len(tels) > 0 {
+		main = 0
+	}
+
+	for i, t := range tels {
+		if i == main {
+			contact.NoTelp = t.number
+			continue
+		}
+		contact.Phones = append(contact.Phones, model.Phone{
+			Type:   contactType(t.types),
+			Number: t.number,
+		})
+	}
+
+	return contact, ""
+}
+
+// parseBirthday returns the date of a BDAY value in
+// model.BirthdayLayout, or "" when it has no full date,
+// such as the year-less --0517.
+func parseBirthday(value string) string {
+	value = strings.TrimSpace(value)
+
+	layouts := []string{model.BirthdayLayout, "20060102"}
+	for _, layout := range layouts {
+		if len(value) < len(layout) {
+			continue
+		}
+
+		date, err := time.Parse(layout, value[:len(layout)])
+		if err == nil {
+			return date.Format(model.BirthdayLayout)
+		}
+	}
+	return ""
+}
+
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"20060102T150405Z",
+	"20060102T150405Z0700",
+}
+
+func parseTimestamp(value string) time.Time {
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, strings.TrimSpace(value))
+		if err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
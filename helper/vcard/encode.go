@@ -0,0 +1,159 @@
+package vcard
+
+import (
+	"bufio"
+	"contact-go/model"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrVersionNotSupported = errors.New("vcard: version not supported")
+
+// Encoder writes contacts as cards of one vCard version.
+type Encoder struct {
+	w       *bufio.Writer
+	version string
+}
+
+func NewEncoder(w io.Writer, version string) *Encoder {
+	e := new(Encoder)
+	e.w = bufio.NewWriter(w)
+	e.version = version
+	return e
+}
+
+// Encode writes contact as one card.
+func (e *Encoder) Encode(contact *model.Contact) error {
+	if !ValidVersion(e.version) {
+		return ErrVersionNotSupported
+	}
+
+	e.writeLine("BEGIN:VCARD")
+	e.writeLine("VERSION:" + e.version)
+	e.writeLine("FN:" + escapeText(contact.Name))
+
+	given, family := splitName(contact.Name)
+	e.writeLine("N:" + escapeText(family) + ";" + escapeText(given) + ";;;")
+
+	if contact.NoTelp != "" {
+		if e.version == Version3 {
+			e.writeLine("TEL;TYPE=VOICE,PREF:" + escapeText(contact.NoTelp))
+		} else {
+			e.writeLine("TEL;VALUE=text;PREF=1:" + escapeText(contact.NoTelp))
+		}
+	}
+	for _, phone := range contact.Phones {
+		params := e.typeParams(phone.Type)
+		if e.version == Version4 {
+			params = ";VALUE=text" + params
+		}
+		e.writeLine("TEL" + params + ":" + escapeText(phone.Number))
+	}
+
+	for _, email := range contact.Emails {
+		params := e.typeParams(email.Type)
+		if e.version == Version3 {
+			params = ";TYPE=INTERNET" + strings.Replace(params, ";TYPE=", ",", 1)
+		}
+		e.writeLine("EMAIL" + params + ":" + escapeText(email.Address))
+	}
+
+	for _, address := range contact.Addresses {
+		components := []string{
+			"", "",
+			address.Street,
+			address.City,
+			address.Region,
+			address.PostalCode,
+			address.Country,
+		}
+		for i, component := range components {
+			components[i] = escapeText(component)
+		}
+		e.writeLine("ADR" + e.typeParams(address.Type) + ":" + strings.Join(components, ";"))
+	}
+
+	if contact.Company != "" {
+		e.writeLine("ORG:" + escapeText(contact.Company))
+	}
+	if contact.JobTitle != "" {
+		e.writeLine("TITLE:" + escapeText(contact.JobTitle))
+	}
+	if contact.Birthday != "" {
+		e.writeLine("BDAY:" + e.birthday(contact.Birthday))
+	}
+	if contact.Notes != "" {
+		e.writeLine("NOTE:" + escapeText(contact.Notes))
+	}
+	if !contact.UpdatedAt.IsZero() {
+		e.writeLine("REV:" + e.timestamp(contact.UpdatedAt))
+	}
+
+	e.writeLine("END:VCARD")
+	return e.w.Flush()
+}
+
+func (e *Encoder) typeParams(contactType string) string {
+	if value := typeParam(contactType, e.version); value != "" {
+		return ";TYPE=" + value
+	}
+	return ""
+}
+
+// birthday writes a model.BirthdayLayout date in the basic format
+// vCard 4.0 asks for, keeping it as is for 3.0.
+func (e *Encoder) birthday(birthday string) string {
+	if e.version == Version3 {
+		return birthday
+	}
+
+	date, err := time.Parse(model.BirthdayLayout, birthday)
+	if err != nil {
+		return escapeText(birthday)
+	}
+	return date.Format("20060102")
+}
+
+func (e *Encoder) timestamp(t time.Time) string {
+	t = t.UTC()
+	if e.version == Version3 {
+		return t.Format("2006-01-02T15:04:05Z")
+	}
+	return t.Format("20060102T150405Z")
+}
+
+// writeLine writes a content line, folding it every maxLineLength
+// octets without splitting a UTF-8 sequence.
+func (e *Encoder) writeLine(line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		_, _ = e.w.WriteString(line[:cut])
+		_, _ = e.w.WriteString("\r\n ")
+		line = line[cut:]
+
+		// the leading space of a continuation counts towards its length
+		limit = maxLineLength - 1
+	}
+
+	_, _ = e.w.WriteString(line)
+	_, _ = e.w.WriteString("\r\n")
+}
+
+// splitName guesses the given and family name for the N property,
+// taking the last word as the family name.
+func splitName(name string) (given string, family string) {
+	name = strings.TrimSpace(name)
+	i := strings.LastIndex(name, " ")
+	if i < 0 {
+		return name, ""
+	}
+	return strings.TrimSpace(name[:i]), name[i+1:]
+}
@@ -0,0 +1,130 @@
+// Package vcard reads and writes contacts as vCard 3.0 (RFC 2426)
+// and vCard 4.0 (RFC 6350) cards.
+//
+// A contact's NoTelp is written as the preferred TEL of the card,
+// and read back from the preferred TEL, or the first one when
+// none is preferred.
+package vcard
+
+import (
+	"contact-go/model"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version3 = "3.0"
+	Version4 = "4.0"
+
+	// MediaType is the content type of a .vcf file.
+	MediaType = "text/vcard; charset=utf-8"
+
+	// maxLineLength is the length in octets a content line
+	// is folded at, not counting the line break.
+	maxLineLength = 75
+)
+
+// ValidVersion reports whether version can be written by an Encoder.
+func ValidVersion(version string) bool {
+	return version == Version3 || version == Version4
+}
+
+// ParseError is a card that could not be read. The Decoder skips
+// past it, so decoding may carry on with the next card.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return "vcard: line " + strconv.Itoa(e.Line) + ": " + e.Msg
+}
+
+// typeParam returns the TYPE value of a model type, or "" for
+// model.TypeOther, which vCard has no type for.
+func typeParam(contactType string, version string) string {
+	var value string
+	switch contactType {
+	case model.TypeHome:
+		value = "home"
+	case model.TypeWork:
+		value = "work"
+	case model.TypeMobile:
+		value = "cell"
+	default:
+		return ""
+	}
+
+	if version == Version3 {
+		return strings.ToUpper(value)
+	}
+	return value
+}
+
+// contactType returns the model type of the first TYPE value it knows,
+// or model.TypeOther when there is none.
+func contactType(types []string) string {
+	for _, t := range types {
+		switch strings.ToLower(t) {
+		case "home":
+			return model.TypeHome
+		case "work":
+			return model.TypeWork
+		case "cell", "mobile", "iphone":
+			return model.TypeMobile
+		}
+	}
+	return model.TypeOther
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	",", `\,`,
+	";", `\;`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitValue splits a structured value such as N or ADR on the
+// separators that are not escaped, unescaping each component.
+func splitValue(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, unescapeText(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, unescapeText(s[start:]))
+}
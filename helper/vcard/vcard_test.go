@@ -0,0 +1,158 @@
+package vcard
+
+import (
+	"bytes"
+	"contact-go/model"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testContact = model.Contact{
+	Name:   "Jane Smith",
+	NoTelp: "555-555-5678",
+	Phones: []model.Phone{
+		{Type: model.TypeWork, Number: "555-555-0000"},
+		{Type: model.TypeMobile, Number: "555-555-1111"},
+		{Type: model.TypeOther, Number: "555-555-2222"},
+	},
+	Emails: []model.Email{
+		{Type: model.TypeHome, Address: "jane@example.com"},
+	},
+	Addresses: []model.Address{
+		{Type: model.TypeWork, Street: "Jl. Sudirman 1, Lt. 2; Blok A", City: "Jakarta", PostalCode: "10220", Country: "Indonesia"},
+	},
+	Company:   "Acme",
+	JobTitle:  "Engineer",
+	Birthday:  "1990-05-17",
+	Notes:     "met at a conference,\nlikes coffee \\ tea",
+	UpdatedAt: time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC),
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, version := range []string{Version3, Version4} {
+		t.Run(version, func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder := NewEncoder(&buf, version)
+			assert.NoError(t, encoder.Encode(&testContact))
+			assert.NoError(t, encoder.Encode(&model.Contact{Name: "Reva", NoTelp: "000"}))
+
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+				assert.LessOrEqual(t, len(line), maxLineLength, line)
+			}
+
+			decoder := NewDecoder(&buf)
+
+			got, err := decoder.Decode()
+			assert.NoError(t, err)
+			assert.Equal(t, &testContact, got)
+
+			got, err = decoder.Decode()
+			assert.NoError(t, err)
+			assert.Equal(t, &model.Contact{Name: "Reva", NoTelp: "000"}, got)
+
+			_, err = decoder.Decode()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestEncode_version(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewEncoder(&buf, "2.1").Encode(&testContact)
+	assert.Equal(t, ErrVersionNotSupported, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestEncode_fold(t *testing.T) {
+	var buf bytes.Buffer
+	contact := model.Contact{Name: "Jane", Notes: strings.Repeat("é", 100)}
+	assert.NoError(t, NewEncoder(&buf, Version4).Encode(&contact))
+
+	assert.Contains(t, buf.String(), "\r\n ")
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, line)
+	}
+
+	got, err := NewDecoder(&buf).Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, contact.Notes, got.Notes)
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		card string
+		want *model.Contact
+	}{
+		{
+			name: "vcard 2.1 with LF line breaks",
+			card: "BEGIN:VCARD\nVERSION:2.1\nN:Smith;Jane\nTEL;WORK;VOICE:555-555-0000\nTEL;CELL;PREF:555-555-5678\nEND:VCARD\n",
+			want: &model.Contact{
+				Name:   "Jane Smith",
+				NoTelp: "555-555-5678",
+				Phones: []model.Phone{{Type: model.TypeWork, Number: "555-555-0000"}},
+			},
+		},
+		{
+			name: "folded lines, groups and quoted params",
+			card: "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Jane\r\n  Smith\r\nitem1.TEL;TYPE=\"home,voice\";VALUE=uri:tel:555-555-5678\r\n" +
+				"item2.EMAIL;TYPE=work:jane@example.com\r\nBDAY:19900517T000000\r\nEND:VCARD\r\n",
+			want: &model.Contact{
+				Name:     "Jane Smith",
+				NoTelp:   "555-555-5678",
+				Emails:   []model.Email{{Type: model.TypeWork, Address: "jane@example.com"}},
+				Birthday: "1990-05-17",
+			},
+		},
+		{
+			name: "year-less birthday",
+			card: "BEGIN:VCARD\nVERSION:4.0\nFN:Jane\nTEL:555\nBDAY:--0517\nEND:VCARD\n",
+			want: &model.Contact{Name: "Jane", NoTelp: "555"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDecoder(strings.NewReader(tt.card)).Decode()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecode_recover(t *testing.T) {
+	cards := strings.Join([]string{
+		"BEGIN:VCARD", "VERSION:3.0", "FN:First", "TEL:111", "END:VCARD",
+		"BEGIN:VCARD", "VERSION:3.0", "TEL:222", "END:VCARD",
+		"BEGIN:VCARD", "VERSION:3.0", "FN:Third", "no colon here", "END:VCARD",
+		"garbage",
+		"BEGIN:VCARD", "VERSION:5.0", "FN:Fifth", "END:VCARD",
+		"BEGIN:VCARD", "VERSION:3.0", "FN:Sixth",
+		"BEGIN:VCARD", "VERSION:3.0", "FN:Seventh", "TEL:777", "END:VCARD",
+		"BEGIN:VCARD", "FN:Eighth",
+	}, "\n")
+	decoder := NewDecoder(strings.NewReader(cards))
+
+	wantErrLines := []int{0, 6, 13, 15, 16, 20, 0, 28}
+	for i, wantLine := range wantErrLines {
+		got, err := decoder.Decode()
+		if wantLine == 0 {
+			assert.NoError(t, err, "card %d", i+1)
+			assert.NotNil(t, got, "card %d", i+1)
+			continue
+		}
+
+		var parseErr *ParseError
+		if assert.True(t, errors.As(err, &parseErr), "card %d: %v", i+1, err) {
+			assert.Equal(t, wantLine, parseErr.Line, "card %d: %v", i+1, err)
+		}
+	}
+
+	_, err := decoder.Decode()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,57 @@
+// Package version tells which build is running, as stamped at build
+// time with
+//
+//	go build -ldflags "-X contact-go/helper/version.Version=v1.2.0 -X contact-go/helper/version.Commit=$(git rev-parse HEAD)"
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	// Version is the version of the build, dev when it was not stamped.
+	Version = "dev"
+	// Commit is the commit the build is of, which falls back to the one
+	// the go tool records when it was not stamped.
+	Commit = ""
+)
+
+// Info is which build is running, and on what storage.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"go_version"`
+	Storage   string `json:"storage"`
+	Driver    string `json:"driver,omitempty"`
+}
+
+// Get returns the info of this build, keeping contacts in storage
+// through driver.
+func Get(storage string, driver string) Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		GoVersion: runtime.Version(),
+		Storage:   storage,
+		Driver:    driver,
+	}
+	if info.Commit == "" {
+		info.Commit = vcsRevision()
+	}
+	return info
+}
+
+// vcsRevision returns the commit the go tool recorded the build of, if any.
+func vcsRevision() string {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range buildInfo.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
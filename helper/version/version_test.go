@@ -0,0 +1,27 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	defer func(version string, commit string) { Version, Commit = version, commit }(Version, Commit)
+
+	Version, Commit = "v1.2.0", "0a4ba5d"
+	assert.Equal(t, Info{
+		Version:   "v1.2.0",
+		Commit:    "0a4ba5d",
+		GoVersion: runtime.Version(),
+		Storage:   "sql",
+		Driver:    "mysql",
+	}, Get("sql", "mysql"))
+
+	Version, Commit = "dev", ""
+	info := Get("json", "")
+	assert.Equal(t, "dev", info.Version)
+	assert.Equal(t, "json", info.Storage)
+	assert.Empty(t, info.Driver)
+}
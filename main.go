@@ -5,81 +5,343 @@ import (
 	"contact-go/config/db"
 	"contact-go/handler"
 	"contact-go/helper"
+	"contact-go/helper/actor"
+	"contact-go/helper/auth"
+	"contact-go/helper/health"
 	"contact-go/helper/input"
+	"contact-go/helper/jwt"
 	"contact-go/helper/logger"
+	"contact-go/helper/rbac"
+	"contact-go/helper/server"
+	"contact-go/helper/tenant"
+	"contact-go/helper/tlscert"
+	"contact-go/helper/version"
 	"contact-go/middleware"
 	"contact-go/repository"
 	"contact-go/usecase"
+	"context"
+	"database/sql"
+	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 )
 
 func main() {
+	addressBook := flag.String("tenant", tenant.Default, "address book the menu works on")
+	flag.Parse()
+
 	config, err := config.LoadConfig()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if args := flag.Args(); len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := runMigrate(config, args[1:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "doctor":
+			if err := runDoctor(config); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
 	l := logger.New(true)
 
-	contactUC := createContactUsecase(config)
+	contactUC, groupUC, addressBookUC, sqlDB := createUsecases(config, l)
 
 	switch config.Mode {
 	case "http":
-		contactHTTPHandler := handler.NewContactHTTPHandler(contactUC)
-		err := NewServer(config.Port, l, contactHTTPHandler)
+		authenticator, err := newAuthenticator(config.Auth, config.TLS)
+		if err != nil {
+			l.Fatal().Err(err).Msg("auth fail to load")
+		}
+		if authenticator == nil {
+			l.Warn().Msg("auth is off, anyone who can reach the port can read and change contacts")
+		}
+
+		contactHTTPHandler := handler.NewContactHTTPHandler(contactUC, config.Import.MaxSize, config.Import.MaxRows)
+		groupHTTPHandler := handler.NewGroupHTTPHandler(groupUC)
+		addressBookHTTPHandler := handler.NewAddressBookHTTPHandler(addressBookUC)
+		healthHTTPHandler := handler.NewHealthHTTPHandler(storageChecks(config, sqlDB), config.Database.Timeout, version.Get(storageOf(config)))
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err = NewServer(ctx, config, l, authenticator, addressBookUC, contactHTTPHandler, groupHTTPHandler, addressBookHTTPHandler, healthHTTPHandler)
+		stop()
 		if err != nil {
 			l.Fatal().Err(err).Msg("server fail to start")
 		}
+
+		//* only once the requests in flight are done with it
+		if sqlDB != nil {
+			if err := sqlDB.Close(); err != nil {
+				l.Error().Err(err).Msg("database fail to close")
+			}
+		}
+		l.Info().Msg("server stopped")
 	default:
+		book, err := addressBookUC.Detail(context.Background(), *addressBook)
+		if err != nil {
+			l.Fatal().Err(err).Str("tenant", *addressBook).Msg("address book fail to load")
+		}
+
 		input := input.NewInputReader(os.Stdin)
-		contactCLIHandler := handler.NewContactHandler(contactUC, input)
+		contactCLIHandler := handler.NewContactHandler(contactUC, input, book.Name)
+		groupCLIHandler := handler.NewGroupHandler(groupUC, input, book.Name)
 
-		menu := handler.NewMenu(contactCLIHandler, input, helper.ClearTerminal, helper.ShowMenuList)
-		err := menu.ShowMenu()
+		menu := handler.NewMenu(contactCLIHandler, groupCLIHandler, input, helper.ClearTerminal, helper.ShowMenuList, helper.ShowGroupMenuList)
+		err = menu.ShowMenu()
 		if err != nil {
 			l.Fatal().Err(err).Msg("server fail to start")
 		}
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
 	}
 }
 
-func createContactUsecase(config *config.Config) usecase.ContactUsecase {
+// createUsecases returns the usecases of the configured storage, along
+// with the database pool they share, nil for the json and memory storages.
+// The audit entries the json and memory storages fail to store are logged
+// to l.
+func createUsecases(config *config.Config, l *logger.Logger) (usecase.ContactUsecase, usecase.GroupUsecase, usecase.AddressBookUsecase, *sql.DB) {
+	var sqlDB *sql.DB
 	var contactRepo repository.ContactRepository
+	var groupRepo repository.GroupRepository
+	var auditRepo repository.AuditRepository
+	var addressBookRepo repository.AddressBookRepository
+	var transactor repository.Transactor
 	switch config.Storage {
 	case "sql":
 		switch config.Database.Driver {
 		case "mysql":
-			db, err := db.NewMysqlDatabase(config)
+			var err error
+			sqlDB, err = db.NewMysqlDatabase(config)
 			if err != nil {
 				log.Fatal(err)
 			}
-			contactRepo = repository.NewContactMysqlRepository(db)
+			if config.Database.AutoMigrate {
+				autoMigrate(sqlDB, db.DialectMysql)
+			}
+			contactRepo = repository.NewContactMysqlRepository(sqlDB)
+			groupRepo = repository.NewGroupMysqlRepository(sqlDB)
+			auditRepo = repository.NewAuditMysqlRepository(sqlDB)
+			transactor = repository.NewMysqlTransactor(sqlDB)
+			addressBookRepo = repository.NewAddressBookMysqlRepository(sqlDB)
 		case "gorm":
-			db, err := db.NewGormDatabase(config)
+			gormDB, err := db.NewGormDatabase(config)
+			if err != nil {
+				log.Fatal(err)
+			}
+			sqlDB, err = gormDB.DB()
+			if err != nil {
+				log.Fatal(err)
+			}
+			if config.Database.AutoMigrate {
+				autoMigrate(sqlDB, db.DialectPostgres)
+			}
+			contactRepo = repository.NewContactGormRepository(gormDB)
+			groupRepo = repository.NewGroupGormRepository(gormDB)
+			auditRepo = repository.NewAuditGormRepository(gormDB)
+			transactor = repository.NewGormTransactor(gormDB)
+			addressBookRepo = repository.NewAddressBookGormRepository(gormDB)
+		case "sqlite":
+			var err error
+			sqlDB, err = db.NewSqliteDatabase(config)
 			if err != nil {
 				log.Fatal(err)
 			}
-			contactRepo = repository.NewContactGormRepository(db)
+			contactRepo = repository.NewContactSqliteRepository(sqlDB)
+			groupRepo = repository.NewGroupSqliteRepository(sqlDB)
+			auditRepo = repository.NewAuditSqliteRepository(sqlDB)
+			transactor = repository.NewSqliteTransactor(sqlDB)
+			addressBookRepo = repository.NewAddressBookSqliteRepository(sqlDB)
 		default:
 			log.Fatalln("database driver not existed")
 		}
 	case "json":
-		jsonFilePath := "data/contact.json"
-		contactRepo = repository.NewContactJsonRepository(jsonFilePath)
+		//* every address book but the default one is kept in files of its own
+		contactRepo = repository.NewContactTenantRepository(func(name string) repository.ContactRepository {
+			return repository.NewContactJsonRepository(repository.TenantPath(config.JSON.Path, name), config.JSON.Backups)
+		})
+		groupRepo = repository.NewGroupTenantRepository(func(name string) repository.GroupRepository {
+			return repository.NewGroupJsonRepository(repository.TenantPath(config.JSON.GroupsPath, name))
+		})
+		auditRepo = repository.NewAuditTenantRepository(func(name string) repository.AuditRepository {
+			return repository.NewAuditJsonRepository(repository.TenantPath(config.JSON.AuditPath, name))
+		})
+		addressBookRepo = repository.NewAddressBookJsonRepository(config.JSON.AddressBooksPath, config.JSON.Path)
 	default:
-		contactRepo = repository.NewContactRepository()
+		contactRepo = repository.NewContactTenantRepository(func(string) repository.ContactRepository {
+			return repository.NewContactRepository()
+		})
+		groupRepo = repository.NewGroupTenantRepository(func(string) repository.GroupRepository {
+			return repository.NewGroupRepository()
+		})
+		auditRepo = repository.NewAuditTenantRepository(func(string) repository.AuditRepository {
+			return repository.NewAuditRepository()
+		})
+		addressBookRepo = repository.NewAddressBookRepository()
 	}
-	return usecase.NewContactUsecase(contactRepo)
+
+	contactUC := usecase.NewContactUsecase(contactRepo, groupRepo, auditRepo, transactor, config.Database.Timeout, config.Phone.DefaultRegion, config.Trash.Retention, l)
+	groupUC := usecase.NewGroupUsecase(groupRepo, contactRepo, config.Database.Timeout)
+	addressBookUC := usecase.NewAddressBookUsecase(addressBookRepo, config.Database.Timeout)
+	if config.RBAC.Enabled() {
+		principals, err := rbacPrincipals(config.RBAC)
+		if err != nil {
+			log.Fatal(err)
+		}
+		policy, err := rbac.NewPolicy(config.RBAC.Roles, principals, config.RBAC.DefaultRole)
+		if err != nil {
+			log.Fatal(err)
+		}
+		contactUC = usecase.NewAuthorizedContactUsecase(contactUC, policy)
+		groupUC = usecase.NewAuthorizedGroupUsecase(groupUC, policy)
+		addressBookUC = usecase.NewAuthorizedAddressBookUsecase(addressBookUC, policy)
+	}
+	return contactUC, groupUC, addressBookUC, sqlDB
+}
+
+// rbacPrincipals returns the roles config gives each caller, by the
+// actor ID of the cli and the principal ID of everyone else.
+func rbacPrincipals(config config.RBAC) (map[string][]string, error) {
+	principals := map[string][]string{actor.CLI: config.CLI}
+	for namespace, names := range config.Principals {
+		for name, roles := range names {
+			id, err := auth.QualifiedID(namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			principals[id] = roles
+		}
+	}
+	return principals, nil
+}
+
+// tenantPrincipals returns the address book config binds each caller
+// to, by principal ID.
+func tenantPrincipals(config config.Tenant) (map[string]string, error) {
+	principals := make(map[string]string)
+	for namespace, names := range config.Principals {
+		for name, addressBook := range names {
+			id, err := auth.QualifiedID(namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			principals[id] = addressBook
+		}
+	}
+	return principals, nil
 }
 
-func NewServer(port string, logger *logger.Logger, handler handler.ContactHTTPHandler) error {
+// storageOf returns the storage contacts are kept in, and the
+// database driver when that is sql.
+func storageOf(config *config.Config) (string, string) {
+	switch config.Storage {
+	case "sql":
+		return config.Storage, config.Database.Driver
+	case "json":
+		return config.Storage, ""
+	default:
+		return "memory", ""
+	}
+}
+
+// storageChecks returns the checks of whether the configured storage
+// can be reached, sqlDB being its database pool when it has one. The
+// json storage is checked by the files of the default address book,
+// the others are kept next to them.
+func storageChecks(config *config.Config, sqlDB *sql.DB) []health.Check {
+	switch {
+	case sqlDB != nil:
+		return []health.Check{health.Ping(sqlDB)}
+	case config.Storage == "json":
+		return []health.Check{
+			health.File("contacts", config.JSON.Path),
+			health.File("groups", config.JSON.GroupsPath),
+			health.File("audit", config.JSON.AuditPath),
+			health.File("address_books", config.JSON.AddressBooksPath),
+		}
+	default:
+		return nil
+	}
+}
+
+// newAuthenticator returns the authenticator of the http api,
+// nil while auth is off.
+func newAuthenticator(config config.Auth, tls config.TLS) (*auth.Authenticator, error) {
+	if !config.Enabled() && !tls.ClientCerts() {
+		return nil, nil
+	}
+
+	var verifier *jwt.Verifier
+	if config.JWTKeyFile != "" {
+		var err error
+		verifier, err = jwt.LoadVerifier(config.JWTKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		verifier.Issuer = config.JWTIssuer
+		verifier.Audience = config.JWTAudience
+	}
+
+	authenticator, err := auth.NewAuthenticator(config.APIKeys, verifier)
+	if err != nil {
+		return nil, err
+	}
+	authenticator.Scope = config.JWTScope
+	authenticator.ClientCerts = tls.ClientCerts()
+
+	return authenticator, nil
+}
+
+// NewServer serves the http api on the configured host and port, over
+// https when tls is configured, to the callers authenticator accepts
+// or, when it is nil, to anyone, each in the address book the tenant
+// config and addressBookUC let them work on, along with the probes and
+// version of healthHandler, which anyone may call. It returns once ctx
+// is done and the requests in flight finished.
+func NewServer(ctx context.Context, config *config.Config, logger *logger.Logger, authenticator *auth.Authenticator, addressBookUC usecase.AddressBookUsecase, handler handler.ContactHTTPHandler, groupHandler handler.GroupHTTPHandler, addressBookHandler handler.AddressBookHTTPHandler, healthHandler handler.HealthHTTPHandler) error {
+	principals, err := tenantPrincipals(config.Tenant)
+	if err != nil {
+		return err
+	}
+
 	mux := http.NewServeMux()
 
 	muxMiddleware := new(middleware.Middleware)
 	muxMiddleware.Handler = mux
 
-	muxMiddleware.Use(middleware.Cors)
+	//* the middlewares used first run last, so preflight requests are
+	//* answered by Cors before they would need credentials, and the
+	//* address book is picked once the caller is known
+	muxMiddleware.Use(
+		func(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
+			return middleware.Tenant(principals, addressBookUC, w, r, next)
+		},
+	)
+	if authenticator != nil {
+		muxMiddleware.Use(
+			func(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
+				return middleware.Auth(authenticator, w, r, next)
+			},
+		)
+	}
+	muxMiddleware.Use(
+		func(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
+			return middleware.Cors(config.CORS.AllowedOrigins, w, r, next)
+		},
+	)
 	muxMiddleware.Use(middleware.ContentTypeJson)
 	muxMiddleware.Use(
 		func(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
@@ -105,13 +367,142 @@ func NewServer(port string, logger *logger.Logger, handler handler.ContactHTTPHa
 		}
 	})
 
+	mux.HandleFunc("/contacts/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			handler.Search(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/export.vcf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			handler.ExportVCard(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/export.csv", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			handler.ExportCSV(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/import.csv", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "POST":
+			handler.ImportCSV(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/import", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "POST":
+			handler.ImportVCard(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/duplicates", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			handler.Duplicates(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/merge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "POST":
+			handler.Merge(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/batch", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "POST":
+			handler.Batch(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/trash", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			handler.Trash(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/contacts/trash/purge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "POST":
+			handler.Purge(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
 	mux.HandleFunc("/contacts/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("content-type", "application/json")
 		switch r.Method {
 		case "GET":
+			if strings.HasSuffix(r.URL.Path, ".vcf") {
+				handler.DetailVCard(w, r)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/history") {
+				handler.History(w, r)
+				return
+			}
 			handler.Detail(w, r)
-		case "PATCH":
+		case "POST":
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/restore"):
+				handler.Restore(w, r)
+			case strings.HasSuffix(r.URL.Path, "/revert"):
+				handler.Revert(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		case "PUT":
 			handler.Update(w, r)
+		case "PATCH":
+			handler.Patch(w, r)
 		case "DELETE":
 			handler.Delete(w, r)
 		default:
@@ -120,16 +511,125 @@ func NewServer(port string, logger *logger.Logger, handler handler.ContactHTTPHa
 		}
 	})
 
-	server := &http.Server{
-		Addr:    "localhost:" + port,
-		Handler: muxMiddleware,
+	mux.HandleFunc("/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			groupHandler.List(w, r)
+		case "POST":
+			groupHandler.Add(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/groups/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/members") {
+			switch r.Method {
+			case "GET":
+				groupHandler.Members(w, r)
+			case "POST":
+				groupHandler.AddMembers(w, r)
+			case "DELETE":
+				groupHandler.RemoveMembers(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			groupHandler.Detail(w, r)
+		case "PUT":
+			groupHandler.Update(w, r)
+		case "DELETE":
+			groupHandler.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	mux.HandleFunc("/address-books", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			addressBookHandler.List(w, r)
+		case "POST":
+			addressBookHandler.Add(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	//* probes come without credentials or an address book, so they are
+	//* answered before any middleware, by a mux of their own
+	root := http.NewServeMux()
+	root.Handle("/", muxMiddleware)
+
+	root.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			healthHandler.Healthz(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	root.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			healthHandler.Readyz(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	root.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case "GET":
+			healthHandler.Version(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	})
+
+	srv := &http.Server{
+		Addr:         net.JoinHostPort(config.Host, config.Port),
+		Handler:      root,
+		ReadTimeout:  config.Server.ReadTimeout,
+		WriteTimeout: config.Server.WriteTimeout,
+		IdleTimeout:  config.Server.IdleTimeout,
 	}
 
-	err := server.ListenAndServe()
-	if err != nil {
-		return err
+	scheme := "http"
+	if config.TLS.Enabled() {
+		loader, err := tlscert.NewLoader(config.TLS.CertFile, config.TLS.KeyFile, config.TLS.ClientCAFile, config.TLS.RequireClientCert)
+		if err != nil {
+			return err
+		}
+		loader.OnReload = func(err error) {
+			if err != nil {
+				logger.Error().Err(err).Msg("tls certificate fail to reload, still serving the previous one")
+				return
+			}
+			logger.Info().Msg("tls certificate reloaded")
+		}
+		srv.TLSConfig = loader.TLSConfig()
+		scheme = "https"
 	}
 
-	logger.Info().Msgf("live on http://localhost:%s", port)
-	return nil
+	return server.Run(ctx, srv, config.Server.ShutdownTimeout, func(addr net.Addr) {
+		logger.Info().Msgf("live on %s://%s", scheme, addr)
+	})
 }
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"contact-go/helper/actor"
+	"contact-go/helper/apperrors"
+	"contact-go/helper/auth"
+	"contact-go/helper/response"
+	"net/http"
+)
+
+// Auth serves only the requests authenticator accepts, with who made
+// them in their context, as the principal and, by its ID, as the actor to audit.
+func Auth(authenticator *auth.Authenticator, w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			code, message := apperrors.HandleAppError(err)
+
+			challenge := `Bearer realm="contact-go"`
+			if code == http.StatusForbidden {
+				challenge += `, error="insufficient_scope", scope="` + authenticator.Scope + `"`
+			} else if r.Header.Get("Authorization") != "" {
+				challenge += `, error="invalid_token"`
+			}
+			w.Header().Set("WWW-Authenticate", challenge)
+
+			_ = response.NewJsonResponse(w, code, message, nil)
+			return
+		}
+
+		ctx := actor.NewContext(auth.NewContext(r.Context(), principal), principal.ID())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
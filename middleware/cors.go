@@ -2,13 +2,20 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 )
 
-func Cors(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
+// Cors lets the browser pages of allowedOrigins call the http api, and
+// no other page.
+func Cors(allowedOrigins []string, w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, GET, POST, PUT, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-CSRF-Token")
+		w.Header().Add("Vary", "Origin")
+		if origin := r.Header.Get("Origin"); origin != "" && allowedOrigin(allowedOrigins, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, GET, POST, PUT, PATCH, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-CSRF-Token, If-Match, If-None-Match, Authorization, X-API-Key, X-Tenant")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, WWW-Authenticate")
+		}
 		if r.Method == "OPTIONS" {
 			_, _ = w.Write([]byte("allowed"))
 			return
@@ -16,3 +23,12 @@ func Cors(w http.ResponseWriter, r *http.Request, next http.Handler) http.Handle
 		next.ServeHTTP(w, r)
 	})
 }
+
+func allowedOrigin(allowedOrigins []string, origin string) bool {
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(allowed), "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/auth"
+	"contact-go/helper/response"
+	"contact-go/helper/tenant"
+	"contact-go/usecase"
+	"net/http"
+)
+
+// Tenant serves every request in the address book it asked for in the
+// X-Tenant header, or the default one. A caller the http api
+// authenticated may be bound to an address book, by their token or by
+// principals, which maps principal IDs to address books, and then only
+// ever gets theirs. Picking any other address book is up to the
+// AddressBookUsecase, which looks it up in the context of the request.
+func Tenant(principals map[string]string, addressBookUC usecase.AddressBookUsecase, w http.ResponseWriter, r *http.Request, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var bound string
+		if principal, ok := auth.FromContext(r.Context()); ok {
+			bound = principal.Tenant
+			if bound == "" {
+				bound = principals[principal.ID()]
+			}
+		}
+
+		name, err := tenant.Select(bound, r.Header.Get(tenant.Header))
+		ctx := tenant.NewContext(r.Context(), name)
+		if bound != "" {
+			ctx = tenant.NewBoundContext(r.Context(), name)
+		}
+		if err == nil {
+			_, err = addressBookUC.Detail(ctx, name)
+		}
+		if err != nil {
+			code, message := apperrors.HandleAppError(err)
+			_ = response.NewJsonResponse(w, code, message, nil)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"contact-go/helper/auth"
+	"contact-go/helper/rbac"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"contact-go/repository"
+	"contact-go/usecase"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTenant(t *testing.T) {
+	addressBookUC := usecase.NewAddressBookUsecase(repository.NewAddressBookRepository(), time.Second)
+	if _, err := addressBookUC.Add(context.Background(), &model.AddressBookRequest{Name: "sales"}); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"reader": {rbac.PermissionRead},
+		"admin":  {rbac.PermissionRead, rbac.PermissionAdmin},
+	}, nil, "reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	principals := map[string]string{"apikey:sales-bot": "sales"}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		header    string
+		wantCode  int
+		wantBook  string
+		wantBound bool
+	}{
+		{
+			name:     "anonymous picks",
+			header:   "sales",
+			wantCode: http.StatusOK,
+			wantBook: "sales",
+		},
+		{
+			name:      "unbound gets the default",
+			principal: &auth.Principal{Name: "bob", Method: auth.MethodJWT},
+			wantCode:  http.StatusOK,
+			wantBook:  tenant.Default,
+		},
+		{
+			name:      "unbound admin picks",
+			principal: &auth.Principal{Name: "bob", Method: auth.MethodJWT, Roles: []string{"admin"}},
+			header:    "sales",
+			wantCode:  http.StatusOK,
+			wantBook:  "sales",
+		},
+		{
+			name:      "unbound reader does not pick",
+			principal: &auth.Principal{Name: "bob", Method: auth.MethodJWT},
+			header:    "sales",
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "bound by principals",
+			principal: &auth.Principal{Name: "sales-bot", Method: auth.MethodAPIKey},
+			wantCode:  http.StatusOK,
+			wantBook:  "sales",
+			wantBound: true,
+		},
+		{
+			name:      "bound by token",
+			principal: &auth.Principal{Name: "bob", Method: auth.MethodJWT, Tenant: "sales"},
+			header:    "sales",
+			wantCode:  http.StatusOK,
+			wantBook:  "sales",
+			wantBound: true,
+		},
+		{
+			name:      "bound does not pick another",
+			principal: &auth.Principal{Name: "sales-bot", Method: auth.MethodAPIKey},
+			header:    tenant.Default,
+			wantCode:  http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBook string
+			var gotBound bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotBook, gotBound = tenant.FromContext(r.Context()), tenant.Bound(r.Context())
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/contacts", nil)
+			if tt.principal != nil {
+				r = r.WithContext(auth.NewContext(r.Context(), tt.principal))
+			}
+			if tt.header != "" {
+				r.Header.Set(tenant.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			uc := usecase.NewAuthorizedAddressBookUsecase(addressBookUC, policy)
+			Tenant(principals, uc, w, r, next).ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("Tenant() code = %d, want %d", w.Code, tt.wantCode)
+			}
+			if gotBook != tt.wantBook || gotBound != tt.wantBound {
+				t.Errorf("Tenant() address book = %q, bound %v, want %q, bound %v", gotBook, gotBound, tt.wantBook, tt.wantBound)
+			}
+		})
+	}
+}
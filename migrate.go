@@ -0,0 +1,126 @@
+package main
+
+import (
+	"contact-go/config"
+	"contact-go/config/db"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// openDatabase connects to the configured SQL database without migrating
+// it and returns the connection along with its dialect.
+func openDatabase(cfg *config.Config) (*sql.DB, string, error) {
+	var sqlDB *sql.DB
+	var dialect string
+	var err error
+
+	switch cfg.Database.Driver {
+	case "mysql":
+		dialect = db.DialectMysql
+		sqlDB, err = db.NewMysqlDatabase(cfg)
+	case "gorm":
+		dialect = db.DialectPostgres
+		gormDB, gormErr := db.NewGormDatabase(cfg)
+		if gormErr != nil {
+			return nil, "", gormErr
+		}
+		sqlDB, err = gormDB.DB()
+	case "sqlite":
+		dialect = db.DialectSqlite
+		sqlDB, err = db.OpenSqliteDatabase(cfg)
+	default:
+		return nil, "", errors.New("database driver not existed")
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return sqlDB, dialect, nil
+}
+
+// openMigrator connects to the configured SQL database without migrating it
+// and returns the migrator for its dialect along with the connection.
+func openMigrator(cfg *config.Config) (*db.Migrator, *sql.DB, error) {
+	sqlDB, dialect, err := openDatabase(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrator, err := db.NewMigrator(sqlDB, dialect)
+	if err != nil {
+		sqlDB.Close()
+		return nil, nil, err
+	}
+
+	return migrator, sqlDB, nil
+}
+
+// runMigrate handles `contact-go migrate up|down|status`.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: contact-go migrate up|down|status")
+	}
+
+	migrator, sqlDB, err := openMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		migration, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if migration == nil {
+			fmt.Println("no migration to roll back")
+			return nil
+		}
+		fmt.Printf("rolled back %04d_%s\n", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+	default:
+		return errors.New("usage: contact-go migrate up|down|status")
+	}
+
+	return nil
+}
+
+// autoMigrate brings the schema up to date on startup when db.auto_migrate is set.
+func autoMigrate(sqlDB *sql.DB, dialect string) {
+	migrator, err := db.NewMigrator(sqlDB, dialect)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
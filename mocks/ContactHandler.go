@@ -24,11 +24,71 @@ func (_m *ContactHandler) Detail() {
 	_m.Called()
 }
 
+// Duplicates provides a mock function with given fields:
+func (_m *ContactHandler) Duplicates() {
+	_m.Called()
+}
+
+// ExportCSV provides a mock function with given fields:
+func (_m *ContactHandler) ExportCSV() {
+	_m.Called()
+}
+
+// ExportVCard provides a mock function with given fields:
+func (_m *ContactHandler) ExportVCard() {
+	_m.Called()
+}
+
+// ImportCSV provides a mock function with given fields:
+func (_m *ContactHandler) ImportCSV() {
+	_m.Called()
+}
+
+// ImportVCard provides a mock function with given fields:
+func (_m *ContactHandler) ImportVCard() {
+	_m.Called()
+}
+
 // List provides a mock function with given fields:
 func (_m *ContactHandler) List() {
 	_m.Called()
 }
 
+// Merge provides a mock function with given fields:
+func (_m *ContactHandler) Merge() {
+	_m.Called()
+}
+
+// Purge provides a mock function with given fields:
+func (_m *ContactHandler) Purge() {
+	_m.Called()
+}
+
+// Restore provides a mock function with given fields:
+func (_m *ContactHandler) Restore() {
+	_m.Called()
+}
+
+// Revert provides a mock function with given fields:
+func (_m *ContactHandler) Revert() {
+	_m.Called()
+}
+
+// Search provides a mock function with given fields:
+func (_m *ContactHandler) Search() {
+	_m.Called()
+}
+
+// Trash provides a mock function with given fields:
+func (_m *ContactHandler) Trash() {
+	_m.Called()
+}
+
+// Undo provides a mock function with given fields:
+func (_m *ContactHandler) Undo() {
+	_m.Called()
+}
+
 // Update provides a mock function with given fields:
 func (_m *ContactHandler) Update() {
 	_m.Called()
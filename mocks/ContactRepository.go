@@ -4,6 +4,8 @@ package mocks
 
 import (
 	model "contact-go/model"
+	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,25 +15,25 @@ type ContactRepository struct {
 	mock.Mock
 }
 
-// Add provides a mock function with given fields: contact
-func (_m *ContactRepository) Add(contact *model.Contact) (*model.Contact, error) {
-	ret := _m.Called(contact)
+// Add provides a mock function with given fields: ctx, contact
+func (_m *ContactRepository) Add(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	ret := _m.Called(ctx, contact)
 
 	var r0 *model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.Contact) (*model.Contact, error)); ok {
-		return rf(contact)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Contact) (*model.Contact, error)); ok {
+		return rf(ctx, contact)
 	}
-	if rf, ok := ret.Get(0).(func(*model.Contact) *model.Contact); ok {
-		r0 = rf(contact)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Contact) *model.Contact); ok {
+		r0 = rf(ctx, contact)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.Contact) error); ok {
-		r1 = rf(contact)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Contact) error); ok {
+		r1 = rf(ctx, contact)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -39,13 +41,39 @@ func (_m *ContactRepository) Add(contact *model.Contact) (*model.Contact, error)
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *ContactRepository) Delete(id int64) error {
-	ret := _m.Called(id)
+// Batch provides a mock function with given fields: ctx, ops, atomic
+func (_m *ContactRepository) Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error) {
+	ret := _m.Called(ctx, ops, atomic)
+
+	var r0 []model.ContactOperationResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.ContactOperation, bool) ([]model.ContactOperationResult, error)); ok {
+		return rf(ctx, ops, atomic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []model.ContactOperation, bool) []model.ContactOperationResult); ok {
+		r0 = rf(ctx, ops, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ContactOperationResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []model.ContactOperation, bool) error); ok {
+		r1 = rf(ctx, ops, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id, version, deletedAt
+func (_m *ContactRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	ret := _m.Called(ctx, id, version, deletedAt)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, version, deletedAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -53,25 +81,186 @@ func (_m *ContactRepository) Delete(id int64) error {
 	return r0
 }
 
-// Detail provides a mock function with given fields: id
-func (_m *ContactRepository) Detail(id int64) (*model.Contact, error) {
-	ret := _m.Called(id)
+// Detail provides a mock function with given fields: ctx, id
+func (_m *ContactRepository) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Contact, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Contact); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *ContactRepository) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []model.Contact
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactQuery) ([]model.Contact, int64, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactQuery) []model.Contact); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.ContactQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *model.ContactQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Merge provides a mock function with given fields: ctx, id, contact, mergedIDs
+func (_m *ContactRepository) Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, id, contact, mergedIDs)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.Contact, []int64) (*model.Contact, error)); ok {
+		return rf(ctx, id, contact, mergedIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.Contact, []int64) *model.Contact); ok {
+		r0 = rf(ctx, id, contact, mergedIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *model.Contact, []int64) error); ok {
+		r1 = rf(ctx, id, contact, mergedIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Patch provides a mock function with given fields: ctx, id, contact, fields
+func (_m *ContactRepository) Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error) {
+	ret := _m.Called(ctx, id, contact, fields)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.Contact, []string) (*model.Contact, error)); ok {
+		return rf(ctx, id, contact, fields)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.Contact, []string) *model.Contact); ok {
+		r0 = rf(ctx, id, contact, fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *model.Contact, []string) error); ok {
+		r1 = rf(ctx, id, contact, fields)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, before
+func (_m *ContactRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Recreate provides a mock function with given fields: ctx, contact
+func (_m *ContactRepository) Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	ret := _m.Called(ctx, contact)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Contact) (*model.Contact, error)); ok {
+		return rf(ctx, contact)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Contact) *model.Contact); ok {
+		r0 = rf(ctx, contact)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Contact) error); ok {
+		r1 = rf(ctx, contact)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ContactRepository) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*model.Contact, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Contact, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *model.Contact); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Contact); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,25 +268,25 @@ func (_m *ContactRepository) Detail(id int64) (*model.Contact, error) {
 	return r0, r1
 }
 
-// List provides a mock function with given fields:
-func (_m *ContactRepository) List() ([]model.Contact, error) {
-	ret := _m.Called()
+// Search provides a mock function with given fields: ctx, query
+func (_m *ContactRepository) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	ret := _m.Called(ctx, query)
 
 	var r0 []model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]model.Contact, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.Contact, error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func() []model.Contact); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Contact); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -105,25 +294,25 @@ func (_m *ContactRepository) List() ([]model.Contact, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: id, contact
-func (_m *ContactRepository) Update(id int64, contact *model.Contact) (*model.Contact, error) {
-	ret := _m.Called(id, contact)
+// Update provides a mock function with given fields: ctx, id, contact
+func (_m *ContactRepository) Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error) {
+	ret := _m.Called(ctx, id, contact)
 
 	var r0 *model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, *model.Contact) (*model.Contact, error)); ok {
-		return rf(id, contact)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.Contact) (*model.Contact, error)); ok {
+		return rf(ctx, id, contact)
 	}
-	if rf, ok := ret.Get(0).(func(int64, *model.Contact) *model.Contact); ok {
-		r0 = rf(id, contact)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.Contact) *model.Contact); ok {
+		r0 = rf(ctx, id, contact)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, *model.Contact) error); ok {
-		r1 = rf(id, contact)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *model.Contact) error); ok {
+		r1 = rf(ctx, id, contact)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	model "contact-go/model"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,25 +14,25 @@ type ContactUsecase struct {
 	mock.Mock
 }
 
-// Add provides a mock function with given fields: req
-func (_m *ContactUsecase) Add(req *model.ContactRequest) (*model.Contact, error) {
-	ret := _m.Called(req)
+// Add provides a mock function with given fields: ctx, req
+func (_m *ContactUsecase) Add(ctx context.Context, req *model.ContactRequest) (*model.Contact, error) {
+	ret := _m.Called(ctx, req)
 
 	var r0 *model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.ContactRequest) (*model.Contact, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactRequest) (*model.Contact, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*model.ContactRequest) *model.Contact); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactRequest) *model.Contact); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.ContactRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.ContactRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -39,13 +40,39 @@ func (_m *ContactUsecase) Add(req *model.ContactRequest) (*model.Contact, error)
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *ContactUsecase) Delete(id int64) error {
-	ret := _m.Called(id)
+// Batch provides a mock function with given fields: ctx, req
+func (_m *ContactUsecase) Batch(ctx context.Context, req *model.ContactBatchRequest) ([]model.ContactBatchResult, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 []model.ContactBatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactBatchRequest) ([]model.ContactBatchResult, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactBatchRequest) []model.ContactBatchResult); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ContactBatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.ContactBatchRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *ContactUsecase) Delete(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -53,25 +80,195 @@ func (_m *ContactUsecase) Delete(id int64) error {
 	return r0
 }
 
-// Detail provides a mock function with given fields: id
-func (_m *ContactUsecase) Detail(id int64) (*model.Contact, error) {
-	ret := _m.Called(id)
+// Detail provides a mock function with given fields: ctx, id
+func (_m *ContactUsecase) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Contact, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Contact); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Duplicates provides a mock function with given fields: ctx
+func (_m *ContactUsecase) Duplicates(ctx context.Context) ([][]model.Contact, error) {
+	ret := _m.Called(ctx)
+
+	var r0 [][]model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([][]model.Contact, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) [][]model.Contact); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByPhone provides a mock function with given fields: ctx, noTelp
+func (_m *ContactUsecase) FindByPhone(ctx context.Context, noTelp string) (*model.Contact, error) {
+	ret := _m.Called(ctx, noTelp)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Contact, error)); ok {
+		return rf(ctx, noTelp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Contact); ok {
+		r0 = rf(ctx, noTelp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, noTelp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// History provides a mock function with given fields: ctx, id, query
+func (_m *ContactUsecase) History(ctx context.Context, id int64, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []model.AuditEntry
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.AuditQuery) ([]model.AuditEntry, int64, error)); ok {
+		return rf(ctx, id, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.AuditQuery) []model.AuditEntry); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *model.AuditQuery) int64); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, *model.AuditQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *ContactUsecase) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []model.Contact
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactQuery) ([]model.Contact, int64, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactQuery) []model.Contact); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.ContactQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *model.ContactQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Merge provides a mock function with given fields: ctx, ids
+func (_m *ContactUsecase) Merge(ctx context.Context, ids []int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) (*model.Contact, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) *model.Contact); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Patch provides a mock function with given fields: ctx, id, req, fields
+func (_m *ContactUsecase) Patch(ctx context.Context, id int64, req *model.ContactRequest, fields []string) (*model.Contact, error) {
+	ret := _m.Called(ctx, id, req, fields)
 
 	var r0 *model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*model.Contact, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.ContactRequest, []string) (*model.Contact, error)); ok {
+		return rf(ctx, id, req, fields)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *model.Contact); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.ContactRequest, []string) *model.Contact); ok {
+		r0 = rf(ctx, id, req, fields)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *model.ContactRequest, []string) error); ok {
+		r1 = rf(ctx, id, req, fields)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,25 +276,127 @@ func (_m *ContactUsecase) Detail(id int64) (*model.Contact, error) {
 	return r0, r1
 }
 
-// List provides a mock function with given fields:
-func (_m *ContactUsecase) List() ([]model.Contact, error) {
-	ret := _m.Called()
+// Purge provides a mock function with given fields: ctx
+func (_m *ContactUsecase) Purge(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ContactUsecase) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Contact, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Contact); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revert provides a mock function with given fields: ctx, id, revision
+func (_m *ContactUsecase) Revert(ctx context.Context, id int64, revision int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, id, revision)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*model.Contact, error)); ok {
+		return rf(ctx, id, revision)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *model.Contact); ok {
+		r0 = rf(ctx, id, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query
+func (_m *ContactUsecase) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	ret := _m.Called(ctx, query)
 
 	var r0 []model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]model.Contact, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.Contact, error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func() []model.Contact); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Contact); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Undo provides a mock function with given fields: ctx, revision
+func (_m *ContactUsecase) Undo(ctx context.Context, revision int64) (*model.Contact, error) {
+	ret := _m.Called(ctx, revision)
+
+	var r0 *model.Contact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Contact, error)); ok {
+		return rf(ctx, revision)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Contact); ok {
+		r0 = rf(ctx, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Contact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, revision)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -105,25 +404,25 @@ func (_m *ContactUsecase) List() ([]model.Contact, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: id, req
-func (_m *ContactUsecase) Update(id int64, req *model.ContactRequest) (*model.Contact, error) {
-	ret := _m.Called(id, req)
+// Update provides a mock function with given fields: ctx, id, req
+func (_m *ContactUsecase) Update(ctx context.Context, id int64, req *model.ContactRequest) (*model.Contact, error) {
+	ret := _m.Called(ctx, id, req)
 
 	var r0 *model.Contact
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, *model.ContactRequest) (*model.Contact, error)); ok {
-		return rf(id, req)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.ContactRequest) (*model.Contact, error)); ok {
+		return rf(ctx, id, req)
 	}
-	if rf, ok := ret.Get(0).(func(int64, *model.ContactRequest) *model.Contact); ok {
-		r0 = rf(id, req)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.ContactRequest) *model.Contact); ok {
+		r0 = rf(ctx, id, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Contact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, *model.ContactRequest) error); ok {
-		r1 = rf(id, req)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *model.ContactRequest) error); ok {
+		r1 = rf(ctx, id, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -131,6 +430,20 @@ func (_m *ContactUsecase) Update(id int64, req *model.ContactRequest) (*model.Co
 	return r0, r1
 }
 
+// Validate provides a mock function with given fields: ctx, req
+func (_m *ContactUsecase) Validate(ctx context.Context, req *model.ContactRequest) error {
+	ret := _m.Called(ctx, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ContactRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewContactUsecase interface {
 	mock.TestingT
 	Cleanup(func())
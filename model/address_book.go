@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// AddressBook keeps a set of contacts, with their groups and history,
+// apart from every other, such as those of another department. Its
+// name is what a caller picks it by.
+type AddressBook struct {
+	Name      string    `json:"name" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime:false"`
+}
+
+func (AddressBook) TableName() string {
+	return "address_books"
+}
+
+// AddressBookRequest carries the name of an address book to add.
+type AddressBookRequest struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	AuditAdd     = "add"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditMerge   = "merge"
+	AuditRevert  = "revert"
+)
+
+// AuditEntry records one change to a contact: who made it, when, and the
+// contact as it was before and after. Before is nil for an added contact,
+// and After for a deleted one. Its ID doubles as the revision of the
+// contact, the one After is a snapshot of.
+type AuditEntry struct {
+	ID        int64     `json:"id" gorm:"primarykey"`
+	ContactID int64     `json:"contact_id"`
+	Actor     string    `json:"actor"`
+	Operation string    `json:"operation"`
+	Before    *Contact  `json:"before" gorm:"column:before_data;serializer:json"`
+	After     *Contact  `json:"after" gorm:"column:after_data;serializer:json"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime:false"`
+}
+
+func (AuditEntry) TableName() string {
+	return "contact_audits"
+}
+
+// AuditQuery pages through the history of the contact ContactID,
+// newest entry first. A zero Limit returns every entry.
+type AuditQuery struct {
+	ContactID int64
+	Limit     int
+	Offset    int
+}
@@ -1,14 +1,236 @@
 package model
 
+import "time"
+
+// Contact keeps its main number in NoTelp, which List and Search filter on,
+// and any further numbers in Phones. Numbers are stored in E.164, with
+// NoTelpRaw keeping the main number as it was entered. CreatedAt and
+// UpdatedAt are stamped by the usecase, so the SQL backends must not
+// set them on their own. DeletedAt is set while the contact is in the trash.
+// Version starts at 1 and goes up with every update, so an update made
+// from a stale copy can be told apart and rejected.
 type Contact struct {
-	ID     int64  `json:"id" gorm:"primarykey"`
-	Name   string `json:"name"`
-	NoTelp string `json:"no_telp"`
+	ID        int64      `json:"id" gorm:"primarykey"`
+	Name      string     `json:"name"`
+	NoTelp    string     `json:"no_telp"`
+	NoTelpRaw string     `json:"no_telp_raw,omitempty"`
+	Phones    []Phone    `json:"phones,omitempty" gorm:"serializer:json"`
+	Emails    []Email    `json:"emails,omitempty" gorm:"serializer:json"`
+	Addresses []Address  `json:"addresses,omitempty" gorm:"serializer:json"`
+	Company   string     `json:"company,omitempty"`
+	JobTitle  string     `json:"job_title,omitempty"`
+	Birthday  string     `json:"birthday,omitempty"`
+	Notes     string     `json:"notes,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime:false"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime:false"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Version   int64      `json:"version" gorm:"default:1"`
+}
+
+// Phone keeps Number in E.164 once stored, and Raw as it was entered.
+type Phone struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+	Raw    string `json:"raw,omitempty"`
 }
 
-var Contacts []Contact
+type Email struct {
+	Type    string `json:"type"`
+	Address string `json:"address"`
+}
+
+type Address struct {
+	Type       string `json:"type"`
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
 
+// ContactRequest carries the details of a contact to add or update.
+// Force adds the contact even when it looks like one already stored.
+// Version, when set, is the version an update expects to replace.
 type ContactRequest struct {
-	Name   string `json:"name"`
-	NoTelp string `json:"no_telp"`
+	Name      string    `json:"name"`
+	NoTelp    string    `json:"no_telp"`
+	Phones    []Phone   `json:"phones"`
+	Emails    []Email   `json:"emails"`
+	Addresses []Address `json:"addresses"`
+	Company   string    `json:"company"`
+	JobTitle  string    `json:"job_title"`
+	Birthday  string    `json:"birthday"`
+	Notes     string    `json:"notes"`
+	Force     bool      `json:"force,omitempty"`
+	Version   int64     `json:"version,omitempty"`
+}
+
+// ContactFields are the fields of a contact a patch may set, by their
+// JSON names, which are their column names as well. Setting no_telp
+// sets no_telp_raw along with it.
+var ContactFields = []string{
+	"name", "no_telp", "phones", "emails", "addresses",
+	"company", "job_title", "birthday", "notes",
+}
+
+// ContactMergeRequest names the contacts to merge. The first is kept
+// and takes in the details of the rest, which are moved to the trash.
+type ContactMergeRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+
+	// MaxBatchOperations caps the operations of a single batch.
+	MaxBatchOperations = 1000
+)
+
+// ContactBatchRequest lists the operations of a batch. An atomic batch
+// is stored all or nothing; otherwise every operation stands on its own.
+type ContactBatchRequest struct {
+	Atomic     bool                    `json:"atomic"`
+	Operations []ContactBatchOperation `json:"operations"`
+}
+
+// ContactBatchOperation creates Contact, replaces the contact ID with
+// Contact, or moves the contact ID to the trash. Version is checked
+// the way Update and Delete check it.
+type ContactBatchOperation struct {
+	Op      string          `json:"op"`
+	ID      int64           `json:"id,omitempty"`
+	Version int64           `json:"version,omitempty"`
+	Contact *ContactRequest `json:"contact,omitempty"`
+}
+
+// ContactBatchResult reports how one batch operation fared, numbered
+// from 1 in request order. Contact is what a create or update stored.
+type ContactBatchResult struct {
+	Operation int      `json:"operation"`
+	Op        string   `json:"op"`
+	ID        int64    `json:"id,omitempty"`
+	Contact   *Contact `json:"contact,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// ContactBatchSummary counts the operations of a batch by outcome.
+// Every operation of a failed atomic batch counts as failed.
+type ContactBatchSummary struct {
+	Atomic    bool `json:"atomic"`
+	Succeeded int  `json:"succeeded"`
+	Failed    int  `json:"failed"`
+}
+
+// ContactOperation is a batch operation ready to store: Contact is
+// added or replaces ID, checking Contact.Version, or ID is moved to
+// the trash as of DeletedAt, checking Version.
+type ContactOperation struct {
+	Op        string
+	ID        int64
+	Contact   *Contact
+	Version   int64
+	DeletedAt time.Time
+}
+
+// ContactOperationResult is the contact an operation stored, or the
+// error it failed with.
+type ContactOperationResult struct {
+	Contact *Contact
+	Err     error
+}
+
+// ContactPurgeResult reports how many contacts a purge removed for good.
+type ContactPurgeResult struct {
+	Purged int64 `json:"purged"`
+}
+
+// ContactImportResult reports how one imported card fared, numbered
+// from 1 in file order. ID is set when the card was added, Error when not.
+type ContactImportResult struct {
+	Card  int    `json:"card"`
+	Name  string `json:"name,omitempty"`
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportReject = "reject"
+)
+
+// ContactCSVResult reports what importing one CSV row did, or would do
+// on a dry run. Line is where the row starts in the file, and ID is
+// the contact it created or updated.
+type ContactCSVResult struct {
+	Line   int    `json:"line"`
+	Action string `json:"action"`
+	Name   string `json:"name,omitempty"`
+	NoTelp string `json:"no_telp,omitempty"`
+	ID     int64  `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ContactCSVSummary struct {
+	DryRun   bool `json:"dry_run"`
+	Created  int  `json:"created"`
+	Updated  int  `json:"updated"`
+	Rejected int  `json:"rejected"`
+}
+
+const (
+	TypeHome   = "home"
+	TypeWork   = "work"
+	TypeMobile = "mobile"
+	TypeOther  = "other"
+
+	// BirthdayLayout is the format of Contact.Birthday.
+	BirthdayLayout = "2006-01-02"
+)
+
+// ContactTypes are the types a phone, email or address may be tagged with.
+// An empty type is stored as TypeOther.
+var ContactTypes = map[string]bool{
+	TypeHome:   true,
+	TypeWork:   true,
+	TypeMobile: true,
+	TypeOther:  true,
+}
+
+const (
+	DefaultContactLimit = 50
+	MaxContactLimit     = 1000
+	MaxSearchResults    = 50
+
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ContactSortFields maps the sort keys accepted from clients
+// to the column name used by the SQL backends.
+var ContactSortFields = map[string]string{
+	"id":      "id",
+	"name":    "name",
+	"no_telp": "no_telp",
+}
+
+// ContactQuery describes which page of contacts List should return.
+// A zero Limit means no limit, in which case Offset is ignored.
+// Deleted lists the contacts in the trash instead of the others.
+// Group and Tag keep to the members of a group, named by its id or its
+// name; the usecase turns either into IDs, which, when not empty, keeps
+// to the contacts listed in it.
+type ContactQuery struct {
+	Limit   int
+	Offset  int
+	SortBy  string
+	Order   string
+	Name    string
+	NoTelp  string
+	Deleted bool
+	Group   int64
+	Tag     string
+	IDs     []int64
 }
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Group gathers contacts under a name, such as a team, a customer or an
+// on-call rotation. Its name doubles as a tag: it is unique regardless
+// of case, and is what the contacts of the group are filtered by.
+// A contact may be in any number of groups.
+type Group struct {
+	ID          int64     `json:"id" gorm:"primarykey"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime:false"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime:false"`
+}
+
+func (Group) TableName() string {
+	return "contact_groups"
+}
+
+// GroupRequest carries the details of a group to add or update.
+type GroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GroupMembersRequest names the contacts to add to or remove from a group.
+type GroupMembersRequest struct {
+	ContactIDs []int64 `json:"contact_ids"`
+}
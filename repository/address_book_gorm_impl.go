@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type addressBookGormRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressBookGormRepository(db *gorm.DB) AddressBookRepository {
+	r := new(addressBookGormRepository)
+	r.db = db
+
+	return r
+}
+
+func (repo *addressBookGormRepository) List(ctx context.Context) ([]model.AddressBook, error) {
+	var books []model.AddressBook
+
+	result := repo.db.WithContext(ctx).Order("name ASC").Find(&books)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+func (repo *addressBookGormRepository) Add(ctx context.Context, book *model.AddressBook) (*model.AddressBook, error) {
+	newBook := *book
+
+	result := repo.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&newBook)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookDuplicate)
+	}
+
+	return &newBook, nil
+}
+
+func (repo *addressBookGormRepository) Detail(ctx context.Context, name string) (*model.AddressBook, error) {
+	book := new(model.AddressBook)
+
+	result := repo.db.WithContext(ctx).Where("name = ?", name).First(book)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookNotFound)
+	}
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"sort"
+	"sync"
+)
+
+type addressBookRepository struct {
+	mu    sync.RWMutex
+	books []model.AddressBook
+}
+
+func NewAddressBookRepository() AddressBookRepository {
+	return new(addressBookRepository)
+}
+
+func addressBookIndex(books []model.AddressBook, name string) (int, error) {
+	for i, v := range books {
+		if name == v.Name {
+			return i, nil
+		}
+	}
+
+	return -1, apperrors.NewAppError(apperrors.ErrAddressBookNotFound)
+}
+
+// sortedAddressBooks returns a copy of books ordered by name.
+func sortedAddressBooks(books []model.AddressBook) []model.AddressBook {
+	sorted := append([]model.AddressBook{}, books...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func (repo *addressBookRepository) List(ctx context.Context) ([]model.AddressBook, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return sortedAddressBooks(repo.books), nil
+}
+
+func (repo *addressBookRepository) Add(ctx context.Context, book *model.AddressBook) (*model.AddressBook, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, err := addressBookIndex(repo.books, book.Name); err == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookDuplicate)
+	}
+
+	newBook := *book
+	repo.books = append(repo.books, newBook)
+
+	return &newBook, nil
+}
+
+func (repo *addressBookRepository) Detail(ctx context.Context, name string) (*model.AddressBook, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	index, err := addressBookIndex(repo.books, name)
+	if err != nil {
+		return nil, err
+	}
+
+	book := repo.books[index]
+	return &book, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"reflect"
+	"testing"
+)
+
+// checkAddressBookRepository runs repo through adding, listing and
+// looking up address books.
+func checkAddressBookRepository(t *testing.T, repo AddressBookRepository) {
+	ctx := context.Background()
+
+	sales, err := repo.Add(ctx, &model.AddressBook{Name: "sales", CreatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	support, err := repo.Add(ctx, &model.AddressBook{Name: "support", CreatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if _, err := repo.Add(ctx, &model.AddressBook{Name: "sales", CreatedAt: testContactTime}); err == nil || err.Error() != apperrors.ErrAddressBookDuplicate {
+		t.Errorf("Add() of a taken name error = %v, want %v", err, apperrors.ErrAddressBookDuplicate)
+	}
+
+	books, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []model.AddressBook{*sales, *support}; !reflect.DeepEqual(books, want) {
+		t.Errorf("List() = %+v, want %+v", books, want)
+	}
+
+	if got, err := repo.Detail(ctx, "support"); err != nil || !reflect.DeepEqual(got, support) {
+		t.Errorf("Detail() = %+v, %v, want %+v", got, err, support)
+	}
+	if _, err := repo.Detail(ctx, "marketing"); err == nil || err.Error() != apperrors.ErrAddressBookNotFound {
+		t.Errorf("Detail() of a missing address book error = %v, want %v", err, apperrors.ErrAddressBookNotFound)
+	}
+}
+
+func Test_addressBookRepository(t *testing.T) {
+	checkAddressBookRepository(t, NewAddressBookRepository())
+}
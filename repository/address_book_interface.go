@@ -0,0 +1,20 @@
+//go:generate mockery --output=../mocks --name AddressBookRepository
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+)
+
+// AddressBookRepository keeps the address books there are, other than
+// the default one every storage has from the start.
+type AddressBookRepository interface {
+	// List returns every address book, ordered by name.
+	List(ctx context.Context) ([]model.AddressBook, error)
+	// Add stores book, failing with apperrors.ErrAddressBookDuplicate
+	// when there is one by that name already.
+	Add(ctx context.Context, book *model.AddressBook) (*model.AddressBook, error)
+	// Detail returns the address book called name, failing with
+	// apperrors.ErrAddressBookNotFound when there is none.
+	Detail(ctx context.Context, name string) (*model.AddressBook, error)
+}
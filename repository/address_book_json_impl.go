@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"os"
+)
+
+// addressBookJsonRepository keeps the address books in a jsonStore of
+// their own. Each one keeps its contacts in a file of its own, named by
+// TenantPath after the contacts of the default address book.
+type addressBookJsonRepository struct {
+	store        jsonStore
+	contactsPath string
+}
+
+// NewAddressBookJsonRepository stores address books in jsonFilePath,
+// starting the contacts of each with an empty file next to contactsPath.
+func NewAddressBookJsonRepository(jsonFilePath string, contactsPath string) AddressBookRepository {
+	repo := new(addressBookJsonRepository)
+	repo.store.path = jsonFilePath
+	repo.contactsPath = contactsPath
+	return repo
+}
+
+// decodeJSON reads the address books, none when the file is not there yet.
+func (repo *addressBookJsonRepository) decodeJSON() ([]model.AddressBook, error) {
+	var books []model.AddressBook
+	err := repo.store.decode(&books)
+	if err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// createContacts starts the contacts of the address book name with an
+// empty list, keeping any contacts already in its file.
+func (repo *addressBookJsonRepository) createContacts(name string) error {
+	file, err := os.OpenFile(TenantPath(repo.contactsPath, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString("[]\n")
+	return err
+}
+
+func (repo *addressBookJsonRepository) List(ctx context.Context) ([]model.AddressBook, error) {
+	unlock, err := repo.store.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	books, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedAddressBooks(books), nil
+}
+
+func (repo *addressBookJsonRepository) Add(ctx context.Context, book *model.AddressBook) (*model.AddressBook, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	books, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := addressBookIndex(books, book.Name); err == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookDuplicate)
+	}
+
+	err = repo.createContacts(book.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	newBook := *book
+	books = append(books, newBook)
+
+	err = repo.store.encode(&books)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newBook, nil
+}
+
+func (repo *addressBookJsonRepository) Detail(ctx context.Context, name string) (*model.AddressBook, error) {
+	unlock, err := repo.store.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	books, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := addressBookIndex(books, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &books[index], nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func Test_addressBookJsonRepository(t *testing.T) {
+	dir := t.TempDir()
+	contactsPath := filepath.Join(dir, "contact.json")
+
+	checkAddressBookRepository(t, NewAddressBookJsonRepository(filepath.Join(dir, "address_book.json"), contactsPath))
+
+	//* each address book starts with a contacts file of its own
+	contacts, _, err := NewContactJsonRepository(TenantPath(contactsPath, "sales"), 0).List(context.Background(), &model.ContactQuery{})
+	if err != nil || len(contacts) != 0 {
+		t.Errorf("List() of a new address book = %+v, %v, want no contacts", contacts, err)
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type addressBookMysqlRepository struct {
+	db *sql.DB
+}
+
+func NewAddressBookMysqlRepository(db *sql.DB) AddressBookRepository {
+	return &addressBookMysqlRepository{
+		db: db,
+	}
+}
+
+func (repo *addressBookMysqlRepository) List(ctx context.Context) ([]model.AddressBook, error) {
+	sqlQuery := "SELECT " + addressBookColumns + " FROM address_book ORDER BY name ASC"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAddressBooks(rows)
+}
+
+func (repo *addressBookMysqlRepository) Add(ctx context.Context, book *model.AddressBook) (*model.AddressBook, error) {
+	sqlQuery := "INSERT IGNORE INTO address_book(name, created_at) VALUES (?, ?)"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, book.Name, book.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookDuplicate)
+	}
+
+	newBook := *book
+	return &newBook, nil
+}
+
+func (repo *addressBookMysqlRepository) Detail(ctx context.Context, name string) (*model.AddressBook, error) {
+	book := new(model.AddressBook)
+
+	sqlQuery := "SELECT " + addressBookColumns + " FROM address_book WHERE name = ? LIMIT 1"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	err = scanAddressBook(stmt.QueryRowContext(ctx, name), book)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_addressBookMysqlRepository(t *testing.T) {
+	sales := model.AddressBook{Name: "sales", CreatedAt: testContactTime}
+	insertQuery := regexp.QuoteMeta("INSERT IGNORE INTO address_book(name, created_at) VALUES (?, ?)")
+
+	tests := []struct {
+		name       string
+		run        func(AddressBookRepository) (interface{}, error)
+		beforeTest func(sqlmock.Sqlmock)
+		want       interface{}
+		wantErr    error
+	}{
+		{
+			name: "list",
+			run: func(repo AddressBookRepository) (interface{}, error) {
+				return repo.List(context.Background())
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT " + addressBookColumns + " FROM address_book ORDER BY name ASC")).
+					ExpectQuery().
+					WillReturnRows(sqlmock.NewRows([]string{"name", "created_at"}).AddRow("sales", testContactTime))
+			},
+			want: []model.AddressBook{sales},
+		},
+		{
+			name: "add",
+			run: func(repo AddressBookRepository) (interface{}, error) {
+				return repo.Add(context.Background(), &sales)
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(insertQuery).
+					ExpectExec().
+					WithArgs("sales", testContactTime).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: &sales,
+		},
+		{
+			name: "add taken name",
+			run: func(repo AddressBookRepository) (interface{}, error) {
+				return repo.Add(context.Background(), &sales)
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(insertQuery).
+					ExpectExec().
+					WithArgs("sales", testContactTime).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want:    (*model.AddressBook)(nil),
+			wantErr: apperrors.NewAppError(apperrors.ErrAddressBookDuplicate),
+		},
+		{
+			name: "detail missing",
+			run: func(repo AddressBookRepository) (interface{}, error) {
+				return repo.Detail(context.Background(), "marketing")
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT " + addressBookColumns + " FROM address_book WHERE name = ? LIMIT 1")).
+					ExpectQuery().
+					WithArgs("marketing").
+					WillReturnRows(sqlmock.NewRows([]string{"name", "created_at"}))
+			},
+			want:    (*model.AddressBook)(nil),
+			wantErr: apperrors.NewAppError(apperrors.ErrAddressBookNotFound),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer db.Close()
+
+			tt.beforeTest(mock)
+
+			got, err := tt.run(NewAddressBookMysqlRepository(db))
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"contact-go/model"
+	"database/sql"
+)
+
+// addressBookColumns are the columns of the address_book table
+// in the order scanAddressBook reads them.
+const addressBookColumns = "name, created_at"
+
+// scanAddressBook reads a row of addressBookColumns, with the timestamp in UTC.
+func scanAddressBook(row rowScanner, book *model.AddressBook) error {
+	err := row.Scan(&book.Name, &book.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	book.CreatedAt = book.CreatedAt.UTC()
+	return nil
+}
+
+func scanAddressBooks(rows *sql.Rows) ([]model.AddressBook, error) {
+	var books []model.AddressBook
+
+	for rows.Next() {
+		var book model.AddressBook
+		if err := scanAddressBook(rows, &book); err != nil {
+			return nil, err
+		}
+
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
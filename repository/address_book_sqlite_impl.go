@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type addressBookSqliteRepository struct {
+	db *sql.DB
+}
+
+func NewAddressBookSqliteRepository(db *sql.DB) AddressBookRepository {
+	return &addressBookSqliteRepository{
+		db: db,
+	}
+}
+
+func (repo *addressBookSqliteRepository) List(ctx context.Context) ([]model.AddressBook, error) {
+	sqlQuery := "SELECT " + addressBookColumns + " FROM address_book ORDER BY name ASC"
+	rows, err := repo.db.QueryContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAddressBooks(rows)
+}
+
+func (repo *addressBookSqliteRepository) Add(ctx context.Context, book *model.AddressBook) (*model.AddressBook, error) {
+	sqlQuery := "INSERT OR IGNORE INTO address_book(name, created_at) VALUES (?, ?)"
+	result, err := repo.db.ExecContext(ctx, sqlQuery, book.Name, book.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookDuplicate)
+	}
+
+	newBook := *book
+	return &newBook, nil
+}
+
+func (repo *addressBookSqliteRepository) Detail(ctx context.Context, name string) (*model.AddressBook, error) {
+	book := new(model.AddressBook)
+
+	sqlQuery := "SELECT " + addressBookColumns + " FROM address_book WHERE name = ? LIMIT 1"
+	err := scanAddressBook(repo.db.QueryRowContext(ctx, sqlQuery, name), book)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
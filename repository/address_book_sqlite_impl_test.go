@@ -0,0 +1,7 @@
+package repository
+
+import "testing"
+
+func Test_addressBookSqliteRepository(t *testing.T) {
+	checkAddressBookRepository(t, NewAddressBookSqliteRepository(newSqliteTestDatabase(t)))
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type auditGormRepository struct {
+	db *gorm.DB
+}
+
+// gormAuditEntry is an entry as the audit table stores it,
+// along with the address book it is in.
+type gormAuditEntry struct {
+	model.AuditEntry
+	Tenant string
+}
+
+func (gormAuditEntry) TableName() string {
+	return model.AuditEntry{}.TableName()
+}
+
+func NewAuditGormRepository(db *gorm.DB) AuditRepository {
+	r := new(auditGormRepository)
+	r.db = db
+
+	return r
+}
+
+func (repo *auditGormRepository) Add(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	row := &gormAuditEntry{AuditEntry: *entry, Tenant: tenant.FromContext(ctx)}
+
+	result := repo.db.WithContext(ctx).Omit("ID").Create(row)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return &row.AuditEntry, nil
+}
+
+func (repo *auditGormRepository) Detail(ctx context.Context, id int64) (*model.AuditEntry, error) {
+	entry := new(model.AuditEntry)
+
+	result := repo.db.WithContext(ctx).Scopes(inTenant(ctx)).First(entry, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NewAppError(apperrors.ErrRevisionNotFound)
+	}
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (repo *auditGormRepository) List(ctx context.Context, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	var entries []model.AuditEntry
+
+	page := repo.db.WithContext(ctx).Scopes(inTenant(ctx)).Where("contact_id = ?", query.ContactID).Order("id DESC")
+	if query.Limit > 0 {
+		page = page.Limit(query.Limit).Offset(query.Offset)
+	}
+
+	result := page.Find(&entries)
+	if err := result.Error; err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(entries))
+	if query.Limit > 0 {
+		result = repo.db.WithContext(ctx).Model(&model.AuditEntry{}).Scopes(inTenant(ctx)).Where("contact_id = ?", query.ContactID).Count(&total)
+		if err := result.Error; err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+	"sync"
+)
+
+type auditRepository struct {
+	mu      sync.RWMutex
+	entries []model.AuditEntry
+}
+
+func NewAuditRepository() AuditRepository {
+	return new(auditRepository)
+}
+
+func (repo *auditRepository) Add(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	newEntry := *entry
+	newEntry.ID = lastAuditID(repo.entries) + 1
+
+	repo.entries = append(repo.entries, newEntry)
+
+	return &newEntry, nil
+}
+
+func (repo *auditRepository) Detail(ctx context.Context, id int64) (*model.AuditEntry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return auditEntryByID(repo.entries, id)
+}
+
+func (repo *auditRepository) List(ctx context.Context, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	entries, total := queryAuditEntries(repo.entries, query)
+	return entries, total, nil
+}
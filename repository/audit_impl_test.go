@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// checkAuditRepository appends the history of two contacts to repo
+// and pages through it.
+func checkAuditRepository(t *testing.T, repo AuditRepository) {
+	ctx := context.Background()
+
+	before := &model.Contact{ID: 1, Name: "Reva", NoTelp: "+15555551234", Phones: []model.Phone{{Type: "work", Number: "+15555550000"}},
+		CreatedAt: testContactTime, UpdatedAt: testContactTime, Version: 1}
+	after := *before
+	after.Name = "Reva Tirta"
+	after.UpdatedAt = testContactTime.Add(time.Hour)
+	after.Version = 2
+
+	var added []model.AuditEntry
+	for i, entry := range []model.AuditEntry{
+		{ContactID: 1, Actor: "cli", Operation: model.AuditAdd, After: before},
+		{ContactID: 2, Actor: "cli", Operation: model.AuditAdd, After: &model.Contact{ID: 2, Name: "Bagas", NoTelp: "+15555559012", CreatedAt: testContactTime, UpdatedAt: testContactTime, Version: 1}},
+		{ContactID: 1, Actor: "alice", Operation: model.AuditUpdate, Before: before, After: &after},
+		{ContactID: 1, Actor: "alice", Operation: model.AuditDelete, Before: &after},
+	} {
+		entry.CreatedAt = testContactTime.Add(time.Duration(i) * time.Minute)
+		got, err := repo.Add(ctx, &entry)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if got.ID <= 0 || (len(added) > 0 && got.ID <= added[len(added)-1].ID) {
+			t.Fatalf("Add() id = %d, want ids going up", got.ID)
+		}
+		added = append(added, *got)
+	}
+
+	entries, total, err := repo.List(ctx, &model.AuditQuery{ContactID: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []model.AuditEntry{added[3], added[2], added[0]}; total != 3 || !reflect.DeepEqual(entries, want) {
+		t.Errorf("List() = %+v, %d, want %+v, 3", entries, total, want)
+	}
+
+	entries, total, err = repo.List(ctx, &model.AuditQuery{ContactID: 1, Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []model.AuditEntry{added[2]}; total != 3 || !reflect.DeepEqual(entries, want) {
+		t.Errorf("List() page = %+v, %d, want %+v, 3", entries, total, want)
+	}
+
+	entries, total, err = repo.List(ctx, &model.AuditQuery{ContactID: 3})
+	if err != nil || len(entries) != 0 || total != 0 {
+		t.Errorf("List() of a contact without history = %+v, %d, %v, want none", entries, total, err)
+	}
+
+	entry, err := repo.Detail(ctx, added[2].ID)
+	if err != nil {
+		t.Fatalf("Detail() error = %v", err)
+	}
+	if !reflect.DeepEqual(entry, &added[2]) {
+		t.Errorf("Detail() = %+v, want %+v", entry, &added[2])
+	}
+
+	var appErr *apperrors.AppError
+	if _, err := repo.Detail(ctx, added[3].ID+1); !errors.As(err, &appErr) || appErr.Message != apperrors.ErrRevisionNotFound {
+		t.Errorf("Detail() of an unknown revision error = %v, want %s", err, apperrors.ErrRevisionNotFound)
+	}
+}
+
+func Test_auditRepository(t *testing.T) {
+	checkAuditRepository(t, NewAuditRepository())
+}
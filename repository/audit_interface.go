@@ -0,0 +1,19 @@
+//go:generate mockery --output=../mocks --name AuditRepository
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+)
+
+// AuditRepository keeps the audit log of contact changes. Entries are
+// only ever appended, and outlive the contacts they are about.
+type AuditRepository interface {
+	Add(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error)
+	// Detail returns the entry id, failing with apperrors.ErrRevisionNotFound
+	// when there is none.
+	Detail(ctx context.Context, id int64) (*model.AuditEntry, error)
+	// List returns a page of the entries of query.ContactID, newest first,
+	// along with how many entries the contact has in all.
+	List(ctx context.Context, query *model.AuditQuery) ([]model.AuditEntry, int64, error)
+}
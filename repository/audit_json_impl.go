@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"bytes"
+	"contact-go/model"
+	"context"
+	"encoding/json"
+)
+
+// auditJsonRepository keeps the audit log in a jsonStore of its own, an
+// entry per line, so that adding one appends it rather than writing the
+// whole log again. A log written as a JSON array, before entries got a
+// line each, is read as the lines of its entries.
+type auditJsonRepository struct {
+	store jsonStore
+}
+
+func NewAuditJsonRepository(jsonFilePath string) AuditRepository {
+	repo := new(auditJsonRepository)
+	repo.store.path = jsonFilePath
+	return repo
+}
+
+// decodeAuditLine adds the entries of line to entries: the entry of the line,
+// or those of a log written as a JSON array.
+func decodeAuditLine(line []byte, entries []model.AuditEntry) ([]model.AuditEntry, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) > 0 && line[0] == '[' {
+		var array []model.AuditEntry
+		if err := json.Unmarshal(line, &array); err != nil {
+			return nil, err
+		}
+		return append(entries, array...), nil
+	}
+
+	var entry model.AuditEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return nil, err
+	}
+	return append(entries, entry), nil
+}
+
+// decodeJSON returns the entries of the log, oldest first.
+func (repo *auditJsonRepository) decodeJSON() ([]model.AuditEntry, error) {
+	var entries []model.AuditEntry
+	err := repo.store.decodeLines(func(line []byte) error {
+		var err error
+		entries, err = decodeAuditLine(line, entries)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (repo *auditJsonRepository) Add(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var last []model.AuditEntry
+	line, err := repo.store.lastLine()
+	if err != nil {
+		return nil, err
+	}
+	if line != nil {
+		last, err = decodeAuditLine(line, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newEntry := *entry
+	newEntry.ID = lastAuditID(last) + 1
+
+	err = repo.store.appendLine(&newEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newEntry, nil
+}
+
+func (repo *auditJsonRepository) Detail(ctx context.Context, id int64) (*model.AuditEntry, error) {
+	unlock, err := repo.store.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return auditEntryByID(entries, id)
+}
+
+func (repo *auditJsonRepository) List(ctx context.Context, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	unlock, err := repo.store.lock(ctx, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer unlock()
+
+	entries, err := repo.decodeJSON()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	found, total := queryAuditEntries(entries, query)
+	return found, total, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_auditJsonRepository(t *testing.T) {
+	checkAuditRepository(t, NewAuditJsonRepository(filepath.Join(t.TempDir(), "audit.json")))
+}
+
+func Test_auditJsonRepository_lines(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		wantIDs   []int64
+		wantLines int
+	}{
+		{
+			name:      "not created yet",
+			wantIDs:   []int64{1},
+			wantLines: 1,
+		},
+		{
+			name:      "lines",
+			file:      `{"id":1,"contact_id":1,"operation":"add"}` + "\n",
+			wantIDs:   []int64{1, 2},
+			wantLines: 2,
+		},
+		{
+			name:      "json array",
+			file:      `[{"id":1,"contact_id":1,"operation":"add"},{"id":2,"contact_id":1,"operation":"update"}]` + "\n",
+			wantIDs:   []int64{1, 2, 3},
+			wantLines: 2,
+		},
+		{
+			name:      "line cut off by a crash",
+			file:      `{"id":1,"contact_id":1,"operation":"add"}` + "\n" + `{"id":2,"contact_id":1,"oper`,
+			wantIDs:   []int64{1, 2},
+			wantLines: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.json")
+			if tt.file != "" {
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			repo := NewAuditJsonRepository(path)
+
+			if _, err := repo.Add(context.Background(), &model.AuditEntry{ContactID: 1, Operation: model.AuditUpdate}); err != nil {
+				t.Fatalf("auditJsonRepository.Add() error = %v", err)
+			}
+
+			entries, _, err := repo.List(context.Background(), &model.AuditQuery{ContactID: 1})
+			if err != nil {
+				t.Fatalf("auditJsonRepository.List() error = %v", err)
+			}
+			var ids []int64
+			for _, entry := range entries {
+				ids = append([]int64{entry.ID}, ids...)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("auditJsonRepository.List() IDs = %v, want %v", ids, tt.wantIDs)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); len(lines) != tt.wantLines {
+				t.Errorf("audit file has %d lines, want %d:\n%s", len(lines), tt.wantLines, data)
+			}
+		})
+	}
+}
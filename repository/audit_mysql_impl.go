@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type auditMysqlRepository struct {
+	// conn runs the statements: db, or the transaction of a change of
+	// contacts the entries are about.
+	conn sqlConn
+}
+
+func NewAuditMysqlRepository(db *sql.DB) AuditRepository {
+	return &auditMysqlRepository{
+		conn: db,
+	}
+}
+
+func (repo *auditMysqlRepository) Add(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	sqlQuery := "INSERT INTO contact_audit(contact_id, actor, operation, before_data, after_data, created_at, tenant) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	row, err := stmt.ExecContext(ctx, append(auditArgs(entry), tenant.FromContext(ctx))...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := row.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	newEntry := *entry
+	newEntry.ID = id
+
+	return &newEntry, nil
+}
+
+func (repo *auditMysqlRepository) Detail(ctx context.Context, id int64) (*model.AuditEntry, error) {
+	entry := new(model.AuditEntry)
+
+	sqlQuery := "SELECT " + auditColumns + " FROM contact_audit WHERE tenant = ? AND id = ? LIMIT 1"
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	err = scanAuditEntry(stmt.QueryRowContext(ctx, tenant.FromContext(ctx), id), entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrRevisionNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (repo *auditMysqlRepository) List(ctx context.Context, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	sqlQuery := "SELECT " + auditColumns + " FROM contact_audit WHERE tenant = ? AND contact_id = ? ORDER BY id DESC"
+	args := []interface{}{tenant.FromContext(ctx), query.ContactID}
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit, query.Offset)
+	}
+
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditEntries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(entries))
+	if query.Limit > 0 {
+		countQuery := "SELECT COUNT(*) FROM contact_audit WHERE tenant = ? AND contact_id = ?"
+		err = repo.conn.QueryRowContext(ctx, countQuery, tenant.FromContext(ctx), query.ContactID).Scan(&total)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entries, total, nil
+}
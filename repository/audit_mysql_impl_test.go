@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_auditMysqlRepository_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	after := &model.Contact{ID: 1, Name: "test", NoTelp: "+15555553232"}
+	mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO contact_audit(contact_id, actor, operation, before_data, after_data, created_at, tenant) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+		ExpectExec().
+		WithArgs(int64(1), "cli", model.AuditAdd, nil, sqlmock.AnyArg(), testContactTime, tenant.Default).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	got, err := NewAuditMysqlRepository(db).Add(context.Background(), &model.AuditEntry{
+		ContactID: 1, Actor: "cli", Operation: model.AuditAdd, After: after, CreatedAt: testContactTime,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(7), got.ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_auditMysqlRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(strings.Split(auditColumns, ", ")).
+		AddRow(int64(2), int64(1), "cli", model.AuditDelete, `{"id":1,"name":"test","no_telp":"+15555553232"}`, nil, testContactTime)
+
+	mock.ExpectPrepare(regexp.QuoteMeta("SELECT "+auditColumns+" FROM contact_audit WHERE tenant = ? AND contact_id = ? ORDER BY id DESC LIMIT ? OFFSET ?")).
+		ExpectQuery().
+		WithArgs(tenant.Default, int64(1), 1, 0).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM contact_audit WHERE tenant = ? AND contact_id = ?")).
+		WithArgs(tenant.Default, int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(2)))
+
+	entries, total, err := NewAuditMysqlRepository(db).List(context.Background(), &model.AuditQuery{ContactID: 1, Limit: 1})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []model.AuditEntry{{
+			ID: 2, ContactID: 1, Actor: "cli", Operation: model.AuditDelete,
+			Before:    &model.Contact{ID: 1, Name: "test", NoTelp: "+15555553232"},
+			CreatedAt: testContactTime,
+		}}, entries)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_auditMysqlRepository_Detail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	detailQuery := "SELECT " + auditColumns + " FROM contact_audit WHERE tenant = ? AND id = ? LIMIT 1"
+	mock.ExpectPrepare(regexp.QuoteMeta(detailQuery)).
+		ExpectQuery().
+		WithArgs(tenant.Default, int64(2)).
+		WillReturnRows(sqlmock.NewRows(strings.Split(auditColumns, ", ")).
+			AddRow(int64(2), int64(1), "cli", model.AuditAdd, nil, `{"id":1,"name":"test","no_telp":"+15555553232"}`, testContactTime))
+	mock.ExpectPrepare(regexp.QuoteMeta(detailQuery)).
+		ExpectQuery().
+		WithArgs(tenant.Default, int64(3)).
+		WillReturnRows(sqlmock.NewRows(strings.Split(auditColumns, ", ")))
+
+	repo := NewAuditMysqlRepository(db)
+
+	entry, err := repo.Detail(context.Background(), 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &model.AuditEntry{
+			ID: 2, ContactID: 1, Actor: "cli", Operation: model.AuditAdd,
+			After:     &model.Contact{ID: 1, Name: "test", NoTelp: "+15555553232"},
+			CreatedAt: testContactTime,
+		}, entry)
+	}
+
+	_, err = repo.Detail(context.Background(), 3)
+	assert.EqualError(t, err, apperrors.ErrRevisionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"sort"
+)
+
+func lastAuditID(entries []model.AuditEntry) int64 {
+	if len(entries) == 0 {
+		return 0
+	}
+	return entries[len(entries)-1].ID
+}
+
+func auditEntryByID(entries []model.AuditEntry, id int64) (*model.AuditEntry, error) {
+	for i := range entries {
+		if entries[i].ID == id {
+			entry := entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, apperrors.NewAppError(apperrors.ErrRevisionNotFound)
+}
+
+// queryAuditEntries returns the page of entries query asks for, newest
+// first, along with the number of entries of its contact.
+func queryAuditEntries(entries []model.AuditEntry, query *model.AuditQuery) ([]model.AuditEntry, int64) {
+	var found []model.AuditEntry
+	for _, entry := range entries {
+		if entry.ContactID == query.ContactID {
+			found = append(found, entry)
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].ID > found[j].ID
+	})
+
+	total := int64(len(found))
+	if query.Offset >= len(found) {
+		return nil, total
+	}
+	found = found[query.Offset:]
+	if query.Limit > 0 && query.Limit < len(found) {
+		found = found[:query.Limit]
+	}
+	return found, total
+}
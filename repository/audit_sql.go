@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"contact-go/model"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// auditColumns are the columns of the audit table
+// in the order scanAuditEntry reads them.
+const auditColumns = "id, contact_id, actor, operation, before_data, after_data, created_at"
+
+// auditSnapshot stores the snapshot of an audit entry as a JSON document,
+// NULL when there is none.
+type auditSnapshot struct {
+	contact **model.Contact
+}
+
+func (s auditSnapshot) Value() (driver.Value, error) {
+	if *s.contact == nil {
+		return nil, nil
+	}
+	return jsonColumn{*s.contact}.Value()
+}
+
+func (s auditSnapshot) Scan(src interface{}) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unsupported json column type %T", src)
+	}
+
+	*s.contact = nil
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, s.contact)
+}
+
+// scanAuditEntry reads a row of auditColumns, with the timestamp in UTC.
+func scanAuditEntry(row rowScanner, entry *model.AuditEntry) error {
+	err := row.Scan(&entry.ID, &entry.ContactID, &entry.Actor, &entry.Operation,
+		auditSnapshot{&entry.Before}, auditSnapshot{&entry.After}, &entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	entry.CreatedAt = entry.CreatedAt.UTC()
+	return nil
+}
+
+func scanAuditEntries(rows *sql.Rows) ([]model.AuditEntry, error) {
+	var entries []model.AuditEntry
+
+	for rows.Next() {
+		var entry model.AuditEntry
+		if err := scanAuditEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// auditArgs are the values of the columns an insert of entry writes,
+// in the order contact_id, actor, operation, before_data, after_data, created_at.
+func auditArgs(entry *model.AuditEntry) []interface{} {
+	return []interface{}{
+		entry.ContactID, entry.Actor, entry.Operation,
+		auditSnapshot{&entry.Before}, auditSnapshot{&entry.After}, entry.CreatedAt,
+	}
+}
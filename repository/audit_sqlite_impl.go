@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type auditSqliteRepository struct {
+	// conn runs the statements: db, or the transaction of a change of
+	// contacts the entries are about.
+	conn sqlConn
+}
+
+func NewAuditSqliteRepository(db *sql.DB) AuditRepository {
+	return &auditSqliteRepository{
+		conn: db,
+	}
+}
+
+func (repo *auditSqliteRepository) Add(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	sqlQuery := "INSERT INTO contact_audit(contact_id, actor, operation, before_data, after_data, created_at, tenant) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	row, err := repo.conn.ExecContext(ctx, sqlQuery, append(auditArgs(entry), tenant.FromContext(ctx))...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := row.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	newEntry := *entry
+	newEntry.ID = id
+
+	return &newEntry, nil
+}
+
+func (repo *auditSqliteRepository) Detail(ctx context.Context, id int64) (*model.AuditEntry, error) {
+	entry := new(model.AuditEntry)
+
+	sqlQuery := "SELECT " + auditColumns + " FROM contact_audit WHERE tenant = ? AND id = ? LIMIT 1"
+	err := scanAuditEntry(repo.conn.QueryRowContext(ctx, sqlQuery, tenant.FromContext(ctx), id), entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrRevisionNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (repo *auditSqliteRepository) List(ctx context.Context, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	sqlQuery := "SELECT " + auditColumns + " FROM contact_audit WHERE tenant = ? AND contact_id = ? ORDER BY id DESC"
+	args := []interface{}{tenant.FromContext(ctx), query.ContactID}
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit, query.Offset)
+	}
+
+	rows, err := repo.conn.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditEntries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(entries))
+	if query.Limit > 0 {
+		countQuery := "SELECT COUNT(*) FROM contact_audit WHERE tenant = ? AND contact_id = ?"
+		err = repo.conn.QueryRowContext(ctx, countQuery, tenant.FromContext(ctx), query.ContactID).Scan(&total)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entries, total, nil
+}
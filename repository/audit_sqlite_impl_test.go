@@ -0,0 +1,7 @@
+package repository
+
+import "testing"
+
+func Test_auditSqliteRepository(t *testing.T) {
+	checkAuditRepository(t, NewAuditSqliteRepository(newSqliteTestDatabase(t)))
+}
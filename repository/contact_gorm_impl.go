@@ -1,8 +1,13 @@
 package repository
 
 import (
-	"contact-go/config/db"
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
 	"contact-go/model"
+	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -12,6 +17,22 @@ type contactGormRepository struct {
 	db *gorm.DB
 }
 
+var gormContactColumns = strings.Split(contactColumns, ", ")
+
+// errBatchFailed rolls back an atomic batch with a failed operation.
+var errBatchFailed = errors.New("batch operation failed")
+
+// gormContact is a contact as the contacts table stores it,
+// along with the address book it is in.
+type gormContact struct {
+	model.Contact
+	Tenant string
+}
+
+func (gormContact) TableName() string {
+	return "contacts"
+}
+
 func NewContactGormRepository(db *gorm.DB) ContactRepository {
 	r := new(contactGormRepository)
 	r.db = db
@@ -25,43 +46,103 @@ func NewContactGormRepository(db *gorm.DB) ContactRepository {
 // On the other hand, the db.QueryRowContext(...) function is used for
 // executing SQL queries that return a single row of result set.
 
-func (repo *contactGormRepository) List() ([]model.Contact, error) {
+// inTenant selects the rows of the address book of ctx.
+func inTenant(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant = ?", tenant.FromContext(ctx))
+	}
+}
+
+func filterContacts(query *model.ContactQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where(trashCondition(query.Deleted))
+		if query.Name != "" {
+			db = db.Where("name ILIKE ?", likePattern(query.Name))
+		}
+		if query.NoTelp != "" {
+			db = db.Where("no_telp LIKE ?", likePattern(query.NoTelp))
+		}
+		if len(query.IDs) > 0 {
+			db = db.Where("id IN ?", query.IDs)
+		}
+		return db
+	}
+}
+
+func sortContacts(query *model.ContactQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		column, direction := sortColumn(query)
+		db = db.Order(column + " " + direction)
+		if column != "id" {
+			db = db.Order("id " + direction)
+		}
+		return db
+	}
+}
+
+// currentVersion selects the contact id out of the trash,
+// and only at version when that is set.
+func currentVersion(id int64, version int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("id = ? AND deleted_at IS NULL", id)
+		if version != 0 {
+			db = db.Where("version = ?", version)
+		}
+		return db
+	}
+}
+
+func paginateContacts(query *model.ContactQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Limit <= 0 {
+			return db
+		}
+		return db.Limit(query.Limit).Offset(query.Offset)
+	}
+}
+
+func (repo *contactGormRepository) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
 	var contacts []model.Contact
 	var err error
 
-	ctx, cancel := db.NewContext()
-	defer cancel()
-
-	result := repo.db.WithContext(ctx).Select("id", "name", "no_telp").Order("id ASC").Find(&contacts)
+	result := repo.db.WithContext(ctx).Select(gormContactColumns).
+		Scopes(inTenant(ctx), filterContacts(query), sortContacts(query), paginateContacts(query)).
+		Find(&contacts)
 
 	if err = result.Error; err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return contacts, nil
-}
+	total := int64(len(contacts))
+	if query.Limit > 0 {
+		result = repo.db.WithContext(ctx).Model(&model.Contact{}).Scopes(inTenant(ctx), filterContacts(query)).Count(&total)
+		if err = result.Error; err != nil {
+			return nil, 0, err
+		}
+	}
 
-func (repo *contactGormRepository) Add(contact *model.Contact) (*model.Contact, error) {
-	ctx, cancel := db.NewContext()
-	defer cancel()
+	return contacts, total, nil
+}
 
-	result := repo.db.WithContext(ctx).Select("Name", "NoTelp").Create(&contact)
+func (repo *contactGormRepository) Add(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	row := &gormContact{Contact: cloneContact(contact), Tenant: tenant.FromContext(ctx)}
+	result := repo.db.WithContext(ctx).Omit("ID").Create(row)
 
 	if err := result.Error; err != nil {
 		return nil, err
 	}
 
-	return contact, nil
+	return &row.Contact, nil
 }
 
-func (repo *contactGormRepository) Detail(id int64) (*model.Contact, error) {
+func (repo *contactGormRepository) Detail(ctx context.Context, id int64) (*model.Contact, error) {
 	contact := new(model.Contact)
 
-	ctx, cancel := db.NewContext()
-	defer cancel()
-
-	result := repo.db.WithContext(ctx).Select("ID", "Name", "NoTelp").First(&contact, id)
+	result := repo.db.WithContext(ctx).Select(gormContactColumns).Scopes(inTenant(ctx)).Where("deleted_at IS NULL").First(&contact, id)
 
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
 	if err := result.Error; err != nil {
 		return nil, err
 	}
@@ -69,35 +150,192 @@ func (repo *contactGormRepository) Detail(id int64) (*model.Contact, error) {
 	return contact, nil
 }
 
-func (repo *contactGormRepository) Update(id int64, contact *model.Contact) (*model.Contact, error) {
-	ctx, cancel := db.NewContext()
-	defer cancel()
+func (repo *contactGormRepository) Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error) {
+	return repo.Patch(ctx, id, contact, model.ContactFields)
+}
 
+// Patch bumps the version first, which also locks the row, and only
+// then writes the fields and reads the contact back. The columns are
+// named so that zero values are written too.
+func (repo *contactGormRepository) Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error) {
 	updatedContact := new(model.Contact)
 
 	returning := clause.Returning{
-		Columns: []clause.Column{{Name: "id"}, {Name: "name"}, {Name: "no_telp"}},
+		Columns: make([]clause.Column, 0, len(gormContactColumns)),
+	}
+	for _, column := range gormContactColumns {
+		returning.Columns = append(returning.Columns, clause.Column{Name: column})
 	}
-	result := repo.db.WithContext(ctx).Model(&updatedContact).Clauses(returning).Where("id = ?", id).Updates(contact)
 
-	if err := result.Error; err != nil {
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Contact{}).Scopes(inTenant(ctx), currentVersion(id, contact.Version)).
+			UpdateColumn("version", gorm.Expr("version + 1"))
+		if err := result.Error; err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			return missingOrStale(ctx, repo, id)
+		}
+
+		return tx.Model(&updatedContact).Clauses(returning).Where("id = ?", id).
+			Select(contactFieldColumns(fields)).Updates(contact).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return updatedContact, nil
 }
 
-func (repo *contactGormRepository) Delete(id int64) error {
-	contact := new(model.Contact)
-
-	ctx, cancel := db.NewContext()
-	defer cancel()
-
-	result := repo.db.WithContext(ctx).Delete(&contact, id)
+func (repo *contactGormRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	result := repo.db.WithContext(ctx).Model(&model.Contact{}).
+		Scopes(inTenant(ctx), currentVersion(id, version)).
+		Update("deleted_at", deletedAt)
 
 	if err := result.Error; err != nil {
 		return err
 	}
+	if result.RowsAffected == 0 {
+		return missingOrStale(ctx, repo, id)
+	}
 
 	return nil
 }
+
+// Search relies on the pg_trgm extension for similarity(),
+// which tolerates typos that ILIKE alone would miss.
+func (repo *contactGormRepository) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	var contacts []model.Contact
+
+	conditions := repo.db.Where("name ILIKE ?", likePattern(query)).
+		Or("similarity(name, ?) > ?", query, 0.3)
+	if digits := digitsOnly(query); len(digits) >= 3 {
+		conditions = conditions.Or("regexp_replace(no_telp, '[^0-9]', '', 'g') LIKE ?", likePattern(digits))
+	}
+
+	rank := clause.OrderBy{
+		Expression: clause.Expr{SQL: "similarity(name, ?) DESC, id ASC", Vars: []interface{}{query}},
+	}
+	result := repo.db.WithContext(ctx).Select(gormContactColumns).
+		Scopes(inTenant(ctx)).
+		Where("deleted_at IS NULL").
+		Where(conditions).
+		Clauses(rank).
+		Limit(model.MaxSearchResults).
+		Find(&contacts)
+
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+func (repo *contactGormRepository) Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error) {
+	merged := new(model.Contact)
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lock every row first, so a missing one fails the merge before anything is written
+		var found []int64
+		err := tx.Model(&model.Contact{}).Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(inTenant(ctx)).
+			Where("id IN ? AND deleted_at IS NULL", append([]int64{id}, mergedIDs...)).Pluck("id", &found).Error
+		if err != nil {
+			return err
+		}
+		if len(found) != len(mergedIDs)+1 {
+			return apperrors.NewAppError(apperrors.ErrContactNotFound)
+		}
+
+		err = tx.Model(&model.Contact{}).Where("id = ?", id).Select(contactFieldColumns(model.ContactFields)).Updates(contact).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.Contact{}).Where("id = ?", id).UpdateColumn("version", gorm.Expr("version + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.Contact{}).Where("id IN ?", mergedIDs).Update("deleted_at", contact.UpdatedAt).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Select(gormContactColumns).First(merged, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// Batch runs an atomic batch on one transaction, with the transactions
+// of the single-contact methods folded into it.
+func (repo *contactGormRepository) Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error) {
+	if !atomic {
+		return batchOperations(ctx, repo, ops, false), nil
+	}
+
+	var results []model.ContactOperationResult
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := &contactGormRepository{db: tx.Session(&gorm.Session{DisableNestedTransaction: true})}
+
+		results = batchOperations(ctx, txRepo, ops, true)
+		if failedOperation(results) {
+			return errBatchFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchFailed) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (repo *contactGormRepository) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	result := repo.db.WithContext(ctx).Model(&model.Contact{}).Scopes(inTenant(ctx)).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *contactGormRepository) Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	var taken int64
+	result := repo.db.WithContext(ctx).Model(&model.Contact{}).Where("id = ?", contact.ID).Count(&taken)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactDuplicate)
+	}
+
+	row := &gormContact{Contact: cloneContact(contact), Tenant: tenant.FromContext(ctx)}
+	row.DeletedAt = nil
+
+	result = repo.db.WithContext(ctx).Create(row)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return &row.Contact, nil
+}
+
+func (repo *contactGormRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := repo.db.WithContext(ctx).Scopes(inTenant(ctx)).Where("deleted_at < ?", before).Delete(&model.Contact{})
+
+	if err := result.Error; err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"contact-go/helper/tenant"
 	"contact-go/model"
+	"context"
 	"database/sql"
 	"errors"
 	"log"
@@ -81,13 +83,16 @@ func TestGormRepoSuite(t *testing.T) {
 func (s *GormRepoSuite) Test_contactGormRepository_List() {
 	tests := []struct {
 		name       string
+		query      *model.ContactQuery
 		beforeTest func(sqlmock.Sqlmock, string)
 		want       []model.Contact
+		wantTotal  int64
 		wantErr    bool
 	}{
 		// TODO: Add test cases.
 		{
-			name: "success",
+			name:  "success",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				rows := s.NewRows([]string{"id", "name", "no_telp"}).
 					AddRow(int64(1), "test", "555-555-3232")
@@ -99,10 +104,35 @@ func (s *GormRepoSuite) Test_contactGormRepository_List() {
 			want: []model.Contact{
 				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
 			},
-			wantErr: false,
+			wantTotal: 1,
+			wantErr:   false,
 		},
 		{
-			name: "failed",
+			name:  "success filtered page",
+			query: &model.ContactQuery{Name: "te", SortBy: "name", Order: "desc", Limit: 10, Offset: 10},
+			beforeTest: func(s sqlmock.Sqlmock, _ string) {
+				rows := s.NewRows([]string{"id", "name", "no_telp"}).
+					AddRow(int64(1), "test", "555-555-3232")
+
+				s.ExpectPrepare(regexp.QuoteMeta(`SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE tenant = $1 AND deleted_at IS NULL AND name ILIKE $2 ORDER BY name DESC,id DESC LIMIT 10 OFFSET 10`)).
+					ExpectQuery().
+					WithArgs(tenant.Default, "%te%").
+					WillReturnRows(rows)
+
+				s.ExpectPrepare(regexp.QuoteMeta(`SELECT count(*) FROM "contacts" WHERE tenant = $1 AND deleted_at IS NULL AND name ILIKE $2`)).
+					ExpectQuery().
+					WithArgs(tenant.Default, "%te%").
+					WillReturnRows(s.NewRows([]string{"count"}).AddRow(int64(11)))
+			},
+			want: []model.Contact{
+				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
+			},
+			wantTotal: 11,
+			wantErr:   false,
+		},
+		{
+			name:  "failed",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				s.ExpectQuery(regexp.QuoteMeta(query)).
 					WillReturnError(assert.AnError)
@@ -111,7 +141,8 @@ func (s *GormRepoSuite) Test_contactGormRepository_List() {
 			wantErr: true,
 		},
 		{
-			name: "failed prepare statement",
+			name:  "failed prepare statement",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				err := errors.New("prepare stmt error")
 
@@ -124,17 +155,18 @@ func (s *GormRepoSuite) Test_contactGormRepository_List() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := `SELECT "id","name","no_telp" FROM "contacts" ORDER BY id ASC`
+			sqlQuery := `SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE tenant = $1 AND deleted_at IS NULL ORDER BY id ASC`
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			got, err := s.repo.List()
+			got, total, err := s.repo.List(context.Background(), tt.query)
 			log.Println("case:", tt.name, ", got:", got, ", error:", err)
 
 			if s.Equal(tt.wantErr, err != nil, "contactGormRepository.List() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactGormRepository.List() = %v, want %v", got, tt.want)
+				s.Equal(tt.wantTotal, total, "contactGormRepository.List() total = %v, want %v", total, tt.wantTotal)
 			}
 
 			if err := s.mockSQL.ExpectationsWereMet(); err != nil {
@@ -160,8 +192,12 @@ func (s *GormRepoSuite) Test_contactGormRepository_Add() {
 			name: "success",
 			args: args{
 				contact: &model.Contact{
-					Name:   "test",
-					NoTelp: "555-555-3232",
+					Name:      "test",
+					NoTelp:    "555-555-3232",
+					Emails:    []model.Email{{Type: model.TypeWork, Address: "test@example.com"}},
+					Company:   "Acme",
+					CreatedAt: testContactTime,
+					UpdatedAt: testContactTime,
 				},
 			},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
@@ -170,13 +206,19 @@ func (s *GormRepoSuite) Test_contactGormRepository_Add() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectQuery().
-					WithArgs("test", "555-555-3232").
+					WithArgs("test", "555-555-3232", "", nil, `[{"type":"work","address":"test@example.com"}]`, nil,
+						"Acme", "", "", "", testContactTime, testContactTime, nil, int64(1), tenant.Default).
 					WillReturnRows(rows)
 			},
 			want: &model.Contact{
-				ID:     1,
-				Name:   "test",
-				NoTelp: "555-555-3232",
+				ID:        1,
+				Name:      "test",
+				NoTelp:    "555-555-3232",
+				Emails:    []model.Email{{Type: model.TypeWork, Address: "test@example.com"}},
+				Company:   "Acme",
+				CreatedAt: testContactTime,
+				UpdatedAt: testContactTime,
+				Version:   1,
 			},
 			wantErr: false,
 		},
@@ -190,7 +232,7 @@ func (s *GormRepoSuite) Test_contactGormRepository_Add() {
 			},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				s.ExpectQuery(regexp.QuoteMeta(query)).
-					WithArgs("", "555-555-3232").
+					WithArgs("", "555-555-3232", "", nil, nil, nil, "", "", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, int64(1), tenant.Default).
 					WillReturnError(assert.AnError)
 			},
 			want:    nil,
@@ -216,13 +258,13 @@ func (s *GormRepoSuite) Test_contactGormRepository_Add() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := `INSERT INTO "contacts" ("name","no_telp") VALUES ($1,$2) RETURNING "id"`
+			sqlQuery := `INSERT INTO "contacts" ("name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version","tenant") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING "id"`
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			got, err := s.repo.Add(tt.args.contact)
+			got, err := s.repo.Add(context.Background(), tt.args.contact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactGormRepository.Add() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactGormRepository.Add() = %v, want %v", got, tt.want)
@@ -258,7 +300,7 @@ func (s *GormRepoSuite) Test_contactGormRepository_Detail() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectQuery().
-					WithArgs(int64(1)).
+					WithArgs(int64(1), tenant.Default).
 					WillReturnRows(rows)
 			},
 			want: &model.Contact{
@@ -275,7 +317,7 @@ func (s *GormRepoSuite) Test_contactGormRepository_Detail() {
 			},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				s.ExpectQuery(regexp.QuoteMeta(query)).
-					WithArgs(int64(0)).
+					WithArgs(int64(0), tenant.Default).
 					WillReturnError(assert.AnError)
 			},
 			want:    nil,
@@ -295,16 +337,30 @@ func (s *GormRepoSuite) Test_contactGormRepository_Detail() {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "not found",
+			args: args{
+				id: 2,
+			},
+			beforeTest: func(s sqlmock.Sqlmock, query string) {
+				s.ExpectPrepare(regexp.QuoteMeta(query)).
+					ExpectQuery().
+					WithArgs(int64(2), tenant.Default).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "no_telp"}))
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := `SELECT "id","name","no_telp" FROM "contacts" WHERE "contacts"."id" = $1 ORDER BY "contacts"."id" LIMIT 1`
+			sqlQuery := `SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE deleted_at IS NULL AND "contacts"."id" = $1 AND tenant = $2 ORDER BY "contacts"."id" LIMIT 1`
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			got, err := s.repo.Detail(tt.args.id)
+			got, err := s.repo.Detail(context.Background(), tt.args.id)
 
 			if s.Equal(tt.wantErr, err != nil, "contactGormRepository.Detail() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactGormRepository.Detail() = %v, want %v", got, tt.want)
@@ -318,6 +374,11 @@ func (s *GormRepoSuite) Test_contactGormRepository_Detail() {
 }
 
 func (s *GormRepoSuite) Test_contactGormRepository_Update() {
+	bumpQuery := `UPDATE "contacts" SET "version"=version + 1 WHERE tenant = $1 AND (id = $2 AND deleted_at IS NULL)`
+	staleBumpQuery := `UPDATE "contacts" SET "version"=version + 1 WHERE tenant = $1 AND (id = $2 AND deleted_at IS NULL) AND version = $3`
+	updateQuery := `UPDATE "contacts" SET "name"=$1,"no_telp"=$2,"no_telp_raw"=$3,"phones"=$4,"emails"=$5,"addresses"=$6,"company"=$7,"job_title"=$8,"birthday"=$9,"notes"=$10,"updated_at"=$11 WHERE id = $12 RETURNING "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version"`
+	detailQuery := `SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE deleted_at IS NULL AND "contacts"."id" = $1 AND tenant = $2 ORDER BY "contacts"."id" LIMIT 1`
+
 	type args struct {
 		id      int64
 		contact *model.Contact
@@ -325,7 +386,7 @@ func (s *GormRepoSuite) Test_contactGormRepository_Update() {
 	tests := []struct {
 		name       string
 		args       args
-		beforeTest func(sqlmock.Sqlmock, string)
+		beforeTest func(sqlmock.Sqlmock)
 		want       *model.Contact
 		wantErr    bool
 	}{
@@ -335,57 +396,82 @@ func (s *GormRepoSuite) Test_contactGormRepository_Update() {
 			args: args{
 				id: 1,
 				contact: &model.Contact{
-					Name:   "jangkrik",
-					NoTelp: "555-555-4000",
+					Name:      "jangkrik",
+					NoTelp:    "555-555-4000",
+					UpdatedAt: testContactTime,
 				},
 			},
-			beforeTest: func(s sqlmock.Sqlmock, query string) {
-				rows := s.NewRows([]string{"id", "name", "no_telp"}).
-					AddRow(int64(1), "jangkrik", "555-555-4000")
-
-				s.ExpectPrepare(regexp.QuoteMeta(query)).
+			beforeTest: func(s sqlmock.Sqlmock) {
+				rows := s.NewRows([]string{"id", "name", "no_telp", "emails", "created_at", "updated_at", "version"}).
+					AddRow(int64(1), "jangkrik", "555-555-4000", `[{"type":"home","address":"jangkrik@example.com"}]`, testContactTime, testContactTime, int64(2))
+
+				//* statements are prepared once on the pool, then again on the transaction
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(bumpQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(bumpQuery)).
+					ExpectExec().
+					WithArgs(tenant.Default, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectPrepare(regexp.QuoteMeta(updateQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(updateQuery)).
 					ExpectQuery().
-					WithArgs("jangkrik", "555-555-4000", int64(1)).
+					WithArgs("jangkrik", "555-555-4000", "", nil, nil, nil, "", "", "", "", testContactTime, int64(1)).
 					WillReturnRows(rows)
+				s.ExpectCommit()
 			},
 			want: &model.Contact{
-				ID:     1,
-				Name:   "jangkrik",
-				NoTelp: "555-555-4000",
+				ID:        1,
+				Name:      "jangkrik",
+				NoTelp:    "555-555-4000",
+				Emails:    []model.Email{{Type: model.TypeHome, Address: "jangkrik@example.com"}},
+				CreatedAt: testContactTime,
+				UpdatedAt: testContactTime,
+				Version:   2,
 			},
 			wantErr: false,
 		},
 		{
-			name: "invalid id, name, or no_telp",
+			name: "stale version",
 			args: args{
-				id: 0,
+				id: 1,
 				contact: &model.Contact{
-					Name:   "jangkrik",
-					NoTelp: "555-555-4000",
+					Name:      "jangkrik",
+					NoTelp:    "555-555-4000",
+					UpdatedAt: testContactTime,
+					Version:   1,
 				},
 			},
-			beforeTest: func(s sqlmock.Sqlmock, query string) {
-				s.ExpectQuery(regexp.QuoteMeta(query)).
-					WithArgs("jangkrik", "555-555-4000", int64(0)).
-					WillReturnError(assert.AnError)
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(staleBumpQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(staleBumpQuery)).
+					ExpectExec().
+					WithArgs(tenant.Default, int64(1), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				s.ExpectQuery(regexp.QuoteMeta(detailQuery)).
+					WithArgs(int64(1), tenant.Default).
+					WillReturnRows(s.NewRows([]string{"id", "version"}).AddRow(int64(1), int64(2)))
+				s.ExpectRollback()
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
-			name: "failed to prepare statement",
+			name: "failed",
 			args: args{
-				id: 1,
+				id: 0,
 				contact: &model.Contact{
-					Name:   "jangkrik",
-					NoTelp: "555-555-4000",
+					Name:      "jangkrik",
+					NoTelp:    "555-555-4000",
+					UpdatedAt: testContactTime,
 				},
 			},
-			beforeTest: func(s sqlmock.Sqlmock, query string) {
-				err := errors.New("prepare stmt error")
-
-				s.ExpectPrepare(regexp.QuoteMeta(query)).
-					WillReturnError(err)
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(bumpQuery)).
+					WithArgs(tenant.Default, int64(0)).
+					WillReturnError(assert.AnError)
+				s.ExpectRollback()
 			},
 			want:    nil,
 			wantErr: true,
@@ -393,13 +479,11 @@ func (s *GormRepoSuite) Test_contactGormRepository_Update() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := `UPDATE "contacts" SET "name"=$1,"no_telp"=$2 WHERE id = $3 RETURNING "id","name","no_telp"`
-
 			if tt.beforeTest != nil {
-				tt.beforeTest(s.mockSQL, sqlQuery)
+				tt.beforeTest(s.mockSQL)
 			}
 
-			got, err := s.repo.Update(tt.args.id, tt.args.contact)
+			got, err := s.repo.Update(context.Background(), tt.args.id, tt.args.contact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactGormRepository.Update() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactGormRepository.Update() = %v, want %v", got, tt.want)
@@ -412,6 +496,104 @@ func (s *GormRepoSuite) Test_contactGormRepository_Update() {
 	}
 }
 
+func (s *GormRepoSuite) Test_contactGormRepository_Patch() {
+	bumpQuery := `UPDATE "contacts" SET "version"=version + 1 WHERE tenant = $1 AND (id = $2 AND deleted_at IS NULL)`
+	patchQuery := `UPDATE "contacts" SET "company"=$1,"updated_at"=$2 WHERE id = $3 RETURNING "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version"`
+
+	rows := sqlmock.NewRows([]string{"id", "name", "no_telp", "updated_at", "version"}).
+		AddRow(int64(1), "jangkrik", "555-555-4000", testContactTime, int64(2))
+
+	//* statements are prepared once on the pool, then again on the transaction
+	s.mockSQL.ExpectBegin()
+	s.mockSQL.ExpectPrepare(regexp.QuoteMeta(bumpQuery))
+	s.mockSQL.ExpectPrepare(regexp.QuoteMeta(bumpQuery)).
+		ExpectExec().
+		WithArgs(tenant.Default, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mockSQL.ExpectPrepare(regexp.QuoteMeta(patchQuery))
+	s.mockSQL.ExpectPrepare(regexp.QuoteMeta(patchQuery)).
+		ExpectQuery().
+		WithArgs("", testContactTime, int64(1)).
+		WillReturnRows(rows)
+	s.mockSQL.ExpectCommit()
+
+	//* an emptied field is written, not skipped as a zero value
+	got, err := s.repo.Patch(context.Background(), 1, &model.Contact{UpdatedAt: testContactTime}, []string{"company"})
+
+	s.NoError(err)
+	s.Equal(&model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime, Version: 2}, got)
+	s.NoError(s.mockSQL.ExpectationsWereMet())
+}
+
+func (s *GormRepoSuite) Test_contactGormRepository_Batch() {
+	insertQuery := `INSERT INTO "contacts" ("name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version","tenant") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING "id"`
+	deleteQuery := `UPDATE "contacts" SET "deleted_at"=$1 WHERE tenant = $2 AND (id = $3 AND deleted_at IS NULL)`
+	detailQuery := `SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE deleted_at IS NULL AND "contacts"."id" = $1 AND tenant = $2 ORDER BY "contacts"."id" LIMIT 1`
+
+	tests := []struct {
+		name       string
+		beforeTest func(sqlmock.Sqlmock)
+		wantFailed bool
+	}{
+		{
+			name: "atomic",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				//* statements are prepared once on the pool, then again on the transaction
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+					ExpectQuery().
+					WithArgs("jangkrik", "555-555-4000", "", nil, nil, nil, "", "", "", "", testContactTime, testContactTime, nil, int64(1), tenant.Default).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(8)))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+					ExpectExec().
+					WithArgs(testContactTime, tenant.Default, int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+		},
+		{
+			name: "atomic rolled back",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+					ExpectQuery().
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(8)))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+					ExpectExec().
+					WithArgs(testContactTime, tenant.Default, int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				s.ExpectPrepare(regexp.QuoteMeta(detailQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(detailQuery)).
+					ExpectQuery().
+					WithArgs(int64(7), tenant.Default).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				s.ExpectRollback()
+			},
+			wantFailed: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			ops := []model.ContactOperation{
+				{Op: model.BatchCreate, Contact: &model.Contact{Name: "jangkrik", NoTelp: "555-555-4000", CreatedAt: testContactTime, UpdatedAt: testContactTime}},
+				{Op: model.BatchDelete, ID: 7, DeletedAt: testContactTime},
+			}
+			results, err := s.repo.Batch(context.Background(), ops, true)
+
+			s.NoError(err)
+			if s.Len(results, 2) {
+				s.Equal(int64(8), results[0].Contact.ID)
+				s.Equal(tt.wantFailed, results[1].Err != nil, "contactGormRepository.Batch() error = %v", results[1].Err)
+			}
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
+
 func (s *GormRepoSuite) Test_contactGormRepository_Delete() {
 	type args struct {
 		id int64
@@ -433,11 +615,23 @@ func (s *GormRepoSuite) Test_contactGormRepository_Delete() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs(int64(1)).
+					WithArgs(testContactTime, tenant.Default, int64(1)).
 					WillReturnResult(result)
 			},
 			wantErr: false,
 		},
+		{
+			name: "missing or already in trash",
+			args: args{
+				id: 2,
+			},
+			beforeTest: func(s sqlmock.Sqlmock, query string) {
+				s.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(testContactTime, tenant.Default, int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
 		{
 			name: "failed",
 			args: args{
@@ -445,7 +639,7 @@ func (s *GormRepoSuite) Test_contactGormRepository_Delete() {
 			},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				s.ExpectExec(regexp.QuoteMeta(query)).
-					WithArgs(int64(0)).
+					WithArgs(testContactTime, tenant.Default, int64(0)).
 					WillReturnError(assert.AnError)
 			},
 			wantErr: true,
@@ -466,15 +660,320 @@ func (s *GormRepoSuite) Test_contactGormRepository_Delete() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := `DELETE FROM "contacts" WHERE "contacts"."id" = $1`
+			sqlQuery := `UPDATE "contacts" SET "deleted_at"=$1 WHERE tenant = $2 AND (id = $3 AND deleted_at IS NULL)`
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			err := s.repo.Delete(tt.args.id)
+			err := s.repo.Delete(context.Background(), tt.args.id, 0, testContactTime)
 
 			s.Equal(tt.wantErr, err != nil, "contactUsecase.Delete() error = %v, wantErr %v", err, tt.wantErr)
 		})
 	}
 }
+
+func (s *GormRepoSuite) Test_contactGormRepository_Search() {
+	tests := []struct {
+		name       string
+		query      string
+		beforeTest func(sqlmock.Sqlmock)
+		want       []model.Contact
+		wantErr    bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:  "success",
+			query: "tset",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				rows := s.NewRows([]string{"id", "name", "no_telp"}).
+					AddRow(int64(1), "test", "555-555-3232")
+
+				s.ExpectPrepare(regexp.QuoteMeta(`SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE deleted_at IS NULL AND (name ILIKE $1 OR similarity(name, $2) > $3) AND tenant = $4 ORDER BY similarity(name, $5) DESC, id ASC LIMIT 50`)).
+					ExpectQuery().
+					WithArgs("%tset%", "tset", 0.3, tenant.Default, "tset").
+					WillReturnRows(rows)
+			},
+			want: []model.Contact{
+				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "success by phone digits",
+			query: "3232",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				rows := s.NewRows([]string{"id", "name", "no_telp"}).
+					AddRow(int64(1), "test", "555-555-3232")
+
+				s.ExpectPrepare(regexp.QuoteMeta(`SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE deleted_at IS NULL AND (name ILIKE $1 OR similarity(name, $2) > $3 OR regexp_replace(no_telp, '[^0-9]', '', 'g') LIKE $4) AND tenant = $5 ORDER BY similarity(name, $6) DESC, id ASC LIMIT 50`)).
+					ExpectQuery().
+					WithArgs("%3232%", "3232", 0.3, "%3232%", tenant.Default, "3232").
+					WillReturnRows(rows)
+			},
+			want: []model.Contact{
+				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "failed prepare statement",
+			query: "test",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(`SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts"`)).
+					WillReturnError(errors.New("prepare stmt error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			if tt.beforeTest != nil {
+				tt.beforeTest(s.mockSQL)
+			}
+
+			got, err := s.repo.Search(context.Background(), tt.query)
+
+			if s.Equal(tt.wantErr, err != nil, "contactGormRepository.Search() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactGormRepository.Search() = %v, want %v", got, tt.want)
+			}
+
+			if err := s.mockSQL.ExpectationsWereMet(); err != nil {
+				s.Errorf(err, "there were unfulfilled expectations: %s")
+			}
+		})
+	}
+}
+
+func (s *GormRepoSuite) Test_contactGormRepository_Merge() {
+	lockQuery := `SELECT "id" FROM "contacts" WHERE (id IN ($1,$2,$3) AND deleted_at IS NULL) AND tenant = $4 FOR UPDATE`
+	updateQuery := `UPDATE "contacts" SET "name"=$1,"no_telp"=$2,"no_telp_raw"=$3,"phones"=$4,"emails"=$5,"addresses"=$6,"company"=$7,"job_title"=$8,"birthday"=$9,"notes"=$10,"updated_at"=$11 WHERE id = $12`
+	versionQuery := `UPDATE "contacts" SET "version"=version + 1 WHERE id = $1`
+	trashQuery := `UPDATE "contacts" SET "deleted_at"=$1 WHERE id IN ($2,$3)`
+	detailQuery := `SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE "contacts"."id" = $1 ORDER BY "contacts"."id" LIMIT 1`
+
+	contact := &model.Contact{Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime}
+
+	tests := []struct {
+		name       string
+		beforeTest func(sqlmock.Sqlmock)
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				//* statements are prepared once on the pool, then again on the transaction
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(lockQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(lockQuery)).
+					ExpectQuery().
+					WithArgs(int64(1), int64(2), int64(3), tenant.Default).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+				s.ExpectPrepare(regexp.QuoteMeta(updateQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(updateQuery)).
+					ExpectExec().
+					WithArgs("jangkrik", "555-555-4000", "", nil, nil, nil, "", "", "", "", testContactTime, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectPrepare(regexp.QuoteMeta(versionQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(versionQuery)).
+					ExpectExec().
+					WithArgs(int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectPrepare(regexp.QuoteMeta(trashQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(trashQuery)).
+					ExpectExec().
+					WithArgs(testContactTime, int64(2), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				s.ExpectPrepare(regexp.QuoteMeta(detailQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(detailQuery)).
+					ExpectQuery().
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "no_telp", "updated_at"}).
+						AddRow(int64(1), "jangkrik", "555-555-4000", testContactTime))
+				s.ExpectCommit()
+			},
+			want:    &model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime},
+			wantErr: false,
+		},
+		{
+			name: "missing contact",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(lockQuery)).
+					ExpectQuery().
+					WithArgs(int64(1), int64(2), int64(3), tenant.Default).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+				s.ExpectRollback()
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Merge(context.Background(), 1, contact, []int64{2, 3})
+
+			s.Equal(tt.wantErr, err != nil, "contactGormRepository.Merge() error = %v, wantErr %v", err, tt.wantErr)
+			s.Equal(tt.want, got)
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *GormRepoSuite) Test_contactGormRepository_Restore() {
+	restoreQuery := `UPDATE "contacts" SET "deleted_at"=$1 WHERE (id = $2 AND deleted_at IS NOT NULL) AND tenant = $3`
+	detailQuery := `SELECT "id","name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version" FROM "contacts" WHERE deleted_at IS NULL AND "contacts"."id" = $1 AND tenant = $2 ORDER BY "contacts"."id" LIMIT 1`
+
+	tests := []struct {
+		name       string
+		id         int64
+		beforeTest func(sqlmock.Sqlmock)
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			id:   1,
+			beforeTest: func(s sqlmock.Sqlmock) {
+				rows := s.NewRows([]string{"id", "name", "no_telp"}).
+					AddRow(int64(1), "jangkrik", "555-555-4000")
+
+				s.ExpectPrepare(regexp.QuoteMeta(restoreQuery)).
+					ExpectExec().
+					WithArgs(nil, int64(1), tenant.Default).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectPrepare(regexp.QuoteMeta(detailQuery)).
+					ExpectQuery().
+					WithArgs(int64(1), tenant.Default).
+					WillReturnRows(rows)
+			},
+			want:    &model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000"},
+			wantErr: false,
+		},
+		{
+			name: "not in trash",
+			id:   2,
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(restoreQuery)).
+					WithArgs(nil, int64(2), tenant.Default).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Restore(context.Background(), tt.id)
+
+			s.Equal(tt.wantErr, err != nil, "contactGormRepository.Restore() error = %v, wantErr %v", err, tt.wantErr)
+			s.Equal(tt.want, got)
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *GormRepoSuite) Test_contactGormRepository_Recreate() {
+	countQuery := `SELECT count(*) FROM "contacts" WHERE id = $1`
+	insertQuery := `INSERT INTO "contacts" ("name","no_telp","no_telp_raw","phones","emails","addresses","company","job_title","birthday","notes","created_at","updated_at","deleted_at","version","tenant","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16) RETURNING "id"`
+	contact := &model.Contact{ID: 3, Name: "test", NoTelp: "555-555-3232", CreatedAt: testContactTime, UpdatedAt: testContactTime, Version: 4}
+
+	tests := []struct {
+		name       string
+		beforeTest func(sqlmock.Sqlmock)
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(countQuery)).
+					ExpectQuery().
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(0)))
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+					ExpectQuery().
+					WithArgs("test", "555-555-3232", "", nil, nil, nil, "", "", "", "", testContactTime, testContactTime, nil, int64(4), tenant.Default, int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(3)))
+			},
+			want:    contact,
+			wantErr: false,
+		},
+		{
+			name: "id taken",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(countQuery)).
+					ExpectQuery().
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(1)))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Recreate(context.Background(), contact)
+
+			if s.Equal(tt.wantErr, err != nil, "contactGormRepository.Recreate() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactGormRepository.Recreate() = %v, want %v", got, tt.want)
+			}
+
+			if err := s.mockSQL.ExpectationsWereMet(); err != nil {
+				s.Errorf(err, "there were unfulfilled expectations: %s")
+			}
+		})
+	}
+}
+
+func (s *GormRepoSuite) Test_contactGormRepository_Purge() {
+	purgeQuery := `DELETE FROM "contacts" WHERE deleted_at < $1 AND tenant = $2`
+
+	tests := []struct {
+		name       string
+		beforeTest func(sqlmock.Sqlmock)
+		want       int64
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(purgeQuery)).
+					ExpectExec().
+					WithArgs(testContactTime, tenant.Default).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "failed",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(purgeQuery)).
+					WithArgs(testContactTime, tenant.Default).
+					WillReturnError(assert.AnError)
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Purge(context.Background(), testContactTime)
+
+			s.Equal(tt.wantErr, err != nil, "contactGormRepository.Purge() error = %v, wantErr %v", err, tt.wantErr)
+			s.Equal(tt.want, got)
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
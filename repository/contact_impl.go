@@ -3,88 +3,169 @@ package repository
 import (
 	"contact-go/helper/apperrors"
 	"contact-go/model"
+	"context"
+	"sync"
+	"time"
 )
 
-type contactRepository struct{}
+type contactRepository struct {
+	mu       sync.RWMutex
+	contacts []model.Contact
+	// lastID is the highest ID ever given, which a purge never lowers.
+	lastID int64
+}
 
 func NewContactRepository() ContactRepository {
 	return new(contactRepository)
 }
 
-func (repo *contactRepository) List() ([]model.Contact, error) {
-	return model.Contacts, nil
+func (repo *contactRepository) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	contacts, total := queryContacts(repo.contacts, query)
+	return contacts, total, nil
 }
 
-func (repo *contactRepository) getLastID() int64 {
-	contacts, _ := repo.List()
+func (repo *contactRepository) Add(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
 
-	var tempID int64
-	for _, v := range contacts {
-		if tempID < v.ID {
-			tempID = v.ID
-		}
-	}
-	return tempID
-}
+	repo.lastID++
 
-func (repo *contactRepository) getIndexByID(id int64) (int, error) {
-	contacts, _ := repo.List()
+	newContact := cloneContact(contact)
+	newContact.ID = repo.lastID
+	newContact.Version = 1
 
-	for i, v := range contacts {
-		if id == v.ID {
-			return i, nil
-		}
-	}
+	repo.contacts = append(repo.contacts, newContact)
 
-	return -1, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	return &newContact, nil
 }
 
-func (repo *contactRepository) Add(contact *model.Contact) (*model.Contact, error) {
-	id := repo.getLastID()
+func (repo *contactRepository) Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
 
-	newContact := contact
-	newContact.ID = id + 1
+	if contactIDTaken(repo.contacts, contact.ID) {
+		return nil, apperrors.NewAppError(apperrors.ErrContactDuplicate)
+	}
 
-	model.Contacts = append(model.Contacts, *newContact)
+	newContact := cloneContact(contact)
+	newContact.DeletedAt = nil
 
-	return newContact, nil
+	repo.contacts = append(repo.contacts, newContact)
+	if newContact.ID > repo.lastID {
+		repo.lastID = newContact.ID
+	}
+
+	return &newContact, nil
 }
 
-func (repo *contactRepository) Detail(id int64) (*model.Contact, error) {
-	contacts, _ := repo.List()
+func (repo *contactRepository) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
 
-	index, err := repo.getIndexByID(id)
+	index, err := contactIndexByID(repo.contacts, id)
 	if err != nil {
 		return nil, err
 	}
 
-	contact := contacts[index]
+	contact := repo.contacts[index]
 
 	return &contact, nil
 }
 
-func (repo *contactRepository) Update(id int64, contact *model.Contact) (*model.Contact, error) {
-	contacts, _ := repo.List()
+func (repo *contactRepository) Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error) {
+	return repo.Patch(ctx, id, contact, model.ContactFields)
+}
 
-	index, err := repo.getIndexByID(id)
+func (repo *contactRepository) Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	index, err := contactIndexByID(repo.contacts, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(&repo.contacts[index], contact.Version); err != nil {
+		return nil, err
+	}
 
-	updatedContact := &contacts[index]
-	updatedContact.Name = contact.Name
-	updatedContact.NoTelp = contact.NoTelp
+	updatedContact := &repo.contacts[index]
+	updateContactFields(updatedContact, contact, fields)
 
-	return updatedContact, nil
+	result := *updatedContact
+	return &result, nil
 }
 
-func (repo *contactRepository) Delete(id int64) error {
-	index, err := repo.getIndexByID(id)
+func (repo *contactRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	index, err := contactIndexByID(repo.contacts, id)
 	if err != nil {
 		return err
 	}
+	if err := checkVersion(&repo.contacts[index], version); err != nil {
+		return err
+	}
 
-	model.Contacts = append(model.Contacts[:index], model.Contacts[index+1:]...)
+	repo.contacts[index].DeletedAt = &deletedAt
 
 	return nil
 }
+
+func (repo *contactRepository) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return searchContacts(repo.contacts, query), nil
+}
+
+func (repo *contactRepository) Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	contacts, merged, err := mergeContacts(repo.contacts, id, contact, mergedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.contacts = contacts
+	return merged, nil
+}
+
+func (repo *contactRepository) Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	contacts, lastID, results := batchContacts(repo.contacts, repo.lastID, ops, atomic)
+	repo.contacts, repo.lastID = contacts, lastID
+
+	return results, nil
+}
+
+func (repo *contactRepository) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	index, err := trashIndexByID(repo.contacts, id)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.contacts[index].DeletedAt = nil
+
+	contact := repo.contacts[index]
+	return &contact, nil
+}
+
+func (repo *contactRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	contacts, purged := purgeContacts(repo.contacts, before)
+	repo.contacts = contacts
+
+	return purged, nil
+}
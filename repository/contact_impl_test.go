@@ -1,59 +1,116 @@
 package repository
 
 import (
+	"contact-go/helper/apperrors"
 	"contact-go/model"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
 
+// testContactTime is a timestamp at the second precision every backend keeps.
+var testContactTime = time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
 type InMemoryRepoSuite struct {
 	suite.Suite
 	repo ContactRepository
 }
 
 func (s *InMemoryRepoSuite) SetupSuite() {
-	model.Contacts = []model.Contact{
+	repo := NewContactRepository()
+	repo.(*contactRepository).contacts = []model.Contact{
 		{ID: 1, Name: "Reva", NoTelp: "555-1234-989"},
 		{ID: 2, Name: "Tirta", NoTelp: "555-5678"},
 		{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
 	}
+	repo.(*contactRepository).lastID = 3
 
-	repo := NewContactRepository()
 	s.repo = repo
 }
 
-func (s *InMemoryRepoSuite) TearDownSuite() {
-	model.Contacts = []model.Contact{}
-}
-
 func TestInMemoryRepoSuite(t *testing.T) {
 	suite.Run(t, new(InMemoryRepoSuite))
 }
 
 func (s *InMemoryRepoSuite) Test_contactRepository_List() {
 	tests := []struct {
-		name    string
-		want    []model.Contact
-		wantErr bool
+		name      string
+		query     *model.ContactQuery
+		want      []model.Contact
+		wantTotal int64
+		wantErr   bool
 	}{
 		// TODO: Add test cases.
 		{
-			name: "success",
+			name:  "success",
+			query: &model.ContactQuery{},
 			want: []model.Contact{
 				{ID: 2, Name: "Tirta", NoTelp: "555-5678"},
 				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
-				{ID: 4, Name: "Mixue", NoTelp: "555-9999"},
+				{ID: 4, Name: "Mixue", NoTelp: "555-9999", Version: 1},
 			},
-			wantErr: false,
+			wantTotal: 3,
+			wantErr:   false,
+		},
+		{
+			name:  "filter by name sorted by name",
+			query: &model.ContactQuery{Name: "A", SortBy: "name", Limit: 1},
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
+			},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name:  "filter by ids",
+			query: &model.ContactQuery{IDs: []int64{2, 4, 9}},
+			want: []model.Contact{
+				{ID: 2, Name: "Tirta", NoTelp: "555-5678"},
+				{ID: 4, Name: "Mixue", NoTelp: "555-9999", Version: 1},
+			},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name:  "filter by no_telp",
+			query: &model.ContactQuery{NoTelp: "9999"},
+			want: []model.Contact{
+				{ID: 4, Name: "Mixue", NoTelp: "555-9999", Version: 1},
+			},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name:  "descending with offset",
+			query: &model.ContactQuery{Order: "desc", Limit: 2, Offset: 1},
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
+				{ID: 2, Name: "Tirta", NoTelp: "555-5678"},
+			},
+			wantTotal: 3,
+			wantErr:   false,
+		},
+		{
+			name:      "offset past the end",
+			query:     &model.ContactQuery{Limit: 2, Offset: 10},
+			want:      []model.Contact{},
+			wantTotal: 3,
+			wantErr:   false,
 		},
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got, err := s.repo.List()
+			got, total, err := s.repo.List(context.Background(), tt.query)
 
 			if s.Equal(tt.wantErr, err != nil, "contactRepository.List() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactRepository.List() = %v, want %v", got, tt.want)
+				s.Equal(tt.wantTotal, total, "contactRepository.List() total = %v, want %v", total, tt.wantTotal)
 			}
 		})
 	}
@@ -79,16 +136,17 @@ func (s *InMemoryRepoSuite) Test_contactRepository_Add() {
 				},
 			},
 			want: &model.Contact{
-				ID:     4,
-				Name:   "Mixue",
-				NoTelp: "555-9999",
+				ID:      4,
+				Name:    "Mixue",
+				NoTelp:  "555-9999",
+				Version: 1,
 			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got, err := s.repo.Add(tt.args.newContact)
+			got, err := s.repo.Add(context.Background(), tt.args.newContact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactRepository.Add() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactRepository.Add() = %v, want %v", got, tt.want)
@@ -131,7 +189,7 @@ func (s *InMemoryRepoSuite) Test_contactRepository_Detail() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got, err := s.repo.Detail(tt.args.id)
+			got, err := s.repo.Detail(context.Background(), tt.args.id)
 
 			if s.Equal(tt.wantErr, err != nil, "contactRepository.Detail() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactRepository.Detail() = %v, want %v", got, tt.want)
@@ -162,12 +220,26 @@ func (s *InMemoryRepoSuite) Test_contactRepository_Update() {
 				},
 			},
 			want: &model.Contact{
-				ID:     3,
-				Name:   "Reva Iota",
-				NoTelp: "555-1234-989",
+				ID:      3,
+				Name:    "Reva Iota",
+				NoTelp:  "555-1234-989",
+				Version: 1,
 			},
 			wantErr: false,
 		},
+		{
+			name: "stale version",
+			args: args{
+				id: 2,
+				updatedContact: &model.Contact{
+					Name:    "Tirta",
+					NoTelp:  "555-5678",
+					Version: 3,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "failed",
 			args: args{
@@ -183,7 +255,7 @@ func (s *InMemoryRepoSuite) Test_contactRepository_Update() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got, err := s.repo.Update(tt.args.id, tt.args.updatedContact)
+			got, err := s.repo.Update(context.Background(), tt.args.id, tt.args.updatedContact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactRepository.Update() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactRepository.Update() = %v, want %v", got, tt.want)
@@ -219,9 +291,560 @@ func (s *InMemoryRepoSuite) Test_contactRepository_Delete() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			err := s.repo.Delete(tt.args.id)
+			err := s.repo.Delete(context.Background(), tt.args.id, 0, testContactTime)
 
 			s.Equal(tt.wantErr, err != nil, "contactRepository.Detail() error = %v, wantErr %v", err, tt.wantErr)
 		})
 	}
 }
+
+func (s *InMemoryRepoSuite) Test_contactRepository_Search() {
+	tests := []struct {
+		name    string
+		query   string
+		want    []model.Contact
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:  "name substring",
+			query: "tirta",
+			want: []model.Contact{
+				{ID: 2, Name: "Tirta", NoTelp: "555-5678"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "name with typo",
+			query: "Bagss",
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "partial phone digits",
+			query: "90-12",
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "no match",
+			query:   "55",
+			want:    []model.Contact{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, err := s.repo.Search(context.Background(), tt.query)
+
+			if s.Equal(tt.wantErr, err != nil, "contactRepository.Search() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactRepository.Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// hammerContactRepository adds, updates, deletes and reads contacts from
+// many goroutines at once. Run it with -race to catch unsynchronised access.
+func hammerContactRepository(t *testing.T, repo ContactRepository, workers int) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	ids := make(chan int64, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			contact, err := repo.Add(context.Background(), &model.Contact{Name: fmt.Sprintf("worker-%d", i), NoTelp: "555-0000"})
+			if err != nil {
+				t.Errorf("Add() error = %v", err)
+				return
+			}
+			ids <- contact.ID
+		}(i)
+	}
+	wg.Wait()
+	close(ids)
+
+	var added []int64
+	seen := make(map[int64]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("Add() returned duplicate id %d", id)
+		}
+		seen[id] = true
+		added = append(added, id)
+	}
+
+	var deleted int
+	for i, id := range added {
+		wg.Add(2)
+		if i%2 == 0 {
+			deleted++
+			go func(id int64) {
+				defer wg.Done()
+				if err := repo.Delete(context.Background(), id, 0, time.Now()); err != nil {
+					t.Errorf("Delete(%d) error = %v", id, err)
+				}
+			}(id)
+		} else {
+			go func(id int64) {
+				defer wg.Done()
+				if _, err := repo.Update(context.Background(), id, &model.Contact{Name: "updated", NoTelp: "555-1111"}); err != nil {
+					t.Errorf("Update(%d) error = %v", id, err)
+				}
+			}(id)
+		}
+
+		go func() {
+			defer wg.Done()
+			if _, _, err := repo.List(context.Background(), &model.ContactQuery{Limit: 10}); err != nil {
+				t.Errorf("List() error = %v", err)
+			}
+			if _, err := repo.Search(context.Background(), "worker"); err != nil {
+				t.Errorf("Search() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	contacts, total, err := repo.List(context.Background(), &model.ContactQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := int64(len(added) - deleted); total != want {
+		t.Errorf("List() total = %d, want %d", total, want)
+	}
+	for _, v := range contacts {
+		if v.Name != "updated" {
+			t.Errorf("contact %d was not updated, got name %q", v.ID, v.Name)
+		}
+	}
+}
+
+func Test_contactRepository_ConcurrentAccess(t *testing.T) {
+	hammerContactRepository(t, NewContactRepository(), 100)
+}
+
+// checkContactDetails stores a contact with every detail filled in
+// and checks that repo gives all of it back, keeping the creation
+// time when the contact is updated. The contact handed to Add is the
+// caller's still, changing it afterwards changes nothing stored.
+func checkContactDetails(t *testing.T, repo ContactRepository) {
+	ctx := context.Background()
+
+	contact := &model.Contact{
+		Name:      "Reva",
+		NoTelp:    "555-1234-989",
+		Phones:    []model.Phone{{Type: model.TypeWork, Number: "555-0000"}},
+		Emails:    []model.Email{{Type: model.TypeHome, Address: "reva@example.com"}},
+		Addresses: []model.Address{{Type: model.TypeHome, Street: "Jl. Merdeka 1", City: "Bandung", Country: "ID"}},
+		Company:   "Acme",
+		JobTitle:  "Engineer",
+		Birthday:  "1990-05-17",
+		Notes:     "likes tea",
+		CreatedAt: testContactTime,
+		UpdatedAt: testContactTime,
+	}
+
+	added, err := repo.Add(ctx, contact)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if contact.ID != 0 || contact.Version != 0 {
+		t.Errorf("Add() changed its argument to %+v", contact)
+	}
+	contact.Phones[0].Number = "555-9999"
+
+	got, err := repo.Detail(ctx, added.ID)
+	if err != nil {
+		t.Fatalf("Detail() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, added) || got.Phones[0].Number != "555-0000" {
+		t.Errorf("Detail() = %+v, want %+v", got, added)
+	}
+
+	update := &model.Contact{
+		Name:      "Reva",
+		NoTelp:    "555-1234-989",
+		Emails:    []model.Email{{Type: model.TypeWork, Address: "reva@acme.example"}},
+		Notes:     "prefers coffee now",
+		UpdatedAt: testContactTime.Add(time.Hour),
+	}
+	want := *update
+	want.ID = added.ID
+	want.CreatedAt = testContactTime
+	want.Version = 2
+
+	updated, err := repo.Update(ctx, added.ID, update)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !reflect.DeepEqual(updated, &want) {
+		t.Errorf("Update() = %+v, want %+v", updated, &want)
+	}
+
+	got, err = repo.Detail(ctx, added.ID)
+	if err != nil {
+		t.Fatalf("Detail() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &want) {
+		t.Errorf("Detail() after Update() = %+v, want %+v", got, &want)
+	}
+}
+
+func Test_contactRepository_Details(t *testing.T) {
+	checkContactDetails(t, NewContactRepository())
+}
+
+// checkContactMerge merges three stored contacts into one and checks
+// that a merge naming a missing contact changes nothing.
+func checkContactMerge(t *testing.T, repo ContactRepository) {
+	ctx := context.Background()
+
+	var ids []int64
+	for _, name := range []string{"Reva", "Reva S", "Reva Saputra"} {
+		added, err := repo.Add(ctx, &model.Contact{Name: name, NoTelp: "555-1234-989", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		ids = append(ids, added.ID)
+	}
+
+	merge := &model.Contact{
+		Name:      "Reva Saputra",
+		NoTelp:    "555-1234-989",
+		Phones:    []model.Phone{{Type: model.TypeOther, Number: "555-0000"}},
+		UpdatedAt: testContactTime.Add(time.Hour),
+	}
+	want := *merge
+	want.ID = ids[0]
+	want.CreatedAt = testContactTime
+	want.Version = 2
+
+	if _, err := repo.Merge(ctx, ids[0], merge, []int64{ids[1], 999}); err == nil {
+		t.Fatalf("Merge() with a missing contact error = nil")
+	}
+	if _, total, _ := repo.List(ctx, &model.ContactQuery{}); total != 3 {
+		t.Fatalf("List() after failed Merge() total = %d, want 3", total)
+	}
+
+	merged, err := repo.Merge(ctx, ids[0], merge, ids[1:])
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if !reflect.DeepEqual(merged, &want) {
+		t.Errorf("Merge() = %+v, want %+v", merged, &want)
+	}
+
+	contacts, total, err := repo.List(ctx, &model.ContactQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 1 || !reflect.DeepEqual(contacts, []model.Contact{want}) {
+		t.Errorf("List() after Merge() = %+v, want only %+v", contacts, want)
+	}
+
+	trash, total, err := repo.List(ctx, &model.ContactQuery{Deleted: true})
+	if err != nil {
+		t.Fatalf("List() trash error = %v", err)
+	}
+	if total != 2 || trash[0].DeletedAt == nil || !trash[0].DeletedAt.Equal(merge.UpdatedAt) {
+		t.Errorf("List() trash after Merge() = %+v, want the 2 merged contacts", trash)
+	}
+}
+
+func Test_contactRepository_Merge(t *testing.T) {
+	checkContactMerge(t, NewContactRepository())
+}
+
+// checkContactTrash moves a contact to the trash, checks that only the
+// trash listing still sees it, restores it and purges it again.
+func checkContactTrash(t *testing.T, repo ContactRepository) {
+	ctx := context.Background()
+
+	added, err := repo.Add(ctx, &model.Contact{Name: "Reva", NoTelp: "555-1234-989", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	deletedAt := testContactTime.Add(time.Hour)
+
+	if err := repo.Delete(ctx, added.ID, 0, deletedAt); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(ctx, added.ID, 0, deletedAt); err == nil {
+		t.Errorf("Delete() of a contact in the trash error = nil")
+	}
+	if _, err := repo.Detail(ctx, added.ID); err == nil {
+		t.Errorf("Detail() of a contact in the trash error = nil")
+	}
+	if _, total, _ := repo.List(ctx, &model.ContactQuery{}); total != 0 {
+		t.Errorf("List() total = %d, want 0", total)
+	}
+	if found, _ := repo.Search(ctx, "Reva"); len(found) != 0 {
+		t.Errorf("Search() = %+v, want none", found)
+	}
+
+	trash, total, err := repo.List(ctx, &model.ContactQuery{Deleted: true})
+	if err != nil {
+		t.Fatalf("List() trash error = %v", err)
+	}
+	if total != 1 || trash[0].ID != added.ID || trash[0].DeletedAt == nil || !trash[0].DeletedAt.Equal(deletedAt) {
+		t.Fatalf("List() trash = %+v, want the deleted contact", trash)
+	}
+
+	restored, err := repo.Restore(ctx, added.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !reflect.DeepEqual(restored, added) {
+		t.Errorf("Restore() = %+v, want %+v", restored, added)
+	}
+	if _, err := repo.Restore(ctx, added.ID); err == nil {
+		t.Errorf("Restore() of a contact not in the trash error = nil")
+	}
+
+	if err := repo.Delete(ctx, added.ID, 0, deletedAt); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if purged, err := repo.Purge(ctx, deletedAt); err != nil || purged != 0 {
+		t.Errorf("Purge() before the deletion = %d, %v, want 0", purged, err)
+	}
+	if purged, err := repo.Purge(ctx, deletedAt.Add(time.Second)); err != nil || purged != 1 {
+		t.Errorf("Purge() after the deletion = %d, %v, want 1", purged, err)
+	}
+	if _, total, _ := repo.List(ctx, &model.ContactQuery{Deleted: true}); total != 0 {
+		t.Errorf("List() trash after Purge() total = %d, want 0", total)
+	}
+	if _, err := repo.Restore(ctx, added.ID); err == nil {
+		t.Errorf("Restore() of a purged contact error = nil")
+	}
+
+	//* the ID of the purged contact, the highest one, is not given again
+	next, err := repo.Add(ctx, &model.Contact{Name: "Tirta", NoTelp: "555-5678", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() after Purge() error = %v", err)
+	}
+	if next.ID <= added.ID {
+		t.Errorf("Add() after Purge() ID = %d, want above the purged %d", next.ID, added.ID)
+	}
+}
+
+// checkContactRecreate purges a contact and stores it again under its
+// own ID, which only goes through while no contact has that ID.
+func checkContactRecreate(t *testing.T, repo ContactRepository) {
+	ctx := context.Background()
+
+	added, err := repo.Add(ctx, &model.Contact{Name: "Reva", NoTelp: "555-1234-989", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	kept, err := repo.Add(ctx, &model.Contact{Name: "Bagas", NoTelp: "555-9012-989", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	var appErr *apperrors.AppError
+	if _, err := repo.Recreate(ctx, added); !errors.As(err, &appErr) || appErr.Message != apperrors.ErrContactDuplicate {
+		t.Errorf("Recreate() of a stored contact error = %v, want %s", err, apperrors.ErrContactDuplicate)
+	}
+
+	deletedAt := testContactTime.Add(time.Hour)
+	if err := repo.Delete(ctx, added.ID, 0, deletedAt); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.Recreate(ctx, added); !errors.As(err, &appErr) || appErr.Message != apperrors.ErrContactDuplicate {
+		t.Errorf("Recreate() of a contact in the trash error = %v, want %s", err, apperrors.ErrContactDuplicate)
+	}
+	if _, err := repo.Purge(ctx, deletedAt.Add(time.Second)); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	recreate := *added
+	recreate.Name = "Reva Tirta"
+	recreate.UpdatedAt = deletedAt.Add(time.Hour)
+	recreate.Version = 3
+
+	got, err := repo.Recreate(ctx, &recreate)
+	if err != nil {
+		t.Fatalf("Recreate() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &recreate) {
+		t.Errorf("Recreate() = %+v, want %+v", got, &recreate)
+	}
+	if stored, err := repo.Detail(ctx, added.ID); err != nil || !reflect.DeepEqual(stored, &recreate) {
+		t.Errorf("Detail() of the recreated contact = %+v, %v, want %+v", stored, err, &recreate)
+	}
+
+	if next, err := repo.Add(ctx, &model.Contact{Name: "Citra", NoTelp: "555-5678-989", CreatedAt: testContactTime, UpdatedAt: testContactTime}); err != nil || next.ID == added.ID || next.ID == kept.ID {
+		t.Errorf("Add() after Recreate() = %+v, %v, want a new ID", next, err)
+	}
+}
+
+// checkContactVersion updates and deletes a contact from a stale copy
+// and checks that only the copy at the stored version gets through.
+func checkContactVersion(t *testing.T, repo ContactRepository) {
+	ctx := context.Background()
+
+	added, err := repo.Add(ctx, &model.Contact{Name: "Reva", NoTelp: "555-1234-989", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if added.Version != 1 {
+		t.Fatalf("Add() version = %d, want 1", added.Version)
+	}
+
+	update := &model.Contact{Name: "Reva S", NoTelp: "555-1234-989", UpdatedAt: testContactTime, Version: 1}
+	updated, err := repo.Update(ctx, added.ID, update)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("Update() version = %d, want 2", updated.Version)
+	}
+
+	var appErr *apperrors.AppError
+	if _, err := repo.Update(ctx, added.ID, update); !errors.As(err, &appErr) || appErr.Message != apperrors.ErrContactVersion {
+		t.Errorf("Update() from a stale copy error = %v, want %s", err, apperrors.ErrContactVersion)
+	}
+	if _, err := repo.Update(ctx, 999, update); !errors.As(err, &appErr) || appErr.Message != apperrors.ErrContactNotFound {
+		t.Errorf("Update() of a missing contact error = %v, want %s", err, apperrors.ErrContactNotFound)
+	}
+	if err := repo.Delete(ctx, added.ID, 1, testContactTime); !errors.As(err, &appErr) || appErr.Message != apperrors.ErrContactVersion {
+		t.Errorf("Delete() from a stale copy error = %v, want %s", err, apperrors.ErrContactVersion)
+	}
+	if err := repo.Delete(ctx, added.ID, 2, testContactTime); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
+
+// checkContactPatch patches a single field of a contact and checks
+// that the rest, zero values included, are left as stored.
+func checkContactPatch(t *testing.T, repo ContactRepository) {
+	ctx := context.Background()
+
+	added, err := repo.Add(ctx, &model.Contact{
+		Name:      "Reva",
+		NoTelp:    "+15551234989",
+		NoTelpRaw: "555-1234-989",
+		Emails:    []model.Email{{Type: model.TypeHome, Address: "reva@example.com"}},
+		Company:   "Acme",
+		CreatedAt: testContactTime,
+		UpdatedAt: testContactTime,
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	updatedAt := testContactTime.Add(time.Hour)
+	patched, err := repo.Patch(ctx, added.ID, &model.Contact{Company: "", UpdatedAt: updatedAt}, []string{"company"})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+
+	want := *added
+	want.Company = ""
+	want.UpdatedAt = updatedAt
+	want.Version = 2
+	if !reflect.DeepEqual(patched, &want) {
+		t.Errorf("Patch() = %+v, want %+v", patched, &want)
+	}
+
+	patched, err = repo.Patch(ctx, added.ID, &model.Contact{NoTelp: "+15559999999", NoTelpRaw: "555-999-9999", UpdatedAt: updatedAt, Version: 2}, []string{"no_telp"})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	want.NoTelp, want.NoTelpRaw, want.Version = "+15559999999", "555-999-9999", 3
+	if !reflect.DeepEqual(patched, &want) {
+		t.Errorf("Patch() = %+v, want %+v", patched, &want)
+	}
+
+	var appErr *apperrors.AppError
+	if _, err := repo.Patch(ctx, added.ID, &model.Contact{Name: "Reva S", Version: 2}, []string{"name"}); !errors.As(err, &appErr) || appErr.Message != apperrors.ErrContactVersion {
+		t.Errorf("Patch() from a stale copy error = %v, want %s", err, apperrors.ErrContactVersion)
+	}
+}
+
+// checkContactBatch runs a best-effort batch and two atomic ones, and
+// checks that a failed atomic batch stores none of its operations.
+func checkContactBatch(t *testing.T, repo ContactRepository) {
+	ctx := context.Background()
+
+	added, err := repo.Add(ctx, &model.Contact{Name: "Reva", NoTelp: "+15551234989", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	results, err := repo.Batch(ctx, []model.ContactOperation{
+		{Op: model.BatchCreate, Contact: &model.Contact{Name: "Tirta", NoTelp: "+15555678", CreatedAt: testContactTime, UpdatedAt: testContactTime}},
+		{Op: model.BatchUpdate, ID: added.ID, Contact: &model.Contact{Name: "Reva S", NoTelp: "+15551234989", UpdatedAt: testContactTime, Version: 1}},
+		{Op: model.BatchDelete, ID: 999, DeletedAt: testContactTime},
+	}, false)
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	if len(results) != 3 || results[0].Err != nil || results[1].Err != nil || results[2].Err == nil {
+		t.Fatalf("Batch() best effort = %+v, want the last one alone to fail", results)
+	}
+	created := results[0].Contact
+	if created.ID == 0 || created.Version != 1 || results[1].Contact.Name != "Reva S" || results[1].Contact.Version != 2 {
+		t.Errorf("Batch() best effort contacts = %+v, %+v", created, results[1].Contact)
+	}
+
+	results, err = repo.Batch(ctx, []model.ContactOperation{
+		{Op: model.BatchCreate, Contact: &model.Contact{Name: "Bagas", NoTelp: "+15559012", CreatedAt: testContactTime, UpdatedAt: testContactTime}},
+		{Op: model.BatchDelete, ID: added.ID, DeletedAt: testContactTime},
+		{Op: model.BatchUpdate, ID: created.ID, Contact: &model.Contact{Name: "Tirta", NoTelp: "+15555678", UpdatedAt: testContactTime, Version: 5}},
+	}, true)
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	var appErr *apperrors.AppError
+	if len(results) != 3 || !errors.As(results[2].Err, &appErr) || appErr.Message != apperrors.ErrContactVersion {
+		t.Fatalf("Batch() failed atomic = %+v, want it to stop at the stale update", results)
+	}
+
+	contacts, total, err := repo.List(ctx, &model.ContactQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 2 || contacts[0].ID != added.ID || contacts[1].ID != created.ID {
+		t.Errorf("List() after a failed atomic Batch() = %+v, want Reva S and Tirta alone", contacts)
+	}
+
+	results, err = repo.Batch(ctx, []model.ContactOperation{
+		{Op: model.BatchDelete, ID: added.ID, Version: 2, DeletedAt: testContactTime},
+		{Op: model.BatchDelete, ID: created.ID, Version: 1, DeletedAt: testContactTime},
+	}, true)
+	if err != nil || failedOperation(results) {
+		t.Fatalf("Batch() atomic = %+v, %v", results, err)
+	}
+	if _, total, _ := repo.List(ctx, &model.ContactQuery{Deleted: true}); total != 2 {
+		t.Errorf("List() trash after an atomic Batch() total = %d, want 2", total)
+	}
+}
+
+func Test_contactRepository_Batch(t *testing.T) {
+	checkContactBatch(t, NewContactRepository())
+}
+
+func Test_contactRepository_Patch(t *testing.T) {
+	checkContactPatch(t, NewContactRepository())
+}
+
+func Test_contactRepository_Version(t *testing.T) {
+	checkContactVersion(t, NewContactRepository())
+}
+
+func Test_contactRepository_Trash(t *testing.T) {
+	checkContactTrash(t, NewContactRepository())
+}
+
+func Test_contactRepository_Recreate(t *testing.T) {
+	checkContactRecreate(t, NewContactRepository())
+}
@@ -1,12 +1,45 @@
 //go:generate mockery --output=../mocks --name ContactRepository
 package repository
 
-import "contact-go/model"
+import (
+	"contact-go/model"
+	"context"
+	"time"
+)
 
 type ContactRepository interface {
-	List() ([]model.Contact, error)
-	Add(contact *model.Contact) (*model.Contact, error)
-	Detail(id int64) (*model.Contact, error)
-	Update(id int64, contact *model.Contact) (*model.Contact, error)
-	Delete(id int64) error
+	List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error)
+	// Add stores contact at version 1.
+	Add(ctx context.Context, contact *model.Contact) (*model.Contact, error)
+	Detail(ctx context.Context, id int64) (*model.Contact, error)
+	// Update replaces the contact id with contact and bumps its version.
+	// A non-zero contact.Version must match the stored one, or the
+	// update fails with apperrors.ErrContactVersion.
+	Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error)
+	// Patch is Update limited to the model.ContactFields named in fields,
+	// leaving the others as stored.
+	Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error)
+	// Delete moves the contact id to the trash as of deletedAt, checking
+	// a non-zero version the way Update does. Contacts in the trash are
+	// left out by every other method but List of the trash, Restore and Purge.
+	Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error
+	Search(ctx context.Context, query string) ([]model.Contact, error)
+	// Merge updates the contact id with contact, bumping its version,
+	// and moves mergedIDs to the trash as of contact.UpdatedAt,
+	// all or nothing.
+	Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error)
+	// Restore takes the contact id out of the trash.
+	Restore(ctx context.Context, id int64) (*model.Contact, error)
+	// Recreate stores a purged contact again under its own ID and at its
+	// own version, failing with apperrors.ErrContactDuplicate when a
+	// contact, in the trash or not, already has that ID.
+	Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error)
+	// Batch stores ops in order, returning how each fared. An atomic
+	// batch is stored all or nothing and stops at the first failing
+	// operation, so its results end there. The error is kept for
+	// failures of the batch as a whole.
+	Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error)
+	// Purge permanently removes the contacts moved to the trash
+	// before before, returning how many it removed.
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
@@ -1,114 +1,163 @@
 package repository
 
 import (
+	"bytes"
 	"contact-go/helper/apperrors"
 	"contact-go/model"
+	"context"
 	"encoding/json"
 	"os"
+	"time"
 )
 
+// contactJsonRepository keeps the contacts in a jsonStore, which
+// keeps the previous versions of the file as backups.
 type contactJsonRepository struct {
-	jsonFile string
+	store jsonStore
 }
 
-func NewContactJsonRepository(jsonFilePath string) ContactRepository {
+// contactFile is the layout of the file. LastID is the highest ID ever
+// given, which a purge never lowers, so an ID is never given twice.
+// Files written before it are a bare array of contacts.
+type contactFile struct {
+	LastID   int64           `json:"last_id"`
+	Contacts []model.Contact `json:"contacts"`
+}
+
+// NewContactJsonRepository stores contacts in jsonFilePath and keeps
+// the previous backups versions of it next to the file.
+func NewContactJsonRepository(jsonFilePath string, backups int) ContactRepository {
 	repo := new(contactJsonRepository)
-	repo.jsonFile = jsonFilePath
+	repo.store.path = jsonFilePath
+	repo.store.backups = backups
 	return repo
 }
 
-func (repo *contactJsonRepository) encodeJSON() error {
-	writer, err := os.Create(repo.jsonFile)
-	if err != nil {
-		return err
+// encodeJSON writes contacts and the last ID given to the file.
+func (repo *contactJsonRepository) encodeJSON(contacts []model.Contact, lastID int64) error {
+	if contacts == nil {
+		contacts = []model.Contact{}
 	}
-	defer writer.Close()
+	return repo.store.encode(&contactFile{LastID: lastID, Contacts: contacts})
+}
 
-	encoder := json.NewEncoder(writer)
-	err = encoder.Encode(&model.Contacts)
+// decodeJSON returns the contacts of the file and the last ID given,
+// none when the file is not there yet.
+func (repo *contactJsonRepository) decodeJSON() ([]model.Contact, int64, error) {
+	data, err := os.ReadFile(repo.store.path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	return nil
-}
 
-func (repo *contactJsonRepository) decodeJSON() error {
-	reader, err := os.Open(repo.jsonFile)
+	var file contactFile
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &file.Contacts)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	defer reader.Close()
 
-	decoder := json.NewDecoder(reader)
-	err = decoder.Decode(&model.Contacts)
-	if err != nil {
-		return err
+	//* a legacy file, or one edited by hand, may hold higher IDs
+	if last := lastContactID(file.Contacts); last > file.LastID {
+		file.LastID = last
 	}
-	return nil
+	return file.Contacts, file.LastID, nil
 }
 
-func (repo *contactJsonRepository) List() ([]model.Contact, error) {
-	err := repo.decodeJSON()
+func (repo *contactJsonRepository) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	unlock, err := repo.store.lock(ctx, false)
 	if err != nil {
-		return []model.Contact{}, err
+		return []model.Contact{}, 0, err
 	}
+	defer unlock()
 
-	return model.Contacts, nil
-}
+	contacts, _, err := repo.decodeJSON()
+	if err != nil {
+		return []model.Contact{}, 0, err
+	}
 
-func (repo *contactJsonRepository) getLastID() (int64, error) {
-	contacts, err := repo.List()
+	contacts, total := queryContacts(contacts, query)
+	return contacts, total, nil
+}
 
-	var tempID int64
-	for _, v := range contacts {
-		if tempID < v.ID {
-			tempID = v.ID
-		}
+func (repo *contactJsonRepository) Add(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return nil, err
 	}
-	return tempID, err
-}
+	defer unlock()
 
-func (repo *contactJsonRepository) getIndexByID(id int64) (int, error) {
-	contacts, err := repo.List()
+	contacts, lastID, err := repo.decodeJSON()
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 
-	for i, v := range contacts {
-		if id == v.ID {
-			return i, nil
-		}
+	lastID++
+
+	newContact := cloneContact(contact)
+	newContact.ID = lastID
+	newContact.Version = 1
+
+	contacts = append(contacts, newContact)
+
+	err = repo.encodeJSON(contacts, lastID)
+	if err != nil {
+		return nil, err
 	}
 
-	return -1, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	return &newContact, nil
 }
 
-func (repo *contactJsonRepository) Add(contact *model.Contact) (*model.Contact, error) {
-	id, err := repo.getLastID()
+func (repo *contactJsonRepository) Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	contacts, lastID, err := repo.decodeJSON()
 	if err != nil {
 		return nil, err
 	}
 
-	newContact := contact
-	newContact.ID = id + 1
+	if contactIDTaken(contacts, contact.ID) {
+		return nil, apperrors.NewAppError(apperrors.ErrContactDuplicate)
+	}
+
+	newContact := cloneContact(contact)
+	newContact.DeletedAt = nil
 
-	model.Contacts = append(model.Contacts, *newContact)
+	contacts = append(contacts, newContact)
+	if newContact.ID > lastID {
+		lastID = newContact.ID
+	}
 
-	err = repo.encodeJSON()
+	err = repo.encodeJSON(contacts, lastID)
 	if err != nil {
 		return nil, err
 	}
 
-	return newContact, nil
+	return &newContact, nil
 }
 
-func (repo *contactJsonRepository) Detail(id int64) (*model.Contact, error) {
-	contacts, err := repo.List()
+func (repo *contactJsonRepository) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	unlock, err := repo.store.lock(ctx, false)
 	if err != nil {
 		return nil, err
 	}
+	defer unlock()
 
-	index, err := repo.getIndexByID(id)
+	contacts, _, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := contactIndexByID(contacts, id)
 	if err != nil {
 		return nil, err
 	}
@@ -118,41 +167,188 @@ func (repo *contactJsonRepository) Detail(id int64) (*model.Contact, error) {
 	return &contact, nil
 }
 
-func (repo *contactJsonRepository) Update(id int64, contact *model.Contact) (*model.Contact, error) {
-	contacts, err := repo.List()
+func (repo *contactJsonRepository) Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error) {
+	return repo.Patch(ctx, id, contact, model.ContactFields)
+}
+
+func (repo *contactJsonRepository) Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error) {
+	unlock, err := repo.store.lock(ctx, true)
 	if err != nil {
 		return nil, err
 	}
+	defer unlock()
 
-	index, err := repo.getIndexByID(id)
+	contacts, lastID, err := repo.decodeJSON()
 	if err != nil {
 		return nil, err
 	}
 
+	index, err := contactIndexByID(contacts, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(&contacts[index], contact.Version); err != nil {
+		return nil, err
+	}
+
 	updatedContact := &contacts[index]
-	updatedContact.Name = contact.Name
-	updatedContact.NoTelp = contact.NoTelp
+	updateContactFields(updatedContact, contact, fields)
 
-	err = repo.encodeJSON()
+	err = repo.encodeJSON(contacts, lastID)
 	if err != nil {
 		return nil, err
 	}
 
-	return updatedContact, nil
+	result := *updatedContact
+	return &result, nil
 }
 
-func (repo *contactJsonRepository) Delete(id int64) error {
-	index, err := repo.getIndexByID(id)
+func (repo *contactJsonRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	unlock, err := repo.store.lock(ctx, true)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	model.Contacts = append(model.Contacts[:index], model.Contacts[index+1:]...)
+	contacts, lastID, err := repo.decodeJSON()
+	if err != nil {
+		return err
+	}
 
-	err = repo.encodeJSON()
+	index, err := contactIndexByID(contacts, id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(&contacts[index], version); err != nil {
+		return err
+	}
+
+	contacts[index].DeletedAt = &deletedAt
+
+	err = repo.encodeJSON(contacts, lastID)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (repo *contactJsonRepository) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	unlock, err := repo.store.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	contacts, _, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return searchContacts(contacts, query), nil
+}
+
+func (repo *contactJsonRepository) Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	contacts, lastID, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, merged, err := mergeContacts(contacts, id, contact, mergedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.encodeJSON(contacts, lastID)
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// Batch rewrites the file once for the whole batch, and not at all
+// when an atomic batch fails.
+func (repo *contactJsonRepository) Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	contacts, lastID, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, lastID, results := batchContacts(contacts, lastID, ops, atomic)
+	if atomic && failedOperation(results) {
+		return results, nil
+	}
+
+	err = repo.encodeJSON(contacts, lastID)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (repo *contactJsonRepository) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	contacts, lastID, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := trashIndexByID(contacts, id)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts[index].DeletedAt = nil
+
+	err = repo.encodeJSON(contacts, lastID)
+	if err != nil {
+		return nil, err
+	}
+
+	contact := contacts[index]
+	return &contact, nil
+}
+
+func (repo *contactJsonRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	unlock, err := repo.store.lock(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	contacts, lastID, err := repo.decodeJSON()
+	if err != nil {
+		return 0, err
+	}
+
+	contacts, purged := purgeContacts(contacts, before)
+	if purged == 0 {
+		return 0, nil
+	}
+
+	err = repo.encodeJSON(contacts, lastID)
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
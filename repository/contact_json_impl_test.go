@@ -1,10 +1,16 @@
 package repository
 
 import (
+	"contact-go/helper/health"
 	"contact-go/model"
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -45,14 +51,14 @@ func (s *JsonRepoSuite) SetupSuite() {
 	tempFileName, err := mockJsonFile(&contacts, "", "test_contact_*.json")
 	s.NoError(err)
 
-	repo := NewContactJsonRepository(tempFileName)
+	repo := NewContactJsonRepository(tempFileName, 0)
 
 	s.repo = repo
 }
 
 func (s *JsonRepoSuite) TearDownSuite() {
-	model.Contacts = []model.Contact{}
-	os.Remove(s.repo.(*contactJsonRepository).jsonFile)
+	os.Remove(s.repo.(*contactJsonRepository).store.path)
+	os.Remove(s.repo.(*contactJsonRepository).store.lockPath())
 }
 
 func TestJsonRepoSuite(t *testing.T) {
@@ -61,27 +67,52 @@ func TestJsonRepoSuite(t *testing.T) {
 
 func (s *JsonRepoSuite) Test_contactJsonRepository_List() {
 	tests := []struct {
-		name    string
-		want    []model.Contact
-		wantErr bool
+		name      string
+		query     *model.ContactQuery
+		want      []model.Contact
+		wantTotal int64
+		wantErr   bool
 	}{
 		// TODO: Add test cases.
 		{
-			name: "success",
+			name:  "success",
+			query: &model.ContactQuery{},
 			want: []model.Contact{
 				{ID: 1, Name: "Reva", NoTelp: "555-1234-989"},
 				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
-				{ID: 4, Name: "Test1", NoTelp: "131-555-1"},
+				{ID: 4, Name: "Test1", NoTelp: "131-555-1", Version: 1},
 			},
-			wantErr: false,
+			wantTotal: 3,
+			wantErr:   false,
+		},
+		{
+			name:  "filter by no_telp sorted by name desc",
+			query: &model.ContactQuery{NoTelp: "555", SortBy: "name", Order: "desc"},
+			want: []model.Contact{
+				{ID: 4, Name: "Test1", NoTelp: "131-555-1", Version: 1},
+				{ID: 1, Name: "Reva", NoTelp: "555-1234-989"},
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
+			},
+			wantTotal: 3,
+			wantErr:   false,
+		},
+		{
+			name:  "paginated",
+			query: &model.ContactQuery{Limit: 1, Offset: 1},
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
+			},
+			wantTotal: 3,
+			wantErr:   false,
 		},
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got, err := s.repo.List()
+			got, total, err := s.repo.List(context.Background(), tt.query)
 
 			if s.Equal(tt.wantErr, err != nil, "contactJsonRepository.List() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactJsonRepository.List() = %v, want %v", got, tt.want)
+				s.Equal(tt.wantTotal, total, "contactJsonRepository.List() total = %v, want %v", total, tt.wantTotal)
 			}
 		})
 	}
@@ -107,9 +138,10 @@ func (s *JsonRepoSuite) Test_contactJsonRepository_Add() {
 				},
 			},
 			want: &model.Contact{
-				ID:     4,
-				Name:   "Test1",
-				NoTelp: "131-555-1",
+				ID:      4,
+				Name:    "Test1",
+				NoTelp:  "131-555-1",
+				Version: 1,
 			},
 			wantErr: false,
 		},
@@ -124,7 +156,7 @@ func (s *JsonRepoSuite) Test_contactJsonRepository_Add() {
 			// 	return err
 			// }()
 
-			got, err := s.repo.Add(tt.args.newContact)
+			got, err := s.repo.Add(context.Background(), tt.args.newContact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactJsonRepository.Add() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactJsonRepository.Add() = %v, want %v", got, tt.want)
@@ -167,7 +199,7 @@ func (s *JsonRepoSuite) Test_contactJsonRepository_Detail() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got, err := s.repo.Detail(tt.args.id)
+			got, err := s.repo.Detail(context.Background(), tt.args.id)
 
 			if s.Equal(tt.wantErr, err != nil, "contactJsonRepository.Detail() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactJsonRepository.Detail() = %v, want %v", got, tt.want)
@@ -198,9 +230,10 @@ func (s *JsonRepoSuite) Test_contactJsonRepository_Update() {
 				},
 			},
 			want: &model.Contact{
-				ID:     3,
-				Name:   "Test1r",
-				NoTelp: "131-555-1345",
+				ID:      3,
+				Name:    "Test1r",
+				NoTelp:  "131-555-1345",
+				Version: 1,
 			},
 			wantErr: false,
 		},
@@ -219,7 +252,7 @@ func (s *JsonRepoSuite) Test_contactJsonRepository_Update() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			got, err := s.repo.Update(tt.args.id, tt.args.updatedContact)
+			got, err := s.repo.Update(context.Background(), tt.args.id, tt.args.updatedContact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactJsonRepository.Update() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactJsonRepository.Update() = %v, want %v", got, tt.want)
@@ -256,7 +289,7 @@ func (s *JsonRepoSuite) Test_contactJsonRepository_Delete() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			err := s.repo.Delete(tt.args.id)
+			err := s.repo.Delete(context.Background(), tt.args.id, 0, testContactTime)
 
 			s.Equal(tt.wantErr, err != nil, "contactJsonRepository.Delete() error = %v, wantErr %v", err, tt.wantErr)
 		})
@@ -276,7 +309,6 @@ func Test_contactJsonRepository_deencodeJSON(t *testing.T) {
 		pattern string
 		wantErr bool
 	}{
-		// TODO: Add test cases.
 		{
 			name:    "success",
 			pattern: "test_contact_*.json",
@@ -295,16 +327,269 @@ func Test_contactJsonRepository_deencodeJSON(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("mockJsonFile error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if err != nil {
+				jsonFile = filepath.Join(tt.dir, "contact.json")
+			}
 
-			repo := &contactJsonRepository{
-				jsonFile: jsonFile,
+			repo := new(contactJsonRepository)
+			repo.store.path = jsonFile
+			defer os.Remove(jsonFile)
+
+			want, wantLastID := contacts, int64(3)
+			if tt.wantErr {
+				want, wantLastID = nil, 0
+			}
+			got, gotLastID, err := repo.decodeJSON()
+			if err != nil {
+				t.Errorf("contactJsonRepository.decodeJSON() of a bare array error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) || gotLastID != wantLastID {
+				t.Errorf("contactJsonRepository.decodeJSON() of a bare array = %v, %d, want %v, %d", got, gotLastID, want, wantLastID)
 			}
-			if err := repo.encodeJSON(); (err != nil) != tt.wantErr {
+
+			if err := repo.encodeJSON(contacts, 5); (err != nil) != tt.wantErr {
 				t.Errorf("contactJsonRepository.encodeJSON() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if err := repo.decodeJSON(); (err != nil) != tt.wantErr {
-				t.Errorf("contactJsonRepository.decodeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			if tt.wantErr {
+				return
+			}
+			got, gotLastID, err = repo.decodeJSON()
+			if err != nil {
+				t.Errorf("contactJsonRepository.decodeJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, contacts) || gotLastID != 5 {
+				t.Errorf("contactJsonRepository.decodeJSON() = %v, %d, want %v, 5", got, gotLastID, contacts)
+			}
+		})
+	}
+}
+
+func Test_contactJsonRepository_NotCreated(t *testing.T) {
+	jsonFile := filepath.Join(t.TempDir(), "contact.json")
+	checks := []health.Check{health.File("contacts", jsonFile)}
+
+	results, ready := health.Run(context.Background(), checks, time.Second)
+	if !ready || results[0].Status != health.StatusMissing {
+		t.Fatalf("health.Run() = %v, %v, want ready and %s", results, ready, health.StatusMissing)
+	}
+
+	repo := NewContactJsonRepository(jsonFile, 2)
+
+	contacts, total, err := repo.List(context.Background(), new(model.ContactQuery))
+	if err != nil {
+		t.Fatalf("contactJsonRepository.List() error = %v", err)
+	}
+	if len(contacts) != 0 || total != 0 {
+		t.Errorf("contactJsonRepository.List() = %v, %d, want no contacts", contacts, total)
+	}
+
+	added, err := repo.Add(context.Background(), &model.Contact{Name: "Reva", NoTelp: "555-0000"})
+	if err != nil {
+		t.Fatalf("contactJsonRepository.Add() error = %v", err)
+	}
+	if added.ID != 1 {
+		t.Errorf("contactJsonRepository.Add() ID = %d, want 1", added.ID)
+	}
+
+	results, ready = health.Run(context.Background(), checks, time.Second)
+	if !ready || results[0].Status != health.StatusOK {
+		t.Errorf("health.Run() after Add = %v, %v, want ready and %s", results, ready, health.StatusOK)
+	}
+}
+
+func (s *JsonRepoSuite) Test_contactJsonRepository_Search() {
+	tests := []struct {
+		name    string
+		query   string
+		want    []model.Contact
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:  "name prefix",
+			query: "rev",
+			want: []model.Contact{
+				{ID: 1, Name: "Reva", NoTelp: "555-1234-989"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "formatted phone",
+			query: "(555) 1234",
+			want: []model.Contact{
+				{ID: 1, Name: "Reva", NoTelp: "555-1234-989"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "phone digits shared by all",
+			query: "555",
+			want: []model.Contact{
+				{ID: 1, Name: "Reva", NoTelp: "555-1234-989"},
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012"},
+				{ID: 4, Name: "Test1", NoTelp: "131-555-1", Version: 1},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, err := s.repo.Search(context.Background(), tt.query)
+
+			if s.Equal(tt.wantErr, err != nil, "contactJsonRepository.Search() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactJsonRepository.Search() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_contactJsonRepository_ConcurrentAccess(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	hammerContactRepository(t, NewContactJsonRepository(jsonFile, 2), 30)
+}
+
+func Test_contactJsonRepository_CancelledContext(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	repo := NewContactJsonRepository(jsonFile, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repo.Add(ctx, &model.Contact{Name: "Reva", NoTelp: "555-0000"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("contactJsonRepository.Add() error = %v, want %v", err, context.Canceled)
+	}
+
+	contacts, _, err := repo.List(context.Background(), new(model.ContactQuery))
+	if err != nil {
+		t.Fatalf("contactJsonRepository.List() error = %v", err)
+	}
+	if len(contacts) != 0 {
+		t.Errorf("contactJsonRepository.List() = %v, want no contacts", contacts)
+	}
+}
+
+func Test_contactJsonRepository_encodeJSON_backups(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "contact.json")
+
+	repo := NewContactJsonRepository(jsonFile, 2).(*contactJsonRepository)
+	if err := repo.encodeJSON([]model.Contact{}, 0); err != nil {
+		t.Fatalf("contactJsonRepository.encodeJSON() error = %v", err)
+	}
+
+	for _, name := range []string{"Reva", "Tirta", "Bagas"} {
+		if _, err := repo.Add(context.Background(), &model.Contact{Name: name, NoTelp: "555-0000"}); err != nil {
+			t.Fatalf("contactJsonRepository.Add() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantLen  int
+		wantGone bool
+	}{
+		{name: "current", path: jsonFile, wantLen: 3},
+		{name: "newest backup", path: filepath.Join(dir, "contact_backup.json"), wantLen: 2},
+		{name: "older backup", path: filepath.Join(dir, "contact_backup_2.json"), wantLen: 1},
+		{name: "rotated out", path: filepath.Join(dir, "contact_backup_3.json"), wantGone: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.path)
+			if tt.wantGone {
+				if !os.IsNotExist(err) {
+					t.Errorf("%s should not exist, error = %v", tt.path, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFile(%s) error = %v", tt.path, err)
+			}
+
+			var file contactFile
+			if err := json.Unmarshal(data, &file); err != nil {
+				t.Fatalf("json.Unmarshal(%s) error = %v", tt.path, err)
+			}
+			if len(file.Contacts) != tt.wantLen {
+				t.Errorf("%s has %d contacts, want %d", tt.path, len(file.Contacts), tt.wantLen)
+			}
+		})
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(dir, "*.tmp-*"))
+	if err != nil || len(leftovers) > 0 {
+		t.Errorf("temporary files left behind: %v, error = %v", leftovers, err)
+	}
+}
+
+func Test_contactJsonRepository_Details(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkContactDetails(t, NewContactJsonRepository(jsonFile, 0))
+}
+
+func Test_contactJsonRepository_Merge(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkContactMerge(t, NewContactJsonRepository(jsonFile, 0))
+}
+
+func Test_contactJsonRepository_Trash(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkContactTrash(t, NewContactJsonRepository(jsonFile, 0))
+}
+
+func Test_contactJsonRepository_Recreate(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkContactRecreate(t, NewContactJsonRepository(jsonFile, 0))
+}
+
+func Test_contactJsonRepository_Version(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkContactVersion(t, NewContactJsonRepository(jsonFile, 0))
+}
+
+func Test_contactJsonRepository_Patch(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkContactPatch(t, NewContactJsonRepository(jsonFile, 0))
+}
+
+func Test_contactJsonRepository_Batch(t *testing.T) {
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, t.TempDir(), "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkContactBatch(t, NewContactJsonRepository(jsonFile, 0))
+}
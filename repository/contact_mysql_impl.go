@@ -1,18 +1,28 @@
 package repository
 
 import (
-	"contact-go/config/db"
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
 	"contact-go/model"
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 type contactMysqlRepository struct {
 	db *sql.DB
+	// conn runs the statements: db, or the transaction of an atomic
+	// batch or of a Transactor.
+	conn sqlConn
 }
 
 func NewContactMysqlRepository(db *sql.DB) ContactRepository {
 	return &contactMysqlRepository{
-		db: db,
+		db:   db,
+		conn: db,
 	}
 }
 
@@ -22,56 +32,83 @@ func NewContactMysqlRepository(db *sql.DB) ContactRepository {
 // On the other hand, the db.QueryRowContext(...) function is used for
 // executing SQL queries that return a single row of result set.
 
-func (repo *contactMysqlRepository) List() ([]model.Contact, error) {
+func (repo *contactMysqlRepository) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
 	var contacts []model.Contact
-	var contact model.Contact
 	var err error
 
-	ctx, cancel := db.NewContext()
-	defer cancel()
+	conditions := []string{"tenant = ?", trashCondition(query.Deleted)}
+	args := []interface{}{tenant.FromContext(ctx)}
+	if query.Name != "" {
+		conditions = append(conditions, "name LIKE ?")
+		args = append(args, likePattern(query.Name))
+	}
+	if query.NoTelp != "" {
+		conditions = append(conditions, "no_telp LIKE ?")
+		args = append(args, likePattern(query.NoTelp))
+	}
+	if len(query.IDs) > 0 {
+		ids, idArgs := idList(query.IDs)
+		conditions = append(conditions, "id IN "+ids)
+		args = append(args, idArgs...)
+	}
 
-	sqlQuery := "SELECT id, name, no_telp FROM contact ORDER BY id ASC"
-	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	column, direction := sortColumn(query)
+	orderBy := fmt.Sprintf(" ORDER BY %s %s", column, direction)
+	if column != "id" {
+		orderBy += fmt.Sprintf(", id %s", direction)
+	}
+
+	sqlQuery := "SELECT " + contactColumns + " FROM contact" + where + orderBy
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT ? OFFSET ?"
+	}
+
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
 	if err != nil {
-		return contacts, err
+		return contacts, 0, err
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryContext(ctx)
+	queryArgs := args
+	if query.Limit > 0 {
+		queryArgs = append(queryArgs[:len(queryArgs):len(queryArgs)], query.Limit, query.Offset)
+	}
+
+	rows, err := stmt.QueryContext(ctx, queryArgs...)
 	if err != nil {
-		return contacts, err
+		return contacts, 0, err
 	}
 	defer rows.Close()
 
-	for rows.Next() {
-		err = rows.Scan(&contact.ID, &contact.Name, &contact.NoTelp)
-		if err != nil {
-			return contacts, err
-		}
-
-		contacts = append(contacts, contact)
+	contacts, err = scanContacts(rows)
+	if err != nil {
+		return contacts, 0, err
 	}
 
-	err = rows.Err()
-	if err != nil {
-		return contacts, err
+	total := int64(len(contacts))
+	if query.Limit > 0 {
+		countQuery := "SELECT COUNT(*) FROM contact" + where
+		err = repo.conn.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
-	return contacts, nil
+	return contacts, total, nil
 }
 
-func (repo *contactMysqlRepository) Add(contact *model.Contact) (*model.Contact, error) {
-	ctx, cancel := db.NewContext()
-	defer cancel()
-
-	sqlQuery1 := "INSERT INTO contact(name, no_telp) VALUES (?, ?)"
-	stmt, err := repo.db.PrepareContext(ctx, sqlQuery1)
+func (repo *contactMysqlRepository) Add(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	sqlQuery1 := "INSERT INTO contact(name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, updated_at, created_at, tenant) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery1)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	row, err := stmt.ExecContext(ctx, contact.Name, contact.NoTelp)
+	args := append(contactDetailArgs(contact), contact.CreatedAt, tenant.FromContext(ctx))
+	row, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -81,30 +118,29 @@ func (repo *contactMysqlRepository) Add(contact *model.Contact) (*model.Contact,
 		return nil, err
 	}
 
-	newContact := new(model.Contact)
+	newContact := cloneContact(contact)
 	newContact.ID = id
-	newContact.Name = contact.Name
-	newContact.NoTelp = contact.NoTelp
+	newContact.Version = 1
 
-	return newContact, nil
+	return &newContact, nil
 }
 
-func (repo *contactMysqlRepository) Detail(id int64) (*model.Contact, error) {
+func (repo *contactMysqlRepository) Detail(ctx context.Context, id int64) (*model.Contact, error) {
 	contact := new(model.Contact)
 	var err error
 
-	ctx, cancel := db.NewContext()
-	defer cancel()
-
-	sqlQuery := "SELECT id, name, no_telp FROM contact WHERE id = ? LIMIT 1"
-	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	sqlQuery := "SELECT " + contactColumns + " FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1"
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	row := stmt.QueryRowContext(ctx, id)
-	err = row.Scan(&contact.ID, &contact.Name, &contact.NoTelp)
+	row := stmt.QueryRowContext(ctx, tenant.FromContext(ctx), id)
+	err = scanContact(row, contact)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -112,45 +148,240 @@ func (repo *contactMysqlRepository) Detail(id int64) (*model.Contact, error) {
 	return contact, nil
 }
 
-func (repo *contactMysqlRepository) Update(id int64, contact *model.Contact) (*model.Contact, error) {
-	ctx, cancel := db.NewContext()
-	defer cancel()
+func (repo *contactMysqlRepository) Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error) {
+	return repo.Patch(ctx, id, contact, model.ContactFields)
+}
 
-	sqlQuery := "UPDATE contact SET name = ?, no_telp = ? WHERE id = ?"
-	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+func (repo *contactMysqlRepository) Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error) {
+	set, args := contactPatchSet(contact, fields)
+	sqlQuery := "UPDATE contact SET " + set + " WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
+	args = append(args, tenant.FromContext(ctx), id)
+	if contact.Version != 0 {
+		sqlQuery += " AND version = ?"
+		args = append(args, contact.Version)
+	}
+
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, contact.Name, contact.NoTelp, id)
+	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	updatedContact := new(model.Contact)
-	updatedContact.ID = id
-	updatedContact.Name = contact.Name
-	updatedContact.NoTelp = contact.NoTelp
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, missingOrStale(ctx, repo, id)
+	}
 
-	return updatedContact, nil
+	return repo.Detail(ctx, id)
 }
 
-func (repo *contactMysqlRepository) Delete(id int64) error {
-	ctx, cancel := db.NewContext()
-	defer cancel()
+func (repo *contactMysqlRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	sqlQuery := "UPDATE contact SET deleted_at = ? WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
+	args := []interface{}{deletedAt, tenant.FromContext(ctx), id}
+	if version != 0 {
+		sqlQuery += " AND version = ?"
+		args = append(args, version)
+	}
 
-	sqlQuery := "DELETE FROM contact WHERE id = ?"
-	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, id)
+	result, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return missingOrStale(ctx, repo, id)
+	}
 
 	return nil
 }
+
+func (repo *contactMysqlRepository) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	pattern := likePattern(query)
+	conditions := "name LIKE ?"
+	args := []interface{}{tenant.FromContext(ctx), pattern}
+
+	// match phone numbers on their digits, whatever separators were typed
+	if digits := digitsOnly(query); len(digits) >= 3 {
+		conditions += " OR REGEXP_REPLACE(no_telp, '[^0-9]', '') LIKE ?"
+		args = append(args, likePattern(digits))
+	}
+
+	sqlQuery := "SELECT " + contactColumns + " FROM contact WHERE tenant = ? AND deleted_at IS NULL AND (" + conditions + ")" +
+		" ORDER BY LOCATE(?, name) = 0, LOCATE(?, name), id ASC LIMIT ?"
+	args = append(args, query, query, model.MaxSearchResults)
+
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanContacts(rows)
+}
+
+// Merge locks every row it touches before writing, so a contact that is
+// missing or deleted meanwhile fails the merge rather than half of it.
+func (repo *contactMysqlRepository) Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error) {
+	tx, err := beginTx(ctx, repo.db, repo.conn)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, args := idList(append([]int64{id}, mergedIDs...))
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM contact WHERE tenant = ? AND id IN "+ids+" AND deleted_at IS NULL FOR UPDATE", append([]interface{}{tenant.FromContext(ctx)}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	found := 0
+	for rows.Next() {
+		found++
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if found != len(args) {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+
+	sqlQuery := "UPDATE contact SET name = ?, no_telp = ?, no_telp_raw = ?, phones = ?, emails = ?, addresses = ?, company = ?, job_title = ?, birthday = ?, notes = ?, updated_at = ?, version = version + 1 WHERE id = ?"
+	_, err = tx.ExecContext(ctx, sqlQuery, append(contactDetailArgs(contact), id)...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, args = idList(mergedIDs)
+	_, err = tx.ExecContext(ctx, "UPDATE contact SET deleted_at = ? WHERE id IN "+ids, append([]interface{}{contact.UpdatedAt}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *contactMysqlRepository) Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error) {
+	if !atomic {
+		return batchOperations(ctx, repo, ops, false), nil
+	}
+
+	tx, err := beginTx(ctx, repo.db, repo.conn)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	conn := newPreparedTx(repo.db, tx.Tx)
+	defer conn.close()
+
+	results := batchOperations(ctx, &contactMysqlRepository{db: repo.db, conn: conn}, ops, true)
+	if failedOperation(results) {
+		return results, nil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (repo *contactMysqlRepository) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	sqlQuery := "UPDATE contact SET deleted_at = NULL WHERE tenant = ? AND id = ? AND deleted_at IS NOT NULL"
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, tenant.FromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *contactMysqlRepository) Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	var taken int64
+	err := repo.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM contact WHERE id = ?", contact.ID).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactDuplicate)
+	}
+
+	sqlQuery := "INSERT INTO contact(name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, updated_at, created_at, id, version, tenant) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	args := append(contactDetailArgs(contact), contact.CreatedAt, contact.ID, contact.Version, tenant.FromContext(ctx))
+	_, err = stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	newContact := cloneContact(contact)
+	newContact.DeletedAt = nil
+
+	return &newContact, nil
+}
+
+func (repo *contactMysqlRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	sqlQuery := "DELETE FROM contact WHERE tenant = ? AND deleted_at < ?"
+	stmt, err := repo.conn.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, tenant.FromContext(ctx), before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
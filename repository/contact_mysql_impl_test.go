@@ -1,11 +1,15 @@
 package repository
 
 import (
+	"contact-go/helper/tenant"
 	"contact-go/model"
+	"context"
 	"database/sql"
 	"errors"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -38,6 +42,26 @@ func (s *MysqlRepoSuite) TearDownTest() {
 	defer s.mockDB.Close()
 }
 
+// mysqlContactRows returns contacts as rows of the contact table.
+func mysqlContactRows(contacts ...model.Contact) *sqlmock.Rows {
+	rows := sqlmock.NewRows(strings.Split(contactColumns, ", "))
+	for _, contact := range contacts {
+		phones, _ := jsonColumn{contact.Phones}.Value()
+		emails, _ := jsonColumn{contact.Emails}.Value()
+		addresses, _ := jsonColumn{contact.Addresses}.Value()
+
+		var deletedAt interface{}
+		if contact.DeletedAt != nil {
+			deletedAt = *contact.DeletedAt
+		}
+
+		rows.AddRow(contact.ID, contact.Name, contact.NoTelp, contact.NoTelpRaw, phones, emails, addresses,
+			contact.Company, contact.JobTitle, contact.Birthday, contact.Notes,
+			contact.CreatedAt, contact.UpdatedAt, deletedAt, contact.Version)
+	}
+	return rows
+}
+
 func TestMysqlRepoSuite(t *testing.T) {
 	suite.Run(t, new(MysqlRepoSuite))
 }
@@ -45,16 +69,18 @@ func TestMysqlRepoSuite(t *testing.T) {
 func (s *MysqlRepoSuite) Test_contactMysqlRepository_List() {
 	tests := []struct {
 		name       string
+		query      *model.ContactQuery
 		beforeTest func(sqlmock.Sqlmock, string)
 		want       []model.Contact
+		wantTotal  int64
 		wantErr    bool
 	}{
 		// TODO: Add test cases.
 		{
-			name: "success",
+			name:  "success",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
-				rows := s.NewRows([]string{"id", "name", "no_telp"}).
-					AddRow(int64(1), "test", "555-555-3232")
+				rows := mysqlContactRows(model.Contact{ID: 1, Name: "test", NoTelp: "555-555-3232"})
 
 				s.ExpectPrepare(query).
 					ExpectQuery().
@@ -63,10 +89,51 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_List() {
 			want: []model.Contact{
 				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
 			},
-			wantErr: false,
+			wantTotal: 1,
+			wantErr:   false,
 		},
 		{
-			name: "failed",
+			name:  "success filtered page",
+			query: &model.ContactQuery{Name: "te", SortBy: "name", Order: "desc", Limit: 10},
+			beforeTest: func(s sqlmock.Sqlmock, _ string) {
+				rows := mysqlContactRows(model.Contact{ID: 1, Name: "test", NoTelp: "555-555-3232"})
+
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND deleted_at IS NULL AND name LIKE ? ORDER BY name DESC, id DESC LIMIT ? OFFSET ?")).
+					ExpectQuery().
+					WithArgs(tenant.Default, "%te%", 10, 0).
+					WillReturnRows(rows)
+
+				s.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM contact WHERE tenant = ? AND deleted_at IS NULL AND name LIKE ?")).
+					WithArgs(tenant.Default, "%te%").
+					WillReturnRows(s.NewRows([]string{"count"}).AddRow(int64(11)))
+			},
+			want: []model.Contact{
+				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
+			},
+			wantTotal: 11,
+			wantErr:   false,
+		},
+		{
+			name:  "failed count",
+			query: &model.ContactQuery{NoTelp: "555", Limit: 10},
+			beforeTest: func(s sqlmock.Sqlmock, _ string) {
+				rows := mysqlContactRows(model.Contact{ID: 1, Name: "test", NoTelp: "555-555-3232"})
+
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND deleted_at IS NULL AND no_telp LIKE ? ORDER BY id ASC LIMIT ? OFFSET ?")).
+					ExpectQuery().
+					WithArgs(tenant.Default, "%555%", 10, 0).
+					WillReturnRows(rows)
+
+				s.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM contact WHERE tenant = ? AND deleted_at IS NULL AND no_telp LIKE ?")).
+					WithArgs(tenant.Default, "%555%").
+					WillReturnError(assert.AnError)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "failed",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				s.ExpectPrepare(query).
 					ExpectQuery().
@@ -76,7 +143,8 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_List() {
 			wantErr: true,
 		},
 		{
-			name: "failed prepare statement",
+			name:  "failed prepare statement",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				err := errors.New("prepare stmt error")
 
@@ -87,10 +155,11 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_List() {
 			wantErr: true,
 		},
 		{
-			name: "failed rows scan",
+			name:  "failed rows scan",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
-				rows := s.NewRows([]string{"id", "name", "no_telp"}).
-					AddRow(int64(1), nil, "555-555-3232").
+				rows := s.NewRows(strings.Split(contactColumns, ", ")).
+					AddRow(int64(1), nil, "555-555-3232", "", nil, nil, nil, "", "", "", "", testContactTime, testContactTime, nil, int64(1)).
 					RowError(1, errors.New("scanErr"))
 
 				s.ExpectPrepare(query).
@@ -101,9 +170,10 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_List() {
 			wantErr: true,
 		},
 		{
-			name: "failed rows err",
+			name:  "failed rows err",
+			query: &model.ContactQuery{},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
-				rows := s.NewRows([]string{"id", "name", "no_telp"}).
+				rows := s.NewRows(strings.Split(contactColumns, ", ")).
 					CloseError(errors.New("row error"))
 
 				s.ExpectPrepare(query).
@@ -116,16 +186,17 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_List() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := "SELECT id, name, no_telp FROM contact ORDER BY id ASC"
+			sqlQuery := "SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND deleted_at IS NULL ORDER BY id ASC"
 
 			if tt.beforeTest != nil {
-				tt.beforeTest(s.mockSQL, sqlQuery)
+				tt.beforeTest(s.mockSQL, regexp.QuoteMeta(sqlQuery))
 			}
 
-			got, err := s.repo.List()
+			got, total, err := s.repo.List(context.Background(), tt.query)
 
 			if s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.List() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactMysqlRepository.List() = %v, want %v", got, tt.want)
+				s.Equal(tt.wantTotal, total, "contactMysqlRepository.List() total = %v, want %v", total, tt.wantTotal)
 			}
 
 			if err := s.mockSQL.ExpectationsWereMet(); err != nil {
@@ -151,8 +222,12 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Add() {
 			name: "success",
 			args: args{
 				contact: &model.Contact{
-					Name:   "test",
-					NoTelp: "555-555-3232",
+					Name:      "test",
+					NoTelp:    "555-555-3232",
+					Phones:    []model.Phone{{Type: model.TypeWork, Number: "555-555-4000"}},
+					Birthday:  "1990-05-17",
+					CreatedAt: testContactTime,
+					UpdatedAt: testContactTime,
 				},
 			},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
@@ -160,13 +235,19 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Add() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs("test", "555-555-3232").
+					WithArgs("test", "555-555-3232", "", `[{"type":"work","number":"555-555-4000"}]`, "null", "null",
+						"", "", "1990-05-17", "", testContactTime, testContactTime, tenant.Default).
 					WillReturnResult(result)
 			},
 			want: &model.Contact{
-				ID:     1,
-				Name:   "test",
-				NoTelp: "555-555-3232",
+				ID:        1,
+				Name:      "test",
+				NoTelp:    "555-555-3232",
+				Phones:    []model.Phone{{Type: model.TypeWork, Number: "555-555-4000"}},
+				Birthday:  "1990-05-17",
+				CreatedAt: testContactTime,
+				UpdatedAt: testContactTime,
+				Version:   1,
 			},
 			wantErr: false,
 		},
@@ -183,7 +264,7 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Add() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs("", "555-555-3232").
+					WithArgs("", "555-555-3232", "", "null", "null", "null", "", "", "", "", time.Time{}, time.Time{}, tenant.Default).
 					WillReturnError(err)
 			},
 			want:    nil,
@@ -219,7 +300,7 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Add() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs("test", "555-555-3232").
+					WithArgs("test", "555-555-3232", "", "null", "null", "null", "", "", "", "", time.Time{}, time.Time{}, tenant.Default).
 					WillReturnResult(result)
 			},
 			want:    nil,
@@ -228,13 +309,13 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Add() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := "INSERT INTO contact(name, no_telp) VALUES (?, ?)"
+			sqlQuery := "INSERT INTO contact(name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, updated_at, created_at, tenant) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			got, err := s.repo.Add(tt.args.contact)
+			got, err := s.repo.Add(context.Background(), tt.args.contact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Add() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactMysqlRepository.Add() = %v, want %v", got, tt.want)
@@ -265,12 +346,11 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Detail() {
 				id: 1,
 			},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
-				rows := sqlmock.NewRows([]string{"id", "name", "no_telp"}).
-					AddRow(int64(1), "test", "555-555-3232")
+				rows := mysqlContactRows(model.Contact{ID: 1, Name: "test", NoTelp: "555-555-3232"})
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectQuery().
-					WithArgs(int64(1)).
+					WithArgs(tenant.Default, int64(1)).
 					WillReturnRows(rows)
 			},
 			want: &model.Contact{
@@ -288,7 +368,7 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Detail() {
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectQuery().
-					WithArgs(int64(0)).
+					WithArgs(tenant.Default, int64(0)).
 					WillReturnError(sql.ErrNoRows)
 			},
 			want:    nil,
@@ -311,13 +391,13 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Detail() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := "SELECT id, name, no_telp FROM contact WHERE id = ? LIMIT 1"
+			sqlQuery := "SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1"
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			got, err := s.repo.Detail(tt.args.id)
+			got, err := s.repo.Detail(context.Background(), tt.args.id)
 
 			if s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Detail() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactMysqlRepository.Detail() = %v, want %v", got, tt.want)
@@ -348,8 +428,10 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Update() {
 			args: args{
 				id: 1,
 				contact: &model.Contact{
-					Name:   "jangkrik",
-					NoTelp: "555-555-4000",
+					Name:      "jangkrik",
+					NoTelp:    "555-555-4000",
+					Notes:     "met at the conference",
+					UpdatedAt: testContactTime,
 				},
 			},
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
@@ -357,16 +439,56 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Update() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs("jangkrik", "555-555-4000", int64(1)).
+					WithArgs("jangkrik", "555-555-4000", "", "null", "null", "null", "", "", "", "met at the conference", testContactTime, tenant.Default, int64(1)).
 					WillReturnResult(result)
+
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT "+contactColumns+" FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1")).
+					ExpectQuery().
+					WithArgs(tenant.Default, int64(1)).
+					WillReturnRows(mysqlContactRows(model.Contact{
+						ID:        1,
+						Name:      "jangkrik",
+						NoTelp:    "555-555-4000",
+						Notes:     "met at the conference",
+						CreatedAt: testContactTime.Add(-time.Hour),
+						UpdatedAt: testContactTime,
+					}))
 			},
 			want: &model.Contact{
-				ID:     1,
-				Name:   "jangkrik",
-				NoTelp: "555-555-4000",
+				ID:        1,
+				Name:      "jangkrik",
+				NoTelp:    "555-555-4000",
+				Notes:     "met at the conference",
+				CreatedAt: testContactTime.Add(-time.Hour),
+				UpdatedAt: testContactTime,
 			},
 			wantErr: false,
 		},
+		{
+			name: "stale version",
+			args: args{
+				id: 1,
+				contact: &model.Contact{
+					Name:      "jangkrik",
+					NoTelp:    "555-555-4000",
+					UpdatedAt: testContactTime,
+					Version:   1,
+				},
+			},
+			beforeTest: func(s sqlmock.Sqlmock, query string) {
+				s.ExpectPrepare(regexp.QuoteMeta(query+" AND version = ?")).
+					ExpectExec().
+					WithArgs("jangkrik", "555-555-4000", "", "null", "null", "null", "", "", "", "", testContactTime, tenant.Default, int64(1), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT "+contactColumns+" FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1")).
+					ExpectQuery().
+					WithArgs(tenant.Default, int64(1)).
+					WillReturnRows(mysqlContactRows(model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000", Version: 2}))
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "invalid id, name, or no_telp",
 			args: args{
@@ -381,7 +503,7 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Update() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs("test", "555-555-3232", int64(0)).
+					WithArgs("test", "555-555-3232", "", "null", "null", "null", "", "", "", "", time.Time{}, tenant.Default, int64(0)).
 					WillReturnError(err)
 
 			},
@@ -409,13 +531,13 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Update() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := "UPDATE contact SET name = ?, no_telp = ? WHERE id = ?"
+			sqlQuery := "UPDATE contact SET name = ?, no_telp = ?, no_telp_raw = ?, phones = ?, emails = ?, addresses = ?, company = ?, job_title = ?, birthday = ?, notes = ?, updated_at = ?, version = version + 1 WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			got, err := s.repo.Update(tt.args.id, tt.args.contact)
+			got, err := s.repo.Update(context.Background(), tt.args.id, tt.args.contact)
 
 			if s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Update() error = %v, wantErr %v", err, tt.wantErr) {
 				s.Equal(tt.want, got, "contactMysqlRepository.Update() = %v, want %v", got, tt.want)
@@ -428,6 +550,137 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Update() {
 	}
 }
 
+func (s *MysqlRepoSuite) Test_contactMysqlRepository_Patch() {
+	patchQuery := "UPDATE contact SET no_telp = ?, no_telp_raw = ?, notes = ?, updated_at = ?, version = version + 1 WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
+	detailQuery := "SELECT " + contactColumns + " FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1"
+
+	contact := &model.Contact{NoTelp: "+15555554000", NoTelpRaw: "555-555-4000", UpdatedAt: testContactTime}
+	stored := model.Contact{ID: 1, Name: "jangkrik", NoTelp: "+15555554000", NoTelpRaw: "555-555-4000", UpdatedAt: testContactTime, Version: 2}
+
+	s.mockSQL.ExpectPrepare(regexp.QuoteMeta(patchQuery)).
+		ExpectExec().
+		WithArgs("+15555554000", "555-555-4000", "", testContactTime, tenant.Default, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mockSQL.ExpectPrepare(regexp.QuoteMeta(detailQuery)).
+		ExpectQuery().
+		WithArgs(tenant.Default, int64(1)).
+		WillReturnRows(mysqlContactRows(stored))
+
+	got, err := s.repo.Patch(context.Background(), 1, contact, []string{"no_telp", "notes"})
+
+	s.NoError(err)
+	s.Equal(&stored, got)
+	s.NoError(s.mockSQL.ExpectationsWereMet())
+}
+
+func (s *MysqlRepoSuite) Test_contactMysqlRepository_Batch() {
+	insertQuery := "INSERT INTO contact(name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, updated_at, created_at, tenant) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	deleteQuery := "UPDATE contact SET deleted_at = ? WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
+	detailQuery := "SELECT " + contactColumns + " FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1"
+
+	ops := []model.ContactOperation{
+		{Op: model.BatchCreate, Contact: &model.Contact{Name: "jangkrik", NoTelp: "+15555554000", CreatedAt: testContactTime, UpdatedAt: testContactTime}},
+		{Op: model.BatchDelete, ID: 7, DeletedAt: testContactTime},
+	}
+
+	tests := []struct {
+		name       string
+		atomic     bool
+		beforeTest func(sqlmock.Sqlmock)
+		wantFailed bool
+	}{
+		{
+			name:   "atomic",
+			atomic: true,
+			beforeTest: func(s sqlmock.Sqlmock) {
+				//* statements are prepared once on the pool, then again on the transaction
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+					ExpectExec().
+					WithArgs("jangkrik", "+15555554000", "", "null", "null", "null", "", "", "", "", testContactTime, testContactTime, tenant.Default).
+					WillReturnResult(sqlmock.NewResult(8, 1))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+					ExpectExec().
+					WithArgs(testContactTime, tenant.Default, int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+		},
+		{
+			name:   "atomic rolled back",
+			atomic: true,
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+					ExpectExec().
+					WillReturnResult(sqlmock.NewResult(8, 1))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+					ExpectExec().
+					WithArgs(testContactTime, tenant.Default, int64(7)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				s.ExpectPrepare(regexp.QuoteMeta(detailQuery))
+				s.ExpectPrepare(regexp.QuoteMeta(detailQuery)).
+					ExpectQuery().
+					WithArgs(tenant.Default, int64(7)).
+					WillReturnError(sql.ErrNoRows)
+				s.ExpectRollback()
+			},
+			wantFailed: true,
+		},
+		{
+			name: "best effort",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+					ExpectExec().
+					WillReturnResult(sqlmock.NewResult(8, 1))
+				s.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+					ExpectExec().
+					WithArgs(testContactTime, tenant.Default, int64(7)).
+					WillReturnError(assert.AnError)
+			},
+			wantFailed: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			results, err := s.repo.Batch(context.Background(), ops, tt.atomic)
+
+			s.NoError(err)
+			if s.Len(results, 2) {
+				s.Equal(int64(8), results[0].Contact.ID)
+				s.Equal(tt.wantFailed, results[1].Err != nil, "contactMysqlRepository.Batch() error = %v", results[1].Err)
+			}
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+
+	s.Run("atomic prepares a statement once", func() {
+		s.mockSQL.ExpectBegin()
+		s.mockSQL.ExpectPrepare(regexp.QuoteMeta(insertQuery))
+		s.mockSQL.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+			ExpectExec().
+			WillReturnResult(sqlmock.NewResult(8, 1))
+		s.mockSQL.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		s.mockSQL.ExpectCommit()
+
+		results, err := s.repo.Batch(context.Background(), []model.ContactOperation{ops[0], ops[0]}, true)
+
+		s.NoError(err)
+		if s.Len(results, 2) {
+			s.Equal(int64(8), results[0].Contact.ID)
+			s.Equal(int64(9), results[1].Contact.ID)
+		}
+		s.NoError(s.mockSQL.ExpectationsWereMet())
+	})
+}
+
 func (s *MysqlRepoSuite) Test_contactMysqlRepository_Delete() {
 	type args struct {
 		id int64
@@ -449,11 +702,24 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Delete() {
 
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs(int64(1)).
+					WithArgs(testContactTime, tenant.Default, int64(1)).
 					WillReturnResult(result)
 			},
 			wantErr: false,
 		},
+		{
+			name: "missing or already in trash",
+			args: args{
+				id: 2,
+			},
+			beforeTest: func(s sqlmock.Sqlmock, query string) {
+				s.ExpectPrepare(regexp.QuoteMeta(query)).
+					ExpectExec().
+					WithArgs(testContactTime, tenant.Default, int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
 		{
 			name: "failed",
 			args: args{
@@ -462,7 +728,7 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Delete() {
 			beforeTest: func(s sqlmock.Sqlmock, query string) {
 				s.ExpectPrepare(regexp.QuoteMeta(query)).
 					ExpectExec().
-					WithArgs(int64(0)).
+					WithArgs(testContactTime, tenant.Default, int64(0)).
 					WillReturnError(sql.ErrNoRows)
 			},
 			wantErr: true,
@@ -483,15 +749,321 @@ func (s *MysqlRepoSuite) Test_contactMysqlRepository_Delete() {
 	}
 	for _, tt := range tests {
 		s.Run(tt.name, func() {
-			sqlQuery := "DELETE FROM contact WHERE id = ?"
+			sqlQuery := "UPDATE contact SET deleted_at = ? WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
 
 			if tt.beforeTest != nil {
 				tt.beforeTest(s.mockSQL, sqlQuery)
 			}
 
-			err := s.repo.Delete(tt.args.id)
+			err := s.repo.Delete(context.Background(), tt.args.id, 0, testContactTime)
 
 			s.Equal(tt.wantErr, err != nil, "contactUsecase.Delete() error = %v, wantErr %v", err, tt.wantErr)
 		})
 	}
 }
+
+func (s *MysqlRepoSuite) Test_contactMysqlRepository_Search() {
+	tests := []struct {
+		name       string
+		query      string
+		beforeTest func(sqlmock.Sqlmock)
+		want       []model.Contact
+		wantErr    bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:  "success by name",
+			query: "te",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				rows := mysqlContactRows(model.Contact{ID: 1, Name: "test", NoTelp: "555-555-3232"})
+
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND deleted_at IS NULL AND (name LIKE ?) ORDER BY LOCATE(?, name) = 0, LOCATE(?, name), id ASC LIMIT ?")).
+					ExpectQuery().
+					WithArgs(tenant.Default, "%te%", "te", "te", model.MaxSearchResults).
+					WillReturnRows(rows)
+			},
+			want: []model.Contact{
+				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "success by phone digits",
+			query: "555-32",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				rows := mysqlContactRows(model.Contact{ID: 1, Name: "test", NoTelp: "555-555-3232"})
+
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND deleted_at IS NULL AND (name LIKE ? OR REGEXP_REPLACE(no_telp, '[^0-9]', '') LIKE ?) ORDER BY LOCATE(?, name) = 0, LOCATE(?, name), id ASC LIMIT ?")).
+					ExpectQuery().
+					WithArgs(tenant.Default, "%555-32%", "%55532%", "555-32", "555-32", model.MaxSearchResults).
+					WillReturnRows(rows)
+			},
+			want: []model.Contact{
+				{ID: 1, Name: "test", NoTelp: "555-555-3232"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "failed",
+			query: "te",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND deleted_at IS NULL AND (name LIKE ?")).
+					ExpectQuery().
+					WillReturnError(assert.AnError)
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:  "failed prepare statement",
+			query: "te",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version FROM contact WHERE tenant = ? AND deleted_at IS NULL AND (name LIKE ?")).
+					WillReturnError(errors.New("prepare stmt error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			if tt.beforeTest != nil {
+				tt.beforeTest(s.mockSQL)
+			}
+
+			got, err := s.repo.Search(context.Background(), tt.query)
+
+			if s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Search() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactMysqlRepository.Search() = %v, want %v", got, tt.want)
+			}
+
+			if err := s.mockSQL.ExpectationsWereMet(); err != nil {
+				s.Errorf(err, "there were unfulfilled expectations: %s")
+			}
+		})
+	}
+}
+
+func (s *MysqlRepoSuite) Test_contactMysqlRepository_Merge() {
+	lockQuery := "SELECT id FROM contact WHERE tenant = ? AND id IN (?, ?, ?) AND deleted_at IS NULL FOR UPDATE"
+	updateQuery := "UPDATE contact SET name = ?, no_telp = ?, no_telp_raw = ?, phones = ?, emails = ?, addresses = ?, company = ?, job_title = ?, birthday = ?, notes = ?, updated_at = ?, version = version + 1 WHERE id = ?"
+	trashQuery := "UPDATE contact SET deleted_at = ? WHERE id IN (?, ?)"
+
+	contact := &model.Contact{Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime}
+
+	tests := []struct {
+		name       string
+		beforeTest func(sqlmock.Sqlmock)
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(tenant.Default, int64(1), int64(2), int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs("jangkrik", "555-555-4000", "", "null", "null", "null", "", "", "", "", testContactTime, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(trashQuery)).
+					WithArgs(testContactTime, int64(2), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				s.ExpectCommit()
+
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT "+contactColumns+" FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1")).
+					ExpectQuery().
+					WithArgs(tenant.Default, int64(1)).
+					WillReturnRows(mysqlContactRows(model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime}))
+			},
+			want:    &model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime},
+			wantErr: false,
+		},
+		{
+			name: "missing contact",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(tenant.Default, int64(1), int64(2), int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+				s.ExpectRollback()
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "failed trash",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(tenant.Default, int64(1), int64(2), int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(trashQuery)).
+					WillReturnError(assert.AnError)
+				s.ExpectRollback()
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Merge(context.Background(), 1, contact, []int64{2, 3})
+
+			s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Merge() error = %v, wantErr %v", err, tt.wantErr)
+			s.Equal(tt.want, got)
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *MysqlRepoSuite) Test_contactMysqlRepository_Restore() {
+	restoreQuery := "UPDATE contact SET deleted_at = NULL WHERE tenant = ? AND id = ? AND deleted_at IS NOT NULL"
+
+	tests := []struct {
+		name       string
+		id         int64
+		beforeTest func(sqlmock.Sqlmock)
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			id:   1,
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(restoreQuery)).
+					ExpectExec().
+					WithArgs(tenant.Default, int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT "+contactColumns+" FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1")).
+					ExpectQuery().
+					WithArgs(tenant.Default, int64(1)).
+					WillReturnRows(mysqlContactRows(model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime}))
+			},
+			want:    &model.Contact{ID: 1, Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime},
+			wantErr: false,
+		},
+		{
+			name: "not in trash",
+			id:   2,
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(restoreQuery)).
+					ExpectExec().
+					WithArgs(tenant.Default, int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Restore(context.Background(), tt.id)
+
+			s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Restore() error = %v, wantErr %v", err, tt.wantErr)
+			s.Equal(tt.want, got)
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *MysqlRepoSuite) Test_contactMysqlRepository_Recreate() {
+	countQuery := "SELECT COUNT(*) FROM contact WHERE id = ?"
+	insertQuery := "INSERT INTO contact(name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, updated_at, created_at, id, version, tenant) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	contact := &model.Contact{ID: 3, Name: "jangkrik", NoTelp: "555-555-4000", UpdatedAt: testContactTime, CreatedAt: testContactTime, Version: 4}
+
+	tests := []struct {
+		name       string
+		beforeTest func(sqlmock.Sqlmock)
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(countQuery)).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(0)))
+				s.ExpectPrepare(regexp.QuoteMeta(insertQuery)).
+					ExpectExec().
+					WithArgs("jangkrik", "555-555-4000", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "", "", "", "", testContactTime, testContactTime, int64(3), int64(4), tenant.Default).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+			},
+			want:    contact,
+			wantErr: false,
+		},
+		{
+			name: "id taken",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(countQuery)).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(1)))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Recreate(context.Background(), contact)
+
+			s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Recreate() error = %v, wantErr %v", err, tt.wantErr)
+			s.Equal(tt.want, got)
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *MysqlRepoSuite) Test_contactMysqlRepository_Purge() {
+	purgeQuery := "DELETE FROM contact WHERE tenant = ? AND deleted_at < ?"
+
+	tests := []struct {
+		name       string
+		beforeTest func(sqlmock.Sqlmock)
+		want       int64
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(purgeQuery)).
+					ExpectExec().
+					WithArgs(tenant.Default, testContactTime).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			want:    3,
+			wantErr: false,
+		},
+		{
+			name: "failed",
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta(purgeQuery)).
+					ExpectExec().
+					WithArgs(tenant.Default, testContactTime).
+					WillReturnError(assert.AnError)
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.beforeTest(s.mockSQL)
+
+			got, err := s.repo.Purge(context.Background(), testContactTime)
+
+			s.Equal(tt.wantErr, err != nil, "contactMysqlRepository.Purge() error = %v, wantErr %v", err, tt.wantErr)
+			s.Equal(tt.want, got)
+			s.NoError(s.mockSQL.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// likePattern wraps s in wildcards for a LIKE comparison,
+// escaping the characters LIKE would otherwise treat as wildcards.
+func likePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(s) + "%"
+}
+
+// sortColumn returns the column and direction to order by,
+// falling back to id ascending for unknown values.
+func sortColumn(query *model.ContactQuery) (string, string) {
+	column, ok := model.ContactSortFields[query.SortBy]
+	if !ok {
+		column = "id"
+	}
+
+	direction := "ASC"
+	if strings.EqualFold(query.Order, model.SortDesc) {
+		direction = "DESC"
+	}
+	return column, direction
+}
+
+// lastContactID returns the highest ID in contacts, or zero when it is empty.
+func lastContactID(contacts []model.Contact) int64 {
+	var tempID int64
+	for _, v := range contacts {
+		if tempID < v.ID {
+			tempID = v.ID
+		}
+	}
+	return tempID
+}
+
+// contactIDTaken reports whether a contact in contacts, in the trash
+// or not, has the ID id.
+func contactIDTaken(contacts []model.Contact, id int64) bool {
+	for _, v := range contacts {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// contactIndexByID finds the contact id among those not in the trash.
+func contactIndexByID(contacts []model.Contact, id int64) (int, error) {
+	for i, v := range contacts {
+		if id == v.ID && v.DeletedAt == nil {
+			return i, nil
+		}
+	}
+
+	return -1, apperrors.NewAppError(apperrors.ErrContactNotFound)
+}
+
+// trashIndexByID finds the contact id among those in the trash.
+func trashIndexByID(contacts []model.Contact, id int64) (int, error) {
+	for i, v := range contacts {
+		if id == v.ID && v.DeletedAt != nil {
+			return i, nil
+		}
+	}
+
+	return -1, apperrors.NewAppError(apperrors.ErrContactNotFound)
+}
+
+// checkVersion fails when version is set and contact is at another one.
+func checkVersion(contact *model.Contact, version int64) error {
+	if version != 0 && version != contact.Version {
+		return apperrors.NewAppError(apperrors.ErrContactVersion)
+	}
+	return nil
+}
+
+// missingOrStale tells why an update or delete of the contact id
+// guarded by a version touched no row: the contact is not there,
+// or it is at another version.
+func missingOrStale(ctx context.Context, repo ContactRepository, id int64) error {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return err
+	}
+	return apperrors.NewAppError(apperrors.ErrContactVersion)
+}
+
+// purgeContacts drops the contacts moved to the trash before before,
+// returning the rest and how many it dropped.
+func purgeContacts(contacts []model.Contact, before time.Time) ([]model.Contact, int64) {
+	kept := make([]model.Contact, 0, len(contacts))
+	for _, v := range contacts {
+		if v.DeletedAt == nil || !v.DeletedAt.Before(before) {
+			kept = append(kept, v)
+		}
+	}
+	return kept, int64(len(contacts) - len(kept))
+}
+
+// queryContacts applies the filter, sort and page of query to contacts
+// for the backends that keep every contact in memory.
+// It returns the page and the number of contacts matching the filter.
+func queryContacts(contacts []model.Contact, query *model.ContactQuery) ([]model.Contact, int64) {
+	name := strings.ToLower(query.Name)
+
+	var ids map[int64]bool
+	if len(query.IDs) > 0 {
+		ids = make(map[int64]bool, len(query.IDs))
+		for _, id := range query.IDs {
+			ids[id] = true
+		}
+	}
+
+	filtered := make([]model.Contact, 0, len(contacts))
+	for _, v := range contacts {
+		if (v.DeletedAt != nil) != query.Deleted {
+			continue
+		}
+		if ids != nil && !ids[v.ID] {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(v.Name), name) {
+			continue
+		}
+		if query.NoTelp != "" && !strings.Contains(v.NoTelp, query.NoTelp) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+
+	column, direction := sortColumn(query)
+	less := func(a, b model.Contact) bool {
+		switch column {
+		case "name":
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		case "no_telp":
+			if a.NoTelp != b.NoTelp {
+				return a.NoTelp < b.NoTelp
+			}
+		}
+		return a.ID < b.ID
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if direction == "DESC" {
+			return less(filtered[j], filtered[i])
+		}
+		return less(filtered[i], filtered[j])
+	})
+
+	total := int64(len(filtered))
+	if query.Limit <= 0 {
+		return filtered, total
+	}
+
+	start := query.Offset
+	if start > len(filtered) {
+		start = len(filtered)
+	}
+	end := start + query.Limit
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+	return filtered[start:end], total
+}
+
+// updateContactFields copies the fields named in fields, along with
+// UpdatedAt, from src to dst and bumps the version of dst.
+func updateContactFields(dst *model.Contact, src *model.Contact, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case "name":
+			dst.Name = src.Name
+		case "no_telp":
+			dst.NoTelp, dst.NoTelpRaw = src.NoTelp, src.NoTelpRaw
+		case "phones":
+			dst.Phones = clonePhones(src.Phones)
+		case "emails":
+			dst.Emails = cloneEmails(src.Emails)
+		case "addresses":
+			dst.Addresses = cloneAddresses(src.Addresses)
+		case "company":
+			dst.Company = src.Company
+		case "job_title":
+			dst.JobTitle = src.JobTitle
+		case "birthday":
+			dst.Birthday = src.Birthday
+		case "notes":
+			dst.Notes = src.Notes
+		}
+	}
+	dst.UpdatedAt = src.UpdatedAt
+	dst.Version++
+}
+
+// cloneContact copies contact along with its phones, emails and
+// addresses, for what a repository stores or gives back not to share
+// them with the caller.
+func cloneContact(contact *model.Contact) model.Contact {
+	clone := *contact
+	clone.Phones = clonePhones(contact.Phones)
+	clone.Emails = cloneEmails(contact.Emails)
+	clone.Addresses = cloneAddresses(contact.Addresses)
+	return clone
+}
+
+func clonePhones(phones []model.Phone) []model.Phone {
+	if phones == nil {
+		return nil
+	}
+	return append([]model.Phone{}, phones...)
+}
+
+func cloneEmails(emails []model.Email) []model.Email {
+	if emails == nil {
+		return nil
+	}
+	return append([]model.Email{}, emails...)
+}
+
+func cloneAddresses(addresses []model.Address) []model.Address {
+	if addresses == nil {
+		return nil
+	}
+	return append([]model.Address{}, addresses...)
+}
+
+// batchContacts applies ops to contacts for the backends that keep every
+// contact in memory, giving new contacts the IDs after lastID, and
+// returns the contacts and last ID to store and how each operation
+// fared. contacts itself is left alone, so an atomic batch that fails
+// simply stores nothing.
+func batchContacts(contacts []model.Contact, lastID int64, ops []model.ContactOperation, atomic bool) ([]model.Contact, int64, []model.ContactOperationResult) {
+	batchedID := lastID
+	batched := append([]model.Contact(nil), contacts...)
+	results := make([]model.ContactOperationResult, 0, len(ops))
+
+	for _, op := range ops {
+		var result model.ContactOperationResult
+
+		switch op.Op {
+		case model.BatchCreate:
+			newContact := cloneContact(op.Contact)
+			batchedID++
+			newContact.ID = batchedID
+			newContact.Version = 1
+			batched = append(batched, newContact)
+			result.Contact = &newContact
+		case model.BatchUpdate, model.BatchDelete:
+			index, err := contactIndexByID(batched, op.ID)
+			if err != nil {
+				result.Err = err
+				break
+			}
+
+			if op.Op == model.BatchDelete {
+				if result.Err = checkVersion(&batched[index], op.Version); result.Err == nil {
+					deletedAt := op.DeletedAt
+					batched[index].DeletedAt = &deletedAt
+				}
+				break
+			}
+
+			if result.Err = checkVersion(&batched[index], op.Contact.Version); result.Err == nil {
+				updateContactFields(&batched[index], op.Contact, model.ContactFields)
+				updatedContact := batched[index]
+				result.Contact = &updatedContact
+			}
+		default:
+			result.Err = apperrors.NewAppError(apperrors.ErrBatchNotValid)
+		}
+
+		results = append(results, result)
+		if result.Err != nil && atomic {
+			return contacts, lastID, results
+		}
+	}
+
+	return batched, batchedID, results
+}
+
+// batchOperations applies ops one by one through repo, for the backends
+// that run a batch as their single-contact statements, on a transaction
+// when atomic, where each statement is prepared once for the whole batch.
+// An atomic batch stops at the first failing operation.
+func batchOperations(ctx context.Context, repo ContactRepository, ops []model.ContactOperation, atomic bool) []model.ContactOperationResult {
+	results := make([]model.ContactOperationResult, 0, len(ops))
+
+	for _, op := range ops {
+		var result model.ContactOperationResult
+
+		switch op.Op {
+		case model.BatchCreate:
+			result.Contact, result.Err = repo.Add(ctx, op.Contact)
+		case model.BatchUpdate:
+			result.Contact, result.Err = repo.Update(ctx, op.ID, op.Contact)
+		case model.BatchDelete:
+			result.Err = repo.Delete(ctx, op.ID, op.Version, op.DeletedAt)
+		default:
+			result.Err = apperrors.NewAppError(apperrors.ErrBatchNotValid)
+		}
+
+		results = append(results, result)
+		if result.Err != nil && atomic {
+			break
+		}
+	}
+
+	return results
+}
+
+// failedOperation reports whether any of results failed.
+func failedOperation(results []model.ContactOperationResult) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// contactFieldColumns are the columns a patch of fields writes,
+// updated_at included.
+func contactFieldColumns(fields []string) []string {
+	columns := make([]string, 0, len(fields)+2)
+	for _, field := range fields {
+		columns = append(columns, field)
+		if field == "no_telp" {
+			columns = append(columns, "no_telp_raw")
+		}
+	}
+	return append(columns, "updated_at")
+}
+
+// mergeContacts updates the contact id with contact and moves mergedIDs
+// to the trash for the backends that keep every contact in memory.
+// contacts is left as it is when any of them is missing.
+func mergeContacts(contacts []model.Contact, id int64, contact *model.Contact, mergedIDs []int64) ([]model.Contact, *model.Contact, error) {
+	index, err := contactIndexByID(contacts, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	drop := make(map[int64]bool, len(mergedIDs))
+	for _, mergedID := range mergedIDs {
+		if _, err := contactIndexByID(contacts, mergedID); err != nil {
+			return nil, nil, err
+		}
+		drop[mergedID] = true
+	}
+
+	merged := contacts[index]
+	updateContactFields(&merged, contact, model.ContactFields)
+
+	deletedAt := contact.UpdatedAt
+	result := make([]model.Contact, 0, len(contacts))
+	for _, v := range contacts {
+		switch {
+		case v.ID == id:
+			v = merged
+		case drop[v.ID]:
+			v.DeletedAt = &deletedAt
+		}
+		result = append(result, v)
+	}
+	return result, &merged, nil
+}
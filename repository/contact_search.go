@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"contact-go/helper/fuzzy"
+	"contact-go/model"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// digitsOnly strips everything but the digits from s,
+// so "(555) 555-1234" and "555 5551234" compare equal.
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// scoreContact ranks how well contact matches the search query, lower is better.
+// It returns false when the contact does not match at all.
+func scoreContact(contact model.Contact, query string, queryDigits string) (int, bool) {
+	name := strings.ToLower(contact.Name)
+
+	if strings.Contains(name, query) {
+		if strings.HasPrefix(name, query) {
+			return 0, true
+		}
+		return 1, true
+	}
+
+	if len(queryDigits) >= 3 && strings.Contains(digitsOnly(contact.NoTelp), queryDigits) {
+		return 2, true
+	}
+
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '_' || r == '-' || r == '.'
+	})
+	tokens = append(tokens, name)
+
+	best := -1
+	for _, token := range tokens {
+		distance := fuzzy.Distance(query, token)
+		if best == -1 || distance < best {
+			best = distance
+		}
+	}
+
+	if best > fuzzy.MaxTypos(len([]rune(query))) {
+		return 0, false
+	}
+	return 2 + best, true
+}
+
+// searchContacts runs the fuzzy matcher over the contacts not in the trash
+// for the backends that keep every contact in memory, best matches first.
+func searchContacts(contacts []model.Contact, query string) []model.Contact {
+	query = strings.ToLower(strings.TrimSpace(query))
+	queryDigits := digitsOnly(query)
+
+	type match struct {
+		contact model.Contact
+		score   int
+	}
+
+	var matches []match
+	for _, v := range contacts {
+		if v.DeletedAt != nil {
+			continue
+		}
+		if score, ok := scoreContact(v, query, queryDigits); ok {
+			matches = append(matches, match{contact: v, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].contact.ID < matches[j].contact.ID
+	})
+
+	if len(matches) > model.MaxSearchResults {
+		matches = matches[:model.MaxSearchResults]
+	}
+
+	result := make([]model.Contact, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.contact)
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// contactColumns are the columns of the contact table
+// in the order scanContact reads them.
+const contactColumns = "id, name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, created_at, updated_at, deleted_at, version"
+
+// trashCondition selects the contacts in the trash when deleted is set,
+// and those not in it otherwise.
+func trashCondition(deleted bool) string {
+	if deleted {
+		return "deleted_at IS NOT NULL"
+	}
+	return "deleted_at IS NULL"
+}
+
+// sqlConn is what the statements of a repository run on,
+// either a *sql.DB or a *sql.Tx.
+type sqlConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// jsonColumn stores a slice field of model.Contact as a JSON document,
+// reading NULL and empty documents back as a nil slice.
+type jsonColumn struct {
+	v interface{}
+}
+
+func (c jsonColumn) Value() (driver.Value, error) {
+	data, err := json.Marshal(c.v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c jsonColumn) Scan(src interface{}) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unsupported json column type %T", src)
+	}
+
+	switch string(data) {
+	case "", "null", "[]":
+		return nil
+	}
+	return json.Unmarshal(data, c.v)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanContact reads a row of contactColumns, with the timestamps in UTC.
+func scanContact(row rowScanner, contact *model.Contact) error {
+	var deletedAt sql.NullTime
+	err := row.Scan(
+		&contact.ID, &contact.Name, &contact.NoTelp, &contact.NoTelpRaw,
+		jsonColumn{&contact.Phones}, jsonColumn{&contact.Emails}, jsonColumn{&contact.Addresses},
+		&contact.Company, &contact.JobTitle, &contact.Birthday, &contact.Notes,
+		&contact.CreatedAt, &contact.UpdatedAt, &deletedAt, &contact.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	contact.CreatedAt = contact.CreatedAt.UTC()
+	contact.UpdatedAt = contact.UpdatedAt.UTC()
+	contact.DeletedAt = nil
+	if deletedAt.Valid {
+		t := deletedAt.Time.UTC()
+		contact.DeletedAt = &t
+	}
+	return nil
+}
+
+func scanContacts(rows *sql.Rows) ([]model.Contact, error) {
+	var contacts []model.Contact
+
+	for rows.Next() {
+		var contact model.Contact
+		if err := scanContact(rows, &contact); err != nil {
+			return nil, err
+		}
+
+		contacts = append(contacts, contact)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// contactDetailArgs are the values of the columns Add and Merge write,
+// in the order name, no_telp, no_telp_raw, phones, emails, addresses,
+// company, job_title, birthday, notes, updated_at.
+func contactDetailArgs(contact *model.Contact) []interface{} {
+	return []interface{}{
+		contact.Name, contact.NoTelp, contact.NoTelpRaw,
+		jsonColumn{contact.Phones}, jsonColumn{contact.Emails}, jsonColumn{contact.Addresses},
+		contact.Company, contact.JobTitle, contact.Birthday, contact.Notes,
+		contact.UpdatedAt,
+	}
+}
+
+// contactColumnValue is the value contact stores in column.
+func contactColumnValue(contact *model.Contact, column string) interface{} {
+	switch column {
+	case "name":
+		return contact.Name
+	case "no_telp":
+		return contact.NoTelp
+	case "no_telp_raw":
+		return contact.NoTelpRaw
+	case "phones":
+		return jsonColumn{contact.Phones}
+	case "emails":
+		return jsonColumn{contact.Emails}
+	case "addresses":
+		return jsonColumn{contact.Addresses}
+	case "company":
+		return contact.Company
+	case "job_title":
+		return contact.JobTitle
+	case "birthday":
+		return contact.Birthday
+	case "notes":
+		return contact.Notes
+	case "updated_at":
+		return contact.UpdatedAt
+	}
+	return nil
+}
+
+// contactPatchSet returns the SET clause of a patch of fields, as in
+// "name = ?, updated_at = ?, version = version + 1", along with its arguments.
+func contactPatchSet(contact *model.Contact, fields []string) (string, []interface{}) {
+	columns := contactFieldColumns(fields)
+	assignments := make([]string, 0, len(columns)+1)
+	args := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		assignments = append(assignments, column+" = ?")
+		args = append(args, contactColumnValue(contact, column))
+	}
+	assignments = append(assignments, "version = version + 1")
+	return strings.Join(assignments, ", "), args
+}
+
+// idList returns the placeholders of an IN clause for ids, as in
+// "(?, ?, ?)", along with ids as its arguments.
+func idList(ids []int64) (string, []interface{}) {
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + ")", args
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type contactSqliteRepository struct {
+	db *sql.DB
+	// conn runs the statements: db, or the transaction of an atomic
+	// batch or of a Transactor.
+	conn sqlConn
+}
+
+func NewContactSqliteRepository(db *sql.DB) ContactRepository {
+	return &contactSqliteRepository{
+		db:   db,
+		conn: db,
+	}
+}
+
+// SQLite has no default escape character for LIKE,
+// so every pattern built by likePattern needs an explicit ESCAPE clause.
+const sqliteLikeEscape = ` ESCAPE '\'`
+
+// sqliteDigits strips the usual phone number separators from a column.
+const sqliteDigits = "REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(no_telp, '-', ''), ' ', ''), '(', ''), ')', ''), '+', '')"
+
+func (repo *contactSqliteRepository) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	conditions := []string{"tenant = ?", trashCondition(query.Deleted)}
+	args := []interface{}{tenant.FromContext(ctx)}
+	if query.Name != "" {
+		conditions = append(conditions, "name LIKE ?"+sqliteLikeEscape)
+		args = append(args, likePattern(query.Name))
+	}
+	if query.NoTelp != "" {
+		conditions = append(conditions, "no_telp LIKE ?"+sqliteLikeEscape)
+		args = append(args, likePattern(query.NoTelp))
+	}
+	if len(query.IDs) > 0 {
+		ids, idArgs := idList(query.IDs)
+		conditions = append(conditions, "id IN "+ids)
+		args = append(args, idArgs...)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	column, direction := sortColumn(query)
+	orderBy := fmt.Sprintf(" ORDER BY %s %s", column, direction)
+	if column != "id" {
+		orderBy += fmt.Sprintf(", id %s", direction)
+	}
+
+	sqlQuery := "SELECT " + contactColumns + " FROM contact" + where + orderBy
+	queryArgs := args
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT ? OFFSET ?"
+		queryArgs = append(queryArgs[:len(queryArgs):len(queryArgs)], query.Limit, query.Offset)
+	}
+
+	rows, err := repo.conn.QueryContext(ctx, sqlQuery, queryArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	contacts, err := scanContacts(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(contacts))
+	if query.Limit > 0 {
+		countQuery := "SELECT COUNT(*) FROM contact" + where
+		err = repo.conn.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return contacts, total, nil
+}
+
+func (repo *contactSqliteRepository) Add(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	sqlQuery := "INSERT INTO contact(name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, updated_at, created_at, tenant) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	args := append(contactDetailArgs(contact), contact.CreatedAt, tenant.FromContext(ctx))
+	row, err := repo.conn.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := row.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	newContact := cloneContact(contact)
+	newContact.ID = id
+	newContact.Version = 1
+
+	return &newContact, nil
+}
+
+func (repo *contactSqliteRepository) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	contact := new(model.Contact)
+
+	sqlQuery := "SELECT " + contactColumns + " FROM contact WHERE tenant = ? AND id = ? AND deleted_at IS NULL LIMIT 1"
+	row := repo.conn.QueryRowContext(ctx, sqlQuery, tenant.FromContext(ctx), id)
+	err := scanContact(row, contact)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+func (repo *contactSqliteRepository) Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error) {
+	return repo.Patch(ctx, id, contact, model.ContactFields)
+}
+
+func (repo *contactSqliteRepository) Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error) {
+	set, args := contactPatchSet(contact, fields)
+	sqlQuery := "UPDATE contact SET " + set + " WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
+	args = append(args, tenant.FromContext(ctx), id)
+	if contact.Version != 0 {
+		sqlQuery += " AND version = ?"
+		args = append(args, contact.Version)
+	}
+
+	result, err := repo.conn.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, missingOrStale(ctx, repo, id)
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *contactSqliteRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	sqlQuery := "UPDATE contact SET deleted_at = ? WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
+	args := []interface{}{deletedAt, tenant.FromContext(ctx), id}
+	if version != 0 {
+		sqlQuery += " AND version = ?"
+		args = append(args, version)
+	}
+
+	result, err := repo.conn.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return missingOrStale(ctx, repo, id)
+	}
+
+	return nil
+}
+
+func (repo *contactSqliteRepository) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	conditions := "name LIKE ?" + sqliteLikeEscape
+	args := []interface{}{tenant.FromContext(ctx), likePattern(query)}
+
+	// match phone numbers on their digits, whatever separators were typed
+	if digits := digitsOnly(query); len(digits) >= 3 {
+		conditions += " OR " + sqliteDigits + " LIKE ?"
+		args = append(args, likePattern(digits))
+	}
+
+	sqlQuery := "SELECT " + contactColumns + " FROM contact WHERE tenant = ? AND deleted_at IS NULL AND (" + conditions + ")" +
+		" ORDER BY INSTR(LOWER(name), LOWER(?)) = 0, INSTR(LOWER(name), LOWER(?)), id ASC LIMIT ?"
+	args = append(args, query, query, model.MaxSearchResults)
+
+	rows, err := repo.conn.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanContacts(rows)
+}
+
+func (repo *contactSqliteRepository) Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error) {
+	tx, err := beginTx(ctx, repo.db, repo.conn)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sqlQuery := "UPDATE contact SET name = ?, no_telp = ?, no_telp_raw = ?, phones = ?, emails = ?, addresses = ?, company = ?, job_title = ?, birthday = ?, notes = ?, updated_at = ?, version = version + 1 WHERE tenant = ? AND id = ? AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, sqlQuery, append(contactDetailArgs(contact), tenant.FromContext(ctx), id)...)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+
+	ids, args := idList(mergedIDs)
+	result, err = tx.ExecContext(ctx, "UPDATE contact SET deleted_at = ? WHERE tenant = ? AND id IN "+ids+" AND deleted_at IS NULL", append([]interface{}{contact.UpdatedAt, tenant.FromContext(ctx)}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err = result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected != int64(len(mergedIDs)) {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *contactSqliteRepository) Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error) {
+	if !atomic {
+		return batchOperations(ctx, repo, ops, false), nil
+	}
+
+	tx, err := beginTx(ctx, repo.db, repo.conn)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	conn := newPreparedTx(repo.db, tx.Tx)
+	defer conn.close()
+
+	results := batchOperations(ctx, &contactSqliteRepository{db: repo.db, conn: conn}, ops, true)
+	if failedOperation(results) {
+		return results, nil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (repo *contactSqliteRepository) Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	var taken int64
+	err := repo.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM contact WHERE id = ?", contact.ID).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactDuplicate)
+	}
+
+	sqlQuery := "INSERT INTO contact(name, no_telp, no_telp_raw, phones, emails, addresses, company, job_title, birthday, notes, updated_at, created_at, id, version, tenant) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	args := append(contactDetailArgs(contact), contact.CreatedAt, contact.ID, contact.Version, tenant.FromContext(ctx))
+	_, err = repo.conn.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	newContact := cloneContact(contact)
+	newContact.DeletedAt = nil
+
+	return &newContact, nil
+}
+
+func (repo *contactSqliteRepository) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	sqlQuery := "UPDATE contact SET deleted_at = NULL WHERE tenant = ? AND id = ? AND deleted_at IS NOT NULL"
+	result, err := repo.conn.ExecContext(ctx, sqlQuery, tenant.FromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *contactSqliteRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	sqlQuery := "DELETE FROM contact WHERE tenant = ? AND deleted_at < ?"
+	result, err := repo.conn.ExecContext(ctx, sqlQuery, tenant.FromContext(ctx), before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"contact-go/config"
+	"contact-go/config/db"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// SqliteRepoSuite runs against a real database file, a new one for
+// every test, checking the same behaviour the in-memory repository is
+// held to.
+type SqliteRepoSuite struct {
+	suite.Suite
+	db   *sql.DB
+	repo ContactRepository
+}
+
+func newSqliteTestDatabase(t *testing.T) *sql.DB {
+	cfg := new(config.Config)
+	cfg.Database.Path = filepath.Join(t.TempDir(), "contact.db")
+
+	sqliteDB, err := db.NewSqliteDatabase(cfg)
+	if err != nil {
+		t.Fatalf("db.NewSqliteDatabase() error = %v", err)
+	}
+	t.Cleanup(func() { sqliteDB.Close() })
+
+	return sqliteDB
+}
+
+// SetupTest stores Reva, Tirta, Bagas and Mixue, as contacts 1 to 4,
+// in a database of the test's own.
+func (s *SqliteRepoSuite) SetupTest() {
+	s.db = newSqliteTestDatabase(s.T())
+	s.repo = NewContactSqliteRepository(s.db)
+
+	for _, contact := range []model.Contact{
+		{Name: "Reva", NoTelp: "555-1234-989"},
+		{Name: "Tirta", NoTelp: "555-5678"},
+		{Name: "Bagas", NoTelp: "555-9012"},
+		{Name: "Mixue", NoTelp: "555-9999"},
+	} {
+		_, err := s.repo.Add(context.Background(), &contact)
+		s.Require().NoError(err)
+	}
+}
+
+func TestSqliteRepoSuite(t *testing.T) {
+	suite.Run(t, new(SqliteRepoSuite))
+}
+
+func (s *SqliteRepoSuite) Test_contactSqliteRepository_List() {
+	// Reva is in the trash, out of every list
+	s.Require().NoError(s.repo.Delete(context.Background(), 1, 0, testContactTime))
+
+	tests := []struct {
+		name      string
+		query     *model.ContactQuery
+		want      []model.Contact
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name:  "success",
+			query: &model.ContactQuery{},
+			want: []model.Contact{
+				{ID: 2, Name: "Tirta", NoTelp: "555-5678", Version: 1},
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012", Version: 1},
+				{ID: 4, Name: "Mixue", NoTelp: "555-9999", Version: 1},
+			},
+			wantTotal: 3,
+			wantErr:   false,
+		},
+		{
+			name:  "filter by name sorted by name",
+			query: &model.ContactQuery{Name: "A", SortBy: "name", Limit: 1},
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012", Version: 1},
+			},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name:  "filter by ids",
+			query: &model.ContactQuery{IDs: []int64{2, 4, 9}},
+			want: []model.Contact{
+				{ID: 2, Name: "Tirta", NoTelp: "555-5678", Version: 1},
+				{ID: 4, Name: "Mixue", NoTelp: "555-9999", Version: 1},
+			},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name:  "filter by no_telp",
+			query: &model.ContactQuery{NoTelp: "9999"},
+			want: []model.Contact{
+				{ID: 4, Name: "Mixue", NoTelp: "555-9999", Version: 1},
+			},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name:  "wildcards are literal",
+			query: &model.ContactQuery{Name: "%"},
+			want:  nil,
+		},
+		{
+			name:  "descending with offset",
+			query: &model.ContactQuery{Order: "desc", Limit: 2, Offset: 1},
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012", Version: 1},
+				{ID: 2, Name: "Tirta", NoTelp: "555-5678", Version: 1},
+			},
+			wantTotal: 3,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, total, err := s.repo.List(context.Background(), tt.query)
+
+			if s.Equal(tt.wantErr, err != nil, "contactSqliteRepository.List() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactSqliteRepository.List() = %v, want %v", got, tt.want)
+				s.Equal(tt.wantTotal, total, "contactSqliteRepository.List() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func (s *SqliteRepoSuite) Test_contactSqliteRepository_Add() {
+	tests := []struct {
+		name       string
+		newContact *model.Contact
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			newContact: &model.Contact{
+				Name:   "Sari",
+				NoTelp: "555-7777",
+			},
+			want: &model.Contact{
+				ID:      5,
+				Name:    "Sari",
+				NoTelp:  "555-7777",
+				Version: 1,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, err := s.repo.Add(context.Background(), tt.newContact)
+
+			if s.Equal(tt.wantErr, err != nil, "contactSqliteRepository.Add() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactSqliteRepository.Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func (s *SqliteRepoSuite) Test_contactSqliteRepository_Detail() {
+	tests := []struct {
+		name    string
+		id      int64
+		want    *model.Contact
+		wantErr bool
+	}{
+		{
+			name: "success",
+			id:   2,
+			want: &model.Contact{
+				ID:      2,
+				Name:    "Tirta",
+				NoTelp:  "555-5678",
+				Version: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "failed",
+			id:      5,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, err := s.repo.Detail(context.Background(), tt.id)
+
+			if s.Equal(tt.wantErr, err != nil, "contactSqliteRepository.Detail() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactSqliteRepository.Detail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func (s *SqliteRepoSuite) Test_contactSqliteRepository_Update() {
+	tests := []struct {
+		name           string
+		id             int64
+		updatedContact *model.Contact
+		want           *model.Contact
+		wantErr        bool
+	}{
+		{
+			name: "success",
+			id:   3,
+			updatedContact: &model.Contact{
+				Name:   "Bagas Putra",
+				NoTelp: "555-9012",
+			},
+			want: &model.Contact{
+				ID:      3,
+				Name:    "Bagas Putra",
+				NoTelp:  "555-9012",
+				Version: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed",
+			id:   5,
+			updatedContact: &model.Contact{
+				Name:   "Test1ra",
+				NoTelp: "131-555-1123",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, err := s.repo.Update(context.Background(), tt.id, tt.updatedContact)
+
+			if s.Equal(tt.wantErr, err != nil, "contactSqliteRepository.Update() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactSqliteRepository.Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func (s *SqliteRepoSuite) Test_contactSqliteRepository_Delete() {
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			id:      1,
+			wantErr: false,
+		},
+		{
+			name:    "failed",
+			id:      10,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := s.repo.Delete(context.Background(), tt.id, 0, testContactTime)
+
+			s.Equal(tt.wantErr, err != nil, "contactSqliteRepository.Delete() error = %v, wantErr %v", err, tt.wantErr)
+		})
+	}
+}
+
+func (s *SqliteRepoSuite) Test_contactSqliteRepository_Search() {
+	tests := []struct {
+		name    string
+		query   string
+		want    []model.Contact
+		wantErr bool
+	}{
+		{
+			name:  "name substring",
+			query: "irt",
+			want: []model.Contact{
+				{ID: 2, Name: "Tirta", NoTelp: "555-5678", Version: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "partial phone digits",
+			query: "90-12",
+			want: []model.Contact{
+				{ID: 3, Name: "Bagas", NoTelp: "555-9012", Version: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "no match",
+			query:   "zz",
+			want:    nil,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, err := s.repo.Search(context.Background(), tt.query)
+
+			if s.Equal(tt.wantErr, err != nil, "contactSqliteRepository.Search() error = %v, wantErr %v", err, tt.wantErr) {
+				s.Equal(tt.want, got, "contactSqliteRepository.Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_contactSqliteRepository_ConcurrentAccess(t *testing.T) {
+	hammerContactRepository(t, NewContactSqliteRepository(newSqliteTestDatabase(t)), 30)
+}
+
+func Test_contactSqliteRepository_Details(t *testing.T) {
+	checkContactDetails(t, NewContactSqliteRepository(newSqliteTestDatabase(t)))
+}
+
+func Test_contactSqliteRepository_Merge(t *testing.T) {
+	checkContactMerge(t, NewContactSqliteRepository(newSqliteTestDatabase(t)))
+}
+
+func Test_contactSqliteRepository_Trash(t *testing.T) {
+	checkContactTrash(t, NewContactSqliteRepository(newSqliteTestDatabase(t)))
+}
+
+func Test_contactSqliteRepository_Recreate(t *testing.T) {
+	checkContactRecreate(t, NewContactSqliteRepository(newSqliteTestDatabase(t)))
+}
+
+func Test_contactSqliteRepository_Version(t *testing.T) {
+	checkContactVersion(t, NewContactSqliteRepository(newSqliteTestDatabase(t)))
+}
+
+func Test_contactSqliteRepository_Patch(t *testing.T) {
+	checkContactPatch(t, NewContactSqliteRepository(newSqliteTestDatabase(t)))
+}
+
+func Test_contactSqliteRepository_Batch(t *testing.T) {
+	checkContactBatch(t, NewContactSqliteRepository(newSqliteTestDatabase(t)))
+}
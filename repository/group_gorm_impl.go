@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type groupGormRepository struct {
+	db *gorm.DB
+}
+
+// gormGroup is a group as the group table stores it,
+// along with the address book it is in.
+type gormGroup struct {
+	model.Group
+	Tenant string
+}
+
+func (gormGroup) TableName() string {
+	return model.Group{}.TableName()
+}
+
+// gormGroupMember is a row of the table linking groups to contacts.
+type gormGroupMember struct {
+	GroupID   int64 `gorm:"primaryKey;autoIncrement:false"`
+	ContactID int64 `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (gormGroupMember) TableName() string {
+	return "contact_group_members"
+}
+
+func NewGroupGormRepository(db *gorm.DB) GroupRepository {
+	r := new(groupGormRepository)
+	r.db = db
+
+	return r
+}
+
+// groupNotFound turns the error of a lookup of a single group
+// into apperrors.ErrGroupNotFound when there was no such group.
+func groupNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+	return err
+}
+
+func (repo *groupGormRepository) List(ctx context.Context) ([]model.Group, error) {
+	var groups []model.Group
+
+	result := repo.db.WithContext(ctx).Scopes(inTenant(ctx)).Order("name ASC").Order("id ASC").Find(&groups)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (repo *groupGormRepository) Add(ctx context.Context, group *model.Group) (*model.Group, error) {
+	row := &gormGroup{Group: *group, Tenant: tenant.FromContext(ctx)}
+
+	result := repo.db.WithContext(ctx).Omit("ID").Create(row)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return &row.Group, nil
+}
+
+func (repo *groupGormRepository) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	group := new(model.Group)
+
+	result := repo.db.WithContext(ctx).Scopes(inTenant(ctx)).First(group, id)
+	if err := result.Error; err != nil {
+		return nil, groupNotFound(err)
+	}
+
+	return group, nil
+}
+
+func (repo *groupGormRepository) FindByName(ctx context.Context, name string) (*model.Group, error) {
+	group := new(model.Group)
+
+	result := repo.db.WithContext(ctx).Scopes(inTenant(ctx)).Where("LOWER(name) = LOWER(?)", name).First(group)
+	if err := result.Error; err != nil {
+		return nil, groupNotFound(err)
+	}
+
+	return group, nil
+}
+
+func (repo *groupGormRepository) Update(ctx context.Context, id int64, group *model.Group) (*model.Group, error) {
+	result := repo.db.WithContext(ctx).Model(&model.Group{}).Scopes(inTenant(ctx)).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"name":        group.Name,
+			"description": group.Description,
+			"updated_at":  group.UpdatedAt,
+		})
+
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *groupGormRepository) Delete(ctx context.Context, id int64) error {
+	result := repo.db.WithContext(ctx).Scopes(inTenant(ctx)).Delete(&model.Group{}, id)
+
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+func (repo *groupGormRepository) Members(ctx context.Context, id int64) ([]int64, error) {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	result := repo.db.WithContext(ctx).Model(&gormGroupMember{}).
+		Where("group_id = ?", id).Order("contact_id ASC").Pluck("contact_id", &ids)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (repo *groupGormRepository) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return err
+	}
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	members := make([]gormGroupMember, 0, len(contactIDs))
+	for _, contactID := range contactIDs {
+		members = append(members, gormGroupMember{GroupID: id, ContactID: contactID})
+	}
+
+	return repo.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error
+}
+
+func (repo *groupGormRepository) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return err
+	}
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	return repo.db.WithContext(ctx).
+		Where("group_id = ? AND contact_id IN ?", id, contactIDs).
+		Delete(&gormGroupMember{}).Error
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+	"sync"
+)
+
+type groupRepository struct {
+	mu     sync.RWMutex
+	groups []storedGroup
+}
+
+func NewGroupRepository() GroupRepository {
+	return new(groupRepository)
+}
+
+func (repo *groupRepository) List(ctx context.Context) ([]model.Group, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return sortedGroups(repo.groups), nil
+}
+
+func (repo *groupRepository) Add(ctx context.Context, group *model.Group) (*model.Group, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	newGroup := *group
+	newGroup.ID = lastGroupID(repo.groups) + 1
+
+	repo.groups = append(repo.groups, storedGroup{Group: newGroup})
+
+	return &newGroup, nil
+}
+
+func (repo *groupRepository) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	index, err := groupIndexByID(repo.groups, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group := repo.groups[index].Group
+	return &group, nil
+}
+
+func (repo *groupRepository) FindByName(ctx context.Context, name string) (*model.Group, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	index, err := groupIndexByName(repo.groups, name)
+	if err != nil {
+		return nil, err
+	}
+
+	group := repo.groups[index].Group
+	return &group, nil
+}
+
+func (repo *groupRepository) Update(ctx context.Context, id int64, group *model.Group) (*model.Group, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	index, err := groupIndexByID(repo.groups, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedGroup := &repo.groups[index].Group
+	updatedGroup.Name = group.Name
+	updatedGroup.Description = group.Description
+	updatedGroup.UpdatedAt = group.UpdatedAt
+
+	result := *updatedGroup
+	return &result, nil
+}
+
+func (repo *groupRepository) Delete(ctx context.Context, id int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	index, err := groupIndexByID(repo.groups, id)
+	if err != nil {
+		return err
+	}
+
+	repo.groups = append(repo.groups[:index], repo.groups[index+1:]...)
+
+	return nil
+}
+
+func (repo *groupRepository) Members(ctx context.Context, id int64) ([]int64, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	index, err := groupIndexByID(repo.groups, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]int64(nil), repo.groups[index].ContactIDs...), nil
+}
+
+func (repo *groupRepository) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	index, err := groupIndexByID(repo.groups, id)
+	if err != nil {
+		return err
+	}
+
+	repo.groups[index].ContactIDs = addGroupMembers(repo.groups[index].ContactIDs, contactIDs)
+
+	return nil
+}
+
+func (repo *groupRepository) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	index, err := groupIndexByID(repo.groups, id)
+	if err != nil {
+		return err
+	}
+
+	repo.groups[index].ContactIDs = removeGroupMembers(repo.groups[index].ContactIDs, contactIDs)
+
+	return nil
+}
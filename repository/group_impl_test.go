@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// checkGroupRepository runs repo through the life of a group, with
+// contactRepo holding the contacts put in it.
+func checkGroupRepository(t *testing.T, repo GroupRepository, contactRepo ContactRepository) {
+	ctx := context.Background()
+
+	var contactIDs []int64
+	for _, contact := range []model.Contact{
+		{Name: "Reva", NoTelp: "555-1234-989"},
+		{Name: "Tirta", NoTelp: "555-5678"},
+		{Name: "Bagas", NoTelp: "555-9012"},
+	} {
+		contact.CreatedAt, contact.UpdatedAt = testContactTime, testContactTime
+		added, err := contactRepo.Add(ctx, &contact)
+		if err != nil {
+			t.Fatalf("ContactRepository.Add() error = %v", err)
+		}
+		contactIDs = append(contactIDs, added.ID)
+	}
+
+	oncall, err := repo.Add(ctx, &model.Group{Name: "On-call", Description: "Pager rotation", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	backend, err := repo.Add(ctx, &model.Group{Name: "Backend", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if oncall.ID <= 0 || backend.ID == oncall.ID {
+		t.Fatalf("Add() ids = %d, %d, want distinct ids", oncall.ID, backend.ID)
+	}
+
+	groups, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []model.Group{*backend, *oncall}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("List() = %+v, want %+v", groups, want)
+	}
+
+	if got, err := repo.FindByName(ctx, "on-call"); err != nil || !reflect.DeepEqual(got, oncall) {
+		t.Errorf("FindByName() = %+v, %v, want %+v", got, err, oncall)
+	}
+	if _, err := repo.FindByName(ctx, "Frontend"); err == nil {
+		t.Errorf("FindByName() of a missing group error = nil")
+	}
+
+	updatedAt := testContactTime.Add(time.Hour)
+	updated, err := repo.Update(ctx, oncall.ID, &model.Group{Name: "On-call", Description: "Weekly rotation", UpdatedAt: updatedAt})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	want := *oncall
+	want.Description = "Weekly rotation"
+	want.UpdatedAt = updatedAt
+	if !reflect.DeepEqual(updated, &want) {
+		t.Errorf("Update() = %+v, want %+v", updated, &want)
+	}
+	if got, err := repo.Detail(ctx, oncall.ID); err != nil || !reflect.DeepEqual(got, &want) {
+		t.Errorf("Detail() = %+v, %v, want %+v", got, err, &want)
+	}
+	if _, err := repo.Update(ctx, 99, &model.Group{Name: "Nope"}); err == nil {
+		t.Errorf("Update() of a missing group error = nil")
+	}
+
+	if err := repo.AddMembers(ctx, oncall.ID, []int64{contactIDs[1], contactIDs[0], contactIDs[1]}); err != nil {
+		t.Fatalf("AddMembers() error = %v", err)
+	}
+	if err := repo.AddMembers(ctx, oncall.ID, []int64{contactIDs[0]}); err != nil {
+		t.Fatalf("AddMembers() of a member error = %v", err)
+	}
+	if members, err := repo.Members(ctx, oncall.ID); err != nil || !reflect.DeepEqual(members, contactIDs[:2]) {
+		t.Errorf("Members() = %v, %v, want %v", members, err, contactIDs[:2])
+	}
+
+	if err := repo.RemoveMembers(ctx, oncall.ID, []int64{contactIDs[0], contactIDs[2]}); err != nil {
+		t.Fatalf("RemoveMembers() error = %v", err)
+	}
+	if members, err := repo.Members(ctx, oncall.ID); err != nil || !reflect.DeepEqual(members, contactIDs[1:2]) {
+		t.Errorf("Members() = %v, %v, want %v", members, err, contactIDs[1:2])
+	}
+	if members, err := repo.Members(ctx, backend.ID); err != nil || len(members) != 0 {
+		t.Errorf("Members() of an empty group = %v, %v, want none", members, err)
+	}
+	if _, err := repo.Members(ctx, 99); err == nil {
+		t.Errorf("Members() of a missing group error = nil")
+	}
+	if err := repo.AddMembers(ctx, 99, contactIDs[:1]); err == nil {
+		t.Errorf("AddMembers() to a missing group error = nil")
+	}
+
+	if err := repo.Delete(ctx, oncall.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.Detail(ctx, oncall.ID); err == nil {
+		t.Errorf("Detail() of a deleted group error = nil")
+	}
+	if err := repo.Delete(ctx, oncall.ID); err == nil {
+		t.Errorf("Delete() of a deleted group error = nil")
+	}
+	if groups, err := repo.List(ctx); err != nil || len(groups) != 1 {
+		t.Errorf("List() after Delete() = %+v, %v, want one group", groups, err)
+	}
+}
+
+func Test_groupRepository(t *testing.T) {
+	checkGroupRepository(t, NewGroupRepository(), NewContactRepository())
+}
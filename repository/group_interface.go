@@ -0,0 +1,30 @@
+//go:generate mockery --output=../mocks --name GroupRepository
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+)
+
+// GroupRepository keeps the groups and which contacts are in them.
+// Names are compared regardless of case. A contact stays in its groups
+// while in the trash, so a restored contact is back where it was.
+type GroupRepository interface {
+	// List returns every group, ordered by name.
+	List(ctx context.Context) ([]model.Group, error)
+	Add(ctx context.Context, group *model.Group) (*model.Group, error)
+	Detail(ctx context.Context, id int64) (*model.Group, error)
+	// FindByName returns the group called name, regardless of case.
+	FindByName(ctx context.Context, name string) (*model.Group, error)
+	// Update replaces the name and description of the group id.
+	Update(ctx context.Context, id int64, group *model.Group) (*model.Group, error)
+	// Delete removes the group id along with its memberships,
+	// leaving the contacts in it as they are.
+	Delete(ctx context.Context, id int64) error
+	// Members returns the ids of the contacts in the group id, ascending.
+	Members(ctx context.Context, id int64) ([]int64, error)
+	// AddMembers puts contactIDs in the group id, skipping those already in it.
+	AddMembers(ctx context.Context, id int64, contactIDs []int64) error
+	// RemoveMembers takes contactIDs out of the group id, skipping those not in it.
+	RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error
+}
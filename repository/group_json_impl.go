@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"contact-go/model"
+	"context"
+)
+
+// groupJsonRepository keeps the groups in a jsonStore of their own.
+type groupJsonRepository struct {
+	store jsonStore
+}
+
+func NewGroupJsonRepository(jsonFilePath string) GroupRepository {
+	repo := new(groupJsonRepository)
+	repo.store.path = jsonFilePath
+	return repo
+}
+
+func (repo *groupJsonRepository) lock(ctx context.Context, exclusive bool) (func(), error) {
+	return repo.store.lock(ctx, exclusive)
+}
+
+func (repo *groupJsonRepository) encodeJSON(groups []storedGroup) error {
+	return repo.store.encode(&groups)
+}
+
+// decodeJSON reads the groups, none when the file is not there yet.
+func (repo *groupJsonRepository) decodeJSON() ([]storedGroup, error) {
+	var groups []storedGroup
+	err := repo.store.decode(&groups)
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (repo *groupJsonRepository) List(ctx context.Context) ([]model.Group, error) {
+	unlock, err := repo.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedGroups(groups), nil
+}
+
+func (repo *groupJsonRepository) Add(ctx context.Context, group *model.Group) (*model.Group, error) {
+	unlock, err := repo.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	newGroup := *group
+	newGroup.ID = lastGroupID(groups) + 1
+
+	groups = append(groups, storedGroup{Group: newGroup})
+
+	err = repo.encodeJSON(groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newGroup, nil
+}
+
+func (repo *groupJsonRepository) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	unlock, err := repo.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := groupIndexByID(groups, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &groups[index].Group, nil
+}
+
+func (repo *groupJsonRepository) FindByName(ctx context.Context, name string) (*model.Group, error) {
+	unlock, err := repo.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := groupIndexByName(groups, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &groups[index].Group, nil
+}
+
+func (repo *groupJsonRepository) Update(ctx context.Context, id int64, group *model.Group) (*model.Group, error) {
+	unlock, err := repo.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := groupIndexByID(groups, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedGroup := &groups[index].Group
+	updatedGroup.Name = group.Name
+	updatedGroup.Description = group.Description
+	updatedGroup.UpdatedAt = group.UpdatedAt
+
+	err = repo.encodeJSON(groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedGroup, nil
+}
+
+func (repo *groupJsonRepository) Delete(ctx context.Context, id int64) error {
+	unlock, err := repo.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return err
+	}
+
+	index, err := groupIndexByID(groups, id)
+	if err != nil {
+		return err
+	}
+
+	groups = append(groups[:index], groups[index+1:]...)
+
+	return repo.encodeJSON(groups)
+}
+
+func (repo *groupJsonRepository) Members(ctx context.Context, id int64) ([]int64, error) {
+	unlock, err := repo.lock(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := groupIndexByID(groups, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups[index].ContactIDs, nil
+}
+
+func (repo *groupJsonRepository) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	unlock, err := repo.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return err
+	}
+
+	index, err := groupIndexByID(groups, id)
+	if err != nil {
+		return err
+	}
+
+	groups[index].ContactIDs = addGroupMembers(groups[index].ContactIDs, contactIDs)
+
+	return repo.encodeJSON(groups)
+}
+
+func (repo *groupJsonRepository) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	unlock, err := repo.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	groups, err := repo.decodeJSON()
+	if err != nil {
+		return err
+	}
+
+	index, err := groupIndexByID(groups, id)
+	if err != nil {
+		return err
+	}
+
+	groups[index].ContactIDs = removeGroupMembers(groups[index].ContactIDs, contactIDs)
+
+	return repo.encodeJSON(groups)
+}
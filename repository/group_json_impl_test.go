@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"contact-go/model"
+	"path/filepath"
+	"testing"
+)
+
+func Test_groupJsonRepository(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile, err := mockJsonFile(&[]model.Contact{}, dir, "test_contact_*.json")
+	if err != nil {
+		t.Fatalf("mockJsonFile error = %v", err)
+	}
+
+	checkGroupRepository(t, NewGroupJsonRepository(filepath.Join(dir, "group.json")), NewContactJsonRepository(jsonFile, 0))
+}
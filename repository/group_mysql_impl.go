@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type groupMysqlRepository struct {
+	db *sql.DB
+}
+
+// NewGroupMysqlRepository relies on the case-insensitive collation of
+// the group table for comparing names.
+func NewGroupMysqlRepository(db *sql.DB) GroupRepository {
+	return &groupMysqlRepository{
+		db: db,
+	}
+}
+
+func (repo *groupMysqlRepository) List(ctx context.Context) ([]model.Group, error) {
+	sqlQuery := "SELECT " + groupColumns + " FROM contact_group WHERE tenant = ? ORDER BY name ASC, id ASC"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanGroups(rows)
+}
+
+func (repo *groupMysqlRepository) Add(ctx context.Context, group *model.Group) (*model.Group, error) {
+	sqlQuery := "INSERT INTO contact_group(name, description, created_at, updated_at, tenant) VALUES (?, ?, ?, ?, ?)"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	row, err := stmt.ExecContext(ctx, group.Name, group.Description, group.CreatedAt, group.UpdatedAt, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := row.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	newGroup := *group
+	newGroup.ID = id
+
+	return &newGroup, nil
+}
+
+func (repo *groupMysqlRepository) findGroup(ctx context.Context, condition string, arg interface{}) (*model.Group, error) {
+	group := new(model.Group)
+
+	sqlQuery := "SELECT " + groupColumns + " FROM contact_group WHERE tenant = ? AND " + condition + " LIMIT 1"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	err = scanGroup(stmt.QueryRowContext(ctx, tenant.FromContext(ctx), arg), group)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (repo *groupMysqlRepository) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	return repo.findGroup(ctx, "id = ?", id)
+}
+
+func (repo *groupMysqlRepository) FindByName(ctx context.Context, name string) (*model.Group, error) {
+	return repo.findGroup(ctx, "name = ?", name)
+}
+
+func (repo *groupMysqlRepository) Update(ctx context.Context, id int64, group *model.Group) (*model.Group, error) {
+	sqlQuery := "UPDATE contact_group SET name = ?, description = ?, updated_at = ? WHERE tenant = ? AND id = ?"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, group.Name, group.Description, group.UpdatedAt, tenant.FromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	// MySQL counts only the rows it changed, so a missing group
+	// is told apart by reading it back
+	return repo.Detail(ctx, id)
+}
+
+func (repo *groupMysqlRepository) Delete(ctx context.Context, id int64) error {
+	sqlQuery := "DELETE FROM contact_group WHERE tenant = ? AND id = ?"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, tenant.FromContext(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+func (repo *groupMysqlRepository) Members(ctx context.Context, id int64) ([]int64, error) {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return nil, err
+	}
+
+	sqlQuery := "SELECT contact_id FROM contact_group_member WHERE group_id = ? ORDER BY contact_id ASC"
+	stmt, err := repo.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanMemberIDs(rows)
+}
+
+func (repo *groupMysqlRepository) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return err
+	}
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	values, args := memberValues(id, contactIDs)
+	_, err := repo.db.ExecContext(ctx, "INSERT IGNORE INTO contact_group_member(group_id, contact_id) VALUES "+values, args...)
+	return err
+}
+
+func (repo *groupMysqlRepository) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return err
+	}
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	ids, args := idList(contactIDs)
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM contact_group_member WHERE group_id = ? AND contact_id IN "+ids, append([]interface{}{id}, args...)...)
+	return err
+}
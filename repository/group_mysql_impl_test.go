@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// mysqlGroupRows returns groups as rows of the group table.
+func mysqlGroupRows(groups ...model.Group) *sqlmock.Rows {
+	rows := sqlmock.NewRows(strings.Split(groupColumns, ", "))
+	for _, group := range groups {
+		rows.AddRow(group.ID, group.Name, group.Description, group.CreatedAt, group.UpdatedAt)
+	}
+	return rows
+}
+
+func Test_groupMysqlRepository(t *testing.T) {
+	detailQuery := regexp.QuoteMeta("SELECT " + groupColumns + " FROM contact_group WHERE tenant = ? AND id = ? LIMIT 1")
+	team := model.Group{ID: 1, Name: "team", CreatedAt: testContactTime, UpdatedAt: testContactTime}
+
+	tests := []struct {
+		name       string
+		run        func(GroupRepository) (interface{}, error)
+		beforeTest func(sqlmock.Sqlmock)
+		want       interface{}
+		wantErr    error
+	}{
+		{
+			name: "list",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return repo.List(context.Background())
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT " + groupColumns + " FROM contact_group WHERE tenant = ? ORDER BY name ASC, id ASC")).
+					ExpectQuery().
+					WithArgs(tenant.Default).
+					WillReturnRows(mysqlGroupRows(team))
+			},
+			want: []model.Group{team},
+		},
+		{
+			name: "add",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return repo.Add(context.Background(), &model.Group{Name: "team", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("INSERT INTO contact_group(name, description, created_at, updated_at, tenant) VALUES (?, ?, ?, ?, ?)")).
+					ExpectExec().
+					WithArgs("team", "", testContactTime, testContactTime, tenant.Default).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			want: &team,
+		},
+		{
+			name: "find by name missing",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return repo.FindByName(context.Background(), "nobody")
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT "+groupColumns+" FROM contact_group WHERE tenant = ? AND name = ? LIMIT 1")).
+					ExpectQuery().
+					WithArgs(tenant.Default, "nobody").
+					WillReturnRows(mysqlGroupRows())
+			},
+			want:    (*model.Group)(nil),
+			wantErr: apperrors.NewAppError(apperrors.ErrGroupNotFound),
+		},
+		{
+			name: "delete missing",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return nil, repo.Delete(context.Background(), 9)
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(regexp.QuoteMeta("DELETE FROM contact_group WHERE tenant = ? AND id = ?")).
+					ExpectExec().
+					WithArgs(tenant.Default, int64(9)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: apperrors.NewAppError(apperrors.ErrGroupNotFound),
+		},
+		{
+			name: "members",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return repo.Members(context.Background(), 1)
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(detailQuery).ExpectQuery().WithArgs(tenant.Default, int64(1)).WillReturnRows(mysqlGroupRows(team))
+				s.ExpectPrepare(regexp.QuoteMeta("SELECT contact_id FROM contact_group_member WHERE group_id = ? ORDER BY contact_id ASC")).
+					ExpectQuery().
+					WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"contact_id"}).AddRow(int64(2)).AddRow(int64(5)))
+			},
+			want: []int64{2, 5},
+		},
+		{
+			name: "add members",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return nil, repo.AddMembers(context.Background(), 1, []int64{2, 5})
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(detailQuery).ExpectQuery().WithArgs(tenant.Default, int64(1)).WillReturnRows(mysqlGroupRows(team))
+				s.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO contact_group_member(group_id, contact_id) VALUES (?, ?), (?, ?)")).
+					WithArgs(int64(1), int64(2), int64(1), int64(5)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "remove members of missing group",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return nil, repo.RemoveMembers(context.Background(), 9, []int64{2})
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(detailQuery).ExpectQuery().WithArgs(tenant.Default, int64(9)).WillReturnRows(mysqlGroupRows())
+			},
+			wantErr: apperrors.NewAppError(apperrors.ErrGroupNotFound),
+		},
+		{
+			name: "remove members",
+			run: func(repo GroupRepository) (interface{}, error) {
+				return nil, repo.RemoveMembers(context.Background(), 1, []int64{2, 5})
+			},
+			beforeTest: func(s sqlmock.Sqlmock) {
+				s.ExpectPrepare(detailQuery).ExpectQuery().WithArgs(tenant.Default, int64(1)).WillReturnRows(mysqlGroupRows(team))
+				s.ExpectExec(regexp.QuoteMeta("DELETE FROM contact_group_member WHERE group_id = ? AND contact_id IN (?, ?)")).
+					WithArgs(int64(1), int64(2), int64(5)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer db.Close()
+
+			tt.beforeTest(mock)
+
+			got, err := tt.run(NewGroupMysqlRepository(db))
+			assert.Equal(t, tt.wantErr, err)
+			if tt.want != nil {
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"sort"
+	"strings"
+)
+
+// storedGroup is a group as the backends keeping every group in memory
+// hold it, along with the ids of its members.
+type storedGroup struct {
+	model.Group
+	ContactIDs []int64 `json:"contact_ids"`
+}
+
+// lastGroupID returns the highest ID in groups, or zero when it is empty.
+func lastGroupID(groups []storedGroup) int64 {
+	var tempID int64
+	for _, v := range groups {
+		if tempID < v.ID {
+			tempID = v.ID
+		}
+	}
+	return tempID
+}
+
+func groupIndexByID(groups []storedGroup, id int64) (int, error) {
+	for i, v := range groups {
+		if id == v.ID {
+			return i, nil
+		}
+	}
+
+	return -1, apperrors.NewAppError(apperrors.ErrGroupNotFound)
+}
+
+func groupIndexByName(groups []storedGroup, name string) (int, error) {
+	for i, v := range groups {
+		if strings.EqualFold(name, v.Name) {
+			return i, nil
+		}
+	}
+
+	return -1, apperrors.NewAppError(apperrors.ErrGroupNotFound)
+}
+
+// sortedGroups returns the groups ordered by name, then id.
+func sortedGroups(groups []storedGroup) []model.Group {
+	sorted := make([]model.Group, 0, len(groups))
+	for _, v := range groups {
+		sorted = append(sorted, v.Group)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// addGroupMembers adds contactIDs to members, keeping them ascending
+// and each in it once.
+func addGroupMembers(members []int64, contactIDs []int64) []int64 {
+	seen := make(map[int64]bool, len(members)+len(contactIDs))
+	result := make([]int64, 0, len(members)+len(contactIDs))
+	for _, id := range append(members[:len(members):len(members)], contactIDs...) {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// removeGroupMembers drops contactIDs from members.
+func removeGroupMembers(members []int64, contactIDs []int64) []int64 {
+	removed := make(map[int64]bool, len(contactIDs))
+	for _, id := range contactIDs {
+		removed[id] = true
+	}
+
+	result := make([]int64, 0, len(members))
+	for _, id := range members {
+		if !removed[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
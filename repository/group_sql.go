@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"contact-go/model"
+	"database/sql"
+	"strings"
+)
+
+// groupColumns are the columns of the group table
+// in the order scanGroup reads them.
+const groupColumns = "id, name, description, created_at, updated_at"
+
+// scanGroup reads a row of groupColumns, with the timestamps in UTC.
+func scanGroup(row rowScanner, group *model.Group) error {
+	err := row.Scan(&group.ID, &group.Name, &group.Description, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	group.CreatedAt = group.CreatedAt.UTC()
+	group.UpdatedAt = group.UpdatedAt.UTC()
+	return nil
+}
+
+func scanGroups(rows *sql.Rows) ([]model.Group, error) {
+	var groups []model.Group
+
+	for rows.Next() {
+		var group model.Group
+		if err := scanGroup(rows, &group); err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func scanMemberIDs(rows *sql.Rows) ([]int64, error) {
+	var ids []int64
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// memberValues returns the VALUES of an insert of contactIDs into the
+// group id, as in "(?, ?), (?, ?)", along with its arguments.
+func memberValues(id int64, contactIDs []int64) (string, []interface{}) {
+	args := make([]interface{}, 0, 2*len(contactIDs))
+	for _, contactID := range contactIDs {
+		args = append(args, id, contactID)
+	}
+	return strings.TrimSuffix(strings.Repeat("(?, ?), ", len(contactIDs)), ", "), args
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type groupSqliteRepository struct {
+	db *sql.DB
+}
+
+// NewGroupSqliteRepository relies on the NOCASE collation of the name
+// column for comparing names, which folds ASCII letters only.
+func NewGroupSqliteRepository(db *sql.DB) GroupRepository {
+	return &groupSqliteRepository{
+		db: db,
+	}
+}
+
+func (repo *groupSqliteRepository) List(ctx context.Context) ([]model.Group, error) {
+	sqlQuery := "SELECT " + groupColumns + " FROM contact_group WHERE tenant = ? ORDER BY name ASC, id ASC"
+	rows, err := repo.db.QueryContext(ctx, sqlQuery, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanGroups(rows)
+}
+
+func (repo *groupSqliteRepository) Add(ctx context.Context, group *model.Group) (*model.Group, error) {
+	sqlQuery := "INSERT INTO contact_group(name, description, created_at, updated_at, tenant) VALUES (?, ?, ?, ?, ?)"
+	row, err := repo.db.ExecContext(ctx, sqlQuery, group.Name, group.Description, group.CreatedAt, group.UpdatedAt, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := row.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	newGroup := *group
+	newGroup.ID = id
+
+	return &newGroup, nil
+}
+
+func (repo *groupSqliteRepository) findGroup(ctx context.Context, condition string, arg interface{}) (*model.Group, error) {
+	group := new(model.Group)
+
+	sqlQuery := "SELECT " + groupColumns + " FROM contact_group WHERE tenant = ? AND " + condition + " LIMIT 1"
+	err := scanGroup(repo.db.QueryRowContext(ctx, sqlQuery, tenant.FromContext(ctx), arg), group)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (repo *groupSqliteRepository) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	return repo.findGroup(ctx, "id = ?", id)
+}
+
+func (repo *groupSqliteRepository) FindByName(ctx context.Context, name string) (*model.Group, error) {
+	return repo.findGroup(ctx, "name = ?", name)
+}
+
+func (repo *groupSqliteRepository) Update(ctx context.Context, id int64, group *model.Group) (*model.Group, error) {
+	sqlQuery := "UPDATE contact_group SET name = ?, description = ?, updated_at = ? WHERE tenant = ? AND id = ?"
+	result, err := repo.db.ExecContext(ctx, sqlQuery, group.Name, group.Description, group.UpdatedAt, tenant.FromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+
+	return repo.Detail(ctx, id)
+}
+
+func (repo *groupSqliteRepository) Delete(ctx context.Context, id int64) error {
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM contact_group WHERE tenant = ? AND id = ?", tenant.FromContext(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return apperrors.NewAppError(apperrors.ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+func (repo *groupSqliteRepository) Members(ctx context.Context, id int64) ([]int64, error) {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return nil, err
+	}
+
+	sqlQuery := "SELECT contact_id FROM contact_group_member WHERE group_id = ? ORDER BY contact_id ASC"
+	rows, err := repo.db.QueryContext(ctx, sqlQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanMemberIDs(rows)
+}
+
+func (repo *groupSqliteRepository) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return err
+	}
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	values, args := memberValues(id, contactIDs)
+	_, err := repo.db.ExecContext(ctx, "INSERT OR IGNORE INTO contact_group_member(group_id, contact_id) VALUES "+values, args...)
+	return err
+}
+
+func (repo *groupSqliteRepository) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if _, err := repo.Detail(ctx, id); err != nil {
+		return err
+	}
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	ids, args := idList(contactIDs)
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM contact_group_member WHERE group_id = ? AND contact_id IN "+ids, append([]interface{}{id}, args...)...)
+	return err
+}
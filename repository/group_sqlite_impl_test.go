@@ -0,0 +1,8 @@
+package repository
+
+import "testing"
+
+func Test_groupSqliteRepository(t *testing.T) {
+	sqliteDB := newSqliteTestDatabase(t)
+	checkGroupRepository(t, NewGroupSqliteRepository(sqliteDB), NewContactSqliteRepository(sqliteDB))
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"contact-go/helper/filelock"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// jsonStore is a JSON document kept in a file of its own, the file
+// being the source of truth: read on every call and rewritten on every
+// change. mu serialises those read-modify-write cycles within the
+// process, and the lock file next to path does the same across
+// processes. The file is created on the first change, and the previous
+// backups versions of it are kept next to it.
+type jsonStore struct {
+	mu      sync.RWMutex
+	path    string
+	backups int
+}
+
+func (store *jsonStore) lockPath() string {
+	return store.path + ".lock"
+}
+
+// lock takes the in-process and cross-process locks together
+// and returns the function that releases both. It gives up early
+// when ctx is already done, so a cancelled caller never touches the file.
+func (store *jsonStore) lock(ctx context.Context, exclusive bool) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if exclusive {
+		store.mu.Lock()
+		fileLock, err := filelock.Exclusive(store.lockPath())
+		if err != nil {
+			store.mu.Unlock()
+			return nil, err
+		}
+
+		return func() {
+			_ = fileLock.Unlock()
+			store.mu.Unlock()
+		}, nil
+	}
+
+	store.mu.RLock()
+	fileLock, err := filelock.Shared(store.lockPath())
+	if err != nil {
+		store.mu.RUnlock()
+		return nil, err
+	}
+
+	return func() {
+		_ = fileLock.Unlock()
+		store.mu.RUnlock()
+	}, nil
+}
+
+// backupPath names the nth most recent backup, contact_backup.json being
+// the newest and contact_backup_2.json, contact_backup_3.json and so on older.
+func (store *jsonStore) backupPath(n int) string {
+	ext := filepath.Ext(store.path)
+	base := strings.TrimSuffix(store.path, ext) + "_backup"
+	if n > 1 {
+		base += fmt.Sprintf("_%d", n)
+	}
+	return base + ext
+}
+
+// rotateBackups shifts every backup one place older, dropping the oldest,
+// and copies the current file into the newest slot.
+func (store *jsonStore) rotateBackups() error {
+	if store.backups <= 0 {
+		return nil
+	}
+
+	current, err := os.Open(store.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer current.Close()
+
+	for n := store.backups; n > 1; n-- {
+		err = os.Rename(store.backupPath(n-1), store.backupPath(n))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	backup, err := os.Create(store.backupPath(1))
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+
+	_, err = io.Copy(backup, current)
+	if err != nil {
+		return err
+	}
+	return backup.Close()
+}
+
+// encode writes v to a temporary file and renames it over path, so a
+// crash mid-write never leaves a truncated file.
+func (store *jsonStore) encode(v interface{}) error {
+	return store.rewrite(func(encoder *json.Encoder) error {
+		return encoder.Encode(v)
+	})
+}
+
+// rewrite is encode for a file write puts together with encoder.
+func (store *jsonStore) rewrite(write func(encoder *json.Encoder) error) error {
+	dir := filepath.Dir(store.path)
+
+	writer, err := os.CreateTemp(dir, filepath.Base(store.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempFile := writer.Name()
+	defer os.Remove(tempFile)
+	defer writer.Close()
+
+	err = write(json.NewEncoder(writer))
+	if err != nil {
+		return err
+	}
+
+	err = writer.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	err = store.rotateBackups()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tempFile, store.path)
+	if err != nil {
+		return err
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// appendLine adds v to a file of JSON lines as a line of its own,
+// creating the file when it is not there yet. A line a crash left
+// unfinished at the end is cut off first, so that v does not run into it.
+func (store *jsonStore) appendLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(store.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, end, err := lastLine(file)
+	if err != nil {
+		return err
+	}
+
+	err = file.Truncate(end)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteAt(append(data, '\n'), end)
+	if err != nil {
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	syncDir(filepath.Dir(store.path))
+	return nil
+}
+
+// lastLine returns the last line of a file of JSON lines, nil when the
+// file is not there yet or has none.
+func (store *jsonStore) lastLine() ([]byte, error) {
+	file, err := os.Open(store.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	line, _, err := lastLine(file)
+	return line, err
+}
+
+// decodeLines calls fn with every line of a file of JSON lines, in
+// order, but for one a crash left unfinished at the end. A file not
+// there yet has none.
+func (store *jsonStore) decodeLines(fn func(line []byte) error) error {
+	file, err := os.Open(store.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+}
+
+// lastLine returns the last finished line of file, reading it from the
+// end, along with the offset it ends at, where the next line goes.
+func lastLine(file *os.File) ([]byte, int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		n := int64(4096)
+		if n > offset {
+			n = offset
+		}
+		offset -= n
+
+		chunk := make([]byte, n, n+int64(len(tail)))
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			return nil, 0, err
+		}
+		tail = append(chunk, tail...)
+
+		end := bytes.LastIndexByte(tail, '\n')
+		if end < 0 {
+			continue
+		}
+		start := bytes.LastIndexByte(tail[:end], '\n')
+		if start < 0 && offset > 0 {
+			continue
+		}
+		return tail[start+1 : end+1], offset + int64(end) + 1, nil
+	}
+	return nil, 0, nil
+}
+
+// syncDir flushes the directory entry of a rename to disk.
+// Not every platform can open or sync a directory, which is not fatal.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+
+	_ = d.Sync()
+}
+
+// decode reads the document into v, leaving v as it is when the file
+// is not there yet.
+func (store *jsonStore) decode(v interface{}) error {
+	reader, err := os.Open(store.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return json.NewDecoder(reader).Decode(v)
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TenantPath returns path for the default address book, and
+// data/contact.sales.json for data/contact.json of sales.
+func TenantPath(path string, name string) string {
+	if name == tenant.Default {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + name + ext
+}
+
+// tenantRepos keeps a repository per address book, made on first use.
+type tenantRepos[R any] struct {
+	mu      sync.Mutex
+	repos   map[string]R
+	newRepo func(name string) R
+}
+
+func newTenantRepos[R any](newRepo func(name string) R) *tenantRepos[R] {
+	return &tenantRepos[R]{repos: make(map[string]R), newRepo: newRepo}
+}
+
+// repo returns the repository of the address book of ctx.
+func (t *tenantRepos[R]) repo(ctx context.Context) R {
+	name := tenant.FromContext(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.repos[name]
+	if !ok {
+		r = t.newRepo(name)
+		t.repos[name] = r
+	}
+	return r
+}
+
+// contactTenantRepository hands every call to the repository of its address book.
+type contactTenantRepository struct {
+	*tenantRepos[ContactRepository]
+}
+
+// NewContactTenantRepository makes the repository of each address book with newRepo.
+func NewContactTenantRepository(newRepo func(name string) ContactRepository) ContactRepository {
+	return &contactTenantRepository{newTenantRepos(newRepo)}
+}
+
+func (repo *contactTenantRepository) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	return repo.repo(ctx).List(ctx, query)
+}
+
+func (repo *contactTenantRepository) Add(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	return repo.repo(ctx).Add(ctx, contact)
+}
+
+func (repo *contactTenantRepository) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	return repo.repo(ctx).Detail(ctx, id)
+}
+
+func (repo *contactTenantRepository) Update(ctx context.Context, id int64, contact *model.Contact) (*model.Contact, error) {
+	return repo.repo(ctx).Update(ctx, id, contact)
+}
+
+func (repo *contactTenantRepository) Patch(ctx context.Context, id int64, contact *model.Contact, fields []string) (*model.Contact, error) {
+	return repo.repo(ctx).Patch(ctx, id, contact, fields)
+}
+
+func (repo *contactTenantRepository) Delete(ctx context.Context, id int64, version int64, deletedAt time.Time) error {
+	return repo.repo(ctx).Delete(ctx, id, version, deletedAt)
+}
+
+func (repo *contactTenantRepository) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	return repo.repo(ctx).Search(ctx, query)
+}
+
+func (repo *contactTenantRepository) Merge(ctx context.Context, id int64, contact *model.Contact, mergedIDs []int64) (*model.Contact, error) {
+	return repo.repo(ctx).Merge(ctx, id, contact, mergedIDs)
+}
+
+func (repo *contactTenantRepository) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	return repo.repo(ctx).Restore(ctx, id)
+}
+
+func (repo *contactTenantRepository) Recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	return repo.repo(ctx).Recreate(ctx, contact)
+}
+
+func (repo *contactTenantRepository) Batch(ctx context.Context, ops []model.ContactOperation, atomic bool) ([]model.ContactOperationResult, error) {
+	return repo.repo(ctx).Batch(ctx, ops, atomic)
+}
+
+func (repo *contactTenantRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return repo.repo(ctx).Purge(ctx, before)
+}
+
+// groupTenantRepository is contactTenantRepository for groups.
+type groupTenantRepository struct {
+	*tenantRepos[GroupRepository]
+}
+
+func NewGroupTenantRepository(newRepo func(name string) GroupRepository) GroupRepository {
+	return &groupTenantRepository{newTenantRepos(newRepo)}
+}
+
+func (repo *groupTenantRepository) List(ctx context.Context) ([]model.Group, error) {
+	return repo.repo(ctx).List(ctx)
+}
+
+func (repo *groupTenantRepository) Add(ctx context.Context, group *model.Group) (*model.Group, error) {
+	return repo.repo(ctx).Add(ctx, group)
+}
+
+func (repo *groupTenantRepository) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	return repo.repo(ctx).Detail(ctx, id)
+}
+
+func (repo *groupTenantRepository) FindByName(ctx context.Context, name string) (*model.Group, error) {
+	return repo.repo(ctx).FindByName(ctx, name)
+}
+
+func (repo *groupTenantRepository) Update(ctx context.Context, id int64, group *model.Group) (*model.Group, error) {
+	return repo.repo(ctx).Update(ctx, id, group)
+}
+
+func (repo *groupTenantRepository) Delete(ctx context.Context, id int64) error {
+	return repo.repo(ctx).Delete(ctx, id)
+}
+
+func (repo *groupTenantRepository) Members(ctx context.Context, id int64) ([]int64, error) {
+	return repo.repo(ctx).Members(ctx, id)
+}
+
+func (repo *groupTenantRepository) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	return repo.repo(ctx).AddMembers(ctx, id, contactIDs)
+}
+
+func (repo *groupTenantRepository) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	return repo.repo(ctx).RemoveMembers(ctx, id, contactIDs)
+}
+
+// auditTenantRepository is contactTenantRepository for the audit log.
+type auditTenantRepository struct {
+	*tenantRepos[AuditRepository]
+}
+
+func NewAuditTenantRepository(newRepo func(name string) AuditRepository) AuditRepository {
+	return &auditTenantRepository{newTenantRepos(newRepo)}
+}
+
+func (repo *auditTenantRepository) Add(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	return repo.repo(ctx).Add(ctx, entry)
+}
+
+func (repo *auditTenantRepository) Detail(ctx context.Context, id int64) (*model.AuditEntry, error) {
+	return repo.repo(ctx).Detail(ctx, id)
+}
+
+func (repo *auditTenantRepository) List(ctx context.Context, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	return repo.repo(ctx).List(ctx, query)
+}
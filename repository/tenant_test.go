@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTenantPath(t *testing.T) {
+	tests := []struct {
+		path string
+		name string
+		want string
+	}{
+		{path: "data/contact.json", name: tenant.Default, want: "data/contact.json"},
+		{path: "data/contact.json", name: "sales", want: "data/contact.sales.json"},
+		{path: "data/contact", name: "sales", want: "data/contact.sales"},
+	}
+	for _, tt := range tests {
+		if got := TenantPath(tt.path, tt.name); got != tt.want {
+			t.Errorf("TenantPath(%q, %q) = %q, want %q", tt.path, tt.name, got, tt.want)
+		}
+	}
+}
+
+// checkContactTenants checks that contacts and groups added to one
+// address book are nowhere to be seen from another.
+func checkContactTenants(t *testing.T, repo ContactRepository, groupRepo GroupRepository) {
+	sales := tenant.NewContext(context.Background(), "sales")
+	ctx := context.Background()
+
+	contact, err := repo.Add(sales, &model.Contact{Name: "Reva", NoTelp: "555-1234-989", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	group, err := groupRepo.Add(sales, &model.Group{Name: "Leads", CreatedAt: testContactTime, UpdatedAt: testContactTime})
+	if err != nil {
+		t.Fatalf("GroupRepository.Add() error = %v", err)
+	}
+
+	if _, total, err := repo.List(sales, &model.ContactQuery{}); err != nil || total != 1 {
+		t.Errorf("List() of sales total = %d, %v, want 1", total, err)
+	}
+	if _, total, err := repo.List(ctx, &model.ContactQuery{}); err != nil || total != 0 {
+		t.Errorf("List() of the default address book total = %d, %v, want 0", total, err)
+	}
+	if _, err := repo.Detail(ctx, contact.ID); err == nil {
+		t.Errorf("Detail() of a contact of sales error = nil")
+	}
+	if found, err := repo.Search(ctx, "Reva"); err != nil || len(found) != 0 {
+		t.Errorf("Search() = %+v, %v, want nothing", found, err)
+	}
+	if err := repo.Delete(ctx, contact.ID, 0, testContactTime); err == nil {
+		t.Errorf("Delete() of a contact of sales error = nil")
+	}
+
+	if groups, err := groupRepo.List(ctx); err != nil || len(groups) != 0 {
+		t.Errorf("GroupRepository.List() = %+v, %v, want none", groups, err)
+	}
+	if _, err := groupRepo.Detail(ctx, group.ID); err == nil {
+		t.Errorf("GroupRepository.Detail() of a group of sales error = nil")
+	}
+
+	//* the same name may be taken in each address book
+	if _, err := groupRepo.Add(ctx, &model.Group{Name: "Leads", CreatedAt: testContactTime, UpdatedAt: testContactTime}); err != nil {
+		t.Errorf("GroupRepository.Add() of a name taken in sales error = %v", err)
+	}
+}
+
+func Test_contactTenantRepository(t *testing.T) {
+	checkContactTenants(t,
+		NewContactTenantRepository(func(string) ContactRepository { return NewContactRepository() }),
+		NewGroupTenantRepository(func(string) GroupRepository { return NewGroupRepository() }))
+}
+
+func Test_contactTenantRepository_json(t *testing.T) {
+	dir := t.TempDir()
+	contactsPath := filepath.Join(dir, "contact.json")
+	for _, name := range []string{tenant.Default, "sales"} {
+		if err := os.WriteFile(TenantPath(contactsPath, name), []byte("[]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkContactTenants(t,
+		NewContactTenantRepository(func(name string) ContactRepository {
+			return NewContactJsonRepository(TenantPath(contactsPath, name), 0)
+		}),
+		NewGroupTenantRepository(func(name string) GroupRepository {
+			return NewGroupJsonRepository(TenantPath(filepath.Join(dir, "group.json"), name))
+		}))
+}
+
+func Test_contactSqliteRepository_Tenants(t *testing.T) {
+	sqliteDB := newSqliteTestDatabase(t)
+	checkContactTenants(t, NewContactSqliteRepository(sqliteDB), NewGroupSqliteRepository(sqliteDB))
+}
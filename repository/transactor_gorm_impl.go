@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+func NewGormTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+// Transaction folds the transactions of the repositories into its own,
+// the way an atomic batch does.
+func (t *gormTransactor) Transaction(ctx context.Context, fn func(contacts ContactRepository, audit AuditRepository) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tx = tx.Session(&gorm.Session{DisableNestedTransaction: true})
+		return fn(&contactGormRepository{db: tx}, &auditGormRepository{db: tx})
+	})
+}
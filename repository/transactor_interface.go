@@ -0,0 +1,13 @@
+//go:generate mockery --output=../mocks --name Transactor
+package repository
+
+import "context"
+
+// Transactor stores a change of contacts together with its audit
+// entries, for the storages that keep both in one database.
+type Transactor interface {
+	// Transaction runs fn with repositories whose statements all run on
+	// one transaction, committed when fn returns nil and rolled back
+	// otherwise.
+	Transaction(ctx context.Context, fn func(contacts ContactRepository, audit AuditRepository) error) error
+}
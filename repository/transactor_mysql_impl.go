@@ -0,0 +1,12 @@
+package repository
+
+import "database/sql"
+
+func NewMysqlTransactor(db *sql.DB) Transactor {
+	return &sqlTransactor{
+		db: db,
+		repos: func(tx *sql.Tx) (ContactRepository, AuditRepository) {
+			return &contactMysqlRepository{db: db, conn: tx}, &auditMysqlRepository{conn: tx}
+		},
+	}
+}
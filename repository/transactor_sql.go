@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// sqlTransactor is the Transactor of the database/sql backends, repos
+// giving the repositories of the backend on a transaction.
+type sqlTransactor struct {
+	db    *sql.DB
+	repos func(tx *sql.Tx) (ContactRepository, AuditRepository)
+}
+
+func (t *sqlTransactor) Transaction(ctx context.Context, fn func(contacts ContactRepository, audit AuditRepository) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(t.repos(tx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// sqlTx is the transaction a change of several rows runs on. One it
+// joined is left for whoever began it to commit or roll back.
+type sqlTx struct {
+	*sql.Tx
+	joined bool
+}
+
+// beginTx begins a transaction on db, unless conn is one already, which
+// the statements then join.
+func beginTx(ctx context.Context, db *sql.DB, conn sqlConn) (*sqlTx, error) {
+	if tx, ok := conn.(*sql.Tx); ok {
+		return &sqlTx{Tx: tx, joined: true}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{Tx: tx}, nil
+}
+
+func (tx *sqlTx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+func (tx *sqlTx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
+
+// preparedTx is a sqlConn on tx that prepares each statement once on db
+// and runs it on tx as often as a batch asks, the way gorm does with
+// PrepareStmt. Closing a statement it gives leaves the prepared one to
+// the next operation; close releases them all.
+type preparedTx struct {
+	*sql.Tx
+	db    *sql.DB
+	stmts map[string]*sql.Stmt
+}
+
+func newPreparedTx(db *sql.DB, tx *sql.Tx) *preparedTx {
+	return &preparedTx{Tx: tx, db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+func (tx *preparedTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	stmt, ok := tx.stmts[query]
+	if !ok {
+		var err error
+		stmt, err = tx.db.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		tx.stmts[query] = stmt
+	}
+	return tx.Tx.StmtContext(ctx, stmt), nil
+}
+
+func (tx *preparedTx) close() {
+	for _, stmt := range tx.stmts {
+		stmt.Close()
+	}
+}
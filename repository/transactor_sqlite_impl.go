@@ -0,0 +1,12 @@
+package repository
+
+import "database/sql"
+
+func NewSqliteTransactor(db *sql.DB) Transactor {
+	return &sqlTransactor{
+		db: db,
+		repos: func(tx *sql.Tx) (ContactRepository, AuditRepository) {
+			return &contactSqliteRepository{db: db, conn: tx}, &auditSqliteRepository{conn: tx}
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"contact-go/helper/auth"
+	"contact-go/helper/rbac"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"context"
+)
+
+// authorizedAddressBookUsecase is authorizedContactUsecase for address
+// books.
+type authorizedAddressBookUsecase struct {
+	usecase AddressBookUsecase
+	policy  *rbac.Policy
+}
+
+// NewAuthorizedAddressBookUsecase checks the calls to uc against policy,
+// the way NewAuthorizedContactUsecase does, but for Detail, which looks
+// up the address book of every call before its caller does anything. A
+// caller the http api authenticated who is not bound to an address book
+// needs the admin permission to look up any but the default one.
+func NewAuthorizedAddressBookUsecase(uc AddressBookUsecase, policy *rbac.Policy) AddressBookUsecase {
+	return &authorizedAddressBookUsecase{usecase: uc, policy: policy}
+}
+
+func (uc *authorizedAddressBookUsecase) List(ctx context.Context) ([]model.AddressBook, error) {
+	if err := authorize(ctx, uc.policy, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.List(ctx)
+}
+
+func (uc *authorizedAddressBookUsecase) Add(ctx context.Context, req *model.AddressBookRequest) (*model.AddressBook, error) {
+	if err := authorize(ctx, uc.policy, rbac.PermissionAdmin); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Add(ctx, req)
+}
+
+func (uc *authorizedAddressBookUsecase) Detail(ctx context.Context, name string) (*model.AddressBook, error) {
+	if _, ok := auth.FromContext(ctx); ok && !tenant.Bound(ctx) && tenant.Normalize(name) != tenant.Default {
+		if err := authorize(ctx, uc.policy, rbac.PermissionAdmin); err != nil {
+			return nil, err
+		}
+	}
+	return uc.usecase.Detail(ctx, name)
+}
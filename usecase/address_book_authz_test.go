@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/auth"
+	"contact-go/helper/tenant"
+	"contact-go/mocks"
+	"contact-go/model"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authorizedAddressBookUsecase(t *testing.T) {
+	book := &model.AddressBook{Name: "sales"}
+	support := auth.NewContext(context.Background(), &auth.Principal{Name: "helpdesk", Method: auth.MethodAPIKey})
+	intern := auth.NewContext(context.Background(), &auth.Principal{Name: "bob", Method: auth.MethodJWT})
+	admin := auth.NewContext(context.Background(), &auth.Principal{Name: "bob", Method: auth.MethodJWT, Roles: []string{"admin"}})
+	boundIntern := tenant.NewBoundContext(intern, "sales")
+
+	tests := []struct {
+		name    string
+		call    func(AddressBookUsecase) error
+		mock    func(*mocks.AddressBookUsecase)
+		wantErr string
+	}{
+		{
+			name: "intern lists",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.List(intern)
+				return err
+			},
+			mock: func(m *mocks.AddressBookUsecase) {
+				m.On("List", intern).Return([]model.AddressBook{*book}, nil)
+			},
+		},
+		{
+			name: "intern does not add",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Add(intern, &model.AddressBookRequest{Name: "sales"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "anonymous does not add",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Add(context.Background(), &model.AddressBookRequest{Name: "sales"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "support does not add",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Add(support, &model.AddressBookRequest{Name: "sales"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "admin adds",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Add(admin, &model.AddressBookRequest{Name: "sales"})
+				return err
+			},
+			mock: func(m *mocks.AddressBookUsecase) {
+				m.On("Add", admin, &model.AddressBookRequest{Name: "sales"}).Return(book, nil)
+			},
+		},
+		{
+			name: "bound intern looks their address book up",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Detail(boundIntern, "sales")
+				return err
+			},
+			mock: func(m *mocks.AddressBookUsecase) {
+				m.On("Detail", boundIntern, "sales").Return(book, nil)
+			},
+		},
+		{
+			name: "intern looks the default address book up",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Detail(intern, tenant.Default)
+				return err
+			},
+			mock: func(m *mocks.AddressBookUsecase) {
+				m.On("Detail", intern, tenant.Default).Return(&model.AddressBook{Name: tenant.Default}, nil)
+			},
+		},
+		{
+			name: "intern does not pick another address book",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Detail(intern, "sales")
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "admin picks another address book",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Detail(admin, "sales")
+				return err
+			},
+			mock: func(m *mocks.AddressBookUsecase) {
+				m.On("Detail", admin, "sales").Return(book, nil)
+			},
+		},
+		{
+			name: "anonymous looks the address book of a call up",
+			call: func(uc AddressBookUsecase) error {
+				_, err := uc.Detail(context.Background(), "sales")
+				return err
+			},
+			mock: func(m *mocks.AddressBookUsecase) {
+				m.On("Detail", context.Background(), "sales").Return(book, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mocks.AddressBookUsecase)
+			if tt.mock != nil {
+				tt.mock(m)
+			}
+
+			err := tt.call(NewAuthorizedAddressBookUsecase(m, newTestPolicy(t)))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/model"
+	"contact-go/repository"
+	"context"
+	"time"
+)
+
+type addressBookUsecase struct {
+	AddressBookRepo repository.AddressBookRepository
+	Timeout         time.Duration
+
+	now func() time.Time
+}
+
+// NewAddressBookUsecase bounds every repository call by timeout, the
+// way NewContactUsecase does.
+func NewAddressBookUsecase(addressBookRepo repository.AddressBookRepository, timeout time.Duration) AddressBookUsecase {
+	return &addressBookUsecase{
+		AddressBookRepo: addressBookRepo,
+		Timeout:         timeout,
+		now:             now,
+	}
+}
+
+func (uc *addressBookUsecase) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if uc.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, uc.Timeout)
+}
+
+func (uc *addressBookUsecase) List(ctx context.Context) ([]model.AddressBook, error) {
+	if tenant.Bound(ctx) {
+		book, err := uc.Detail(ctx, tenant.FromContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		return []model.AddressBook{*book}, nil
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	books, err := uc.AddressBookRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]model.AddressBook{{Name: tenant.Default}}, books...), nil
+}
+
+func (uc *addressBookUsecase) Add(ctx context.Context, req *model.AddressBookRequest) (*model.AddressBook, error) {
+	name := tenant.Normalize(req.Name)
+	if !tenant.Valid(name) {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookNameNotValid)
+	}
+	if name == tenant.Default {
+		return nil, apperrors.NewAppError(apperrors.ErrAddressBookDuplicate)
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.AddressBookRepo.Add(ctx, &model.AddressBook{Name: name, CreatedAt: uc.now()})
+}
+
+func (uc *addressBookUsecase) Detail(ctx context.Context, name string) (*model.AddressBook, error) {
+	name = tenant.Normalize(name)
+	if name == tenant.Default {
+		return &model.AddressBook{Name: tenant.Default}, nil
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.AddressBookRepo.Detail(ctx, name)
+}
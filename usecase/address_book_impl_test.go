@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/tenant"
+	"contact-go/mocks"
+	"contact-go/model"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestAddressBookUsecase(repo *mocks.AddressBookRepository, now time.Time) *addressBookUsecase {
+	uc := NewAddressBookUsecase(repo, time.Second).(*addressBookUsecase)
+	uc.now = func() time.Time { return now }
+	return uc
+}
+
+func Test_addressBookUsecase_Add(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		req        *model.AddressBookRequest
+		beforeTest func(*mocks.AddressBookRepository)
+		want       *model.AddressBook
+		wantErr    string
+	}{
+		{
+			name: "success",
+			req:  &model.AddressBookRequest{Name: " Sales "},
+			beforeTest: func(repo *mocks.AddressBookRepository) {
+				book := &model.AddressBook{Name: "sales", CreatedAt: now}
+				repo.On("Add", mock.Anything, book).Return(book, nil)
+			},
+			want: &model.AddressBook{Name: "sales", CreatedAt: now},
+		},
+		{
+			name:    "name not valid",
+			req:     &model.AddressBookRequest{Name: "sales/2023"},
+			wantErr: apperrors.ErrAddressBookNameNotValid,
+		},
+		{
+			name:    "default",
+			req:     &model.AddressBookRequest{Name: "Default"},
+			wantErr: apperrors.ErrAddressBookDuplicate,
+		},
+		{
+			name: "failed",
+			req:  &model.AddressBookRequest{Name: "sales"},
+			beforeTest: func(repo *mocks.AddressBookRepository) {
+				repo.On("Add", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+			},
+			wantErr: assert.AnError.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewAddressBookRepository(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(repo)
+			}
+
+			got, err := newTestAddressBookUsecase(repo, now).Add(context.Background(), tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_addressBookUsecase_List(t *testing.T) {
+	repo := mocks.NewAddressBookRepository(t)
+	repo.On("List", mock.Anything).Return([]model.AddressBook{{Name: "sales"}}, nil)
+
+	got, err := newTestAddressBookUsecase(repo, time.Now()).List(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AddressBook{{Name: tenant.Default}, {Name: "sales"}}, got)
+
+	//* a bound caller only sees their own
+	repo.On("Detail", mock.Anything, "sales").Return(&model.AddressBook{Name: "sales"}, nil)
+	got, err = newTestAddressBookUsecase(repo, time.Now()).List(tenant.NewBoundContext(context.Background(), "sales"))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AddressBook{{Name: "sales"}}, got)
+}
+
+func Test_addressBookUsecase_Detail(t *testing.T) {
+	repo := mocks.NewAddressBookRepository(t)
+	repo.On("Detail", mock.Anything, "marketing").Return(nil, apperrors.NewAppError(apperrors.ErrAddressBookNotFound))
+
+	uc := newTestAddressBookUsecase(repo, time.Now())
+
+	//* the default address book is never looked up
+	got, err := uc.Detail(context.Background(), tenant.Default)
+	if assert.NoError(t, err) {
+		assert.Equal(t, tenant.Default, got.Name)
+	}
+
+	_, err = uc.Detail(context.Background(), "Marketing")
+	assert.EqualError(t, err, apperrors.ErrAddressBookNotFound)
+}
@@ -0,0 +1,18 @@
+//go:generate mockery --output=../mocks --name AddressBookUsecase
+
+package usecase
+
+import (
+	"contact-go/model"
+	"context"
+)
+
+// AddressBookUsecase manages the address books contacts are kept in.
+// The default address book is always there, and listed first.
+type AddressBookUsecase interface {
+	List(ctx context.Context) ([]model.AddressBook, error)
+	Add(ctx context.Context, req *model.AddressBookRequest) (*model.AddressBook, error)
+	// Detail returns the address book called name, failing with
+	// apperrors.ErrAddressBookNotFound when there is none.
+	Detail(ctx context.Context, name string) (*model.AddressBook, error)
+}
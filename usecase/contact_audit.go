@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"contact-go/helper/actor"
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"contact-go/repository"
+	"context"
+)
+
+// change runs fn, which makes a change of contacts and records it. With
+// a Transactor, fn gets a copy of uc on repositories of one transaction,
+// so that the change is stored along with its audit entries or not at
+// all. Otherwise fn gets uc itself.
+func (uc *contactUsecase) change(ctx context.Context, fn func(tx *contactUsecase) error) error {
+	if uc.Transactor == nil || uc.AuditRepo == nil {
+		return fn(uc)
+	}
+
+	return uc.Transactor.Transaction(ctx, func(contacts repository.ContactRepository, audit repository.AuditRepository) error {
+		tx := *uc
+		tx.ContactRepo, tx.AuditRepo, tx.Transactor = contacts, audit, nil
+		tx.inTransaction = true
+		return fn(&tx)
+	})
+}
+
+// record appends the change operation made to the contact id to the
+// audit log, by the actor ctx carries. Off a transaction, the change is
+// already stored by then, so a failure to record it is logged rather
+// than failing the change.
+func (uc *contactUsecase) record(ctx context.Context, operation string, id int64, before, after *model.Contact) error {
+	if uc.AuditRepo == nil {
+		return nil
+	}
+
+	_, err := uc.AuditRepo.Add(ctx, &model.AuditEntry{
+		ContactID: id,
+		Actor:     actor.FromContext(ctx),
+		Operation: operation,
+		Before:    before,
+		After:     after,
+		CreatedAt: uc.now(),
+	})
+	if err != nil && !uc.inTransaction {
+		if uc.Logger != nil {
+			uc.Logger.Error().Err(err).Int64("contact_id", id).Str("operation", operation).Msg("audit entry fail to store")
+		}
+		return nil
+	}
+	return err
+}
+
+// snapshot returns the contact id as it is before a change, for record
+// to keep. There is nothing to take while changes go unrecorded.
+func (uc *contactUsecase) snapshot(ctx context.Context, id int64) (*model.Contact, error) {
+	if uc.AuditRepo == nil {
+		return nil, nil
+	}
+	return uc.ContactRepo.Detail(ctx, id)
+}
+
+// trashSnapshot is snapshot for the contact id while it is in the trash.
+func (uc *contactUsecase) trashSnapshot(ctx context.Context, id int64) (*model.Contact, error) {
+	if uc.AuditRepo == nil {
+		return nil, nil
+	}
+
+	contact, err := uc.trashed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if contact == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+	return contact, nil
+}
+
+// trashed returns the contact id while it is in the trash, nil otherwise.
+func (uc *contactUsecase) trashed(ctx context.Context, id int64) (*model.Contact, error) {
+	contacts, _, err := uc.ContactRepo.List(ctx, &model.ContactQuery{Deleted: true, IDs: []int64{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+	return &contacts[0], nil
+}
+
+// batchSnapshots takes the snapshot of the contact each update and delete
+// of ops changes. A contact that is not there has none, and is left for
+// its operation to fail on.
+func (uc *contactUsecase) batchSnapshots(ctx context.Context, ops []model.ContactOperation) ([]*model.Contact, error) {
+	snapshots := make([]*model.Contact, len(ops))
+	if uc.AuditRepo == nil {
+		return snapshots, nil
+	}
+
+	for i, op := range ops {
+		if op.Op == model.BatchCreate {
+			continue
+		}
+
+		contact, err := uc.snapshot(ctx, op.ID)
+		if isAppError(err, apperrors.ErrContactNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshots[i] = contact
+	}
+	return snapshots, nil
+}
+
+// recordBatch records every operation of ops the repository stored.
+func (uc *contactUsecase) recordBatch(ctx context.Context, ops []model.ContactOperation, snapshots []*model.Contact, stored []model.ContactOperationResult) error {
+	for j, result := range stored {
+		if result.Err != nil {
+			continue
+		}
+
+		var err error
+		switch op := ops[j]; op.Op {
+		case model.BatchCreate:
+			err = uc.record(ctx, model.AuditAdd, result.Contact.ID, nil, result.Contact)
+		case model.BatchUpdate:
+			err = uc.record(ctx, model.AuditUpdate, op.ID, snapshots[j], result.Contact)
+		case model.BatchDelete:
+			err = uc.record(ctx, model.AuditDelete, op.ID, snapshots[j], nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// History pages through the audit log of the contact id, newest entry
+// first. A contact no longer stored keeps its history.
+func (uc *contactUsecase) History(ctx context.Context, id int64, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	if id <= 0 {
+		return nil, 0, apperrors.NewAppError(apperrors.ErrContactIdNotValid)
+	}
+	if query == nil {
+		query = new(model.AuditQuery)
+	}
+	if query.Limit < 0 || query.Limit > model.MaxContactLimit {
+		return nil, 0, apperrors.NewAppError(apperrors.ErrContactLimitNotValid)
+	}
+	if query.Offset < 0 {
+		return nil, 0, apperrors.NewAppError(apperrors.ErrContactOffsetNotValid)
+	}
+	if uc.AuditRepo == nil {
+		return nil, 0, nil
+	}
+
+	filtered := *query
+	filtered.ContactID = id
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.AuditRepo.List(ctx, &filtered)
+}
@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"contact-go/config"
+	"contact-go/config/db"
+	"contact-go/helper/actor"
+	"contact-go/helper/apperrors"
+	"contact-go/mocks"
+	"contact-go/model"
+	"contact-go/repository"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestAuditUsecase(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository, now time.Time) *contactUsecase {
+	uc := NewContactUsecase(contactRepo, nil, auditRepo, nil, time.Second, "US", 0, nil).(*contactUsecase)
+	uc.now = func() time.Time { return now }
+	return uc
+}
+
+func Test_contactUsecase_audit(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	stored := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", Version: 1}
+	updated := &model.Contact{ID: 1, Name: "Test Again", NoTelp: "+15555553232", UpdatedAt: now, Version: 2}
+
+	tests := []struct {
+		name       string
+		run        func(context.Context, ContactUsecase) error
+		beforeTest func(*mocks.ContactRepository)
+		want       *model.AuditEntry
+	}{
+		{
+			name: "add",
+			run: func(ctx context.Context, uc ContactUsecase) error {
+				_, err := uc.Add(ctx, &model.ContactRequest{Name: "Test", NoTelp: "555-555-3232", Force: true})
+				return err
+			},
+			beforeTest: func(repo *mocks.ContactRepository) {
+				repo.On("Add", mock.Anything, mock.Anything).Return(stored, nil)
+			},
+			want: &model.AuditEntry{ContactID: 1, Actor: "alice", Operation: model.AuditAdd, After: stored, CreatedAt: now},
+		},
+		{
+			name: "update",
+			run: func(ctx context.Context, uc ContactUsecase) error {
+				_, err := uc.Update(ctx, 1, &model.ContactRequest{Name: "Test Again", NoTelp: "555-555-3232"})
+				return err
+			},
+			beforeTest: func(repo *mocks.ContactRepository) {
+				repo.On("Detail", mock.Anything, int64(1)).Return(stored, nil)
+				repo.On("Update", mock.Anything, int64(1), mock.Anything).Return(updated, nil)
+			},
+			want: &model.AuditEntry{ContactID: 1, Actor: "alice", Operation: model.AuditUpdate, Before: stored, After: updated, CreatedAt: now},
+		},
+		{
+			name: "patch",
+			run: func(ctx context.Context, uc ContactUsecase) error {
+				_, err := uc.Patch(ctx, 1, &model.ContactRequest{Name: "Test Again"}, []string{"name"})
+				return err
+			},
+			beforeTest: func(repo *mocks.ContactRepository) {
+				repo.On("Detail", mock.Anything, int64(1)).Return(stored, nil)
+				repo.On("Patch", mock.Anything, int64(1), mock.Anything, []string{"name"}).Return(updated, nil)
+			},
+			want: &model.AuditEntry{ContactID: 1, Actor: "alice", Operation: model.AuditUpdate, Before: stored, After: updated, CreatedAt: now},
+		},
+		{
+			name: "delete",
+			run: func(ctx context.Context, uc ContactUsecase) error {
+				return uc.Delete(ctx, 1, 0)
+			},
+			beforeTest: func(repo *mocks.ContactRepository) {
+				repo.On("Detail", mock.Anything, int64(1)).Return(stored, nil)
+				repo.On("Delete", mock.Anything, int64(1), int64(0), now).Return(nil)
+			},
+			want: &model.AuditEntry{ContactID: 1, Actor: "alice", Operation: model.AuditDelete, Before: stored, CreatedAt: now},
+		},
+		{
+			name: "restore",
+			run: func(ctx context.Context, uc ContactUsecase) error {
+				_, err := uc.Restore(ctx, 1)
+				return err
+			},
+			beforeTest: func(repo *mocks.ContactRepository) {
+				repo.On("List", mock.Anything, &model.ContactQuery{Deleted: true, IDs: []int64{1}}).Return([]model.Contact{*stored}, int64(1), nil)
+				repo.On("Restore", mock.Anything, int64(1)).Return(stored, nil)
+			},
+			want: &model.AuditEntry{ContactID: 1, Actor: "alice", Operation: model.AuditRestore, Before: stored, After: stored, CreatedAt: now},
+		},
+		{
+			name: "failed change is not recorded",
+			run: func(ctx context.Context, uc ContactUsecase) error {
+				return uc.Delete(ctx, 1, 3)
+			},
+			beforeTest: func(repo *mocks.ContactRepository) {
+				repo.On("Detail", mock.Anything, int64(1)).Return(stored, nil)
+				repo.On("Delete", mock.Anything, int64(1), int64(3), now).Return(apperrors.NewAppError(apperrors.ErrContactVersion))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+			mockAuditRepo := mocks.NewAuditRepository(t)
+			tt.beforeTest(mockContactRepo)
+			if tt.want != nil {
+				mockAuditRepo.On("Add", mock.Anything, tt.want).Return(tt.want, nil)
+			}
+
+			uc := newTestAuditUsecase(mockContactRepo, mockAuditRepo, now)
+
+			err := tt.run(actor.NewContext(context.Background(), "alice"), uc)
+			assert.Equal(t, tt.want == nil, err != nil, "error = %v", err)
+		})
+	}
+}
+
+func Test_contactUsecase_audit_failure(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	stored := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", Version: 1}
+
+	mockContactRepo := mocks.NewContactRepository(t)
+	mockAuditRepo := mocks.NewAuditRepository(t)
+	mockContactRepo.On("Add", mock.Anything, mock.Anything).Return(stored, nil)
+	mockAuditRepo.On("Add", mock.Anything, mock.Anything).Return(nil, errors.New("disk full"))
+
+	uc := newTestAuditUsecase(mockContactRepo, mockAuditRepo, now)
+
+	//* off a transaction the contact is stored already, so the request is not failed
+	got, err := uc.Add(context.Background(), &model.ContactRequest{Name: "Test", NoTelp: "555-555-3232", Force: true})
+	assert.NoError(t, err)
+	assert.Equal(t, stored, got)
+}
+
+func Test_contactUsecase_audit_transaction(t *testing.T) {
+	cfg := new(config.Config)
+	cfg.Database.Path = filepath.Join(t.TempDir(), "contact.db")
+	sqliteDB, err := db.NewSqliteDatabase(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqliteDB.Close()
+
+	uc := NewContactUsecase(repository.NewContactSqliteRepository(sqliteDB), nil, repository.NewAuditSqliteRepository(sqliteDB), repository.NewSqliteTransactor(sqliteDB), time.Second, "US", 0, nil)
+	ctx := context.Background()
+
+	added, err := uc.Add(ctx, &model.ContactRequest{Name: "Reva", NoTelp: "555-555-3232"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, total, err := uc.History(ctx, added.ID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	_, err = uc.Batch(ctx, &model.ContactBatchRequest{Atomic: true, Operations: []model.ContactBatchOperation{
+		{Op: model.BatchCreate, Contact: &model.ContactRequest{Name: "Bagas", NoTelp: "555-555-9012"}},
+		{Op: model.BatchDelete, ID: 99},
+	}})
+	assert.EqualError(t, err, apperrors.ErrContactNotFound)
+
+	//* with the audit log gone, no change can be recorded, so none is stored
+	_, err = sqliteDB.Exec("DROP TABLE contact_audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = uc.Update(ctx, added.ID, &model.ContactRequest{Name: "Reva Again", NoTelp: "555-555-3232"})
+	assert.Error(t, err)
+	_, err = uc.Add(ctx, &model.ContactRequest{Name: "Tirta", NoTelp: "555-555-4545"})
+	assert.Error(t, err)
+	assert.Error(t, uc.Delete(ctx, added.ID, 0))
+
+	contacts, _, err := uc.List(ctx, nil)
+	if assert.NoError(t, err) && assert.Len(t, contacts, 1) {
+		assert.Equal(t, "Reva", contacts[0].Name)
+		assert.Equal(t, int64(1), contacts[0].Version)
+	}
+}
+
+func Test_contactUsecase_Merge_audit(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	first := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232"}
+	second := &model.Contact{ID: 2, Name: "Test", NoTelp: "+15555553233"}
+	merged := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", Phones: []model.Phone{{Type: "other", Number: "+15555553233"}}}
+
+	mockContactRepo := mocks.NewContactRepository(t)
+	mockAuditRepo := mocks.NewAuditRepository(t)
+	mockContactRepo.On("Detail", mock.Anything, int64(1)).Return(first, nil)
+	mockContactRepo.On("Detail", mock.Anything, int64(2)).Return(second, nil)
+	mockContactRepo.On("Merge", mock.Anything, int64(1), mock.Anything, []int64{2}).Return(merged, nil)
+	mockAuditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 1, Actor: actor.Anonymous, Operation: model.AuditMerge, Before: first, After: merged, CreatedAt: now}).Return(nil, nil)
+	mockAuditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 2, Actor: actor.Anonymous, Operation: model.AuditDelete, Before: second, CreatedAt: now}).Return(nil, nil)
+
+	uc := newTestAuditUsecase(mockContactRepo, mockAuditRepo, now)
+
+	got, err := uc.Merge(context.Background(), []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, merged, got)
+}
+
+func Test_contactUsecase_Batch_audit(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	created := &model.Contact{ID: 3, Name: "New", NoTelp: "+15555553232"}
+	stored := &model.Contact{ID: 1, Name: "Old", NoTelp: "+15555553233"}
+
+	mockContactRepo := mocks.NewContactRepository(t)
+	mockAuditRepo := mocks.NewAuditRepository(t)
+	mockContactRepo.On("Detail", mock.Anything, int64(1)).Return(stored, nil)
+	mockContactRepo.On("Detail", mock.Anything, int64(9)).Return(nil, apperrors.NewAppError(apperrors.ErrContactNotFound))
+	mockContactRepo.On("Batch", mock.Anything, mock.Anything, false).Return([]model.ContactOperationResult{
+		{Contact: created},
+		{},
+		{Err: apperrors.NewAppError(apperrors.ErrContactNotFound)},
+	}, nil)
+	mockAuditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 3, Actor: "cli", Operation: model.AuditAdd, After: created, CreatedAt: now}).Return(nil, nil)
+	mockAuditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 1, Actor: "cli", Operation: model.AuditDelete, Before: stored, CreatedAt: now}).Return(nil, nil)
+
+	uc := newTestAuditUsecase(mockContactRepo, mockAuditRepo, now)
+
+	_, err := uc.Batch(actor.NewContext(context.Background(), actor.CLI), &model.ContactBatchRequest{Operations: []model.ContactBatchOperation{
+		{Op: model.BatchCreate, Contact: &model.ContactRequest{Name: "New", NoTelp: "555-555-3232"}},
+		{Op: model.BatchDelete, ID: 1},
+		{Op: model.BatchDelete, ID: 9},
+	}})
+	assert.NoError(t, err)
+}
+
+func Test_contactUsecase_HistoryAfterPurge(t *testing.T) {
+	contactFile := filepath.Join(t.TempDir(), "contact.json")
+	if err := os.WriteFile(contactFile, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		contactRepo repository.ContactRepository
+		auditRepo   repository.AuditRepository
+	}{
+		{name: "memory", contactRepo: repository.NewContactRepository(), auditRepo: repository.NewAuditRepository()},
+		{name: "json", contactRepo: repository.NewContactJsonRepository(contactFile, 0), auditRepo: repository.NewAuditJsonRepository(filepath.Join(t.TempDir(), "audit.json"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+			uc := NewContactUsecase(tt.contactRepo, nil, tt.auditRepo, nil, time.Second, "US", 0, nil).(*contactUsecase)
+			uc.now = func() time.Time { return now }
+			ctx := context.Background()
+
+			purged, err := uc.Add(ctx, &model.ContactRequest{Name: "Purged", NoTelp: "555-555-3232"})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, uc.Delete(ctx, purged.ID, 0))
+			now = now.Add(time.Second)
+			count, err := uc.Purge(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+
+			added, err := uc.Add(ctx, &model.ContactRequest{Name: "Added", NoTelp: "555-555-4545"})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NotEqual(t, purged.ID, added.ID)
+
+			entries, total, err := uc.History(ctx, added.ID, &model.AuditQuery{Limit: 10})
+			if assert.NoError(t, err) && assert.Equal(t, int64(1), total) {
+				assert.Equal(t, model.AuditAdd, entries[0].Operation)
+				assert.Equal(t, "Added", entries[0].After.Name)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_History(t *testing.T) {
+	entries := []model.AuditEntry{{ID: 2, ContactID: 1, Actor: "cli", Operation: model.AuditUpdate}}
+
+	mockAuditRepo := mocks.NewAuditRepository(t)
+	mockAuditRepo.On("List", mock.Anything, &model.AuditQuery{ContactID: 1, Limit: 10}).Return(entries, int64(4), nil)
+
+	uc := NewContactUsecase(nil, nil, mockAuditRepo, nil, time.Second, "US", 0, nil)
+
+	got, total, err := uc.History(context.Background(), 1, &model.AuditQuery{Limit: 10})
+	if assert.NoError(t, err) {
+		assert.Equal(t, entries, got)
+		assert.Equal(t, int64(4), total)
+	}
+
+	_, _, err = uc.History(context.Background(), 0, nil)
+	assert.EqualError(t, err, apperrors.ErrContactIdNotValid)
+	_, _, err = uc.History(context.Background(), 1, &model.AuditQuery{Limit: model.MaxContactLimit + 1})
+	assert.EqualError(t, err, apperrors.ErrContactLimitNotValid)
+	_, _, err = uc.History(context.Background(), 1, &model.AuditQuery{Offset: -1})
+	assert.EqualError(t, err, apperrors.ErrContactOffsetNotValid)
+}
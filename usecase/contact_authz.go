@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"contact-go/helper/actor"
+	"contact-go/helper/apperrors"
+	"contact-go/helper/auth"
+	"contact-go/helper/rbac"
+	"contact-go/model"
+	"context"
+)
+
+// authorizedContactUsecase lets a call through to the usecase it wraps
+// only when the policy gives its caller every permission the call needs.
+type authorizedContactUsecase struct {
+	usecase ContactUsecase
+	policy  *rbac.Policy
+}
+
+// NewAuthorizedContactUsecase checks every call to uc against policy.
+// The caller is the principal the http api authenticated, by their ID,
+// or else the actor of the context, which is how the CLI names itself. A
+// call the caller may not make fails with apperrors.ErrPermissionDenied.
+func NewAuthorizedContactUsecase(uc ContactUsecase, policy *rbac.Policy) ContactUsecase {
+	return &authorizedContactUsecase{usecase: uc, policy: policy}
+}
+
+func (uc *authorizedContactUsecase) authorize(ctx context.Context, permissions ...string) error {
+	return authorize(ctx, uc.policy, permissions...)
+}
+
+// authorize fails with apperrors.ErrPermissionDenied unless policy gives
+// the caller of ctx every one of permissions.
+func authorize(ctx context.Context, policy *rbac.Policy, permissions ...string) error {
+	name, roles := actor.FromContext(ctx), []string(nil)
+	if principal, ok := auth.FromContext(ctx); ok {
+		name, roles = principal.ID(), principal.Roles
+	}
+
+	for _, permission := range permissions {
+		if !policy.Allowed(name, roles, permission) {
+			return apperrors.NewAppError(apperrors.ErrPermissionDenied)
+		}
+	}
+	return nil
+}
+
+func (uc *authorizedContactUsecase) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	if err := uc.authorize(ctx, rbac.PermissionRead); err != nil {
+		return nil, 0, err
+	}
+	return uc.usecase.List(ctx, query)
+}
+
+func (uc *authorizedContactUsecase) Add(ctx context.Context, req *model.ContactRequest) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Add(ctx, req)
+}
+
+func (uc *authorizedContactUsecase) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Detail(ctx, id)
+}
+
+func (uc *authorizedContactUsecase) Update(ctx context.Context, id int64, req *model.ContactRequest) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Update(ctx, id, req)
+}
+
+func (uc *authorizedContactUsecase) Patch(ctx context.Context, id int64, req *model.ContactRequest, fields []string) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Patch(ctx, id, req, fields)
+}
+
+func (uc *authorizedContactUsecase) Delete(ctx context.Context, id int64, version int64) error {
+	if err := uc.authorize(ctx, rbac.PermissionDelete); err != nil {
+		return err
+	}
+	return uc.usecase.Delete(ctx, id, version)
+}
+
+func (uc *authorizedContactUsecase) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Restore(ctx, id)
+}
+
+func (uc *authorizedContactUsecase) Purge(ctx context.Context) (int64, error) {
+	if err := uc.authorize(ctx, rbac.PermissionPurge); err != nil {
+		return 0, err
+	}
+	return uc.usecase.Purge(ctx)
+}
+
+func (uc *authorizedContactUsecase) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Search(ctx, query)
+}
+
+func (uc *authorizedContactUsecase) Validate(ctx context.Context, req *model.ContactRequest) error {
+	if err := uc.authorize(ctx, rbac.PermissionRead); err != nil {
+		return err
+	}
+	return uc.usecase.Validate(ctx, req)
+}
+
+func (uc *authorizedContactUsecase) FindByPhone(ctx context.Context, noTelp string) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.FindByPhone(ctx, noTelp)
+}
+
+func (uc *authorizedContactUsecase) Duplicates(ctx context.Context) ([][]model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Duplicates(ctx)
+}
+
+// Merge needs delete as well as write, as it moves every contact but
+// the one kept to the trash.
+func (uc *authorizedContactUsecase) Merge(ctx context.Context, ids []int64) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionWrite, rbac.PermissionDelete); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Merge(ctx, ids)
+}
+
+// Batch needs write, even for no operations at all, and the permissions
+// of every operation of req, so that a batch cannot do what the caller
+// may not do one contact at a time.
+func (uc *authorizedContactUsecase) Batch(ctx context.Context, req *model.ContactBatchRequest) ([]model.ContactBatchResult, error) {
+	permissions := []string{rbac.PermissionWrite}
+	if req != nil {
+		for _, op := range req.Operations {
+			if op.Op == model.BatchDelete {
+				permissions = append(permissions, rbac.PermissionDelete)
+			}
+		}
+	}
+
+	if err := uc.authorize(ctx, permissions...); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Batch(ctx, req)
+}
+
+func (uc *authorizedContactUsecase) History(ctx context.Context, id int64, query *model.AuditQuery) ([]model.AuditEntry, int64, error) {
+	if err := uc.authorize(ctx, rbac.PermissionRead); err != nil {
+		return nil, 0, err
+	}
+	return uc.usecase.History(ctx, id, query)
+}
+
+// Revert and Undo need delete as well as write, as taking back an add
+// deletes the contact.
+func (uc *authorizedContactUsecase) Revert(ctx context.Context, id int64, revision int64) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionWrite, rbac.PermissionDelete); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Revert(ctx, id, revision)
+}
+
+func (uc *authorizedContactUsecase) Undo(ctx context.Context, revision int64) (*model.Contact, error) {
+	if err := uc.authorize(ctx, rbac.PermissionWrite, rbac.PermissionDelete); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Undo(ctx, revision)
+}
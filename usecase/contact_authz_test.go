@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"contact-go/helper/actor"
+	"contact-go/helper/apperrors"
+	"contact-go/helper/auth"
+	"contact-go/helper/rbac"
+	"contact-go/mocks"
+	"contact-go/model"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPolicy(t *testing.T) *rbac.Policy {
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"intern":  {rbac.PermissionRead},
+		"support": {rbac.PermissionRead, rbac.PermissionWrite},
+		"admin":   {rbac.PermissionRead, rbac.PermissionWrite, rbac.PermissionDelete, rbac.PermissionPurge, rbac.PermissionAdmin},
+	}, map[string][]string{
+		actor.CLI:         {"admin"},
+		"apikey:helpdesk": {"support"},
+	}, "intern")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func Test_authorizedContactUsecase(t *testing.T) {
+	contact := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232"}
+	deleteBatch := &model.ContactBatchRequest{Operations: []model.ContactBatchOperation{
+		{Op: model.BatchCreate, Contact: &model.ContactRequest{Name: "Test", NoTelp: "+15555553232"}},
+		{Op: model.BatchDelete, ID: 2},
+	}}
+	support := auth.NewContext(context.Background(), &auth.Principal{Name: "helpdesk", Method: auth.MethodAPIKey})
+	intern := auth.NewContext(context.Background(), &auth.Principal{Name: "bob", Method: auth.MethodJWT})
+	adminToken := auth.NewContext(context.Background(), &auth.Principal{Name: "bob", Method: auth.MethodJWT, Roles: []string{"admin"}})
+	cli := actor.NewContext(context.Background(), actor.CLI)
+	keyNamedCLI := auth.NewContext(actor.NewContext(context.Background(), "apikey:cli"), &auth.Principal{Name: actor.CLI, Method: auth.MethodAPIKey})
+	tokenOfHelpdesk := auth.NewContext(context.Background(), &auth.Principal{Name: "helpdesk", Method: auth.MethodJWT})
+
+	tests := []struct {
+		name    string
+		call    func(ContactUsecase) error
+		mock    func(*mocks.ContactUsecase)
+		wantErr string
+	}{
+		{
+			name: "intern reads",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Detail(intern, 1)
+				return err
+			},
+			mock: func(m *mocks.ContactUsecase) {
+				m.On("Detail", intern, int64(1)).Return(contact, nil)
+			},
+		},
+		{
+			name: "intern does not add",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Add(intern, &model.ContactRequest{Name: "Test"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "support updates",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Update(support, 1, &model.ContactRequest{Name: "Test"})
+				return err
+			},
+			mock: func(m *mocks.ContactUsecase) {
+				m.On("Update", support, int64(1), &model.ContactRequest{Name: "Test"}).Return(contact, nil)
+			},
+		},
+		{
+			name: "support does not delete",
+			call: func(uc ContactUsecase) error {
+				return uc.Delete(support, 1, 0)
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "support does not merge",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Merge(support, []int64{1, 2})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "support does not batch a delete",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Batch(support, deleteBatch)
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "intern does not send an empty batch",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Batch(intern, &model.ContactBatchRequest{})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "admin from the token batches a delete",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Batch(adminToken, deleteBatch)
+				return err
+			},
+			mock: func(m *mocks.ContactUsecase) {
+				m.On("Batch", adminToken, deleteBatch).Return(nil, nil)
+			},
+		},
+		{
+			name: "support does not purge",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Purge(support)
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "cli purges",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Purge(cli)
+				return err
+			},
+			mock: func(m *mocks.ContactUsecase) {
+				m.On("Purge", cli).Return(int64(3), nil)
+			},
+		},
+		{
+			name: "api key named cli is not the cli",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Purge(keyNamedCLI)
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "token of the api key name is not the api key",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Update(tokenOfHelpdesk, 1, &model.ContactRequest{Name: "Test"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "anonymous has the default role",
+			call: func(uc ContactUsecase) error {
+				_, err := uc.Undo(context.Background(), 5)
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mocks.ContactUsecase)
+			if tt.mock != nil {
+				tt.mock(m)
+			}
+
+			err := tt.call(NewAuthorizedContactUsecase(m, newTestPolicy(t)))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
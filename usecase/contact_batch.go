@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+	"errors"
+	"strings"
+)
+
+// errBatchFailed rolls back the transaction of an atomic batch with a
+// failed operation.
+var errBatchFailed = errors.New("batch operation failed")
+
+// newOperation checks op and builds what the repository stores for it.
+func (uc *contactUsecase) newOperation(op *model.ContactBatchOperation) (*model.ContactOperation, error) {
+	operation := &model.ContactOperation{Op: op.Op, ID: op.ID, Version: op.Version}
+
+	switch op.Op {
+	case model.BatchCreate, model.BatchUpdate:
+		if op.Contact == nil {
+			return nil, apperrors.NewAppError(apperrors.ErrBatchNotValid)
+		}
+		if strings.TrimSpace(op.Contact.Name) == "" {
+			return nil, apperrors.NewAppError(apperrors.ErrContactNameNotValid)
+		}
+		if strings.TrimSpace(op.Contact.NoTelp) == "" {
+			return nil, apperrors.NewAppError(apperrors.ErrContactNoTelpNotValid)
+		}
+
+		contact, err := newContact(op.Contact, uc.Region)
+		if err != nil {
+			return nil, err
+		}
+		contact.UpdatedAt = uc.now()
+		operation.Contact = contact
+
+		if op.Op == model.BatchCreate {
+			contact.CreatedAt = contact.UpdatedAt
+			operation.ID, operation.Version = 0, 0
+			return operation, nil
+		}
+		contact.Version = op.Version
+	case model.BatchDelete:
+		operation.DeletedAt = uc.now()
+	default:
+		return nil, apperrors.NewAppError(apperrors.ErrBatchNotValid)
+	}
+
+	if op.ID <= 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactIdNotValid)
+	}
+	return operation, nil
+}
+
+// Batch checks every operation of req before handing the valid ones to
+// the repository in one go. An atomic batch with an invalid operation
+// is not stored at all. Creates are not checked for duplicates, as a
+// batch usually mirrors contacts kept elsewhere, but a contact is only
+// updated or deleted once per batch, its audit taken before the batch.
+//
+// The error is that of the failed operation when an atomic batch fails,
+// with the results telling which one it was.
+func (uc *contactUsecase) Batch(ctx context.Context, req *model.ContactBatchRequest) ([]model.ContactBatchResult, error) {
+	if len(req.Operations) == 0 || len(req.Operations) > model.MaxBatchOperations {
+		return nil, apperrors.NewAppError(apperrors.ErrBatchNotValid)
+	}
+
+	results := make([]model.ContactBatchResult, len(req.Operations))
+	errs := make([]error, len(req.Operations))
+	ops := make([]model.ContactOperation, 0, len(req.Operations))
+	//* index of each operation sent to the repository, in results
+	sent := make([]int, 0, len(req.Operations))
+	changed := make(map[int64]bool, len(req.Operations))
+
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		results[i] = model.ContactBatchResult{Operation: i + 1, Op: op.Op, ID: op.ID}
+
+		operation, err := uc.newOperation(op)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if operation.Op != model.BatchCreate {
+			if changed[operation.ID] {
+				errs[i] = apperrors.NewAppError(apperrors.ErrBatchIdDuplicate)
+				continue
+			}
+			changed[operation.ID] = true
+		}
+		ops = append(ops, *operation)
+		sent = append(sent, i)
+	}
+
+	failed := -1
+	for i, err := range errs {
+		if err != nil {
+			failed = i
+			break
+		}
+	}
+
+	if failed < 0 || !req.Atomic {
+		ctx, cancel := uc.withTimeout(ctx)
+		defer cancel()
+
+		err := uc.change(ctx, func(tx *contactUsecase) error {
+			snapshots, err := tx.batchSnapshots(ctx, ops)
+			if err != nil {
+				return err
+			}
+
+			stored, err := tx.ContactRepo.Batch(ctx, ops, req.Atomic)
+			if err != nil {
+				return err
+			}
+
+			for j, result := range stored {
+				i := sent[j]
+				errs[i] = result.Err
+				if result.Contact != nil {
+					results[i].ID = result.Contact.ID
+					results[i].Contact = result.Contact
+				}
+				if result.Err != nil && failed < 0 {
+					failed = i
+				}
+			}
+
+			// an atomic batch that failed stored nothing, nor does the
+			// transaction it may have run on
+			if req.Atomic && failed >= 0 {
+				return errBatchFailed
+			}
+			return tx.recordBatch(ctx, ops, snapshots, stored)
+		})
+		if err != nil && !errors.Is(err, errBatchFailed) {
+			return nil, err
+		}
+	}
+
+	if req.Atomic && failed >= 0 {
+		for i := range results {
+			results[i].ID = req.Operations[i].ID
+			results[i].Contact = nil
+			results[i].Error = apperrors.ErrBatchAborted
+		}
+		results[failed].Error = errs[failed].Error()
+		return results, errs[failed]
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+	return results, nil
+}
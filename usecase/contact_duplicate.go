@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/fuzzy"
+	"contact-go/model"
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateError is returned by Add when the new contact looks like
+// the stored contacts in Candidates.
+type DuplicateError struct {
+	Candidates []model.Contact
+}
+
+func (e *DuplicateError) Error() string {
+	return apperrors.ErrContactDuplicate
+}
+
+// Unwrap lets apperrors.HandleAppError answer it as a conflict.
+func (e *DuplicateError) Unwrap() error {
+	return apperrors.NewAppError(apperrors.ErrContactDuplicate)
+}
+
+// normalizeName folds case, punctuation and spacing,
+// so "Jane_Smith" and "jane  smith" compare equal.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// similarNames reports whether two normalized names likely belong to
+// the same person: a few typos apart, or the same words in another order.
+func similarNames(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+
+	length := len([]rune(a))
+	if l := len([]rune(b)); l < length {
+		length = l
+	}
+	if fuzzy.Distance(a, b) <= fuzzy.MaxTypos(length) {
+		return true
+	}
+
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) < 2 || len(wordsA) != len(wordsB) {
+		return false
+	}
+	sort.Strings(wordsA)
+	sort.Strings(wordsB)
+	return strings.Join(wordsA, " ") == strings.Join(wordsB, " ")
+}
+
+// contactNumbers returns the main number of contact and its other numbers.
+func contactNumbers(contact *model.Contact) []string {
+	numbers := []string{contact.NoTelp}
+	for _, p := range contact.Phones {
+		numbers = append(numbers, p.Number)
+	}
+	return numbers
+}
+
+// isDuplicate reports whether a and b share a number or have similar names.
+func isDuplicate(a, b *model.Contact) bool {
+	for _, x := range contactNumbers(a) {
+		for _, y := range contactNumbers(b) {
+			if x != "" && x == y {
+				return true
+			}
+		}
+	}
+	return similarNames(normalizeName(a.Name), normalizeName(b.Name))
+}
+
+// nameQueries returns what to search the store for to find contacts
+// named like name: the whole name, and each of its words when it has
+// several, so reordered names are found too.
+func nameQueries(name string) []string {
+	normalized := normalizeName(name)
+	if normalized == "" {
+		return nil
+	}
+
+	queries := []string{normalized}
+	words := strings.Fields(normalized)
+	if len(words) < 2 {
+		return queries
+	}
+
+	seen := map[string]bool{normalized: true}
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			queries = append(queries, word)
+		}
+	}
+	return queries
+}
+
+// findDuplicates returns the stored contacts that contact looks like.
+// Candidates are those stored under one of its numbers and those
+// the store finds searching for its name, so how far a typo in the
+// name is tolerated depends on the store's search.
+func (uc *contactUsecase) findDuplicates(ctx context.Context, contact *model.Contact) ([]model.Contact, error) {
+	var candidates []model.Contact
+	for _, number := range contactNumbers(contact) {
+		contacts, _, err := uc.ContactRepo.List(ctx, &model.ContactQuery{NoTelp: number})
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, contacts...)
+	}
+
+	for _, query := range nameQueries(contact.Name) {
+		contacts, err := uc.ContactRepo.Search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, contacts...)
+	}
+
+	var duplicates []model.Contact
+	seen := make(map[int64]bool)
+	for i := range candidates {
+		if seen[candidates[i].ID] || !isDuplicate(contact, &candidates[i]) {
+			continue
+		}
+		seen[candidates[i].ID] = true
+		duplicates = append(duplicates, candidates[i])
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].ID < duplicates[j].ID
+	})
+	return duplicates, nil
+}
+
+const (
+	// maxDuplicateGroups is how many groups Duplicates returns at most.
+	maxDuplicateGroups = 100
+	// maxDuplicateBlock is how many contacts a block of names may hold
+	// for clusterDuplicates to compare them, so a name everyone shares
+	// does not make it compare everyone.
+	maxDuplicateBlock = 1000
+)
+
+// clusterDuplicates groups contacts that are duplicates of each other,
+// directly or through another contact of the group. Contacts without
+// a duplicate are left out, and each group keeps the order of contacts.
+// Only the first maxDuplicateGroups groups are returned.
+func clusterDuplicates(contacts []model.Contact) [][]model.Contact {
+	parent := make([]int, len(contacts))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		if ri < rj {
+			parent[rj] = ri
+		} else {
+			parent[ri] = rj
+		}
+	}
+
+	byNumber := make(map[string]int)
+	names := make([]string, len(contacts))
+	for i := range contacts {
+		for _, number := range contactNumbers(&contacts[i]) {
+			if number == "" {
+				continue
+			}
+			if j, ok := byNumber[number]; ok {
+				union(i, j)
+			} else {
+				byNumber[number] = i
+			}
+		}
+		names[i] = normalizeName(contacts[i].Name)
+	}
+
+	for _, block := range nameBlocks(names) {
+		if len(block) > maxDuplicateBlock {
+			continue
+		}
+		for x, i := range block {
+			for _, j := range block[x+1:] {
+				if find(i) != find(j) && similarNames(names[i], names[j]) {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]model.Contact)
+	for i := range contacts {
+		root := find(i)
+		members[root] = append(members[root], contacts[i])
+	}
+
+	groups := [][]model.Contact{}
+	for i := range contacts {
+		if group := members[i]; find(i) == i && len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) > maxDuplicateGroups {
+		groups = groups[:maxDuplicateGroups]
+	}
+	return groups
+}
+
+// nameBlocks returns the indexes of names that share a word, or the
+// first or last three letters, which similar names all but always do.
+func nameBlocks(names []string) map[string][]int {
+	blocks := make(map[string][]int)
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+
+		keys := map[string]bool{}
+		for _, word := range strings.Fields(name) {
+			keys["word:"+word] = true
+		}
+		runes := []rune(name)
+		if len(runes) > 3 {
+			keys["prefix:"+string(runes[:3])] = true
+			keys["suffix:"+string(runes[len(runes)-3:])] = true
+		}
+
+		for key := range keys {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+	return blocks
+}
+
+// mergeDetails combines contacts into the first of them. Its name,
+// main number and other single details are kept, falling back on
+// those of the rest when empty; numbers, emails and addresses are
+// collected from all, and the main numbers of the rest are kept as
+// other phones. Distinct notes are joined.
+func mergeDetails(contacts []model.Contact) *model.Contact {
+	merged := contacts[0]
+	merged.Phones = nil
+	merged.Emails = nil
+	merged.Addresses = nil
+
+	numbers := map[string]bool{merged.NoTelp: true}
+	emails := make(map[string]bool)
+	addresses := make(map[model.Address]bool)
+	seenNotes := make(map[string]bool)
+	var notes []string
+
+	for i, contact := range contacts {
+		if i > 0 && !numbers[contact.NoTelp] {
+			numbers[contact.NoTelp] = true
+			merged.Phones = append(merged.Phones, model.Phone{Type: model.TypeOther, Number: contact.NoTelp, Raw: contact.NoTelpRaw})
+		}
+		for _, p := range contact.Phones {
+			if !numbers[p.Number] {
+				numbers[p.Number] = true
+				merged.Phones = append(merged.Phones, p)
+			}
+		}
+
+		for _, email := range contact.Emails {
+			if key := strings.ToLower(email.Address); !emails[key] {
+				emails[key] = true
+				merged.Emails = append(merged.Emails, email)
+			}
+		}
+
+		for _, address := range contact.Addresses {
+			if !addresses[address] {
+				addresses[address] = true
+				merged.Addresses = append(merged.Addresses, address)
+			}
+		}
+
+		if merged.Name == "" {
+			merged.Name = contact.Name
+		}
+		if merged.Company == "" {
+			merged.Company = contact.Company
+		}
+		if merged.JobTitle == "" {
+			merged.JobTitle = contact.JobTitle
+		}
+		if merged.Birthday == "" {
+			merged.Birthday = contact.Birthday
+		}
+
+		if note := strings.TrimSpace(contact.Notes); note != "" && !seenNotes[note] {
+			seenNotes[note] = true
+			notes = append(notes, note)
+		}
+	}
+
+	merged.Notes = strings.Join(notes, "\n\n")
+	return &merged
+}
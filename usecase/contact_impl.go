@@ -1,44 +1,472 @@
 package usecase
 
 import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/logger"
+	"contact-go/helper/phone"
 	"contact-go/model"
 	"contact-go/repository"
+	"context"
+	"strings"
+	"time"
 )
 
 type contactUsecase struct {
 	ContactRepo repository.ContactRepository
+	GroupRepo   repository.GroupRepository
+	AuditRepo   repository.AuditRepository
+	Transactor  repository.Transactor
+	Timeout     time.Duration
+	Region      string
+	Retention   time.Duration
+	Logger      *logger.Logger
+
+	now func() time.Time
+	// inTransaction is set on the copy change runs a change with on a
+	// transaction of Transactor.
+	inTransaction bool
 }
 
-func NewContactUsecase(contactRepo repository.ContactRepository) ContactUsecase {
+// NewContactUsecase bounds every repository call by timeout,
+// on top of whatever deadline the caller's context already carries.
+// A zero timeout leaves the caller's context untouched. Phone numbers
+// without a country calling code are read as numbers of region.
+// Purge removes contacts kept in the trash for longer than retention.
+// groupRepo holds the groups List filters contacts by, and every change
+// is recorded in auditRepo unless it is nil, on a transaction of
+// transactor along with the change where there is one. Without one, an
+// audit entry that fails to be stored is logged to logger, if any.
+func NewContactUsecase(contactRepo repository.ContactRepository, groupRepo repository.GroupRepository, auditRepo repository.AuditRepository, transactor repository.Transactor, timeout time.Duration, region string, retention time.Duration, logger *logger.Logger) ContactUsecase {
 	return &contactUsecase{
 		ContactRepo: contactRepo,
+		GroupRepo:   groupRepo,
+		AuditRepo:   auditRepo,
+		Transactor:  transactor,
+		Timeout:     timeout,
+		Region:      region,
+		Retention:   retention,
+		Logger:      logger,
+		now:         now,
+	}
+}
+
+// now is the clock stamping CreatedAt, UpdatedAt and DeletedAt, to the second
+// since that is all the SQL backends keep.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (uc *contactUsecase) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if uc.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, uc.Timeout)
+}
+
+func (uc *contactUsecase) List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error) {
+	if query == nil {
+		query = new(model.ContactQuery)
+	}
+
+	if _, ok := model.ContactSortFields[query.SortBy]; query.SortBy != "" && !ok {
+		return nil, 0, apperrors.NewAppError(apperrors.ErrContactSortNotValid)
+	}
+
+	switch strings.ToLower(query.Order) {
+	case "", model.SortAsc, model.SortDesc:
+	default:
+		return nil, 0, apperrors.NewAppError(apperrors.ErrContactOrderNotValid)
+	}
+
+	if query.Limit < 0 || query.Limit > model.MaxContactLimit {
+		return nil, 0, apperrors.NewAppError(apperrors.ErrContactLimitNotValid)
+	}
+
+	if query.Offset < 0 {
+		return nil, 0, apperrors.NewAppError(apperrors.ErrContactOffsetNotValid)
+	}
+
+	// a whole number is matched in the form it is stored in,
+	// anything shorter is left to match as it is
+	if noTelp, err := phone.Normalize(query.NoTelp, uc.Region); err == nil {
+		filtered := *query
+		filtered.NoTelp = noTelp
+		query = &filtered
+	}
+
+	if query.Group < 0 {
+		return nil, 0, apperrors.NewAppError(apperrors.ErrGroupIdNotValid)
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	if query.Group != 0 || query.Tag != "" {
+		ids, err := uc.groupMembers(ctx, query)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(ids) == 0 {
+			return nil, 0, nil
+		}
+
+		filtered := *query
+		filtered.IDs = ids
+		query = &filtered
+	}
+
+	return uc.ContactRepo.List(ctx, query)
+}
+
+// groupMembers returns the ids of the contacts in the group query.Group
+// and in the one tagged query.Tag, in both when both are set. A tag no
+// group is called by matches no contact.
+func (uc *contactUsecase) groupMembers(ctx context.Context, query *model.ContactQuery) ([]int64, error) {
+	var groupIDs []int64
+	if query.Group != 0 {
+		groupIDs = append(groupIDs, query.Group)
+	}
+	if tag := strings.TrimSpace(query.Tag); tag != "" {
+		group, err := uc.GroupRepo.FindByName(ctx, tag)
+		if isAppError(err, apperrors.ErrGroupNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		groupIDs = append(groupIDs, group.ID)
+	}
+
+	var ids []int64
+	for i, groupID := range groupIDs {
+		members, err := uc.GroupRepo.Members(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			ids = members
+			continue
+		}
+
+		inGroup := make(map[int64]bool, len(members))
+		for _, id := range members {
+			inGroup[id] = true
+		}
+		kept := ids[:0]
+		for _, id := range ids {
+			if inGroup[id] {
+				kept = append(kept, id)
+			}
+		}
+		ids = kept
+	}
+	return ids, nil
+}
+
+func (uc *contactUsecase) Add(ctx context.Context, req *model.ContactRequest) (*model.Contact, error) {
+	contact, err := newContact(req, uc.Region)
+	if err != nil {
+		return nil, err
+	}
+	contact.CreatedAt = uc.now()
+	contact.UpdatedAt = contact.CreatedAt
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	if !req.Force {
+		candidates, err := uc.findDuplicates(ctx, contact)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) > 0 {
+			return nil, &DuplicateError{Candidates: candidates}
+		}
+	}
+
+	var added *model.Contact
+	err = uc.change(ctx, func(tx *contactUsecase) error {
+		added, err = tx.ContactRepo.Add(ctx, contact)
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, model.AuditAdd, added.ID, nil, added)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+func (uc *contactUsecase) Detail(ctx context.Context, id int64) (*model.Contact, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.ContactRepo.Detail(ctx, id)
+}
+
+func (uc *contactUsecase) Update(ctx context.Context, id int64, req *model.ContactRequest) (*model.Contact, error) {
+	contact, err := newContact(req, uc.Region)
+	if err != nil {
+		return nil, err
+	}
+	contact.UpdatedAt = uc.now()
+	contact.Version = req.Version
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	var updated *model.Contact
+	err = uc.change(ctx, func(tx *contactUsecase) error {
+		before, err := tx.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = tx.ContactRepo.Update(ctx, id, contact)
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, model.AuditUpdate, id, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Patch sets only the fields of the contact id named in fields, taking
+// their values from req, and checks them the way Update checks a whole
+// request. The fields left out keep their stored values.
+func (uc *contactUsecase) Patch(ctx context.Context, id int64, req *model.ContactRequest, fields []string) (*model.Contact, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	var patched *model.Contact
+	err := uc.change(ctx, func(tx *contactUsecase) error {
+		stored, err := tx.ContactRepo.Detail(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		contact, err := tx.patchContact(stored, req, fields)
+		if err != nil {
+			return err
+		}
+
+		patched, err = tx.ContactRepo.Patch(ctx, id, contact, fields)
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, model.AuditUpdate, id, stored, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// patchContact returns the contact Patch stores, stored with the fields
+// req sets.
+func (uc *contactUsecase) patchContact(stored *model.Contact, req *model.ContactRequest, fields []string) (*model.Contact, error) {
+	patch := &model.ContactRequest{Name: stored.Name, NoTelp: stored.NoTelp}
+	for _, field := range fields {
+		switch field {
+		case "name":
+			patch.Name = req.Name
+		case "no_telp":
+			patch.NoTelp = req.NoTelp
+		case "phones":
+			patch.Phones = req.Phones
+		case "emails":
+			patch.Emails = req.Emails
+		case "addresses":
+			patch.Addresses = req.Addresses
+		case "company":
+			patch.Company = req.Company
+		case "job_title":
+			patch.JobTitle = req.JobTitle
+		case "birthday":
+			patch.Birthday = req.Birthday
+		case "notes":
+			patch.Notes = req.Notes
+		default:
+			return nil, apperrors.NewAppError(apperrors.ErrContactPatchNotValid)
+		}
+	}
+
+	if strings.TrimSpace(patch.Name) == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNameNotValid)
+	}
+	if strings.TrimSpace(patch.NoTelp) == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNoTelpNotValid)
+	}
+
+	contact, err := newContact(patch, uc.Region)
+	if err != nil {
+		return nil, err
+	}
+	contact.UpdatedAt = uc.now()
+	contact.Version = req.Version
+	return contact, nil
+}
+
+func (uc *contactUsecase) Delete(ctx context.Context, id int64, version int64) error {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.change(ctx, func(tx *contactUsecase) error {
+		before, err := tx.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.ContactRepo.Delete(ctx, id, version, tx.now())
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, model.AuditDelete, id, before, nil)
+	})
+}
+
+func (uc *contactUsecase) Restore(ctx context.Context, id int64) (*model.Contact, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	var restored *model.Contact
+	err := uc.change(ctx, func(tx *contactUsecase) error {
+		before, err := tx.trashSnapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		restored, err = tx.ContactRepo.Restore(ctx, id)
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, model.AuditRestore, id, before, restored)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return restored, nil
 }
 
-func (uc *contactUsecase) List() ([]model.Contact, error) {
-	return uc.ContactRepo.List()
+func (uc *contactUsecase) Purge(ctx context.Context) (int64, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.ContactRepo.Purge(ctx, uc.now().Add(-uc.Retention))
 }
 
-func (uc *contactUsecase) Add(req *model.ContactRequest) (*model.Contact, error) {
-	contact := model.Contact{
-		Name:   req.Name,
-		NoTelp: req.NoTelp,
+func (uc *contactUsecase) Search(ctx context.Context, query string) ([]model.Contact, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrContactQueryNotValid)
 	}
-	return uc.ContactRepo.Add(&contact)
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.ContactRepo.Search(ctx, query)
+}
+
+// Validate checks req the way Add and Update do, without storing it.
+func (uc *contactUsecase) Validate(ctx context.Context, req *model.ContactRequest) error {
+	_, err := newContact(req, uc.Region)
+	return err
 }
 
-func (uc *contactUsecase) Detail(id int64) (*model.Contact, error) {
-	return uc.ContactRepo.Detail(id)
+// FindByPhone returns the contact whose main number is noTelp,
+// compared in E.164.
+func (uc *contactUsecase) FindByPhone(ctx context.Context, noTelp string) (*model.Contact, error) {
+	noTelp, err := phone.Normalize(noTelp, uc.Region)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNumberNotValid)
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	contacts, _, err := uc.ContactRepo.List(ctx, &model.ContactQuery{NoTelp: noTelp})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range contacts {
+		if contacts[i].NoTelp == noTelp {
+			return &contacts[i], nil
+		}
+	}
+	return nil, apperrors.NewAppError(apperrors.ErrContactNotFound)
 }
 
-func (uc *contactUsecase) Update(id int64, req *model.ContactRequest) (*model.Contact, error) {
-	contact := model.Contact{
-		Name:   req.Name,
-		NoTelp: req.NoTelp,
+// Duplicates returns the groups of stored contacts that look like
+// each other, the way Add tells a new contact from those stored, up to
+// maxDuplicateGroups of them.
+func (uc *contactUsecase) Duplicates(ctx context.Context) ([][]model.Contact, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	contacts, _, err := uc.ContactRepo.List(ctx, &model.ContactQuery{})
+	if err != nil {
+		return nil, err
 	}
-	return uc.ContactRepo.Update(id, &contact)
+
+	return clusterDuplicates(contacts), nil
 }
 
-func (uc *contactUsecase) Delete(id int64) error {
-	return uc.ContactRepo.Delete(id)
+// Merge combines the contacts ids into the first of them and deletes
+// the rest, in one step.
+func (uc *contactUsecase) Merge(ctx context.Context, ids []int64) (*model.Contact, error) {
+	if len(ids) < 2 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactMergeNotValid)
+	}
+
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			return nil, apperrors.NewAppError(apperrors.ErrContactMergeNotValid)
+		}
+		seen[id] = true
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	var stored *model.Contact
+	err := uc.change(ctx, func(tx *contactUsecase) error {
+		contacts := make([]model.Contact, 0, len(ids))
+		for _, id := range ids {
+			contact, err := tx.ContactRepo.Detail(ctx, id)
+			if err != nil {
+				return err
+			}
+			contacts = append(contacts, *contact)
+		}
+
+		merged := mergeDetails(contacts)
+		merged.UpdatedAt = tx.now()
+
+		var err error
+		stored, err = tx.ContactRepo.Merge(ctx, ids[0], merged, ids[1:])
+		if err != nil {
+			return err
+		}
+
+		// the contacts merged away are deleted, the first one takes their details
+		if err := tx.record(ctx, model.AuditMerge, ids[0], &contacts[0], stored); err != nil {
+			return err
+		}
+		for i, id := range ids[1:] {
+			if err := tx.record(ctx, model.AuditDelete, id, &contacts[i+1], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
 }
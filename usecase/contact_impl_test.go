@@ -1,73 +1,149 @@
 package usecase
 
 import (
+	"contact-go/helper/apperrors"
 	"contact-go/mocks"
 	"contact-go/model"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_contactUsecase_List(t *testing.T) {
 	tests := []struct {
 		name       string
+		query      *model.ContactQuery
+		callRepo   bool
+		repoQuery  *model.ContactQuery
 		repoResult []model.Contact
+		repoTotal  int64
 		repoErr    error
 		want       []model.Contact
+		wantTotal  int64
 		wantErr    bool
 	}{
 		// TODO: Add test cases.
 		{
-			name: "success",
+			name:     "success",
+			query:    &model.ContactQuery{Limit: 10, SortBy: "name", Order: "DESC"},
+			callRepo: true,
 			repoResult: []model.Contact{
 				{ID: 1, Name: "jaguar", NoTelp: "999-888-7777"},
 				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
 				{ID: 3, Name: "jangkrik", NoTelp: "000-000-0000"},
 			},
-			repoErr: nil,
+			repoTotal: 3,
+			repoErr:   nil,
 			want: []model.Contact{
 				{ID: 1, Name: "jaguar", NoTelp: "999-888-7777"},
 				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
 				{ID: 3, Name: "jangkrik", NoTelp: "000-000-0000"},
 			},
-			wantErr: false,
+			wantTotal: 3,
+			wantErr:   false,
 		},
 		{
 			name:       "failed",
+			query:      &model.ContactQuery{},
+			callRepo:   true,
 			repoResult: []model.Contact{},
 			repoErr:    assert.AnError,
 			want:       []model.Contact{},
 			wantErr:    true,
 		},
+		{
+			name:       "filter by whole number",
+			query:      &model.ContactQuery{NoTelp: "(555) 555-5678"},
+			callRepo:   true,
+			repoQuery:  &model.ContactQuery{NoTelp: "+15555555678"},
+			repoResult: []model.Contact{{ID: 2, Name: "Jane_Smith", NoTelp: "+15555555678"}},
+			repoTotal:  1,
+			want:       []model.Contact{{ID: 2, Name: "Jane_Smith", NoTelp: "+15555555678"}},
+			wantTotal:  1,
+			wantErr:    false,
+		},
+		{
+			name:       "filter by part of a number",
+			query:      &model.ContactQuery{NoTelp: "5678"},
+			callRepo:   true,
+			repoResult: []model.Contact{{ID: 2, Name: "Jane_Smith", NoTelp: "+15555555678"}},
+			repoTotal:  1,
+			want:       []model.Contact{{ID: 2, Name: "Jane_Smith", NoTelp: "+15555555678"}},
+			wantTotal:  1,
+			wantErr:    false,
+		},
+		{
+			name:    "invalid sort",
+			query:   &model.ContactQuery{SortBy: "password"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid order",
+			query:   &model.ContactQuery{Order: "sideways"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "limit too large",
+			query:   &model.ContactQuery{Limit: model.MaxContactLimit + 1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "negative offset",
+			query:   &model.ContactQuery{Limit: 10, Offset: -1},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockContactRepo := mocks.NewContactRepository(t)
 
-			mockContactRepo.On("List").Return(tt.repoResult, tt.repoErr)
+			if tt.callRepo {
+				repoQuery := tt.repoQuery
+				if repoQuery == nil {
+					repoQuery = tt.query
+				}
+				mockContactRepo.On("List", mock.Anything, repoQuery).Return(tt.repoResult, tt.repoTotal, tt.repoErr)
+			}
 
-			uc := NewContactUsecase(mockContactRepo)
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
 
-			got, err := uc.List()
+			got, total, err := uc.List(context.Background(), tt.query)
 
 			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.List() error = %v, wantErr %v", err, tt.wantErr) {
 				assert.Equal(t, tt.want, got, "contactUsecase.List() = %v, want %v", got, tt.want)
+				assert.Equal(t, tt.wantTotal, total, "contactUsecase.List() total = %v, want %v", total, tt.wantTotal)
 			}
 		})
 	}
 }
 
 func Test_contactUsecase_Add(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
 	type args struct {
 		req *model.ContactRequest
 	}
 	tests := []struct {
-		name       string
-		args       args
-		repoResult *model.Contact
-		repoErr    error
-		want       *model.Contact
-		wantErr    bool
+		name         string
+		args         args
+		phoneMatches []model.Contact
+		nameMatches  []model.Contact
+		repoContact  *model.Contact
+		repoResult   *model.Contact
+		repoErr      error
+		want         *model.Contact
+		wantDupes    []model.Contact
+		wantErr      bool
 	}{
 		// TODO: Add test cases.
 		{
@@ -78,6 +154,13 @@ func Test_contactUsecase_Add(t *testing.T) {
 					NoTelp: "222-222-3232",
 				},
 			},
+			repoContact: &model.Contact{
+				Name:      "test",
+				NoTelp:    "+12222223232",
+				NoTelpRaw: "222-222-3232",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
 			repoResult: &model.Contact{
 				ID:     1,
 				Name:   "test",
@@ -91,36 +174,263 @@ func Test_contactUsecase_Add(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success with details",
+			args: args{
+				req: &model.ContactRequest{
+					Name:      "test",
+					NoTelp:    "222-222-3232",
+					Phones:    []model.Phone{{Type: "Work", Number: " 222-222-4444 "}},
+					Emails:    []model.Email{{Address: "test@example.com"}},
+					Addresses: []model.Address{{Type: "home", City: "Bandung"}},
+					Company:   " Acme ",
+					Birthday:  "1990-05-17",
+				},
+			},
+			repoContact: &model.Contact{
+				Name:      "test",
+				NoTelp:    "+12222223232",
+				NoTelpRaw: "222-222-3232",
+				Phones:    []model.Phone{{Type: model.TypeWork, Number: "+12222224444", Raw: "222-222-4444"}},
+				Emails:    []model.Email{{Type: model.TypeOther, Address: "test@example.com"}},
+				Addresses: []model.Address{{Type: model.TypeHome, City: "Bandung"}},
+				Company:   "Acme",
+				Birthday:  "1990-05-17",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			repoResult: &model.Contact{ID: 1, Name: "test"},
+			want:       &model.Contact{ID: 1, Name: "test"},
+			wantErr:    false,
+		},
 		{
 			name: "failed",
 			args: args{
 				req: &model.ContactRequest{
+					Name:   "test",
 					NoTelp: "222-222-3232",
 				},
 			},
+			repoContact: &model.Contact{
+				Name:      "test",
+				NoTelp:    "+12222223232",
+				NoTelpRaw: "222-222-3232",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
 			repoResult: nil,
 			repoErr:    assert.AnError,
 			want:       nil,
 			wantErr:    true,
 		},
+		{
+			name: "similar names are not duplicates",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+				},
+			},
+			nameMatches: []model.Contact{{ID: 5, Name: "tester", NoTelp: "+19999999999"}},
+			repoContact: &model.Contact{
+				Name:      "test",
+				NoTelp:    "+12222223232",
+				NoTelpRaw: "222-222-3232",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			repoResult: &model.Contact{ID: 6, Name: "test"},
+			want:       &model.Contact{ID: 6, Name: "test"},
+			wantErr:    false,
+		},
+		{
+			name: "duplicate name",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "Smith, Jane",
+					NoTelp: "222-222-3232",
+				},
+			},
+			nameMatches: []model.Contact{
+				{ID: 5, Name: "Jane Smith", NoTelp: "+19999999999"},
+				{ID: 3, Name: "Smith Jnae", NoTelp: "+18888888888"},
+				{ID: 4, Name: "Janet", NoTelp: "+17777777777"},
+			},
+			want: nil,
+			wantDupes: []model.Contact{
+				{ID: 3, Name: "Smith Jnae", NoTelp: "+18888888888"},
+				{ID: 5, Name: "Jane Smith", NoTelp: "+19999999999"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate number",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+				},
+			},
+			phoneMatches: []model.Contact{
+				{ID: 7, Name: "Budi", NoTelp: "+12222223232"},
+				{ID: 8, Name: "Andi", NoTelp: "+122222232321"},
+			},
+			want:      nil,
+			wantDupes: []model.Contact{{ID: 7, Name: "Budi", NoTelp: "+12222223232"}},
+			wantErr:   true,
+		},
+		{
+			name: "forced duplicate",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+					Force:  true,
+				},
+			},
+			repoContact: &model.Contact{
+				Name:      "test",
+				NoTelp:    "+12222223232",
+				NoTelpRaw: "222-222-3232",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			repoResult: &model.Contact{ID: 6, Name: "test"},
+			want:       &model.Contact{ID: 6, Name: "test"},
+			wantErr:    false,
+		},
+		{
+			name: "empty name",
+			args: args{
+				req: &model.ContactRequest{
+					NoTelp: "222-222-3232",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "blank name",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   " \t ",
+					NoTelp: "222-222-3232",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid email",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+					Emails: []model.Email{{Address: "Test <test@example.com>"}},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid phone",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+					Phones: []model.Phone{{Type: model.TypeHome, Number: " "}},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid number",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-3232",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid phones number",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+					Phones: []model.Phone{{Type: model.TypeHome, Number: "222-CALL-ME"}},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid type",
+			args: args{
+				req: &model.ContactRequest{
+					Name:   "test",
+					NoTelp: "222-222-3232",
+					Phones: []model.Phone{{Type: "fax", Number: "222-222-4444"}},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "empty address",
+			args: args{
+				req: &model.ContactRequest{
+					Name:      "test",
+					NoTelp:    "222-222-3232",
+					Addresses: []model.Address{{Type: model.TypeWork}},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid birthday",
+			args: args{
+				req: &model.ContactRequest{
+					Name:     "test",
+					NoTelp:   "222-222-3232",
+					Birthday: "17-05-1990",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockContact := new(model.Contact)
-			mockContact.Name = tt.args.req.Name
-			mockContact.NoTelp = tt.args.req.NoTelp
-
 			mockContactRepo := mocks.NewContactRepository(t)
 
-			mockContactRepo.On("Add", mockContact).Return(tt.repoResult, tt.repoErr)
+			if (tt.repoContact != nil || tt.wantDupes != nil) && !tt.args.req.Force {
+				mockContactRepo.On("List", mock.Anything, mock.Anything).Return(tt.phoneMatches, int64(len(tt.phoneMatches)), nil)
+				mockContactRepo.On("Search", mock.Anything, mock.Anything).Return(tt.nameMatches, nil).Maybe()
+			}
+			if tt.repoContact != nil {
+				mockContactRepo.On("Add", mock.Anything, tt.repoContact).Return(tt.repoResult, tt.repoErr)
+			}
 
-			uc := NewContactUsecase(mockContactRepo)
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+			uc.(*contactUsecase).now = func() time.Time { return now }
 
-			got, err := uc.Add(tt.args.req)
+			got, err := uc.Add(context.Background(), tt.args.req)
 
 			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Add() error = %v, wantErr %v", err, tt.wantErr) {
 				assert.Equal(t, tt.want, got, "contactUsecase.Add() = %v, want %v", got, tt.want)
 			}
+
+			var dupErr *DuplicateError
+			if errors.As(err, &dupErr) {
+				assert.Equal(t, tt.wantDupes, dupErr.Candidates)
+			} else {
+				assert.Nil(t, tt.wantDupes)
+			}
 		})
 	}
 }
@@ -171,11 +481,11 @@ func Test_contactUsecase_Detail(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockContactRepo := mocks.NewContactRepository(t)
 
-			mockContactRepo.On("Detail", tt.args.id).Return(tt.repoResult, tt.repoErr)
+			mockContactRepo.On("Detail", mock.Anything, tt.args.id).Return(tt.repoResult, tt.repoErr)
 
-			uc := NewContactUsecase(mockContactRepo)
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
 
-			got, err := uc.Detail(tt.args.id)
+			got, err := uc.Detail(context.Background(), tt.args.id)
 
 			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Detail() error = %v, wantErr %v", err, tt.wantErr) {
 				assert.Equal(t, tt.want, got, "contactUsecase.Detail() = %v, want %v", got, tt.want)
@@ -185,6 +495,8 @@ func Test_contactUsecase_Detail(t *testing.T) {
 }
 
 func Test_contactUsecase_Update(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
 	type args struct {
 		id  int64
 		req *model.ContactRequest
@@ -192,6 +504,8 @@ func Test_contactUsecase_Update(t *testing.T) {
 	tests := []struct {
 		name       string
 		args       args
+		invalid    bool
+		repoNoTelp string
 		repoResult *model.Contact
 		repoErr    error
 		want       *model.Contact
@@ -207,46 +521,98 @@ func Test_contactUsecase_Update(t *testing.T) {
 					NoTelp: "222-222-4444",
 				},
 			},
+			repoNoTelp: "+12222224444",
 			repoResult: &model.Contact{
 				ID:     1,
 				Name:   "test",
-				NoTelp: "222-222-4444",
+				NoTelp: "+12222224444",
 			},
 			repoErr: nil,
 			want: &model.Contact{
 				ID:     1,
 				Name:   "test",
-				NoTelp: "222-222-4444",
+				NoTelp: "+12222224444",
 			},
 			wantErr: false,
 		},
+		{
+			name: "stale version",
+			args: args{
+				id: 1,
+				req: &model.ContactRequest{
+					Name:    "test",
+					NoTelp:  "222-222-4444",
+					Version: 2,
+				},
+			},
+			repoNoTelp: "+12222224444",
+			repoResult: nil,
+			repoErr:    apperrors.NewAppError(apperrors.ErrContactVersion),
+			want:       nil,
+			wantErr:    true,
+		},
 		{
 			name: "failed",
 			args: args{
 				id: 1,
 				req: &model.ContactRequest{
+					Name:   "test",
 					NoTelp: "222-222-3232",
 				},
 			},
+			repoNoTelp: "+12222223232",
 			repoResult: nil,
 			repoErr:    assert.AnError,
 			want:       nil,
 			wantErr:    true,
 		},
+		{
+			name: "blank name",
+			args: args{
+				id: 1,
+				req: &model.ContactRequest{
+					Name:   "   ",
+					NoTelp: "222-222-3232",
+				},
+			},
+			invalid: true,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid birthday",
+			args: args{
+				id: 1,
+				req: &model.ContactRequest{
+					Name:     "test",
+					NoTelp:   "222-222-3232",
+					Birthday: "1990-02-30",
+				},
+			},
+			invalid: true,
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockContact := new(model.Contact)
 			mockContact.Name = tt.args.req.Name
-			mockContact.NoTelp = tt.args.req.NoTelp
+			mockContact.NoTelp = tt.repoNoTelp
+			mockContact.NoTelpRaw = tt.args.req.NoTelp
+			mockContact.UpdatedAt = now
+			mockContact.Version = tt.args.req.Version
 
 			mockContactRepo := mocks.NewContactRepository(t)
 
-			mockContactRepo.On("Update", tt.args.id, mockContact).Return(tt.repoResult, tt.repoErr)
+			if !tt.invalid {
+				mockContactRepo.On("Update", mock.Anything, tt.args.id, mockContact).Return(tt.repoResult, tt.repoErr)
+			}
 
-			uc := NewContactUsecase(mockContactRepo)
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+			uc.(*contactUsecase).now = func() time.Time { return now }
 
-			got, err := uc.Update(tt.args.id, tt.args.req)
+			got, err := uc.Update(context.Background(), tt.args.id, tt.args.req)
 
 			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Update() error = %v, wantErr %v", err, tt.wantErr) {
 				assert.Equal(t, tt.want, got, "contactUsecase.Update() = %v, want %v", got, tt.want)
@@ -255,9 +621,90 @@ func Test_contactUsecase_Update(t *testing.T) {
 	}
 }
 
+func Test_contactUsecase_Patch(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	stored := &model.Contact{ID: 1, Name: "test", NoTelp: "+12222224444", Company: "Acme", Version: 2}
+
+	tests := []struct {
+		name      string
+		req       *model.ContactRequest
+		fields    []string
+		wantPatch *model.Contact
+		wantErr   string
+	}{
+		{
+			name:      "one field",
+			req:       &model.ContactRequest{Company: " Initech "},
+			fields:    []string{"company"},
+			wantPatch: &model.Contact{Name: "test", NoTelp: "+12222224444", NoTelpRaw: "+12222224444", Company: "Initech", UpdatedAt: now},
+		},
+		{
+			name:      "cleared field with version",
+			req:       &model.ContactRequest{Version: 2},
+			fields:    []string{"company"},
+			wantPatch: &model.Contact{Name: "test", NoTelp: "+12222224444", NoTelpRaw: "+12222224444", UpdatedAt: now, Version: 2},
+		},
+		{
+			name:      "no_telp normalized",
+			req:       &model.ContactRequest{NoTelp: "222-222-3232"},
+			fields:    []string{"no_telp"},
+			wantPatch: &model.Contact{Name: "test", NoTelp: "+12222223232", NoTelpRaw: "222-222-3232", UpdatedAt: now},
+		},
+		{
+			name:    "cleared name",
+			req:     &model.ContactRequest{},
+			fields:  []string{"name"},
+			wantErr: apperrors.ErrContactNameNotValid,
+		},
+		{
+			name:    "cleared no_telp",
+			req:     &model.ContactRequest{},
+			fields:  []string{"no_telp"},
+			wantErr: apperrors.ErrContactNoTelpNotValid,
+		},
+		{
+			name:    "invalid birthday",
+			req:     &model.ContactRequest{Birthday: "1990-02-30"},
+			fields:  []string{"birthday"},
+			wantErr: apperrors.ErrContactBirthdayNotValid,
+		},
+		{
+			name:    "unknown field",
+			req:     &model.ContactRequest{},
+			fields:  []string{"id"},
+			wantErr: apperrors.ErrContactPatchNotValid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			mockContactRepo.On("Detail", mock.Anything, int64(1)).Return(stored, nil)
+			if tt.wantPatch != nil {
+				mockContactRepo.On("Patch", mock.Anything, int64(1), tt.wantPatch, tt.fields).Return(stored, nil)
+			}
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+			uc.(*contactUsecase).now = func() time.Time { return now }
+
+			got, err := uc.Patch(context.Background(), 1, tt.req, tt.fields)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, stored, got)
+		})
+	}
+}
+
 func Test_contactUsecase_Delete(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
 	type args struct {
-		id int64
+		id      int64
+		version int64
 	}
 	tests := []struct {
 		name    string
@@ -274,6 +721,15 @@ func Test_contactUsecase_Delete(t *testing.T) {
 			repoErr: nil,
 			wantErr: false,
 		},
+		{
+			name: "matching version",
+			args: args{
+				id:      1,
+				version: 3,
+			},
+			repoErr: nil,
+			wantErr: false,
+		},
 		{
 			name: "failed",
 			args: args{
@@ -287,13 +743,723 @@ func Test_contactUsecase_Delete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockContactRepo := mocks.NewContactRepository(t)
 
-			mockContactRepo.On("Delete", tt.args.id).Return(tt.repoErr)
+			mockContactRepo.On("Delete", mock.Anything, tt.args.id, tt.args.version, now).Return(tt.repoErr)
 
-			uc := NewContactUsecase(mockContactRepo)
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+			uc.(*contactUsecase).now = func() time.Time { return now }
 
-			err := uc.Delete(tt.args.id)
+			err := uc.Delete(context.Background(), tt.args.id, tt.args.version)
 
 			assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Delete() error = %v, wantErr %v", err, tt.wantErr)
 		})
 	}
 }
+
+func Test_contactUsecase_Restore(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         int64
+		repoResult *model.Contact
+		repoErr    error
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name:       "success",
+			id:         1,
+			repoResult: &model.Contact{ID: 1, Name: "test", NoTelp: "+12222224444"},
+			repoErr:    nil,
+			want:       &model.Contact{ID: 1, Name: "test", NoTelp: "+12222224444"},
+			wantErr:    false,
+		},
+		{
+			name:       "not in trash",
+			id:         2,
+			repoResult: nil,
+			repoErr:    apperrors.NewAppError(apperrors.ErrContactNotFound),
+			want:       nil,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			mockContactRepo.On("Restore", mock.Anything, tt.id).Return(tt.repoResult, tt.repoErr)
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+
+			got, err := uc.Restore(context.Background(), tt.id)
+
+			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Restore() error = %v, wantErr %v", err, tt.wantErr) {
+				assert.Equal(t, tt.want, got, "contactUsecase.Restore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_Purge(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		retention  time.Duration
+		before     time.Time
+		repoResult int64
+		repoErr    error
+		want       int64
+		wantErr    bool
+	}{
+		{
+			name:       "older than retention",
+			retention:  30 * 24 * time.Hour,
+			before:     now.Add(-30 * 24 * time.Hour),
+			repoResult: 2,
+			want:       2,
+			wantErr:    false,
+		},
+		{
+			name:      "failed without retention",
+			retention: 0,
+			before:    now,
+			repoErr:   assert.AnError,
+			want:      0,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			mockContactRepo.On("Purge", mock.Anything, tt.before).Return(tt.repoResult, tt.repoErr)
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", tt.retention, nil)
+			uc.(*contactUsecase).now = func() time.Time { return now }
+
+			got, err := uc.Purge(context.Background())
+
+			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Purge() error = %v, wantErr %v", err, tt.wantErr) {
+				assert.Equal(t, tt.want, got, "contactUsecase.Purge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_Search(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		callRepo   bool
+		repoResult []model.Contact
+		repoErr    error
+		want       []model.Contact
+		wantErr    bool
+	}{
+		// TODO: Add test cases.
+		{
+			name:     "success",
+			query:    " jane ",
+			callRepo: true,
+			repoResult: []model.Contact{
+				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
+			},
+			want: []model.Contact{
+				{ID: 2, Name: "Jane_Smith", NoTelp: "555-555-5678"},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "failed",
+			query:    "jane",
+			callRepo: true,
+			repoErr:  assert.AnError,
+			want:     nil,
+			wantErr:  true,
+		},
+		{
+			name:    "empty keyword",
+			query:   "  ",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			if tt.callRepo {
+				mockContactRepo.On("Search", mock.Anything, strings.TrimSpace(tt.query)).Return(tt.repoResult, tt.repoErr)
+			}
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+
+			got, err := uc.Search(context.Background(), tt.query)
+
+			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Search() error = %v, wantErr %v", err, tt.wantErr) {
+				assert.Equal(t, tt.want, got, "contactUsecase.Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *model.ContactRequest
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{
+			name: "success",
+			req: &model.ContactRequest{
+				Name:   "Jane_Smith",
+				NoTelp: "555-555-5678",
+				Emails: []model.Email{{Address: "jane@example.com"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid email",
+			req: &model.ContactRequest{
+				Name:   "Jane_Smith",
+				NoTelp: "555-555-5678",
+				Emails: []model.Email{{Address: "jane"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty name",
+			req: &model.ContactRequest{
+				NoTelp: "555-555-5678",
+			},
+			wantErr: true,
+		},
+		{
+			name: "blank name",
+			req: &model.ContactRequest{
+				Name:   "\n",
+				NoTelp: "555-555-5678",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+
+			err := uc.Validate(context.Background(), tt.req)
+
+			assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Validate() error = %v, wantErr %v", err, tt.wantErr)
+		})
+	}
+}
+
+func Test_contactUsecase_FindByPhone(t *testing.T) {
+	tests := []struct {
+		name       string
+		noTelp     string
+		callRepo   bool
+		repoResult []model.Contact
+		repoErr    error
+		want       *model.Contact
+		wantErr    bool
+	}{
+		{
+			name:     "success",
+			noTelp:   "(555) 555-5678",
+			callRepo: true,
+			repoResult: []model.Contact{
+				{ID: 1, Name: "jaguar", NoTelp: "+15555555678999"},
+				{ID: 2, Name: "Jane_Smith", NoTelp: "+15555555678"},
+			},
+			want:    &model.Contact{ID: 2, Name: "Jane_Smith", NoTelp: "+15555555678"},
+			wantErr: false,
+		},
+		{
+			name:       "not found",
+			noTelp:     "555-555-5678",
+			callRepo:   true,
+			repoResult: []model.Contact{{ID: 1, Name: "jaguar", NoTelp: "+15555555678999"}},
+			want:       nil,
+			wantErr:    true,
+		},
+		{
+			name:     "failed",
+			noTelp:   "555-555-5678",
+			callRepo: true,
+			repoErr:  assert.AnError,
+			want:     nil,
+			wantErr:  true,
+		},
+		{
+			name:    "invalid number",
+			noTelp:  "5678",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			if tt.callRepo {
+				query := &model.ContactQuery{NoTelp: "+15555555678"}
+				mockContactRepo.On("List", mock.Anything, query).Return(tt.repoResult, int64(len(tt.repoResult)), tt.repoErr)
+			}
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+
+			got, err := uc.FindByPhone(context.Background(), tt.noTelp)
+
+			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.FindByPhone() error = %v, wantErr %v", err, tt.wantErr) {
+				assert.Equal(t, tt.want, got, "contactUsecase.FindByPhone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_Duplicates(t *testing.T) {
+	var many []model.Contact
+	for i := 0; i <= maxDuplicateGroups; i++ {
+		number := "+1555555" + strconv.Itoa(1000+i)
+		many = append(many,
+			model.Contact{ID: int64(2*i + 1), NoTelp: number},
+			model.Contact{ID: int64(2*i + 2), NoTelp: number},
+		)
+	}
+
+	tests := []struct {
+		name       string
+		repoResult []model.Contact
+		repoErr    error
+		want       [][]model.Contact
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			repoResult: []model.Contact{
+				{ID: 1, Name: "Jane Smith", NoTelp: "+15555555678"},
+				{ID: 2, Name: "Budi", NoTelp: "+6281234567890"},
+				{ID: 3, Name: "J. Smith", NoTelp: "+15555550000", Phones: []model.Phone{{Number: "+15555555678"}}},
+				{ID: 4, Name: "Andi", NoTelp: "+6281200000000"},
+				{ID: 5, Name: "budi_", NoTelp: "+6281211111111"},
+				{ID: 6, Name: "Smith Jane", NoTelp: "+15555559999"},
+			},
+			want: [][]model.Contact{
+				{
+					{ID: 1, Name: "Jane Smith", NoTelp: "+15555555678"},
+					{ID: 3, Name: "J. Smith", NoTelp: "+15555550000", Phones: []model.Phone{{Number: "+15555555678"}}},
+					{ID: 6, Name: "Smith Jane", NoTelp: "+15555559999"},
+				},
+				{
+					{ID: 2, Name: "Budi", NoTelp: "+6281234567890"},
+					{ID: 5, Name: "budi_", NoTelp: "+6281211111111"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "typo in the first letters",
+			repoResult: []model.Contact{
+				{ID: 1, Name: "Kathrine", NoTelp: "+15555555678"},
+				{ID: 2, Name: "Catherine", NoTelp: "+15555550000"},
+			},
+			want: [][]model.Contact{{
+				{ID: 1, Name: "Kathrine", NoTelp: "+15555555678"},
+				{ID: 2, Name: "Catherine", NoTelp: "+15555550000"},
+			}},
+			wantErr: false,
+		},
+		{
+			name:       "more groups than returned",
+			repoResult: many,
+			want: func() [][]model.Contact {
+				var groups [][]model.Contact
+				for i := 0; i < maxDuplicateGroups; i++ {
+					groups = append(groups, many[2*i:2*i+2])
+				}
+				return groups
+			}(),
+			wantErr: false,
+		},
+		{
+			name:       "no duplicates",
+			repoResult: []model.Contact{{ID: 1, Name: "Jane Smith", NoTelp: "+15555555678"}},
+			want:       [][]model.Contact{},
+			wantErr:    false,
+		},
+		{
+			name:    "failed",
+			repoErr: assert.AnError,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			mockContactRepo.On("List", mock.Anything, &model.ContactQuery{}).Return(tt.repoResult, int64(len(tt.repoResult)), tt.repoErr)
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+
+			got, err := uc.Duplicates(context.Background())
+
+			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Duplicates() error = %v, wantErr %v", err, tt.wantErr) {
+				assert.Equal(t, tt.want, got, "contactUsecase.Duplicates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_Merge(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	stored := map[int64]*model.Contact{
+		1: {
+			ID:        1,
+			Name:      "Jane Smith",
+			NoTelp:    "+15555555678",
+			Emails:    []model.Email{{Type: model.TypeWork, Address: "jane@example.com"}},
+			Notes:     "met at the conference",
+			CreatedAt: now.Add(-time.Hour),
+		},
+		2: {
+			ID:        2,
+			Name:      "J. Smith",
+			NoTelp:    "+15555550000",
+			NoTelpRaw: "555-555-0000",
+			Phones:    []model.Phone{{Type: model.TypeHome, Number: "+15555555678"}},
+			Emails:    []model.Email{{Type: model.TypeHome, Address: "JANE@example.com"}},
+			Company:   "Acme",
+			Notes:     "met at the conference",
+		},
+		3: {
+			ID:        3,
+			Name:      "Jane",
+			NoTelp:    "+15555551111",
+			Birthday:  "1990-05-17",
+			Addresses: []model.Address{{Type: model.TypeHome, City: "Bandung"}},
+			Notes:     "likes tea",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ids       []int64
+		callRepo  bool
+		repoErr   error
+		wantMerge *model.Contact
+		wantErr   bool
+	}{
+		{
+			name:     "success",
+			ids:      []int64{1, 2, 3},
+			callRepo: true,
+			wantMerge: &model.Contact{
+				ID:     1,
+				Name:   "Jane Smith",
+				NoTelp: "+15555555678",
+				Phones: []model.Phone{
+					{Type: model.TypeOther, Number: "+15555550000", Raw: "555-555-0000"},
+					{Type: model.TypeOther, Number: "+15555551111"},
+				},
+				Emails:    []model.Email{{Type: model.TypeWork, Address: "jane@example.com"}},
+				Addresses: []model.Address{{Type: model.TypeHome, City: "Bandung"}},
+				Company:   "Acme",
+				Birthday:  "1990-05-17",
+				Notes:     "met at the conference\n\nlikes tea",
+				CreatedAt: now.Add(-time.Hour),
+				UpdatedAt: now,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "failed",
+			ids:      []int64{1, 2},
+			callRepo: true,
+			repoErr:  assert.AnError,
+			wantErr:  true,
+		},
+		{
+			name:    "one contact",
+			ids:     []int64{1},
+			wantErr: true,
+		},
+		{
+			name:    "repeated id",
+			ids:     []int64{1, 2, 1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid id",
+			ids:     []int64{1, 0},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			if tt.callRepo {
+				for _, id := range tt.ids {
+					contact := *stored[id]
+					mockContactRepo.On("Detail", mock.Anything, id).Return(&contact, nil)
+				}
+
+				merge := mockContactRepo.On("Merge", mock.Anything, tt.ids[0], mock.Anything, tt.ids[1:])
+				if tt.wantMerge != nil {
+					merge.Return(tt.wantMerge, nil).Run(func(args mock.Arguments) {
+						assert.Equal(t, tt.wantMerge, args.Get(2))
+					})
+				} else {
+					merge.Return(nil, tt.repoErr)
+				}
+			}
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+			uc.(*contactUsecase).now = func() time.Time { return now }
+
+			got, err := uc.Merge(context.Background(), tt.ids)
+
+			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.Merge() error = %v, wantErr %v", err, tt.wantErr) {
+				assert.Equal(t, tt.wantMerge, got, "contactUsecase.Merge() = %v, want %v", got, tt.wantMerge)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_Timeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{
+			name:         "timeout bounds repository call",
+			timeout:      time.Second,
+			wantDeadline: true,
+		},
+		{
+			name:         "zero timeout keeps caller context",
+			timeout:      0,
+			wantDeadline: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			hasDeadline := mock.MatchedBy(func(ctx context.Context) bool {
+				_, ok := ctx.Deadline()
+				return ok == tt.wantDeadline
+			})
+			mockContactRepo.On("Detail", hasDeadline, int64(1)).Return(&model.Contact{ID: 1}, nil)
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, tt.timeout, "US", 0, nil)
+
+			_, err := uc.Detail(context.Background(), 1)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_nameQueries(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "  ", want: nil},
+		{name: "Reva", want: []string{"reva"}},
+		{name: "Smith, Jane", want: []string{"smith jane", "smith", "jane"}},
+		{name: "Jane  jane", want: []string{"jane jane", "jane"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nameQueries(tt.name))
+		})
+	}
+}
+
+func Test_contactUsecase_Batch(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	create := model.ContactBatchOperation{Op: model.BatchCreate, Contact: &model.ContactRequest{Name: "test", NoTelp: "222-222-4444"}}
+	remove := model.ContactBatchOperation{Op: model.BatchDelete, ID: 2, Version: 3}
+	invalid := model.ContactBatchOperation{Op: model.BatchUpdate, ID: 1, Contact: &model.ContactRequest{NoTelp: "222-222-4444"}}
+
+	created := &model.Contact{ID: 5, Name: "test", NoTelp: "+12222224444", NoTelpRaw: "222-222-4444", CreatedAt: now, UpdatedAt: now, Version: 1}
+	wantOps := []model.ContactOperation{
+		{Op: model.BatchCreate, Contact: &model.Contact{Name: "test", NoTelp: "+12222224444", NoTelpRaw: "222-222-4444", CreatedAt: now, UpdatedAt: now}},
+		{Op: model.BatchDelete, ID: 2, Version: 3, DeletedAt: now},
+	}
+
+	tests := []struct {
+		name        string
+		req         *model.ContactBatchRequest
+		repoResults []model.ContactOperationResult
+		want        []model.ContactBatchResult
+		wantErr     string
+	}{
+		{
+			name: "best effort",
+			req:  &model.ContactBatchRequest{Operations: []model.ContactBatchOperation{create, invalid, remove}},
+			repoResults: []model.ContactOperationResult{
+				{Contact: created},
+				{Err: apperrors.NewAppError(apperrors.ErrContactNotFound)},
+			},
+			want: []model.ContactBatchResult{
+				{Operation: 1, Op: model.BatchCreate, ID: 5, Contact: created},
+				{Operation: 2, Op: model.BatchUpdate, ID: 1, Error: apperrors.ErrContactNameNotValid},
+				{Operation: 3, Op: model.BatchDelete, ID: 2, Error: apperrors.ErrContactNotFound},
+			},
+		},
+		{
+			name: "atomic with an invalid operation",
+			req:  &model.ContactBatchRequest{Atomic: true, Operations: []model.ContactBatchOperation{create, invalid, remove}},
+			want: []model.ContactBatchResult{
+				{Operation: 1, Op: model.BatchCreate, Error: apperrors.ErrBatchAborted},
+				{Operation: 2, Op: model.BatchUpdate, ID: 1, Error: apperrors.ErrContactNameNotValid},
+				{Operation: 3, Op: model.BatchDelete, ID: 2, Error: apperrors.ErrBatchAborted},
+			},
+			wantErr: apperrors.ErrContactNameNotValid,
+		},
+		{
+			name: "atomic failed in the repository",
+			req:  &model.ContactBatchRequest{Atomic: true, Operations: []model.ContactBatchOperation{create, remove}},
+			repoResults: []model.ContactOperationResult{
+				{Contact: created},
+				{Err: apperrors.NewAppError(apperrors.ErrContactVersion)},
+			},
+			want: []model.ContactBatchResult{
+				{Operation: 1, Op: model.BatchCreate, Error: apperrors.ErrBatchAborted},
+				{Operation: 2, Op: model.BatchDelete, ID: 2, Error: apperrors.ErrContactVersion},
+			},
+			wantErr: apperrors.ErrContactVersion,
+		},
+		{
+			name: "best effort with a contact changed twice",
+			req:  &model.ContactBatchRequest{Operations: []model.ContactBatchOperation{create, remove, remove}},
+			repoResults: []model.ContactOperationResult{
+				{Contact: created},
+				{},
+			},
+			want: []model.ContactBatchResult{
+				{Operation: 1, Op: model.BatchCreate, ID: 5, Contact: created},
+				{Operation: 2, Op: model.BatchDelete, ID: 2},
+				{Operation: 3, Op: model.BatchDelete, ID: 2, Error: apperrors.ErrBatchIdDuplicate},
+			},
+		},
+		{
+			name:    "unknown op",
+			req:     &model.ContactBatchRequest{Atomic: true, Operations: []model.ContactBatchOperation{{Op: "upsert"}}},
+			want:    []model.ContactBatchResult{{Operation: 1, Op: "upsert", Error: apperrors.ErrBatchNotValid}},
+			wantErr: apperrors.ErrBatchNotValid,
+		},
+		{
+			name:    "empty",
+			req:     &model.ContactBatchRequest{},
+			wantErr: apperrors.ErrBatchNotValid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+
+			if tt.repoResults != nil {
+				mockContactRepo.On("Batch", mock.Anything, wantOps, tt.req.Atomic).Return(tt.repoResults, nil)
+			}
+
+			uc := NewContactUsecase(mockContactRepo, nil, nil, nil, time.Second, "US", 0, nil)
+			uc.(*contactUsecase).now = func() time.Time { return now }
+
+			got, err := uc.Batch(context.Background(), tt.req)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_contactUsecase_List_group(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      *model.ContactQuery
+		beforeTest func(*mocks.ContactRepository, *mocks.GroupRepository)
+		want       []model.Contact
+		wantErr    bool
+	}{
+		{
+			name:  "by group",
+			query: &model.ContactQuery{Group: 1},
+			beforeTest: func(contactRepo *mocks.ContactRepository, groupRepo *mocks.GroupRepository) {
+				groupRepo.On("Members", mock.Anything, int64(1)).Return([]int64{2, 3}, nil)
+				contactRepo.On("List", mock.Anything, &model.ContactQuery{Group: 1, IDs: []int64{2, 3}}).
+					Return([]model.Contact{{ID: 2, Name: "jane"}, {ID: 3, Name: "john"}}, int64(2), nil)
+			},
+			want: []model.Contact{{ID: 2, Name: "jane"}, {ID: 3, Name: "john"}},
+		},
+		{
+			name:  "by group and tag",
+			query: &model.ContactQuery{Group: 1, Tag: "Team"},
+			beforeTest: func(contactRepo *mocks.ContactRepository, groupRepo *mocks.GroupRepository) {
+				groupRepo.On("FindByName", mock.Anything, "Team").Return(&model.Group{ID: 4, Name: "team"}, nil)
+				groupRepo.On("Members", mock.Anything, int64(1)).Return([]int64{2, 3}, nil)
+				groupRepo.On("Members", mock.Anything, int64(4)).Return([]int64{3, 5}, nil)
+				contactRepo.On("List", mock.Anything, &model.ContactQuery{Group: 1, Tag: "Team", IDs: []int64{3}}).
+					Return([]model.Contact{{ID: 3, Name: "john"}}, int64(1), nil)
+			},
+			want: []model.Contact{{ID: 3, Name: "john"}},
+		},
+		{
+			name:  "empty group",
+			query: &model.ContactQuery{Group: 1},
+			beforeTest: func(_ *mocks.ContactRepository, groupRepo *mocks.GroupRepository) {
+				groupRepo.On("Members", mock.Anything, int64(1)).Return(nil, nil)
+			},
+			want: nil,
+		},
+		{
+			name:  "unknown tag",
+			query: &model.ContactQuery{Tag: "nobody"},
+			beforeTest: func(_ *mocks.ContactRepository, groupRepo *mocks.GroupRepository) {
+				groupRepo.On("FindByName", mock.Anything, "nobody").Return(nil, apperrors.NewAppError(apperrors.ErrGroupNotFound))
+			},
+			want: nil,
+		},
+		{
+			name:  "unknown group",
+			query: &model.ContactQuery{Group: 9},
+			beforeTest: func(_ *mocks.ContactRepository, groupRepo *mocks.GroupRepository) {
+				groupRepo.On("Members", mock.Anything, int64(9)).Return(nil, apperrors.NewAppError(apperrors.ErrGroupNotFound))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid group",
+			query:   &model.ContactQuery{Group: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+			mockGroupRepo := mocks.NewGroupRepository(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mockContactRepo, mockGroupRepo)
+			}
+
+			uc := NewContactUsecase(mockContactRepo, mockGroupRepo, nil, nil, time.Second, "US", 0, nil)
+
+			got, _, err := uc.List(context.Background(), tt.query)
+			if assert.Equal(t, tt.wantErr, err != nil, "contactUsecase.List() error = %v, wantErr %v", err, tt.wantErr) {
+				assert.Equal(t, tt.want, got, "contactUsecase.List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
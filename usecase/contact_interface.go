@@ -2,12 +2,33 @@
 
 package usecase
 
-import "contact-go/model"
+import (
+	"contact-go/model"
+	"context"
+)
 
 type ContactUsecase interface {
-	List() ([]model.Contact, error)
-	Add(req *model.ContactRequest) (*model.Contact, error)
-	Detail(id int64) (*model.Contact, error)
-	Update(id int64, req *model.ContactRequest) (*model.Contact, error)
-	Delete(id int64) error
+	List(ctx context.Context, query *model.ContactQuery) ([]model.Contact, int64, error)
+	Add(ctx context.Context, req *model.ContactRequest) (*model.Contact, error)
+	Detail(ctx context.Context, id int64) (*model.Contact, error)
+	// Update, Patch and Delete only go through while the contact is still
+	// at the given version; a version of 0 skips the check. Update replaces
+	// every field, Patch only the model.ContactFields named in fields.
+	Update(ctx context.Context, id int64, req *model.ContactRequest) (*model.Contact, error)
+	Patch(ctx context.Context, id int64, req *model.ContactRequest, fields []string) (*model.Contact, error)
+	Delete(ctx context.Context, id int64, version int64) error
+	Restore(ctx context.Context, id int64) (*model.Contact, error)
+	Purge(ctx context.Context) (int64, error)
+	Search(ctx context.Context, query string) ([]model.Contact, error)
+	Validate(ctx context.Context, req *model.ContactRequest) error
+	FindByPhone(ctx context.Context, noTelp string) (*model.Contact, error)
+	Duplicates(ctx context.Context) ([][]model.Contact, error)
+	Merge(ctx context.Context, ids []int64) (*model.Contact, error)
+	Batch(ctx context.Context, req *model.ContactBatchRequest) ([]model.ContactBatchResult, error)
+	History(ctx context.Context, id int64, query *model.AuditQuery) ([]model.AuditEntry, int64, error)
+	// Revert brings the contact id back to revision, the ID of an entry
+	// of its history, and Undo takes back the change recorded as revision.
+	// Both return the contact as it is after, nil when that is deleted.
+	Revert(ctx context.Context, id int64, revision int64) (*model.Contact, error)
+	Undo(ctx context.Context, revision int64) (*model.Contact, error)
 }
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/helper/phone"
+	"contact-go/model"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// newContact validates the details of req and builds the contact to store.
+// Phone numbers are normalized to E.164, reading those without a country
+// calling code as numbers of region, and kept as entered alongside.
+// Phones, emails and addresses sent without a type are tagged model.TypeOther.
+func newContact(req *model.ContactRequest, region string) (*model.Contact, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNameNotValid)
+	}
+
+	noTelp, err := phone.Normalize(req.NoTelp, region)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrContactNumberNotValid)
+	}
+
+	contact := &model.Contact{
+		Name:      req.Name,
+		NoTelp:    noTelp,
+		NoTelpRaw: strings.TrimSpace(req.NoTelp),
+		Company:   strings.TrimSpace(req.Company),
+		JobTitle:  strings.TrimSpace(req.JobTitle),
+		Birthday:  strings.TrimSpace(req.Birthday),
+		Notes:     req.Notes,
+	}
+
+	for _, p := range req.Phones {
+		p.Raw = strings.TrimSpace(p.Number)
+		if p.Raw == "" {
+			return nil, apperrors.NewAppError(apperrors.ErrContactPhoneNotValid)
+		}
+
+		number, err := phone.Normalize(p.Raw, region)
+		if err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrContactNumberNotValid)
+		}
+		p.Number = number
+
+		contactType, err := normalizeContactType(p.Type)
+		if err != nil {
+			return nil, err
+		}
+		p.Type = contactType
+
+		contact.Phones = append(contact.Phones, p)
+	}
+
+	for _, email := range req.Emails {
+		email.Address = strings.TrimSpace(email.Address)
+		// reject display names too, only the bare address is stored
+		parsed, err := mail.ParseAddress(email.Address)
+		if err != nil || parsed.Address != email.Address {
+			return nil, apperrors.NewAppError(apperrors.ErrContactEmailNotValid)
+		}
+
+		contactType, err := normalizeContactType(email.Type)
+		if err != nil {
+			return nil, err
+		}
+		email.Type = contactType
+
+		contact.Emails = append(contact.Emails, email)
+	}
+
+	for _, address := range req.Addresses {
+		address.Street = strings.TrimSpace(address.Street)
+		address.City = strings.TrimSpace(address.City)
+		address.Region = strings.TrimSpace(address.Region)
+		address.PostalCode = strings.TrimSpace(address.PostalCode)
+		address.Country = strings.TrimSpace(address.Country)
+		if address.Street == "" && address.City == "" && address.Region == "" &&
+			address.PostalCode == "" && address.Country == "" {
+			return nil, apperrors.NewAppError(apperrors.ErrContactAddressNotValid)
+		}
+
+		contactType, err := normalizeContactType(address.Type)
+		if err != nil {
+			return nil, err
+		}
+		address.Type = contactType
+
+		contact.Addresses = append(contact.Addresses, address)
+	}
+
+	if contact.Birthday != "" {
+		if _, err := time.Parse(model.BirthdayLayout, contact.Birthday); err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrContactBirthdayNotValid)
+		}
+	}
+
+	return contact, nil
+}
+
+func normalizeContactType(contactType string) (string, error) {
+	contactType = strings.ToLower(strings.TrimSpace(contactType))
+	if contactType == "" {
+		return model.TypeOther, nil
+	}
+
+	if !model.ContactTypes[contactType] {
+		return "", apperrors.NewAppError(apperrors.ErrContactTypeNotValid)
+	}
+
+	return contactType, nil
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"context"
+)
+
+// Revert brings the contact id back to what the entry revision of its
+// history left it as, deleting it again when that entry deleted it.
+// The revert is recorded in turn, so it can be reverted or undone too.
+func (uc *contactUsecase) Revert(ctx context.Context, id int64, revision int64) (*model.Contact, error) {
+	if id <= 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrContactIdNotValid)
+	}
+	if revision <= 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrRevisionNotValid)
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	entry, err := uc.revision(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+	if entry.ContactID != id {
+		return nil, apperrors.NewAppError(apperrors.ErrRevisionNotFound)
+	}
+
+	return uc.revertTo(ctx, id, entry.After)
+}
+
+// Undo brings the contact changed by the entry revision back to what it
+// was before that change, deleting it again when the change added it.
+func (uc *contactUsecase) Undo(ctx context.Context, revision int64) (*model.Contact, error) {
+	if revision <= 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrRevisionNotValid)
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	entry, err := uc.revision(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.revertTo(ctx, entry.ContactID, entry.Before)
+}
+
+// revision returns the audit entry revision. There are none to go back
+// to while changes go unrecorded.
+func (uc *contactUsecase) revision(ctx context.Context, revision int64) (*model.AuditEntry, error) {
+	if uc.AuditRepo == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrRevisionNotFound)
+	}
+	return uc.AuditRepo.Detail(ctx, revision)
+}
+
+// revertTo changes the contact id into target, whether it is stored, in
+// the trash or already purged. A nil target deletes the contact instead.
+func (uc *contactUsecase) revertTo(ctx context.Context, id int64, target *model.Contact) (*model.Contact, error) {
+	var reverted *model.Contact
+	err := uc.change(ctx, func(tx *contactUsecase) error {
+		current, err := tx.ContactRepo.Detail(ctx, id)
+		if err != nil && !isAppError(err, apperrors.ErrContactNotFound) {
+			return err
+		}
+
+		if target == nil {
+			if current == nil {
+				return nil
+			}
+			err := tx.ContactRepo.Delete(ctx, id, 0, tx.now())
+			if err != nil {
+				return err
+			}
+			return tx.record(ctx, model.AuditRevert, id, current, nil)
+		}
+
+		contact := *target
+		contact.ID = id
+		contact.UpdatedAt = tx.now()
+		contact.DeletedAt = nil
+		contact.Version = 0
+
+		if current == nil {
+			current, err = tx.trashed(ctx, id)
+			if err != nil {
+				return err
+			}
+			if current == nil {
+				reverted, err = tx.recreate(ctx, &contact)
+				return err
+			}
+
+			_, err = tx.ContactRepo.Restore(ctx, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		reverted, err = tx.ContactRepo.Update(ctx, id, &contact)
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, model.AuditRevert, id, current, reverted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reverted, nil
+}
+
+// recreate stores the purged contact again under its ID, picking up its
+// versions after the last one recorded. Where the backend gave the ID to
+// another contact since, it is added under a new one.
+func (uc *contactUsecase) recreate(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	entries, _, err := uc.AuditRepo.List(ctx, &model.AuditQuery{ContactID: contact.ID, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	contact.Version = 1
+	if len(entries) > 0 {
+		if last := entries[0].Before; last != nil {
+			contact.Version = last.Version + 1
+		} else if last := entries[0].After; last != nil {
+			contact.Version = last.Version + 1
+		}
+	}
+
+	recreated, err := uc.ContactRepo.Recreate(ctx, contact)
+	if isAppError(err, apperrors.ErrContactDuplicate) {
+		recreated, err = uc.ContactRepo.Add(ctx, contact)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return recreated, uc.record(ctx, model.AuditRevert, recreated.ID, nil, recreated)
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/mocks"
+	"contact-go/model"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_contactUsecase_Revert(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	created := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	old := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", CreatedAt: created, Version: 1}
+	current := &model.Contact{ID: 1, Name: "Test Again", NoTelp: "+15555553232", CreatedAt: created, Version: 2}
+	reverted := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", CreatedAt: created, UpdatedAt: now, Version: 3}
+	want := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", CreatedAt: created, UpdatedAt: now}
+
+	tests := []struct {
+		name       string
+		id         int64
+		revision   int64
+		beforeTest func(*mocks.ContactRepository, *mocks.AuditRepository)
+		want       *model.Contact
+		wantErr    string
+	}{
+		{
+			name:     "success",
+			id:       1,
+			revision: 5,
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				auditRepo.On("Detail", mock.Anything, int64(5)).Return(&model.AuditEntry{ID: 5, ContactID: 1, Operation: model.AuditAdd, After: old}, nil)
+				contactRepo.On("Detail", mock.Anything, int64(1)).Return(current, nil)
+				contactRepo.On("Update", mock.Anything, int64(1), want).Return(reverted, nil)
+				auditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 1, Actor: "anonymous", Operation: model.AuditRevert, Before: current, After: reverted, CreatedAt: now}).Return(nil, nil)
+			},
+			want: reverted,
+		},
+		{
+			name:     "to a delete",
+			id:       1,
+			revision: 6,
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				auditRepo.On("Detail", mock.Anything, int64(6)).Return(&model.AuditEntry{ID: 6, ContactID: 1, Operation: model.AuditDelete, Before: old}, nil)
+				contactRepo.On("Detail", mock.Anything, int64(1)).Return(current, nil)
+				contactRepo.On("Delete", mock.Anything, int64(1), int64(0), now).Return(nil)
+				auditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 1, Actor: "anonymous", Operation: model.AuditRevert, Before: current, CreatedAt: now}).Return(nil, nil)
+			},
+		},
+		{
+			name:     "revision of another contact",
+			id:       2,
+			revision: 5,
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				auditRepo.On("Detail", mock.Anything, int64(5)).Return(&model.AuditEntry{ID: 5, ContactID: 1, Operation: model.AuditAdd, After: old}, nil)
+			},
+			wantErr: apperrors.ErrRevisionNotFound,
+		},
+		{
+			name:     "unknown revision",
+			id:       1,
+			revision: 9,
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				auditRepo.On("Detail", mock.Anything, int64(9)).Return(nil, apperrors.NewAppError(apperrors.ErrRevisionNotFound))
+			},
+			wantErr: apperrors.ErrRevisionNotFound,
+		},
+		{
+			name:       "invalid revision",
+			id:         1,
+			beforeTest: func(*mocks.ContactRepository, *mocks.AuditRepository) {},
+			wantErr:    apperrors.ErrRevisionNotValid,
+		},
+		{
+			name:       "invalid id",
+			revision:   5,
+			beforeTest: func(*mocks.ContactRepository, *mocks.AuditRepository) {},
+			wantErr:    apperrors.ErrContactIdNotValid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+			mockAuditRepo := mocks.NewAuditRepository(t)
+			tt.beforeTest(mockContactRepo, mockAuditRepo)
+
+			uc := newTestAuditUsecase(mockContactRepo, mockAuditRepo, now)
+
+			got, err := uc.Revert(context.Background(), tt.id, tt.revision)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_contactUsecase_Undo(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	deletedAt := time.Date(2023, time.January, 1, 12, 0, 0, 0, time.UTC)
+	before := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", Version: 4}
+	trashed := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", Version: 5, DeletedAt: &deletedAt}
+	want := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", UpdatedAt: now}
+	recreate := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", UpdatedAt: now, Version: 5}
+	stored := &model.Contact{ID: 1, Name: "Test", NoTelp: "+15555553232", UpdatedAt: now, Version: 6}
+	notFound := apperrors.NewAppError(apperrors.ErrContactNotFound)
+	deleted := &model.AuditEntry{ID: 7, ContactID: 1, Operation: model.AuditDelete, Before: before}
+
+	tests := []struct {
+		name       string
+		beforeTest func(*mocks.ContactRepository, *mocks.AuditRepository)
+		want       *model.Contact
+	}{
+		{
+			name: "add",
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				auditRepo.On("Detail", mock.Anything, int64(7)).Return(&model.AuditEntry{ID: 7, ContactID: 1, Operation: model.AuditAdd, After: before}, nil)
+				contactRepo.On("Detail", mock.Anything, int64(1)).Return(before, nil)
+				contactRepo.On("Delete", mock.Anything, int64(1), int64(0), now).Return(nil)
+				auditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 1, Actor: "anonymous", Operation: model.AuditRevert, Before: before, CreatedAt: now}).Return(nil, nil)
+			},
+		},
+		{
+			name: "delete of a contact in the trash",
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				auditRepo.On("Detail", mock.Anything, int64(7)).Return(deleted, nil)
+				contactRepo.On("Detail", mock.Anything, int64(1)).Return(nil, notFound)
+				contactRepo.On("List", mock.Anything, &model.ContactQuery{Deleted: true, IDs: []int64{1}}).Return([]model.Contact{*trashed}, int64(1), nil)
+				contactRepo.On("Restore", mock.Anything, int64(1)).Return(before, nil)
+				contactRepo.On("Update", mock.Anything, int64(1), want).Return(stored, nil)
+				auditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 1, Actor: "anonymous", Operation: model.AuditRevert, Before: trashed, After: stored, CreatedAt: now}).Return(nil, nil)
+			},
+			want: stored,
+		},
+		{
+			name: "delete of a purged contact",
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				auditRepo.On("Detail", mock.Anything, int64(7)).Return(deleted, nil)
+				contactRepo.On("Detail", mock.Anything, int64(1)).Return(nil, notFound)
+				contactRepo.On("List", mock.Anything, &model.ContactQuery{Deleted: true, IDs: []int64{1}}).Return(nil, int64(0), nil)
+				auditRepo.On("List", mock.Anything, &model.AuditQuery{ContactID: 1, Limit: 1}).Return([]model.AuditEntry{*deleted}, int64(1), nil)
+				contactRepo.On("Recreate", mock.Anything, recreate).Return(recreate, nil)
+				auditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 1, Actor: "anonymous", Operation: model.AuditRevert, After: recreate, CreatedAt: now}).Return(nil, nil)
+			},
+			want: recreate,
+		},
+		{
+			name: "delete of a contact whose ID was taken",
+			beforeTest: func(contactRepo *mocks.ContactRepository, auditRepo *mocks.AuditRepository) {
+				added := &model.Contact{ID: 8, Name: "Test", NoTelp: "+15555553232", UpdatedAt: now, Version: 1}
+
+				auditRepo.On("Detail", mock.Anything, int64(7)).Return(deleted, nil)
+				contactRepo.On("Detail", mock.Anything, int64(1)).Return(nil, notFound)
+				contactRepo.On("List", mock.Anything, &model.ContactQuery{Deleted: true, IDs: []int64{1}}).Return(nil, int64(0), nil)
+				auditRepo.On("List", mock.Anything, &model.AuditQuery{ContactID: 1, Limit: 1}).Return([]model.AuditEntry{*deleted}, int64(1), nil)
+				contactRepo.On("Recreate", mock.Anything, recreate).Return(nil, apperrors.NewAppError(apperrors.ErrContactDuplicate))
+				contactRepo.On("Add", mock.Anything, recreate).Return(added, nil)
+				auditRepo.On("Add", mock.Anything, &model.AuditEntry{ContactID: 8, Actor: "anonymous", Operation: model.AuditRevert, After: added, CreatedAt: now}).Return(nil, nil)
+			},
+			want: &model.Contact{ID: 8, Name: "Test", NoTelp: "+15555553232", UpdatedAt: now, Version: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContactRepo := mocks.NewContactRepository(t)
+			mockAuditRepo := mocks.NewAuditRepository(t)
+			tt.beforeTest(mockContactRepo, mockAuditRepo)
+
+			uc := newTestAuditUsecase(mockContactRepo, mockAuditRepo, now)
+
+			got, err := uc.Undo(context.Background(), 7)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	uc := NewContactUsecase(nil, nil, nil, nil, time.Second, "US", 0, nil)
+	_, err := uc.Undo(context.Background(), 7)
+	assert.EqualError(t, err, apperrors.ErrRevisionNotFound)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"contact-go/helper/rbac"
+	"contact-go/model"
+	"context"
+)
+
+// authorizedGroupUsecase is authorizedContactUsecase for groups.
+type authorizedGroupUsecase struct {
+	usecase GroupUsecase
+	policy  *rbac.Policy
+}
+
+// NewAuthorizedGroupUsecase checks every call to uc against policy, the
+// way NewAuthorizedContactUsecase does.
+func NewAuthorizedGroupUsecase(uc GroupUsecase, policy *rbac.Policy) GroupUsecase {
+	return &authorizedGroupUsecase{usecase: uc, policy: policy}
+}
+
+func (uc *authorizedGroupUsecase) List(ctx context.Context) ([]model.Group, error) {
+	if err := authorize(ctx, uc.policy, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.List(ctx)
+}
+
+func (uc *authorizedGroupUsecase) Add(ctx context.Context, req *model.GroupRequest) (*model.Group, error) {
+	if err := authorize(ctx, uc.policy, rbac.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Add(ctx, req)
+}
+
+func (uc *authorizedGroupUsecase) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	if err := authorize(ctx, uc.policy, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Detail(ctx, id)
+}
+
+func (uc *authorizedGroupUsecase) Update(ctx context.Context, id int64, req *model.GroupRequest) (*model.Group, error) {
+	if err := authorize(ctx, uc.policy, rbac.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Update(ctx, id, req)
+}
+
+func (uc *authorizedGroupUsecase) Delete(ctx context.Context, id int64) error {
+	if err := authorize(ctx, uc.policy, rbac.PermissionDelete); err != nil {
+		return err
+	}
+	return uc.usecase.Delete(ctx, id)
+}
+
+func (uc *authorizedGroupUsecase) Members(ctx context.Context, id int64) ([]model.Contact, error) {
+	if err := authorize(ctx, uc.policy, rbac.PermissionRead); err != nil {
+		return nil, err
+	}
+	return uc.usecase.Members(ctx, id)
+}
+
+func (uc *authorizedGroupUsecase) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if err := authorize(ctx, uc.policy, rbac.PermissionWrite); err != nil {
+		return err
+	}
+	return uc.usecase.AddMembers(ctx, id, contactIDs)
+}
+
+func (uc *authorizedGroupUsecase) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	if err := authorize(ctx, uc.policy, rbac.PermissionWrite); err != nil {
+		return err
+	}
+	return uc.usecase.RemoveMembers(ctx, id, contactIDs)
+}
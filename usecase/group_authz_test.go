@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"contact-go/helper/actor"
+	"contact-go/helper/apperrors"
+	"contact-go/helper/auth"
+	"contact-go/mocks"
+	"contact-go/model"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authorizedGroupUsecase(t *testing.T) {
+	group := &model.Group{ID: 1, Name: "Family"}
+	support := auth.NewContext(context.Background(), &auth.Principal{Name: "helpdesk", Method: auth.MethodAPIKey})
+	intern := auth.NewContext(context.Background(), &auth.Principal{Name: "bob", Method: auth.MethodJWT})
+	cli := actor.NewContext(context.Background(), actor.CLI)
+
+	tests := []struct {
+		name    string
+		call    func(GroupUsecase) error
+		mock    func(*mocks.GroupUsecase)
+		wantErr string
+	}{
+		{
+			name: "intern lists",
+			call: func(uc GroupUsecase) error {
+				_, err := uc.List(intern)
+				return err
+			},
+			mock: func(m *mocks.GroupUsecase) {
+				m.On("List", intern).Return([]model.Group{*group}, nil)
+			},
+		},
+		{
+			name: "intern reads",
+			call: func(uc GroupUsecase) error {
+				_, err := uc.Detail(intern, 1)
+				return err
+			},
+			mock: func(m *mocks.GroupUsecase) {
+				m.On("Detail", intern, int64(1)).Return(group, nil)
+			},
+		},
+		{
+			name: "intern reads the members",
+			call: func(uc GroupUsecase) error {
+				_, err := uc.Members(intern, 1)
+				return err
+			},
+			mock: func(m *mocks.GroupUsecase) {
+				m.On("Members", intern, int64(1)).Return(nil, nil)
+			},
+		},
+		{
+			name: "intern does not add",
+			call: func(uc GroupUsecase) error {
+				_, err := uc.Add(intern, &model.GroupRequest{Name: "Family"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "intern does not update",
+			call: func(uc GroupUsecase) error {
+				_, err := uc.Update(intern, 1, &model.GroupRequest{Name: "Family"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "intern does not delete",
+			call: func(uc GroupUsecase) error {
+				return uc.Delete(intern, 1)
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "intern does not add members",
+			call: func(uc GroupUsecase) error {
+				return uc.AddMembers(intern, 1, []int64{2})
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "intern does not remove members",
+			call: func(uc GroupUsecase) error {
+				return uc.RemoveMembers(intern, 1, []int64{2})
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "anonymous has the default role",
+			call: func(uc GroupUsecase) error {
+				_, err := uc.Add(context.Background(), &model.GroupRequest{Name: "Family"})
+				return err
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "support adds members",
+			call: func(uc GroupUsecase) error {
+				return uc.AddMembers(support, 1, []int64{2})
+			},
+			mock: func(m *mocks.GroupUsecase) {
+				m.On("AddMembers", support, int64(1), []int64{2}).Return(nil)
+			},
+		},
+		{
+			name: "support does not delete",
+			call: func(uc GroupUsecase) error {
+				return uc.Delete(support, 1)
+			},
+			wantErr: apperrors.ErrPermissionDenied,
+		},
+		{
+			name: "cli deletes",
+			call: func(uc GroupUsecase) error {
+				return uc.Delete(cli, 1)
+			},
+			mock: func(m *mocks.GroupUsecase) {
+				m.On("Delete", cli, int64(1)).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mocks.GroupUsecase)
+			if tt.mock != nil {
+				tt.mock(m)
+			}
+
+			err := tt.call(NewAuthorizedGroupUsecase(m, newTestPolicy(t)))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/model"
+	"contact-go/repository"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+type groupUsecase struct {
+	GroupRepo   repository.GroupRepository
+	ContactRepo repository.ContactRepository
+	Timeout     time.Duration
+
+	now func() time.Time
+}
+
+// NewGroupUsecase bounds every repository call by timeout, the way
+// NewContactUsecase does. contactRepo holds the contacts put in groups.
+func NewGroupUsecase(groupRepo repository.GroupRepository, contactRepo repository.ContactRepository, timeout time.Duration) GroupUsecase {
+	return &groupUsecase{
+		GroupRepo:   groupRepo,
+		ContactRepo: contactRepo,
+		Timeout:     timeout,
+		now:         now,
+	}
+}
+
+func (uc *groupUsecase) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if uc.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, uc.Timeout)
+}
+
+// isAppError reports whether err is, or wraps, the *apperrors.AppError of message.
+func isAppError(err error, message string) bool {
+	var e *apperrors.AppError
+	return errors.As(err, &e) && e.Message == message
+}
+
+// newGroup checks req and builds the group it describes.
+func newGroup(req *model.GroupRequest) (*model.Group, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, apperrors.NewAppError(apperrors.ErrGroupNameNotValid)
+	}
+
+	return &model.Group{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}, nil
+}
+
+// checkName fails when a group other than the group id is already
+// called name.
+func (uc *groupUsecase) checkName(ctx context.Context, name string, id int64) error {
+	group, err := uc.GroupRepo.FindByName(ctx, name)
+	if isAppError(err, apperrors.ErrGroupNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if group.ID != id {
+		return apperrors.NewAppError(apperrors.ErrGroupDuplicate)
+	}
+	return nil
+}
+
+// memberIDs checks the contact ids of a membership change,
+// returning them with the repeats dropped.
+func memberIDs(contactIDs []int64) ([]int64, error) {
+	if len(contactIDs) == 0 || len(contactIDs) > model.MaxContactLimit {
+		return nil, apperrors.NewAppError(apperrors.ErrGroupMembersNotValid)
+	}
+
+	seen := make(map[int64]bool, len(contactIDs))
+	ids := make([]int64, 0, len(contactIDs))
+	for _, id := range contactIDs {
+		if id <= 0 {
+			return nil, apperrors.NewAppError(apperrors.ErrGroupMembersNotValid)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (uc *groupUsecase) List(ctx context.Context) ([]model.Group, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.GroupRepo.List(ctx)
+}
+
+func (uc *groupUsecase) Add(ctx context.Context, req *model.GroupRequest) (*model.Group, error) {
+	group, err := newGroup(req)
+	if err != nil {
+		return nil, err
+	}
+	group.CreatedAt = uc.now()
+	group.UpdatedAt = group.CreatedAt
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	if err := uc.checkName(ctx, group.Name, 0); err != nil {
+		return nil, err
+	}
+
+	return uc.GroupRepo.Add(ctx, group)
+}
+
+func (uc *groupUsecase) Detail(ctx context.Context, id int64) (*model.Group, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.GroupRepo.Detail(ctx, id)
+}
+
+func (uc *groupUsecase) Update(ctx context.Context, id int64, req *model.GroupRequest) (*model.Group, error) {
+	group, err := newGroup(req)
+	if err != nil {
+		return nil, err
+	}
+	group.UpdatedAt = uc.now()
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	if err := uc.checkName(ctx, group.Name, id); err != nil {
+		return nil, err
+	}
+
+	return uc.GroupRepo.Update(ctx, id, group)
+}
+
+func (uc *groupUsecase) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.GroupRepo.Delete(ctx, id)
+}
+
+func (uc *groupUsecase) Members(ctx context.Context, id int64) ([]model.Contact, error) {
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	ids, err := uc.GroupRepo.Members(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	contacts, _, err := uc.ContactRepo.List(ctx, &model.ContactQuery{IDs: ids})
+	return contacts, err
+}
+
+// AddMembers puts the contacts contactIDs in the group id. Every one of
+// them has to be a contact outside the trash, or none is added.
+func (uc *groupUsecase) AddMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	ids, err := memberIDs(contactIDs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	_, total, err := uc.ContactRepo.List(ctx, &model.ContactQuery{IDs: ids})
+	if err != nil {
+		return err
+	}
+	if total != int64(len(ids)) {
+		return apperrors.NewAppError(apperrors.ErrContactNotFound)
+	}
+
+	return uc.GroupRepo.AddMembers(ctx, id, ids)
+}
+
+func (uc *groupUsecase) RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error {
+	ids, err := memberIDs(contactIDs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := uc.withTimeout(ctx)
+	defer cancel()
+
+	return uc.GroupRepo.RemoveMembers(ctx, id, ids)
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"contact-go/helper/apperrors"
+	"contact-go/mocks"
+	"contact-go/model"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestGroupUsecase(groupRepo *mocks.GroupRepository, contactRepo *mocks.ContactRepository, now time.Time) *groupUsecase {
+	uc := NewGroupUsecase(groupRepo, contactRepo, time.Second).(*groupUsecase)
+	uc.now = func() time.Time { return now }
+	return uc
+}
+
+func Test_groupUsecase_Add(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	notFound := apperrors.NewAppError(apperrors.ErrGroupNotFound)
+
+	tests := []struct {
+		name       string
+		req        *model.GroupRequest
+		beforeTest func(*mocks.GroupRepository)
+		want       *model.Group
+		wantErr    string
+	}{
+		{
+			name: "success",
+			req:  &model.GroupRequest{Name: " Team ", Description: "on-call"},
+			beforeTest: func(repo *mocks.GroupRepository) {
+				group := &model.Group{Name: "Team", Description: "on-call", CreatedAt: now, UpdatedAt: now}
+				repo.On("FindByName", mock.Anything, "Team").Return(nil, notFound)
+				repo.On("Add", mock.Anything, group).Return(&model.Group{ID: 1, Name: "Team", Description: "on-call", CreatedAt: now, UpdatedAt: now}, nil)
+			},
+			want: &model.Group{ID: 1, Name: "Team", Description: "on-call", CreatedAt: now, UpdatedAt: now},
+		},
+		{
+			name: "duplicate name",
+			req:  &model.GroupRequest{Name: "team"},
+			beforeTest: func(repo *mocks.GroupRepository) {
+				repo.On("FindByName", mock.Anything, "team").Return(&model.Group{ID: 1, Name: "Team"}, nil)
+			},
+			wantErr: apperrors.ErrGroupDuplicate,
+		},
+		{
+			name:    "empty name",
+			req:     &model.GroupRequest{Name: "  "},
+			wantErr: apperrors.ErrGroupNameNotValid,
+		},
+		{
+			name: "failed",
+			req:  &model.GroupRequest{Name: "team"},
+			beforeTest: func(repo *mocks.GroupRepository) {
+				repo.On("FindByName", mock.Anything, "team").Return(nil, assert.AnError)
+			},
+			wantErr: assert.AnError.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGroupRepo := mocks.NewGroupRepository(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mockGroupRepo)
+			}
+
+			uc := newTestGroupUsecase(mockGroupRepo, nil, now)
+
+			got, err := uc.Add(context.Background(), tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_groupUsecase_Update(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		id         int64
+		req        *model.GroupRequest
+		beforeTest func(*mocks.GroupRepository)
+		want       *model.Group
+		wantErr    string
+	}{
+		{
+			name: "rename keeping the name",
+			id:   1,
+			req:  &model.GroupRequest{Name: "TEAM"},
+			beforeTest: func(repo *mocks.GroupRepository) {
+				repo.On("FindByName", mock.Anything, "TEAM").Return(&model.Group{ID: 1, Name: "team"}, nil)
+				repo.On("Update", mock.Anything, int64(1), &model.Group{Name: "TEAM", UpdatedAt: now}).
+					Return(&model.Group{ID: 1, Name: "TEAM", UpdatedAt: now}, nil)
+			},
+			want: &model.Group{ID: 1, Name: "TEAM", UpdatedAt: now},
+		},
+		{
+			name: "name of another group",
+			id:   2,
+			req:  &model.GroupRequest{Name: "team"},
+			beforeTest: func(repo *mocks.GroupRepository) {
+				repo.On("FindByName", mock.Anything, "team").Return(&model.Group{ID: 1, Name: "team"}, nil)
+			},
+			wantErr: apperrors.ErrGroupDuplicate,
+		},
+		{
+			name: "missing group",
+			id:   3,
+			req:  &model.GroupRequest{Name: "friends"},
+			beforeTest: func(repo *mocks.GroupRepository) {
+				repo.On("FindByName", mock.Anything, "friends").Return(nil, apperrors.NewAppError(apperrors.ErrGroupNotFound))
+				repo.On("Update", mock.Anything, int64(3), mock.Anything).Return(nil, apperrors.NewAppError(apperrors.ErrGroupNotFound))
+			},
+			wantErr: apperrors.ErrGroupNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGroupRepo := mocks.NewGroupRepository(t)
+			tt.beforeTest(mockGroupRepo)
+
+			uc := newTestGroupUsecase(mockGroupRepo, nil, now)
+
+			got, err := uc.Update(context.Background(), tt.id, tt.req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_groupUsecase_Members(t *testing.T) {
+	mockGroupRepo := mocks.NewGroupRepository(t)
+	mockContactRepo := mocks.NewContactRepository(t)
+
+	contacts := []model.Contact{{ID: 2, Name: "jane"}, {ID: 5, Name: "john"}}
+	mockGroupRepo.On("Members", mock.Anything, int64(1)).Return([]int64{2, 5}, nil)
+	mockGroupRepo.On("Members", mock.Anything, int64(2)).Return(nil, nil)
+	mockContactRepo.On("List", mock.Anything, &model.ContactQuery{IDs: []int64{2, 5}}).Return(contacts, int64(2), nil)
+
+	uc := NewGroupUsecase(mockGroupRepo, mockContactRepo, time.Second)
+
+	got, err := uc.Members(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, contacts, got)
+
+	got, err = uc.Members(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_groupUsecase_AddMembers(t *testing.T) {
+	tests := []struct {
+		name       string
+		contactIDs []int64
+		beforeTest func(*mocks.GroupRepository, *mocks.ContactRepository)
+		wantErr    string
+	}{
+		{
+			name:       "success",
+			contactIDs: []int64{2, 5, 2},
+			beforeTest: func(groupRepo *mocks.GroupRepository, contactRepo *mocks.ContactRepository) {
+				contactRepo.On("List", mock.Anything, &model.ContactQuery{IDs: []int64{2, 5}}).
+					Return([]model.Contact{{ID: 2}, {ID: 5}}, int64(2), nil)
+				groupRepo.On("AddMembers", mock.Anything, int64(1), []int64{2, 5}).Return(nil)
+			},
+		},
+		{
+			name:       "missing contact",
+			contactIDs: []int64{2, 9},
+			beforeTest: func(_ *mocks.GroupRepository, contactRepo *mocks.ContactRepository) {
+				contactRepo.On("List", mock.Anything, &model.ContactQuery{IDs: []int64{2, 9}}).
+					Return([]model.Contact{{ID: 2}}, int64(1), nil)
+			},
+			wantErr: apperrors.ErrContactNotFound,
+		},
+		{
+			name:       "no contacts",
+			contactIDs: nil,
+			wantErr:    apperrors.ErrGroupMembersNotValid,
+		},
+		{
+			name:       "invalid contact id",
+			contactIDs: []int64{2, 0},
+			wantErr:    apperrors.ErrGroupMembersNotValid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGroupRepo := mocks.NewGroupRepository(t)
+			mockContactRepo := mocks.NewContactRepository(t)
+			if tt.beforeTest != nil {
+				tt.beforeTest(mockGroupRepo, mockContactRepo)
+			}
+
+			uc := NewGroupUsecase(mockGroupRepo, mockContactRepo, time.Second)
+
+			err := uc.AddMembers(context.Background(), 1, tt.contactIDs)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_groupUsecase_RemoveMembers(t *testing.T) {
+	mockGroupRepo := mocks.NewGroupRepository(t)
+	mockGroupRepo.On("RemoveMembers", mock.Anything, int64(1), []int64{2}).Return(nil)
+
+	uc := NewGroupUsecase(mockGroupRepo, nil, time.Second)
+
+	assert.NoError(t, uc.RemoveMembers(context.Background(), 1, []int64{2, 2}))
+	assert.EqualError(t, uc.RemoveMembers(context.Background(), 1, []int64{-1}), apperrors.ErrGroupMembersNotValid)
+}
@@ -0,0 +1,20 @@
+//go:generate mockery --output=../mocks --name GroupUsecase
+
+package usecase
+
+import (
+	"contact-go/model"
+	"context"
+)
+
+type GroupUsecase interface {
+	List(ctx context.Context) ([]model.Group, error)
+	Add(ctx context.Context, req *model.GroupRequest) (*model.Group, error)
+	Detail(ctx context.Context, id int64) (*model.Group, error)
+	Update(ctx context.Context, id int64, req *model.GroupRequest) (*model.Group, error)
+	Delete(ctx context.Context, id int64) error
+	// Members returns the contacts in the group id, leaving out those in the trash.
+	Members(ctx context.Context, id int64) ([]model.Contact, error)
+	AddMembers(ctx context.Context, id int64, contactIDs []int64) error
+	RemoveMembers(ctx context.Context, id int64, contactIDs []int64) error
+}